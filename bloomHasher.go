@@ -0,0 +1,172 @@
+package redisson
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"math/bits"
+)
+
+// BloomHasher 计算布隆过滤器中元素的位索引，通过 WithBloomHasher 在创建布隆过滤器时选择。
+// 同一名字的布隆过滤器在所有实例上必须使用相同的 BloomHasher 和编解码器。
+type BloomHasher interface {
+	// Indexes 返回编码后的元素 data 在 size 位的位数组中的 hashIterations 个位索引
+	Indexes(data []byte, hashIterations int, size int64) []int64
+}
+
+// SHA256BloomHasher 默认的 BloomHasher，由 SHA256 的前两个 64 位值双哈希得到位索引
+type SHA256BloomHasher struct{}
+
+// Indexes 实现 BloomHasher
+func (SHA256BloomHasher) Indexes(data []byte, hashIterations int, size int64) []int64 {
+	hashBytes := sha256.Sum256(data)
+
+	// 使用两个独立的哈希值进行双哈希
+	hash1 := binary.BigEndian.Uint64(hashBytes[0:8])
+	hash2 := binary.BigEndian.Uint64(hashBytes[8:16])
+
+	indexes := make([]int64, hashIterations)
+	for i := 0; i < hashIterations; i++ {
+		combinedHash := hash1 + uint64(i)*hash2
+		indexes[i] = int64(combinedHash % uint64(size))
+	}
+	return indexes
+}
+
+// HighwayBloomHasher 与 Java Redisson 的 RBloomFilter 相同的 BloomHasher：使用 HighwayHash 128 位哈希，
+// 两个 64 位值交替累加得到位索引。元素的编码也需与 Java 端相同，例如两端都使用 StringCodec。
+type HighwayBloomHasher struct{}
+
+// highwayBloomKey Java Redisson 计算哈希使用的 HighwayHash 密钥
+var highwayBloomKey = [4]uint64{0x9E3779B97F4A7C15, 0xF39CC0605CEDC834, 0x1082276BF3A27251, 0xF86C6A11D0C18E95}
+
+// Indexes 实现 BloomHasher
+func (HighwayBloomHasher) Indexes(data []byte, hashIterations int, size int64) []int64 {
+	hash1, hash2 := highwayHash128(highwayBloomKey, data)
+
+	indexes := make([]int64, hashIterations)
+	hash := hash1
+	for i := 0; i < hashIterations; i++ {
+		indexes[i] = int64(hash&(1<<63-1)) % size
+		if i%2 == 0 {
+			hash += hash2
+		} else {
+			hash += hash1
+		}
+	}
+	return indexes
+}
+
+// highwayHash HighwayHash 的状态，参见 https://github.com/google/highwayhash
+type highwayHash struct {
+	v0, v1, mul0, mul1 [4]uint64
+}
+
+var (
+	highwayInit0 = [4]uint64{0xdbe6d5d5fe4cce2f, 0xa4093822299f31d0, 0x13198a2e03707344, 0x243f6a8885a308d3}
+	highwayInit1 = [4]uint64{0x3bd39e10cb0ef593, 0xc0acf169b5f18a8c, 0xbe5466cf34e90c6c, 0x452821e638d01377}
+)
+
+// highwayHash64 返回 data 的 64 位 HighwayHash
+func highwayHash64(key [4]uint64, data []byte) uint64 {
+	h := newHighwayHash(key, data)
+	for i := 0; i < 4; i++ {
+		h.permuteAndUpdate()
+	}
+	return h.v0[0] + h.v1[0] + h.mul0[0] + h.mul1[0]
+}
+
+// highwayHash128 返回 data 的 128 位 HighwayHash
+func highwayHash128(key [4]uint64, data []byte) (uint64, uint64) {
+	h := newHighwayHash(key, data)
+	for i := 0; i < 6; i++ {
+		h.permuteAndUpdate()
+	}
+	return h.v0[0] + h.mul0[0] + h.v1[2] + h.mul1[2],
+		h.v0[1] + h.mul0[1] + h.v1[3] + h.mul1[3]
+}
+
+// newHighwayHash 返回处理了 data 的状态
+func newHighwayHash(key [4]uint64, data []byte) *highwayHash {
+	h := &highwayHash{mul0: highwayInit0, mul1: highwayInit1}
+	for i := range key {
+		h.v0[i] = highwayInit0[i] ^ key[i]
+		h.v1[i] = highwayInit1[i] ^ bits.RotateLeft64(key[i], 32)
+	}
+	for ; len(data) >= 32; data = data[32:] {
+		h.updatePacket(data)
+	}
+	if len(data) > 0 {
+		h.updateRemainder(data)
+	}
+	return h
+}
+
+// updatePacket 处理 32 字节的数据
+func (h *highwayHash) updatePacket(packet []byte) {
+	var lanes [4]uint64
+	for i := range lanes {
+		lanes[i] = binary.LittleEndian.Uint64(packet[i*8:])
+	}
+	h.update(lanes)
+}
+
+// updateRemainder 处理不足 32 字节的剩余数据
+func (h *highwayHash) updateRemainder(data []byte) {
+	size := len(data)
+	for i := range h.v0 {
+		h.v0[i] += uint64(size)<<32 + uint64(size)
+		low := bits.RotateLeft32(uint32(h.v1[i]), size)
+		high := bits.RotateLeft32(uint32(h.v1[i]>>32), size)
+		h.v1[i] = uint64(low) | uint64(high)<<32
+	}
+	var packet [32]byte
+	size4 := size &^ 3
+	copy(packet[:], data[:size4])
+	remainder := data[size4:]
+	if size&16 != 0 {
+		for i := 0; i < 4; i++ {
+			packet[28+i] = data[size4+i+len(remainder)-4]
+		}
+	} else if len(remainder) > 0 {
+		packet[16] = remainder[0]
+		packet[17] = remainder[len(remainder)>>1]
+		packet[18] = remainder[len(remainder)-1]
+	}
+	h.updatePacket(packet[:])
+}
+
+// update 混合一组 4 个 64 位值
+func (h *highwayHash) update(lanes [4]uint64) {
+	for i := range lanes {
+		h.v1[i] += h.mul0[i] + lanes[i]
+		h.mul0[i] ^= (h.v1[i] & 0xffffffff) * (h.v0[i] >> 32)
+		h.v0[i] += h.mul1[i]
+		h.mul1[i] ^= (h.v0[i] & 0xffffffff) * (h.v1[i] >> 32)
+	}
+	zipperMergeAndAdd(h.v1[1], h.v1[0], &h.v0[1], &h.v0[0])
+	zipperMergeAndAdd(h.v1[3], h.v1[2], &h.v0[3], &h.v0[2])
+	zipperMergeAndAdd(h.v0[1], h.v0[0], &h.v1[1], &h.v1[0])
+	zipperMergeAndAdd(h.v0[3], h.v0[2], &h.v1[3], &h.v1[2])
+}
+
+// permuteAndUpdate 结束时混合状态的一轮
+func (h *highwayHash) permuteAndUpdate() {
+	h.update([4]uint64{
+		bits.RotateLeft64(h.v0[2], 32),
+		bits.RotateLeft64(h.v0[3], 32),
+		bits.RotateLeft64(h.v0[0], 32),
+		bits.RotateLeft64(h.v0[1], 32),
+	})
+}
+
+// zipperMergeAndAdd 将 v1 和 v0 的字节重排后加到 add1 和 add0
+func zipperMergeAndAdd(v1, v0 uint64, add1, add0 *uint64) {
+	*add0 += (((v0 & 0xff000000) | (v1 & 0xff00000000)) >> 24) |
+		(((v0 & 0xff0000000000) | (v1 & 0xff000000000000)) >> 16) |
+		(v0 & 0xff0000) | ((v0 & 0xff00) << 32) |
+		((v1 & 0xff00000000000000) >> 8) | (v0 << 56)
+	*add1 += (((v1 & 0xff000000) | (v0 & 0xff00000000)) >> 24) |
+		(v1 & 0xff0000) | ((v1 & 0xff0000000000) >> 16) |
+		((v1 & 0xff00) << 24) | ((v0 & 0xff000000000000) >> 8) |
+		((v1 & 0xff) << 48) | (v0 & 0xff00000000000000)
+}
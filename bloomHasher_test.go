@@ -0,0 +1,60 @@
+package redisson
+
+import (
+	"context"
+	"testing"
+)
+
+func TestHighwayHash(t *testing.T) {
+	// test vectors of the reference implementation, the key and the data are the bytes 0, 1, 2, ...
+	key := [4]uint64{0x0706050403020100, 0x0F0E0D0C0B0A0908, 0x1716151413121110, 0x1F1E1D1C1B1A1918}
+	data := make([]byte, 64)
+	for i := range data {
+		data[i] = byte(i)
+	}
+	expected64 := map[int]uint64{
+		0: 0x907A56DE22C26E53,
+		1: 0x7EAB43AAC7CDDD78,
+		2: 0xB8D0569AB0B53D62,
+		3: 0x5C6BEFAB8A463D80,
+		4: 0xF205A46893007EDA,
+		5: 0x2B8A1668E4A94541,
+		6: 0xBD4CCC325BEFCA6F,
+		7: 0x4D02AE1738F59482,
+		8: 0xE1205108E55F3171,
+		9: 0x32D2644EC77A1584,
+	}
+	for n, expected := range expected64 {
+		if h := highwayHash64(key, data[:n]); h != expected {
+			t.Errorf("expected the 64 bit hash of %d bytes to be %016X, got %016X", n, expected, h)
+		}
+	}
+	if h1, h2 := highwayHash128(key, nil); h1 != 0x0FED268F9D8FFEC7 || h2 != 0x33565E767F093E6F {
+		t.Errorf("unexpected 128 bit hash of no bytes %016X %016X", h1, h2)
+	}
+}
+
+func TestBloomFilterHasher(t *testing.T) {
+	red := GetRedisson()
+	bf := GetBloomFilter[string](red, "bloom_filter_hasher", WithBloomHasher(HighwayBloomHasher{}), WithCodec(StringCodec{}))
+	defer bf.Delete()
+	bf.Delete()
+	bf.TryInit(1000, 0.01)
+	if !bf.Add("hello") {
+		t.Fatal("expected hello to be added")
+	}
+	if !bf.Contains("hello") || bf.Contains("world") {
+		t.Fatal("expected the filter to contain hello and not world")
+	}
+	// the bits of an element are those Java Redisson sets for the same string with the StringCodec
+	indexes := HighwayBloomHasher{}.Indexes([]byte("hello"), bf.GetHashIterations(), bf.GetSize())
+	for _, idx := range indexes {
+		bit, err := red.client.GetBit(context.Background(), "bloom_filter_hasher", idx).Result()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if bit != 1 {
+			t.Fatalf("expected the bit %d to be set", idx)
+		}
+	}
+}
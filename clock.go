@@ -0,0 +1,253 @@
+package redisson
+
+import (
+	"context"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Clock provides the time to the time-dependent logic of redisson: the lock watchdog and waits,
+// rate limiter timestamps, TTL math and time-bucketed objects.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+
+	// After waits for the duration to elapse and then sends the current time on the returned channel.
+	After(d time.Duration) <-chan time.Time
+}
+
+// afterFuncClock is a Clock which can call a function once a duration elapsed without a goroutine waiting
+// for it, the returned function stops the call and reports whether it was stopped before it ran
+type afterFuncClock interface {
+	AfterFunc(d time.Duration, f func()) func() bool
+}
+
+// clockAfterFunc calls f once d elapsed on clock, waiting in a goroutine on After if clock is not an afterFuncClock.
+// Like the Stop of a time.Timer, the returned function does not wait for f if it already started.
+func clockAfterFunc(clock Clock, d time.Duration, f func()) func() bool {
+	if clock, ok := clock.(afterFuncClock); ok {
+		return clock.AfterFunc(d, f)
+	}
+	stop := make(chan struct{})
+	var done atomic.Bool
+	go func() {
+		select {
+		case <-clock.After(d):
+			if done.CompareAndSwap(false, true) {
+				f()
+			}
+		case <-stop:
+		}
+	}()
+	return func() bool {
+		if !done.CompareAndSwap(false, true) {
+			return false
+		}
+		close(stop)
+		return true
+	}
+}
+
+// SystemClock is the Clock of a Redisson instance when none is configured, it reads the system time.
+var SystemClock Clock = systemClock{}
+
+// systemClock is the Clock reading the system time
+type systemClock struct{}
+
+// Now returns time.Now().
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// After returns time.After(d).
+func (systemClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// AfterFunc returns time.AfterFunc(d, f).Stop.
+func (systemClock) AfterFunc(d time.Duration, f func()) func() bool {
+	return time.AfterFunc(d, f).Stop
+}
+
+// ManualClock is a Clock which only moves when it is advanced, for tests.
+type ManualClock struct {
+	sync.Mutex
+	now     time.Time
+	waiters []*manualWaiter
+}
+
+// manualWaiter is a channel, or a function if f is not nil, waiting for the ManualClock to reach at
+type manualWaiter struct {
+	at time.Time
+	c  chan time.Time
+	f  func()
+}
+
+// NewManualClock returns a ManualClock set to now.
+func NewManualClock(now time.Time) *ManualClock {
+	return &ManualClock{now: now}
+}
+
+// Now returns the time the clock is set to.
+func (c *ManualClock) Now() time.Time {
+	c.Lock()
+	defer c.Unlock()
+	return c.now
+}
+
+// After returns a channel receiving the time once the clock is advanced by d.
+func (c *ManualClock) After(d time.Duration) <-chan time.Time {
+	c.Lock()
+	defer c.Unlock()
+	ch := make(chan time.Time, 1)
+	if d <= 0 {
+		ch <- c.now
+		return ch
+	}
+	c.waiters = append(c.waiters, &manualWaiter{at: c.now.Add(d), c: ch})
+	return ch
+}
+
+// AfterFunc calls f once the clock is advanced by d, f runs in the goroutine advancing the clock.
+func (c *ManualClock) AfterFunc(d time.Duration, f func()) func() bool {
+	if d <= 0 {
+		go f()
+		return func() bool { return false }
+	}
+	c.Lock()
+	defer c.Unlock()
+	w := &manualWaiter{at: c.now.Add(d), f: f}
+	c.waiters = append(c.waiters, w)
+	return func() bool {
+		c.Lock()
+		defer c.Unlock()
+		for i, waiter := range c.waiters {
+			if waiter == w {
+				c.waiters = append(c.waiters[:i], c.waiters[i+1:]...)
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Advance moves the clock forward by d, firing the channels of the waits which elapsed.
+func (c *ManualClock) Advance(d time.Duration) {
+	c.Lock()
+	c.now = c.now.Add(d)
+	var fired []func()
+	waiters := c.waiters[:0]
+	for _, w := range c.waiters {
+		if w.at.After(c.now) {
+			waiters = append(waiters, w)
+			continue
+		}
+		if w.f != nil {
+			fired = append(fired, w.f)
+			continue
+		}
+		w.c <- c.now
+	}
+	c.waiters = waiters
+	c.Unlock()
+	// the functions run unlocked since they may read or wait on the clock
+	for _, f := range fired {
+		f()
+	}
+}
+
+// ServerClock is a Clock following the time of the redis server, so that the timestamps of all the hosts
+// sharing objects agree regardless of the accuracy of their clocks. It adds to the local time the offset
+// to the server time, which is measured with the TIME command and refreshed in the background once stale.
+type ServerClock struct {
+	sync.Mutex
+	client   *redis.Client
+	interval time.Duration
+	offset   time.Duration
+	synced   time.Time
+	syncing  bool
+}
+
+// serverClockSyncTimeout bounds the measures of the offset to the server time made in the background
+const serverClockSyncTimeout = time.Second
+
+// NewServerClock returns a ServerClock refreshing its offset to the time of the server every interval.
+// It does not wait for redis: the first offset is measured in the background and the local time is used until then,
+// call Sync to wait for it.
+func NewServerClock(client *redis.Client, interval time.Duration) *ServerClock {
+	c := &ServerClock{
+		client:   client,
+		interval: interval,
+	}
+	c.Lock()
+	c.syncInBackground()
+	c.Unlock()
+	return c
+}
+
+// Sync measures the offset of the local time to the time of the server.
+func (c *ServerClock) Sync(ctx context.Context) error {
+	start := time.Now()
+	serverTime, err := c.client.Time(ctx).Result()
+	if err != nil {
+		return err
+	}
+	end := time.Now()
+	// the server read its time about halfway through the round trip
+	offset := serverTime.Sub(start.Add(end.Sub(start) / 2))
+	c.Lock()
+	c.offset = offset
+	c.synced = end
+	c.Unlock()
+	return nil
+}
+
+// Offset returns the last measured offset of the local time to the time of the server.
+func (c *ServerClock) Offset() time.Duration {
+	c.Lock()
+	defer c.Unlock()
+	return c.offset
+}
+
+// Now returns the time of the server, refreshing the offset in the background when it is stale.
+func (c *ServerClock) Now() time.Time {
+	now := time.Now()
+	c.Lock()
+	defer c.Unlock()
+	if !c.syncing && now.Sub(c.synced) > c.interval {
+		c.syncInBackground()
+	}
+	return now.Add(c.offset)
+}
+
+// syncInBackground measures the offset in a goroutine, c being locked
+func (c *ServerClock) syncInBackground() {
+	c.syncing = true
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), serverClockSyncTimeout)
+		defer cancel()
+		err := c.Sync(ctx)
+		c.Lock()
+		if err != nil {
+			// keep the last offset, the local time before the first sync, and retry after another interval
+			log.Printf("server clock: sync failed: %v", err)
+			c.synced = time.Now()
+		}
+		c.syncing = false
+		c.Unlock()
+	}()
+}
+
+// After returns time.After(d), durations do not depend on the offset.
+func (c *ServerClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// AfterFunc returns time.AfterFunc(d, f).Stop.
+func (c *ServerClock) AfterFunc(d time.Duration, f func()) func() bool {
+	return time.AfterFunc(d, f).Stop
+}
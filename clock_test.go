@@ -0,0 +1,100 @@
+package redisson
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+func TestManualClock(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	c := NewManualClock(start)
+	after := c.After(10 * time.Second)
+	c.Advance(5 * time.Second)
+	select {
+	case <-after:
+		t.Fatal("fired too early")
+	default:
+	}
+	c.Advance(5 * time.Second)
+	select {
+	case now := <-after:
+		if !now.Equal(start.Add(10 * time.Second)) {
+			t.Fatalf("now=%v", now)
+		}
+	default:
+		t.Fatal("not fired")
+	}
+}
+
+// TestLockRenewManualClock runs the watchdog renewal of TestLockRenew without waiting for it
+func TestLockRenewManualClock(t *testing.T) {
+	redisDB := redis.NewClient(&redis.Options{
+		Addr: redisAddr,
+	})
+	clock := NewManualClock(time.Now())
+	g := NewRedisson(redisDB, WithClock(clock))
+	lock := g.GetLock("TestLockRenewManualClock")
+	if err := lock.LockContext(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	defer lock.Unlock()
+
+	// shorten the lease, the watchdog must restore it once a third of the watchdog timeout elapsed
+	if err := redisDB.PExpire(context.Background(), "TestLockRenewManualClock", 5*time.Second).Err(); err != nil {
+		t.Fatal(err)
+	}
+	clock.Advance(DefaultWatchDogTimeout/3 + time.Second)
+	deadline := time.Now().Add(time.Second)
+	for {
+		ttl, err := redisDB.PTTL(context.Background(), "TestLockRenewManualClock").Result()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ttl > 5*time.Second {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("lease was not renewed, ttl=%v", ttl)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestServerClock(t *testing.T) {
+	redisDB := redis.NewClient(&redis.Options{
+		Addr: redisAddr,
+	})
+	c := NewServerClock(redisDB, time.Minute)
+	if err := c.Sync(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	serverTime, err := redisDB.Time(context.Background()).Result()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := c.Now().Sub(serverTime); d < -time.Second || d > time.Second {
+		t.Fatalf("server clock is %v away from the server time", d)
+	}
+
+	g := NewRedisson(redisDB, WithServerTime(time.Minute))
+	if _, ok := g.clock.(*ServerClock); !ok {
+		t.Fatalf("clock=%T", g.clock)
+	}
+
+	// an unreachable redis does not block the construction, the local time is used
+	unreachable := redis.NewClient(&redis.Options{
+		Addr:        "10.255.255.1:6379",
+		DialTimeout: 5 * time.Second,
+	})
+	start := time.Now()
+	g = NewRedisson(unreachable, WithServerTime(time.Minute))
+	if d := time.Since(start); d > 100*time.Millisecond {
+		t.Fatalf("construction took %v", d)
+	}
+	if d := time.Until(g.clock.Now()); d < -time.Second || d > time.Second {
+		t.Fatalf("clock is %v away from the local time", d)
+	}
+}
@@ -0,0 +1,93 @@
+package redisson
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+)
+
+// Codec encodes values before they are written to redis and decodes them after they are read.
+type Codec interface {
+	Encode(v interface{}) ([]byte, error)
+	Decode(data []byte, v interface{}) error
+}
+
+// DefaultCodec is the codec used by a Redisson instance when none is configured.
+var DefaultCodec Codec = JSONCodec{}
+
+// JSONCodec encodes values with encoding/json.
+type JSONCodec struct{}
+
+// Encode encodes v as JSON.
+func (JSONCodec) Encode(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+// Decode decodes the JSON data into v.
+func (JSONCodec) Decode(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+// StringCodec encodes strings and byte slices as their bytes, like the StringCodec of Java Redisson.
+type StringCodec struct{}
+
+// Encode returns the bytes of v, which must be a string or a []byte.
+func (StringCodec) Encode(v interface{}) ([]byte, error) {
+	switch s := v.(type) {
+	case string:
+		return []byte(s), nil
+	case []byte:
+		return s, nil
+	}
+	return nil, fmt.Errorf("redisson: StringCodec cannot encode %T", v)
+}
+
+// Decode stores data into v, which must be a *string or a *[]byte.
+func (StringCodec) Decode(data []byte, v interface{}) error {
+	switch s := v.(type) {
+	case *string:
+		*s = string(data)
+		return nil
+	case *[]byte:
+		*s = append((*s)[:0], data...)
+		return nil
+	}
+	return fmt.Errorf("redisson: StringCodec cannot decode into %T", v)
+}
+
+// MarshalerCodec encodes the values implementing encoding.BinaryMarshaler or encoding.TextMarshaler with them,
+// and decodes into the values implementing encoding.BinaryUnmarshaler or encoding.TextUnmarshaler with them.
+// Other values are encoded and decoded with Codec, JSONCodec if nil.
+type MarshalerCodec struct {
+	Codec Codec
+}
+
+// Encode encodes v with its MarshalBinary or MarshalText method if it has one.
+func (c MarshalerCodec) Encode(v interface{}) ([]byte, error) {
+	switch m := v.(type) {
+	case encoding.BinaryMarshaler:
+		return m.MarshalBinary()
+	case encoding.TextMarshaler:
+		return m.MarshalText()
+	}
+	return c.codec().Encode(v)
+}
+
+// Decode decodes data into v with its UnmarshalBinary or UnmarshalText method if it has one.
+func (c MarshalerCodec) Decode(data []byte, v interface{}) error {
+	switch m := v.(type) {
+	case encoding.BinaryUnmarshaler:
+		return m.UnmarshalBinary(data)
+	case encoding.TextUnmarshaler:
+		return m.UnmarshalText(data)
+	}
+	return c.codec().Decode(data, v)
+}
+
+// codec returns the codec of the other values
+func (c MarshalerCodec) codec() Codec {
+	if c.Codec == nil {
+		return JSONCodec{}
+	}
+	return c.Codec
+}
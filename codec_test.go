@@ -0,0 +1,60 @@
+package redisson
+
+import (
+	"testing"
+	"time"
+)
+
+func TestJSONCodec(t *testing.T) {
+	data, err := JSONCodec{}.Encode(User{ID: 1, Name: "Alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var u User
+	if err = (JSONCodec{}).Decode(data, &u); err != nil {
+		t.Fatal(err)
+	}
+	if u.ID != 1 || u.Name != "Alice" {
+		t.Fatalf("u=%v", u)
+	}
+}
+
+func TestStringCodec(t *testing.T) {
+	data, err := StringCodec{}.Encode("hello")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var s string
+	if err = (StringCodec{}).Decode(data, &s); err != nil {
+		t.Fatal(err)
+	}
+	if s != "hello" {
+		t.Fatalf("s=%q", s)
+	}
+	if _, err = (StringCodec{}).Encode(1); err == nil {
+		t.Fatal("expected an error encoding an int")
+	}
+}
+
+func TestMarshalerCodec(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	data, err := MarshalerCodec{}.Encode(now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	binary, _ := now.MarshalBinary()
+	if string(data) != string(binary) {
+		t.Fatalf("expected the binary encoding of time.Time, got %q", data)
+	}
+	var decoded time.Time
+	if err = (MarshalerCodec{}).Decode(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !decoded.Equal(now) {
+		t.Fatalf("decoded=%v", decoded)
+	}
+	// values without marshalers use the codec
+	if data, err = (MarshalerCodec{Codec: StringCodec{}}).Encode("hello"); err != nil || string(data) != "hello" {
+		t.Fatalf("data=%q err=%v", data, err)
+	}
+}
@@ -3,6 +3,7 @@ package redisson
 import (
 	"bytes"
 	"fmt"
+	"hash/fnv"
 	"runtime"
 	"strconv"
 	"sync"
@@ -32,6 +33,17 @@ func getId() (uint64, error) {
 	return n, nil
 }
 
+// ownerIdBit marks the ids derived from owner tokens, goroutine ids never reach it
+const ownerIdBit = uint64(1) << 63
+
+// getOwnerId returns the id a lock is held with on behalf of the owner token instead of the current goroutine,
+// so that any goroutine of the instance using the same token holds the same ownership.
+func getOwnerId(owner string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(owner))
+	return h.Sum64() | ownerIdBit
+}
+
 // littleBuf is a pool of 64-byte buffers.
 var littleBuf = sync.Pool{
 	New: func() interface{} {
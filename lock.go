@@ -11,6 +11,9 @@ type innerLocker interface {
 	unlockInner(context.Context, uint64) (*int64, error)
 	getChannelName() string
 	renewExpirationInner(context.Context, uint64) (int64, error)
+	isLockedInner(context.Context) (bool, error)
+	holdCountInner(context.Context, uint64) (int64, error)
+	transferInner(context.Context, uint64, uint64) (int64, error)
 }
 
 // A Lock represents an object that can be locked and unlocked.
@@ -19,6 +22,64 @@ type Lock interface {
 	Lock() error
 	Unlock() error
 
+	// LockWithLease locks for leaseTime, after which the lock expires instead of being renewed by the watchdog.
+	LockWithLease(leaseTime time.Duration) error
+
+	// TryLock locks if the lock is free and reports whether it was locked, without waiting.
+	TryLock() (bool, error)
+
+	// TryLockOnce makes a single attempt to lock, such as one SETNX for a mutex, without subscribing to the channel
+	// of the lock, and reports whether it was locked. A done ctx fails before the attempt is sent.
+	TryLockOnce(ctx context.Context) (bool, error)
+
+	// TryLockWithTimeout waits up to waitTime for the lock and reports whether it was locked.
+	// A positive leaseTime makes the lock expire after leaseTime instead of being renewed by the watchdog.
+	TryLockWithTimeout(waitTime, leaseTime time.Duration) (bool, error)
+
 	LockContext(context.Context) error
+
+	// LockAsync locks for the calling goroutine in the background and sends the result on the returned channel,
+	// which receives exactly one value, so that the wait can be selected together with other events.
+	// A caller which stops waiting must cancel ctx, the lock being released if it is acquired after ctx is done.
+	// Otherwise the lock stays held by the calling goroutine, and renewed by the watchdog, until it is unlocked.
+	LockAsync(ctx context.Context) <-chan error
+
+	// UnlockAsync unlocks the lock held by the calling goroutine in the background and sends the result
+	// on the returned channel.
+	UnlockAsync(ctx context.Context) <-chan error
+
+	// TryLockContext waits for the lock until ctx is done and reports whether it was locked,
+	// a done ctx is reported as not locked instead of ErrObtainLockTimeout.
+	TryLockContext(ctx context.Context) (bool, error)
 	UnlockContext(context.Context) error
+
+	// LockContextWithOwner locks on behalf of the owner token, such as a request id, instead of the calling goroutine,
+	// so that the lock can be handed off to and released by another goroutine of the same instance.
+	LockContextWithOwner(ctx context.Context, owner string) error
+
+	// UnlockContextWithOwner unlocks the lock held on behalf of the owner token, from any goroutine.
+	UnlockContextWithOwner(ctx context.Context, owner string) error
+
+	// Detach hands the holds of the calling goroutine over to a new owner token and returns it,
+	// the lock stays held and renewed until the token is attached to a goroutine or used to unlock.
+	Detach() (string, error)
+
+	// Attach takes the holds of the owner token over to the calling goroutine.
+	Attach(token string) error
+
+	// IsLocked reports whether the lock is held by any goroutine of any instance.
+	IsLocked() (bool, error)
+
+	// IsHeldByCurrentGoroutine reports whether the lock is held by the calling goroutine.
+	IsHeldByCurrentGoroutine() (bool, error)
+
+	// GetHoldCount returns how many times the calling goroutine holds the lock, 0 if it does not.
+	GetHoldCount() (int64, error)
+
+	// LockStats returns the metrics of the locks of the same name created WithLockStats and acquired by this instance,
+	// zero if the lock was created without it.
+	LockStats() LockStats
+
+	// NewCondition returns the condition named name bound to the lock.
+	NewCondition(name string) RCondition
 }
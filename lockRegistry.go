@@ -0,0 +1,397 @@
+package redisson
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// heldLockKey identifies the holds of a lock by a goroutine or an owner token
+type heldLockKey struct {
+	//lock type and name of the lock, the read and the write lock of a name are held separately
+	lock        string
+	goroutineId uint64
+}
+
+// heldLock is a lock held by this instance
+type heldLock struct {
+	sync.Mutex
+	lock *RedissonBaseLock
+	//holds number of times the lock is held, reentrant acquisitions included
+	holds int64
+	//localHolds number of the holds entered locally, which redis does not count
+	localHolds int64
+	//since time the lock was first held, reentrant acquisitions keep it
+	since time.Time
+	//expires time the lease of the lock expires, zero if the watchdog renews it
+	expires time.Time
+	//stopExpiry stops the removal of the holds when the lease expires, nil if the watchdog renews the lock
+	stopExpiry func() bool
+	//removed whether the holds were removed from the registry, new holds are registered in a new heldLock
+	removed bool
+}
+
+// localReentrant is implemented by the lockers which a goroutine holding them renewed by the watchdog
+// enters again without a round trip, see reenterLocally
+type localReentrant interface {
+	reentersLocally()
+}
+
+// getHeldLockKey returns the key of the holds of m by goroutineId in the registry of the instance
+func (m *RedissonBaseLock) getHeldLockKey(goroutineId uint64) heldLockKey {
+	return heldLockKey{lock: fmt.Sprintf("%T:%s", m.lock, m.getRawName()), goroutineId: goroutineId}
+}
+
+// lockHolds returns the holds of key in the registry of the instance, created for m if there are none, locked
+func (m *RedissonBaseLock) lockHolds(key heldLockKey) *heldLock {
+	for {
+		held, _ := m.heldLocks.LoadOrStore(key, &heldLock{lock: m})
+		h := held.(*heldLock)
+		h.Lock()
+		// the holds were removed while they were loaded, retry with new ones
+		if !h.removed {
+			return h
+		}
+		h.Unlock()
+	}
+}
+
+// removeHolds removes h, the locked holds of key, from the registry of the instance
+func (g *Redisson) removeHolds(key heldLockKey, h *heldLock) {
+	h.removed = true
+	if h.stopExpiry != nil {
+		h.stopExpiry()
+		h.stopExpiry = nil
+	}
+	g.heldLocks.CompareAndDelete(key, h)
+}
+
+// expireHolds makes the locked holds h of key expire with the lease of the lock at expires, or never if it is zero
+func (m *RedissonBaseLock) expireHolds(key heldLockKey, h *heldLock, expires time.Time) {
+	if h.stopExpiry != nil {
+		h.stopExpiry()
+		h.stopExpiry = nil
+	}
+	h.expires = expires
+	if expires.IsZero() {
+		return
+	}
+	h.stopExpiry = clockAfterFunc(m.clock, expires.Sub(m.clock.Now()), func() {
+		h.Lock()
+		defer h.Unlock()
+		// the lock was acquired again since
+		if !h.expires.Equal(expires) {
+			return
+		}
+		h.stopExpiry = nil
+		m.removeHolds(key, h)
+	})
+}
+
+// registerHold records in the registry of the instance that goroutineId acquired m holds times, for lease if positive,
+// after which the holds are removed unless m is acquired again, else while the watchdog renews m
+func (m *RedissonBaseLock) registerHold(goroutineId uint64, holds int64, lease time.Duration) {
+	key := m.getHeldLockKey(goroutineId)
+	h := m.lockHolds(key)
+	defer h.Unlock()
+	if h.holds == 0 {
+		h.since = m.clock.Now()
+	}
+	h.holds += holds
+	var expires time.Time
+	if lease > 0 {
+		expires = m.clock.Now().Add(lease)
+	}
+	m.expireHolds(key, h, expires)
+}
+
+// unregisterHold records in the registry of the instance that goroutineId released m once,
+// and returns how long m was held when it was its last hold, else 0
+func (m *RedissonBaseLock) unregisterHold(goroutineId uint64) time.Duration {
+	key := m.getHeldLockKey(goroutineId)
+	held, ok := m.heldLocks.Load(key)
+	if !ok {
+		return 0
+	}
+	h := held.(*heldLock)
+	h.Lock()
+	defer h.Unlock()
+	if h.holds--; h.holds <= 0 {
+		m.removeHolds(key, h)
+		return m.clock.Now().Sub(h.since)
+	}
+	return 0
+}
+
+// forgetHolds removes the holds of m by goroutineId from the registry of the instance, once m is no longer held
+func (m *RedissonBaseLock) forgetHolds(goroutineId uint64) {
+	key := m.getHeldLockKey(goroutineId)
+	held, ok := m.heldLocks.Load(key)
+	if !ok {
+		return
+	}
+	h := held.(*heldLock)
+	h.Lock()
+	defer h.Unlock()
+	m.removeHolds(key, h)
+}
+
+// reenterLocally enters m again for goroutineId without a round trip, if m is a localReentrant held by goroutineId
+// and renewed by the watchdog, and reports whether it did. The hold in redis stands for all the local ones while
+// the watchdog renews it.
+func (m *RedissonBaseLock) reenterLocally(goroutineId uint64) bool {
+	if _, ok := m.lock.(localReentrant); !ok {
+		return false
+	}
+	ee, ok := m.ExpirationRenewalMap.Load(m.getEntryName())
+	if !ok || ee.(*expirationEntry).count(goroutineId) == 0 {
+		return false
+	}
+	held, ok := m.heldLocks.Load(m.getHeldLockKey(goroutineId))
+	if !ok {
+		return false
+	}
+	h := held.(*heldLock)
+	h.Lock()
+	defer h.Unlock()
+	if h.holds == 0 {
+		return false
+	}
+	h.holds++
+	h.localHolds++
+	m.scheduleExpirationRenewal(goroutineId)
+	return true
+}
+
+// leaveLocally records that goroutineId released a hold of m entered locally, if it has one,
+// and reports whether it did
+func (m *RedissonBaseLock) leaveLocally(goroutineId uint64) bool {
+	held, ok := m.heldLocks.Load(m.getHeldLockKey(goroutineId))
+	if !ok {
+		return false
+	}
+	h := held.(*heldLock)
+	h.Lock()
+	defer h.Unlock()
+	if h.localHolds == 0 {
+		return false
+	}
+	h.holds--
+	h.localHolds--
+	return true
+}
+
+// localHolds returns the number of holds of m entered locally by goroutineId
+func (m *RedissonBaseLock) localHolds(goroutineId uint64) int64 {
+	held, ok := m.heldLocks.Load(m.getHeldLockKey(goroutineId))
+	if !ok {
+		return 0
+	}
+	h := held.(*heldLock)
+	h.Lock()
+	defer h.Unlock()
+	return h.localHolds
+}
+
+// moveHolds moves the holds of m by from in the registry of the instance to to, and reports whether from had holds
+func (m *RedissonBaseLock) moveHolds(from, to uint64) bool {
+	fromKey := m.getHeldLockKey(from)
+	held, ok := m.heldLocks.Load(fromKey)
+	if !ok {
+		return false
+	}
+	h := held.(*heldLock)
+	h.Lock()
+	holds, localHolds, since, expires := h.holds, h.localHolds, h.since, h.expires
+	m.removeHolds(fromKey, h)
+	h.Unlock()
+	toKey := m.getHeldLockKey(to)
+	h = m.lockHolds(toKey)
+	defer h.Unlock()
+	if h.holds == 0 {
+		h.since = since
+	}
+	h.holds += holds
+	h.localHolds += localHolds
+	m.expireHolds(toKey, h, expires)
+	return true
+}
+
+// ReleaseAll releases all the locks held by the instance, as many times as they are held, so that a stopping
+// process does not leave them to expire with the watchdog lease. A lock which cannot be released is no longer
+// renewed and expires. It returns the errors of the locks which could not be released. The locks whose lease expired,
+// which the watchdog failed to renew or which an unlock found no longer held are not released.
+func (g *Redisson) ReleaseAll(ctx context.Context) error {
+	var errs []error
+	g.heldLocks.Range(func(k, v any) bool {
+		key := k.(heldLockKey)
+		h := v.(*heldLock)
+		h.Lock()
+		holds := h.holds
+		h.Unlock()
+		for ; holds > 0; holds-- {
+			unlockCtx, cancel := g.withCommandTimeout(ctx)
+			status, err := h.lock.lock.unlockInner(unlockCtx, key.goroutineId)
+			cancel()
+			if err != nil {
+				errs = append(errs, fmt.Errorf("releasing lock %s: %w", h.lock.getRawName(), err))
+				h.lock.cancelExpirationRenewal(0)
+				break
+			}
+			// the lock was lost, it is no longer held
+			if status == nil {
+				break
+			}
+		}
+		h.Lock()
+		g.removeHolds(key, h)
+		h.Unlock()
+		return true
+	})
+	return errors.Join(errs...)
+}
+
+// Shutdown releases all the locks held by the instance, see ReleaseAll, before the process stops.
+func (g *Redisson) Shutdown(ctx context.Context) error {
+	return g.ReleaseAll(ctx)
+}
+
+// lockWaiters counts the goroutines of the instance waiting for a lock, it is removed when the last one stops
+type lockWaiters struct {
+	sync.Mutex
+	n       int64
+	removed bool
+}
+
+// addWaiter records that a goroutine of the instance starts or, with delta -1, stops waiting for the lock named name
+func (g *Redisson) addWaiter(name string, delta int64) {
+	for {
+		v, _ := g.lockWaiters.LoadOrStore(name, &lockWaiters{})
+		waiters := v.(*lockWaiters)
+		waiters.Lock()
+		// the count was removed while it was loaded, retry with a new one
+		if waiters.removed {
+			waiters.Unlock()
+			continue
+		}
+		if waiters.n += delta; waiters.n <= 0 {
+			waiters.removed = true
+			g.lockWaiters.CompareAndDelete(name, waiters)
+		}
+		waiters.Unlock()
+		return
+	}
+}
+
+// LockInfo is the state of a lock reported by DumpLocks.
+type LockInfo struct {
+	// Name is the name of the lock.
+	Name string
+	// Mode is "read" or "write" for a read write lock, empty for the other locks.
+	Mode string
+	// Holders are the hold counts of the lock by the name it is held with, of any instance.
+	// The name is the instance id and the goroutine id, followed by ":write" for the writer of a read write lock.
+	Holders map[string]int64
+	// TTL is the remaining time to live of the lock, 0 if it is free and negative if it does not expire.
+	TTL time.Duration
+	// LocalHolds is the number of holds of the lock by this instance.
+	LocalHolds int64
+	// LocalWaiters is the number of goroutines of this instance waiting for the lock.
+	LocalWaiters int64
+}
+
+// DumpLocks reports the state of the locks this instance knows about, those it holds or waits for, sorted by name,
+// to diagnose deadlocks and contention.
+func (g *Redisson) DumpLocks(ctx context.Context) ([]LockInfo, error) {
+	infos := make(map[string]*LockInfo)
+	getInfo := func(name string) *LockInfo {
+		info, ok := infos[name]
+		if !ok {
+			info = &LockInfo{Name: name, Holders: make(map[string]int64)}
+			infos[name] = info
+		}
+		return info
+	}
+	// the holds entered locally are only counted by this instance
+	localHolds := make(map[string]map[string]int64)
+	g.heldLocks.Range(func(k, v any) bool {
+		h := v.(*heldLock)
+		h.Lock()
+		getInfo(h.lock.getRawName()).LocalHolds += h.holds
+		if h.localHolds > 0 {
+			if localHolds[h.lock.getRawName()] == nil {
+				localHolds[h.lock.getRawName()] = make(map[string]int64)
+			}
+			localHolds[h.lock.getRawName()][h.lock.getLockName(k.(heldLockKey).goroutineId)] += h.localHolds
+		}
+		h.Unlock()
+		return true
+	})
+	g.lockWaiters.Range(func(k, v any) bool {
+		waiters := v.(*lockWaiters)
+		waiters.Lock()
+		if waiters.n > 0 {
+			getInfo(k.(string)).LocalWaiters = waiters.n
+		}
+		waiters.Unlock()
+		return true
+	})
+
+	names := make([]string, 0, len(infos))
+	for name := range infos {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	ctx, cancel := g.withCommandTimeout(ctx)
+	defer cancel()
+	pipe := g.client.Pipeline()
+	types := make([]*redis.StatusCmd, len(names))
+	ttls := make([]*redis.DurationCmd, len(names))
+	for i, name := range names {
+		types[i] = pipe.Type(ctx, name)
+		ttls[i] = pipe.PTTL(ctx, name)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, err
+	}
+
+	result := make([]LockInfo, 0, len(names))
+	for i, name := range names {
+		info := infos[name]
+		if ttl := ttls[i].Val(); ttl > 0 || ttl == -1 {
+			info.TTL = ttl
+		}
+		switch types[i].Val() {
+		case "hash":
+			fields, err := g.client.HGetAll(ctx, name).Result()
+			if err != nil {
+				return nil, err
+			}
+			for field, value := range fields {
+				if field == "mode" {
+					info.Mode = value
+					continue
+				}
+				if count, err := strconv.ParseInt(value, 10, 64); err == nil {
+					info.Holders[field] = count + localHolds[name][field]
+				}
+			}
+		case "string":
+			holder, err := g.client.Get(ctx, name).Result()
+			if err != nil && err != redis.Nil {
+				return nil, err
+			}
+			if err == nil {
+				info.Holders[holder] = 1
+			}
+		}
+		result = append(result, *info)
+	}
+	return result, nil
+}
@@ -0,0 +1,94 @@
+package redisson
+
+import (
+	"sync"
+	"time"
+)
+
+// LockStats are the metrics of the locks of one name acquired by a Redisson instance.
+type LockStats struct {
+	// Acquisitions is the number of times the lock was acquired, reentrant acquisitions included.
+	Acquisitions int64
+	// Contended is the number of acquisitions which had to wait for the lock.
+	Contended int64
+	// Retries is the number of failed attempts of the acquisitions.
+	Retries int64
+	// TotalAcquireTime and MaxAcquireTime are the total and the longest time to acquire the lock.
+	TotalAcquireTime time.Duration
+	MaxAcquireTime   time.Duration
+	// Releases is the number of times the lock was released by its last hold.
+	Releases int64
+	// TotalHoldTime and MaxHoldTime are the total and the longest time the lock was held.
+	TotalHoldTime time.Duration
+	MaxHoldTime   time.Duration
+	// Renewals and RenewalFailures count the renewals of the lock by the watchdog.
+	Renewals        int64
+	RenewalFailures int64
+}
+
+// LockObserver is notified of the activity of the locks of a Redisson instance, to feed an external metrics system.
+// Its methods are called synchronously and must not block.
+type LockObserver interface {
+	// OnAcquire is called when the lock named name is acquired after waiting wait and failing retries attempts.
+	OnAcquire(name string, wait time.Duration, retries int)
+	// OnRelease is called when the lock named name is released by its last hold after being held for hold.
+	OnRelease(name string, hold time.Duration)
+	// OnRenew is called when the watchdog renewed the lock named name, with the error if the renewal failed.
+	OnRenew(name string, err error)
+}
+
+// lockStats records the LockStats of the locks of one name
+type lockStats struct {
+	sync.Mutex
+	stats LockStats
+}
+
+// getLockStats returns the lockStats of the locks named name, creating them on first use
+func (g *Redisson) getLockStats(name string) *lockStats {
+	stats, _ := g.lockStats.LoadOrStore(name, &lockStats{})
+	return stats.(*lockStats)
+}
+
+// acquired records that the lock was acquired after waiting wait and failing retries attempts
+func (s *lockStats) acquired(wait time.Duration, retries int) {
+	s.Lock()
+	defer s.Unlock()
+	s.stats.Acquisitions++
+	if retries > 0 {
+		s.stats.Contended++
+		s.stats.Retries += int64(retries)
+	}
+	s.stats.TotalAcquireTime += wait
+	if wait > s.stats.MaxAcquireTime {
+		s.stats.MaxAcquireTime = wait
+	}
+}
+
+// released records that the lock was released by its last hold after being held for hold
+func (s *lockStats) released(hold time.Duration) {
+	s.Lock()
+	defer s.Unlock()
+	s.stats.Releases++
+	s.stats.TotalHoldTime += hold
+	if hold > s.stats.MaxHoldTime {
+		s.stats.MaxHoldTime = hold
+	}
+}
+
+// renewed records a renewal of the lock by the watchdog
+func (s *lockStats) renewed(err error) {
+	s.Lock()
+	defer s.Unlock()
+	if err != nil {
+		s.stats.RenewalFailures++
+		return
+	}
+	s.stats.Renewals++
+}
+
+// snapshot returns a copy of the LockStats
+func (s *lockStats) snapshot() LockStats {
+	s.Lock()
+	defer s.Unlock()
+	return s.stats
+}
@@ -0,0 +1,400 @@
+package redisson
+
+import (
+	"context"
+)
+
+// RateLimiterAlgorithm 限流算法，通过 WithRateLimiterAlgorithm 在创建限流器时选择。
+// 同一名字的限流器在所有实例上必须使用相同的算法，各算法在 Redis 中的存储结构不同。
+type RateLimiterAlgorithm int
+
+const (
+	// RateLimiterTokenBucket 默认算法，与 Java Redisson 的 RRateLimiter 相同
+	RateLimiterTokenBucket RateLimiterAlgorithm = iota
+	// RateLimiterSlidingWindow 滑动窗口日志：记录窗口内每次获取的时间戳，任意 interval 长度的时间段内
+	// 获取的许可都不超过 rate，不会在窗口边界出现 2 倍的突发。支持 SetRateTiers 设置的多层级限流
+	RateLimiterSlidingWindow
+	// RateLimiterFixedWindow 固定窗口计数：value hash 中每个 interval 窗口一个 HINCRBY 计数，已结束的窗口随后清理。
+	// 每次获取只有一次计数，适合很高的请求速率，代价是相邻窗口的边界处最多允许 2 倍的突发
+	RateLimiterFixedWindow
+	// RateLimiterLeakyBucket 漏桶：桶内的许可以每 interval 流出 rate 个的恒定速度流出，获取的许可进入桶中，
+	// 桶满（rate 个）时需要等待。许可逐个而不是按 interval 成批恢复，等待中的 Acquire 被均匀放行。
+	// 等价于容量为 rate、按 rate/interval 连续补充令牌的令牌桶，不足一个的许可也按经过的时间累计
+	RateLimiterLeakyBucket
+	// RateLimiterCell 在加载了 redis-cell 模块时使用 CL.THROTTLE 的 GCRA 限流，interval 需为整秒，等待时间精确到秒，
+	// 不支持 TryAcquirePermitsWithBurst 和预热；未加载模块时退回到同为 GCRA 模型的 RateLimiterLeakyBucket
+	RateLimiterCell
+)
+
+// String 返回算法名，用于脚本名
+func (a RateLimiterAlgorithm) String() string {
+	switch a {
+	case RateLimiterSlidingWindow:
+		return "slidingWindow"
+	case RateLimiterFixedWindow:
+		return "fixedWindow"
+	case RateLimiterLeakyBucket:
+		return "leakyBucket"
+	case RateLimiterCell:
+		return "cell"
+	default:
+		return "tokenBucket"
+	}
+}
+
+// rateLimiterScripts 一种限流算法的脚本，KEYS 和 ARGV 与 tryAcquireScript、availablePermitsScript 相同，
+// 另有 KEYS[6]、KEYS[7] 为全局和本客户端的预热键，ARGV 的预热时长（毫秒）见 WithWarmUp，
+// tryAcquire 的 ARGV[5] 为 1 时在许可不足时预约许可，见 Reserve，ARGV[6] 为本次允许超出速率的许可数，
+// 最后一个 ARGV 为每次使用时续期配置的过期时间（毫秒），见 rateLimiterKeepAliveScript
+type rateLimiterScripts struct {
+	// name 脚本名前缀
+	name             string
+	tryAcquire       string
+	availablePermits string
+	// reserves tryAcquire 是否支持预约
+	reserves bool
+}
+
+// rateLimiterAlgorithms 各限流算法的脚本
+var rateLimiterAlgorithms = map[RateLimiterAlgorithm]rateLimiterScripts{
+	RateLimiterTokenBucket: {
+		name:             "rateLimiter",
+		tryAcquire:       tryAcquireScript,
+		availablePermits: availablePermitsScript,
+		reserves:         true,
+	},
+	RateLimiterSlidingWindow: {
+		name:             "rateLimiter.slidingWindow",
+		tryAcquire:       slidingWindowTryAcquireScript,
+		availablePermits: slidingWindowAvailablePermitsScript,
+		reserves:         true,
+	},
+	RateLimiterFixedWindow: {
+		name:             "rateLimiter.fixedWindow",
+		tryAcquire:       fixedWindowTryAcquireScript,
+		availablePermits: fixedWindowAvailablePermitsScript,
+		reserves:         true,
+	},
+	RateLimiterLeakyBucket: {
+		name:             "rateLimiter.leakyBucket",
+		tryAcquire:       leakyBucketTryAcquireScript,
+		availablePermits: leakyBucketAvailablePermitsScript,
+		reserves:         true,
+	},
+	RateLimiterCell: {
+		name:             "rateLimiter.cell",
+		tryAcquire:       cellTryAcquireScript,
+		availablePermits: cellAvailablePermitsScript,
+	},
+}
+
+// getScripts 返回限流器使用的脚本，RateLimiterCell 在 redis-cell 模块不可用时使用 RateLimiterLeakyBucket 的脚本
+func (rl *RedissonRateLimiter) getScripts(ctx context.Context) (rateLimiterScripts, error) {
+	if rl.algorithm != RateLimiterCell {
+		return rateLimiterAlgorithms[rl.algorithm], nil
+	}
+	ok, err := rl.hasCommand(ctx, "CL.THROTTLE")
+	if err != nil {
+		return rateLimiterScripts{}, err
+	}
+	if !ok {
+		return rateLimiterAlgorithms[RateLimiterLeakyBucket], nil
+	}
+	return rateLimiterAlgorithms[RateLimiterCell], nil
+}
+
+// rateLimiterTryAcquireArgsScript 获取许可脚本的参数
+const rateLimiterTryAcquireArgsScript = `
+local permits = tonumber(ARGV[1]);
+local now = tonumber(ARGV[2]);
+local warmUp = tonumber(ARGV[4]);
+local reserve = ARGV[5] == '1';
+local burst = tonumber(ARGV[6]);
+local keepAlive = tonumber(ARGV[7]);
+`
+
+// rateLimiterAvailablePermitsArgsScript 查询余量脚本的参数
+const rateLimiterAvailablePermitsArgsScript = `
+local permits = 0;
+local burst = 0;
+local now = tonumber(ARGV[1]);
+local warmUp = tonumber(ARGV[2]);
+local keepAlive = tonumber(ARGV[3]);
+`
+
+// rateLimiterKeepAliveScript 每次使用时将配置的过期时间续为 keepAlive（WithTTL），需先定义 keepAlive，
+// 限流器只在闲置 keepAlive 后过期，value 与 permits 通过 rateLimiterFollowTTLScript 跟随配置
+const rateLimiterKeepAliveScript = `
+if keepAlive > 0 then
+    redis.call('pexpire', KEYS[1], keepAlive);
+end;
+`
+
+// rateLimiterConfigScript 读取配置，按 RateType 选择 value 与 permits 键，需先定义参数 permits、burst、now、warmUp 与 keepAlive。
+// configuredRate 为配置的速率，预热期间 rate 为当前的有效速率：冷启动时为配置的 1/3，在 warmUp 内线性升到配置的速率，
+// 单次请求的许可数不受预热限制。预热键记录预热开始时间，闲置超过 warmUp 后过期，限流器重新冷启动
+const rateLimiterConfigScript = `
+local rate = redis.call('hget', KEYS[1], 'rate');
+local interval = redis.call('hget', KEYS[1], 'interval');
+local type = redis.call('hget', KEYS[1], 'type');
+assert(rate ~= false and interval ~= false and type ~= false, 'RateLimiter is not initialized');
+` + rateLimiterKeepAliveScript + `
+rate = tonumber(rate);
+interval = tonumber(interval);
+local configuredRate = rate;
+
+local valueName = KEYS[2];
+local permitsName = KEYS[4];
+local warmUpName = KEYS[6];
+if type == '1' then
+    valueName = KEYS[3];
+    permitsName = KEYS[5];
+    warmUpName = KEYS[7];
+end;
+assert(rate + burst >= permits, 'Requested permits amount could not exceed defined rate');
+
+local warmUpStart = now;
+if warmUp > 0 then
+    local start = redis.call('get', warmUpName);
+    if start ~= false then
+        warmUpStart = tonumber(start);
+    end;
+    local progress = math.min(1, math.max(0, now - warmUpStart) / warmUp);
+    rate = math.max(permits - burst, 1, math.floor(rate * (1 + 2 * progress) / 3));
+end;
+`
+
+// rateLimiterWarmUpTouchScript 获取许可后记录预热开始时间，并推迟预热键的过期
+const rateLimiterWarmUpTouchScript = `
+if warmUp > 0 then
+    redis.call('set', warmUpName, string.format('%d', warmUpStart), 'px', warmUp);
+end;
+`
+
+// slidingWindowPruneScript 滑动窗口的公共部分：读取配置和 SetRateTiers 设置的其他层级，移除移出最长窗口的记录
+// 并扣减已用许可数，需先定义参数。许可记录为 permits zset，成员为 "随机串:许可数"，分数为获取时间；
+// value 为最长窗口内已用许可数。预热只作用于第一个层级
+const slidingWindowPruneScript = rateLimiterConfigScript + `
+local tiers = {{rate, interval}};
+local keep = interval;
+local tierSpec = redis.call('hget', KEYS[1], 'tiers');
+if tierSpec ~= false then
+    for tierRate, tierInterval in string.gmatch(tierSpec, '(%d+):(%d+)') do
+        table.insert(tiers, {tonumber(tierRate), tonumber(tierInterval)});
+        keep = math.max(keep, tonumber(tierInterval));
+    end;
+end;
+
+local used = tonumber(redis.call('get', valueName) or '0');
+local expired = redis.call('zrangebyscore', permitsName, '-inf', now - keep);
+if #expired > 0 then
+    for i, v in ipairs(expired) do
+        used = used - tonumber(string.match(v, ':(%d+)$'));
+    end;
+    redis.call('zremrangebyscore', permitsName, '-inf', now - keep);
+end;
+
+-- windowEntries 返回最近 window 毫秒内（含预约）的记录和分数
+local function windowEntries(window)
+    return redis.call('zrangebyscore', permitsName, string.format('(%d', now - window), '+inf', 'withscores');
+end;
+
+-- windowUsed 返回最近 window 毫秒内（含预约）已用的许可数
+local function windowUsed(window)
+    if window == keep then
+        return used;
+    end;
+    local sum = 0;
+    local entries = windowEntries(window);
+    for i = 1, #entries, 2 do
+        sum = sum + tonumber(string.match(entries[i], ':(%d+)$'));
+    end;
+    return sum;
+end;
+`
+
+// slidingWindowTryAcquireScript 每个层级窗口内已用许可加上本次请求都不超过其速率时记录本次获取，
+// 否则返回各层级中最长的、最早能腾出足够许可的等待毫秒数
+const slidingWindowTryAcquireScript = rateLimiterTryAcquireArgsScript + slidingWindowPruneScript + `
+local res = nil;
+for _, tier in ipairs(tiers) do
+    assert(tier[1] + burst >= permits, 'Requested permits amount could not exceed defined rate');
+    local need = windowUsed(tier[2]) + permits - tier[1] - burst;
+    if need > 0 then
+        local entries = windowEntries(tier[2]);
+        for i = 1, #entries, 2 do
+            need = need - tonumber(string.match(entries[i], ':(%d+)$'));
+            if need <= 0 then
+                res = math.max(res or 0, tonumber(entries[i + 1]) + tier[2] - now);
+                break;
+            end;
+        end;
+    end;
+end;
+
+-- 预约的许可记在各层级都腾出足够许可的时间，在此之前一直计入窗口
+if res == nil or reserve then
+    redis.call('zadd', permitsName, now + (res or 0), ARGV[3] .. ':' .. permits);
+    used = used + permits;
+` + rateLimiterWarmUpTouchScript + `
+end;
+redis.call('set', valueName, used);
+` + rateLimiterFollowTTLScript + slidingWindowIdleScript + `
+return res;
+`
+
+// slidingWindowAvailablePermitsScript 返回各层级窗口内剩余许可数的最小值
+const slidingWindowAvailablePermitsScript = rateLimiterAvailablePermitsArgsScript + slidingWindowPruneScript + `
+redis.call('set', valueName, used);
+` + rateLimiterFollowTTLScript + slidingWindowIdleScript + `
+local available = rate;
+for _, tier in ipairs(tiers) do
+    available = math.min(available, tier[1] - windowUsed(tier[2]));
+end;
+return math.max(0, available);
+`
+
+// rateLimiterClientIdleScript RateTypePER_CLIENT 模式下，客户端的键在闲置 idle 毫秒、状态与新建时无异后过期，
+// 退出的客户端不会留下键，需先定义 idle。配置的过期时间更早时保留跟随配置的过期时间
+const rateLimiterClientIdleScript = `
+if type == '1' and idle > 0 then
+    local configTTL = redis.call('pttl', KEYS[1]);
+    if configTTL <= 0 or configTTL > idle then
+        redis.call('pexpire', valueName, idle);
+        redis.call('pexpire', permitsName, idle);
+    end;
+end;
+`
+
+// slidingWindowIdleScript 滑动窗口的记录（含预约）全部移出最长窗口后状态与新建时无异
+const slidingWindowIdleScript = `
+local idle = keep;
+local last = redis.call('zrange', permitsName, -1, -1, 'withscores');
+if #last > 0 then
+    idle = keep + math.max(0, tonumber(last[2]) - now);
+end;
+` + rateLimiterClientIdleScript
+
+// rateLimiterFollowTTLScript 让 value 与 permits 跟随配置的过期时间
+const rateLimiterFollowTTLScript = `
+local ttl = redis.call('pttl', KEYS[1]);
+if ttl > 0 then
+    redis.call('pexpire', valueName, ttl);
+    redis.call('pexpire', permitsName, ttl);
+end;
+`
+
+// fixedWindowCounterScript 固定窗口的公共部分：找到当前窗口的计数，需先定义参数。
+// value 为 hash，字段为窗口序号，值为该窗口已获取的许可数，已结束窗口的字段在这里清理，
+// 整个 hash 在最后一个有计数的窗口结束时过期
+const fixedWindowCounterScript = rateLimiterConfigScript + `
+local window = math.floor(now / interval);
+local windowEnd = (window + 1) * interval;
+local windowField = string.format('%d', window);
+if redis.call('hlen', valueName) > 1 then
+    for _, field in ipairs(redis.call('hkeys', valueName)) do
+        if tonumber(field) < window then
+            redis.call('hdel', valueName, field);
+        end;
+    end;
+end;
+local used = tonumber(redis.call('hget', valueName, windowField) or '0');
+`
+
+// fixedWindowCountScript 把 permits 计入 countWindow 窗口，并把 hash 的过期时间延长到该窗口结束
+const fixedWindowCountScript = `
+redis.call('hincrby', valueName, string.format('%d', countWindow), permits);
+local countTTL = (countWindow + 1) * interval - now;
+if redis.call('pttl', valueName) < countTTL then
+    redis.call('pexpire', valueName, countTTL);
+end;
+`
+
+// fixedWindowTryAcquireScript 当前窗口的计数加上本次请求不超过 rate 时计数，否则返回到窗口结束的等待毫秒数
+const fixedWindowTryAcquireScript = rateLimiterTryAcquireArgsScript + fixedWindowCounterScript + `
+if used + permits > rate + burst then
+    if not reserve then
+        return windowEnd - now;
+    end;
+    -- 预约之后第一个还有余量的窗口
+    local countWindow = window + 1;
+    while true do
+        if tonumber(redis.call('hget', valueName, string.format('%d', countWindow)) or '0') + permits <= rate + burst then
+` + fixedWindowCountScript + rateLimiterWarmUpTouchScript + `
+            return countWindow * interval - now;
+        end;
+        countWindow = countWindow + 1;
+    end;
+end;
+local countWindow = window;
+` + fixedWindowCountScript + rateLimiterWarmUpTouchScript + `
+return nil;
+`
+
+// fixedWindowAvailablePermitsScript 返回当前窗口剩余的许可数
+const fixedWindowAvailablePermitsScript = rateLimiterAvailablePermitsArgsScript + fixedWindowCounterScript + `
+return math.max(0, rate - used);
+`
+
+// leakyBucketDrainScript 漏桶的公共部分：按上次更新后经过的时间计算流出后的水位，需先定义参数。
+// value 为 hash，level 为桶内的许可数（可为小数），last 为上次更新的时间
+const leakyBucketDrainScript = rateLimiterConfigScript + `
+local level = 0;
+local state = redis.call('hmget', valueName, 'level', 'last');
+if state[1] ~= false then
+    level = tonumber(state[1]) - math.max(0, now - tonumber(state[2])) * rate / interval;
+    if level < 0 then
+        level = 0;
+    end;
+end;
+`
+
+// leakyBucketTryAcquireScript 水位加上本次请求不超过 rate 时放入桶中，否则返回流出足够许可的等待毫秒数
+const leakyBucketTryAcquireScript = rateLimiterTryAcquireArgsScript + leakyBucketDrainScript + `
+local res = nil;
+if level + permits > rate + burst then
+    res = math.max(1, math.ceil((level + permits - rate - burst) * interval / rate));
+    if not reserve then
+        return res;
+    end;
+end;
+-- 预约时水位可以超过容量，流出到容量以内前其他请求都需要等待
+redis.call('hset', valueName, 'level', tostring(level + permits), 'last', ARGV[2]);
+-- 桶内的许可全部流出后状态与新建时无异
+local idle = math.ceil((level + permits) * interval / rate) + 1;
+` + rateLimiterFollowTTLScript + rateLimiterClientIdleScript + rateLimiterWarmUpTouchScript + `
+return res;
+`
+
+// leakyBucketAvailablePermitsScript 返回桶内剩余的空间
+const leakyBucketAvailablePermitsScript = rateLimiterAvailablePermitsArgsScript + leakyBucketDrainScript + rateLimiterFollowTTLScript + `
+return math.max(0, math.floor(rate - level));
+`
+
+// cellThrottleScript 调用 CL.THROTTLE，需先定义参数。桶的容量为配置的速率，max_burst 在首次使用时
+// 固定在配置的 maxBurst 字段中，所有调用方使用相同的限制，SetRate 与 UpdateRate 时清除；不支持单次的 burst 和预热。
+// 返回 [是否受限, 容量, 剩余许可, 重试等待秒数, 重置秒数]
+const cellThrottleScript = rateLimiterConfigScript + `
+assert(burst == 0, 'CL.THROTTLE does not support a burst');
+local period = interval / 1000;
+assert(period >= 1 and period == math.floor(period), 'CL.THROTTLE needs an interval of whole seconds');
+local maxBurst = redis.call('hget', KEYS[1], 'maxBurst');
+if maxBurst == false then
+    maxBurst = configuredRate - 1;
+    redis.call('hset', KEYS[1], 'maxBurst', maxBurst);
+end;
+local throttle = redis.call('CL.THROTTLE', valueName, tonumber(maxBurst), configuredRate, period, permits);
+`
+
+// cellTryAcquireScript 获取许可，受限时返回重试等待的毫秒数
+const cellTryAcquireScript = rateLimiterTryAcquireArgsScript + cellThrottleScript + `
+if throttle[1] == 1 then
+    return math.max(1, throttle[4]) * 1000;
+end;
+return nil;
+`
+
+// cellAvailablePermitsScript 以 0 个许可调用 CL.THROTTLE，返回剩余许可数
+const cellAvailablePermitsScript = rateLimiterAvailablePermitsArgsScript + cellThrottleScript + `
+return throttle[3];
+`
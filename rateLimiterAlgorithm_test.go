@@ -0,0 +1,642 @@
+package redisson
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// newAlgorithmLimiter returns a rate limiter of algorithm with a manual clock, allowing rate permits per interval
+func newAlgorithmLimiter(t *testing.T, name string, algorithm RateLimiterAlgorithm, rate int64, interval time.Duration, opts ...ObjectOption) (RRateLimiter, *ManualClock) {
+	redisDB := redis.NewClient(&redis.Options{
+		Addr: redisAddr,
+	})
+	clock := NewManualClock(time.Now())
+	g := NewRedisson(redisDB, WithClock(clock))
+	rl := g.GetRateLimiter(name, append(opts, WithRateLimiterAlgorithm(algorithm))...)
+	if _, err := rl.Delete(); err != nil {
+		t.Fatal(err)
+	}
+	if err := rl.SetRateContext(context.Background(), RateTypeOVERALL, rate, interval.Milliseconds(), Milliseconds); err != nil {
+		t.Fatal(err)
+	}
+	return rl, clock
+}
+
+// assertAvailable checks the available permits of rl
+func assertAvailable(t *testing.T, rl RRateLimiter, want int64) {
+	t.Helper()
+	available, err := rl.AvailablePermitsContext(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if available != want {
+		t.Fatalf("available=%d, want %d", available, want)
+	}
+}
+
+// assertAcquire checks whether permits can be acquired from rl
+func assertAcquire(t *testing.T, rl RRateLimiter, permits int64, want bool) {
+	t.Helper()
+	ok, err := rl.TryAcquirePermitsContext(context.Background(), permits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok != want {
+		t.Fatalf("acquiring %d permits: %v, want %v", permits, ok, want)
+	}
+}
+
+func TestRateLimiterSlidingWindow(t *testing.T) {
+	rl, clock := newAlgorithmLimiter(t, "testRateLimiterSlidingWindow", RateLimiterSlidingWindow, 10, time.Second)
+	assertAcquire(t, rl, 4, true)
+	clock.Advance(300 * time.Millisecond)
+	assertAcquire(t, rl, 6, true)
+	assertAcquire(t, rl, 1, false)
+	assertAvailable(t, rl, 0)
+
+	// only the first 4 permits leave the window
+	clock.Advance(700 * time.Millisecond)
+	assertAvailable(t, rl, 4)
+	assertAcquire(t, rl, 5, false)
+	assertAcquire(t, rl, 4, true)
+
+	clock.Advance(300 * time.Millisecond)
+	assertAvailable(t, rl, 6)
+}
+
+func TestRateLimiterFixedWindow(t *testing.T) {
+	rl, clock := newAlgorithmLimiter(t, "testRateLimiterFixedWindow", RateLimiterFixedWindow, 10, time.Second)
+	// start at the beginning of a window
+	now := clock.Now()
+	clock.Advance(now.Truncate(time.Second).Add(time.Second).Sub(now))
+
+	assertAcquire(t, rl, 4, true)
+	clock.Advance(300 * time.Millisecond)
+	assertAcquire(t, rl, 6, true)
+	assertAcquire(t, rl, 1, false)
+	assertAvailable(t, rl, 0)
+
+	// all the permits are back in the next window
+	clock.Advance(700 * time.Millisecond)
+	assertAvailable(t, rl, 10)
+	assertAcquire(t, rl, 10, true)
+	assertAvailable(t, rl, 0)
+
+	// the counts of the ended windows are dropped and Delete removes the rest
+	if n := GetRedisson().client.HLen(context.Background(), "{testRateLimiterFixedWindow}:value").Val(); n != 1 {
+		t.Fatalf("windows=%d", n)
+	}
+	if _, err := rl.Delete(); err != nil {
+		t.Fatal(err)
+	}
+	if keys := GetRedisson().client.Keys(context.Background(), "{testRateLimiterFixedWindow}*").Val(); len(keys) != 0 {
+		t.Fatalf("keys=%v", keys)
+	}
+}
+
+func TestRateLimiterLeakyBucket(t *testing.T) {
+	rl, clock := newAlgorithmLimiter(t, "testRateLimiterLeakyBucket", RateLimiterLeakyBucket, 10, time.Second)
+	assertAcquire(t, rl, 10, true)
+	assertAcquire(t, rl, 1, false)
+
+	// a permit drains every 100ms instead of all of them after the interval
+	clock.Advance(100 * time.Millisecond)
+	assertAvailable(t, rl, 1)
+	assertAcquire(t, rl, 2, false)
+	assertAcquire(t, rl, 1, true)
+	clock.Advance(350 * time.Millisecond)
+	assertAvailable(t, rl, 3)
+
+	ok, err := rl.TryAcquirePermitsWithTimeoutContext(context.Background(), 4, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok {
+		t.Fatal("4 permits should not be acquired")
+	}
+	clock.Advance(time.Second)
+	assertAvailable(t, rl, 10)
+}
+
+func TestRateLimiterWarmUp(t *testing.T) {
+	rl, clock := newAlgorithmLimiter(t, "testRateLimiterWarmUp", RateLimiterFixedWindow, 9, time.Second, WithWarmUp(3*time.Second))
+	now := clock.Now()
+	clock.Advance(now.Truncate(time.Second).Add(time.Second).Sub(now))
+
+	// a cold limiter starts at a third of its rate
+	assertAvailable(t, rl, 3)
+	assertAcquire(t, rl, 3, true)
+	assertAcquire(t, rl, 1, false)
+
+	// halfway through the warm-up
+	clock.Advance(1500 * time.Millisecond)
+	assertAvailable(t, rl, 6)
+	assertAcquire(t, rl, 6, true)
+	assertAcquire(t, rl, 1, false)
+
+	// a request larger than the warming rate is not limited by the warm-up
+	clock.Advance(500 * time.Millisecond)
+	assertAcquire(t, rl, 8, true)
+
+	clock.Advance(time.Second)
+	assertAvailable(t, rl, 9)
+}
+
+// assertReserve checks the wait of a reservation of permits from rl
+func assertReserve(t *testing.T, rl RRateLimiter, permits int64, want time.Duration) {
+	t.Helper()
+	wait, err := rl.ReserveContext(context.Background(), permits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if wait != want {
+		t.Fatalf("reserving %d permits: wait %v, want %v", permits, wait, want)
+	}
+}
+
+func TestRateLimiterReserve(t *testing.T) {
+	t.Run("SlidingWindow", func(t *testing.T) {
+		rl, clock := newAlgorithmLimiter(t, "testRateLimiterReserveSliding", RateLimiterSlidingWindow, 10, time.Second)
+		assertReserve(t, rl, 6, 0)
+		clock.Advance(200 * time.Millisecond)
+		assertReserve(t, rl, 6, 800*time.Millisecond)
+		assertReserve(t, rl, 4, 800*time.Millisecond)
+		// the reservations count in the window from the time they are used
+		assertReserve(t, rl, 1, 1800*time.Millisecond)
+		clock.Advance(800 * time.Millisecond)
+		assertAvailable(t, rl, 0)
+		clock.Advance(time.Second)
+		assertAvailable(t, rl, 9)
+	})
+	t.Run("FixedWindow", func(t *testing.T) {
+		rl, clock := newAlgorithmLimiter(t, "testRateLimiterReserveFixed", RateLimiterFixedWindow, 10, time.Second)
+		now := clock.Now()
+		clock.Advance(now.Truncate(time.Second).Add(time.Second).Sub(now))
+		assertReserve(t, rl, 8, 0)
+		clock.Advance(400 * time.Millisecond)
+		assertReserve(t, rl, 8, 600*time.Millisecond)
+		assertReserve(t, rl, 8, 1600*time.Millisecond)
+		clock.Advance(600 * time.Millisecond)
+		assertAvailable(t, rl, 2)
+	})
+	t.Run("LeakyBucket", func(t *testing.T) {
+		rl, clock := newAlgorithmLimiter(t, "testRateLimiterReserveLeaky", RateLimiterLeakyBucket, 10, time.Second)
+		assertReserve(t, rl, 10, 0)
+		assertReserve(t, rl, 5, 500*time.Millisecond)
+		assertReserve(t, rl, 5, time.Second)
+		clock.Advance(time.Second)
+		assertAvailable(t, rl, 0)
+		clock.Advance(100 * time.Millisecond)
+		assertAvailable(t, rl, 1)
+	})
+	t.Run("TokenBucket", func(t *testing.T) {
+		rl, clock := newAlgorithmLimiter(t, "testRateLimiterReserveToken", RateLimiterTokenBucket, 10, time.Second)
+		assertReserve(t, rl, 6, 0)
+		clock.Advance(200 * time.Millisecond)
+		assertReserve(t, rl, 6, 800*time.Millisecond)
+		assertReserve(t, rl, 4, 800*time.Millisecond)
+		// the reserved permits are released an interval after the time they are used
+		assertReserve(t, rl, 1, 1800*time.Millisecond)
+		assertAcquire(t, rl, 1, false)
+		clock.Advance(800 * time.Millisecond)
+		assertAvailable(t, rl, 0)
+		clock.Advance(time.Second)
+		assertAvailable(t, rl, 9)
+	})
+}
+
+func TestRateLimiterAcquireAsync(t *testing.T) {
+	redisDB := redis.NewClient(&redis.Options{
+		Addr: redisAddr,
+	})
+	rl := NewRedisson(redisDB).GetRateLimiter("testRateLimiterAcquireAsync", WithRateLimiterAlgorithm(RateLimiterLeakyBucket))
+	if _, err := rl.Delete(); err != nil {
+		t.Fatal(err)
+	}
+	if err := rl.SetRate(RateTypeOVERALL, 10, 1, Seconds); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-rl.AcquireAsync(context.Background(), 10); err != nil {
+		t.Fatal(err)
+	}
+
+	// the next permit drains in 100ms
+	select {
+	case err := <-rl.AcquireAsync(context.Background(), 1):
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("the permit was not acquired")
+	}
+
+	// the permits drain in a second, the request is cancelled first
+	ctx, cancel := context.WithCancel(context.Background())
+	result := rl.AcquireAsync(ctx, 10)
+	cancel()
+	if err := <-result; !errors.Is(err, context.Canceled) {
+		t.Fatalf("err=%v", err)
+	}
+}
+
+func TestRateLimiterAcquireAsyncManualClock(t *testing.T) {
+	rl, clock := newAlgorithmLimiter(t, "testRateLimiterAcquireAsyncManualClock", RateLimiterLeakyBucket, 10, time.Second)
+	assertAcquire(t, rl, 10, true)
+	result := rl.AcquireAsync(context.Background(), 2)
+	// the retry is a timer of the clock, not a goroutine sleeping in real time
+	select {
+	case err := <-result:
+		t.Fatalf("acquired before the permits drained, err=%v", err)
+	case <-time.After(300 * time.Millisecond):
+	}
+	clock.Advance(200 * time.Millisecond)
+	select {
+	case err := <-result:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("the permits were not acquired")
+	}
+	assertAvailable(t, rl, 0)
+}
+
+func TestRateLimiterUpdateRate(t *testing.T) {
+	rl, _ := newAlgorithmLimiter(t, "testRateLimiterUpdateRate", RateLimiterSlidingWindow, 10, time.Second)
+	assertAcquire(t, rl, 6, true)
+	if err := rl.UpdateRate(20, 1, Seconds); err != nil {
+		t.Fatal(err)
+	}
+	// the issued permits still count
+	assertAvailable(t, rl, 14)
+	config, err := rl.GetConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.Rate != 20 || config.RateInterval != 1000 || config.RateType != RateTypeOVERALL {
+		t.Fatalf("config=%+v", config)
+	}
+
+	// the tokens left of a token bucket follow the rate
+	bucket, _ := newAlgorithmLimiter(t, "testRateLimiterUpdateRateBucket", RateLimiterTokenBucket, 10, time.Second)
+	ctx := context.Background()
+	redisDB := redis.NewClient(&redis.Options{Addr: redisAddr})
+	if err := redisDB.Set(ctx, "{testRateLimiterUpdateRateBucket}:value", 4, time.Minute).Err(); err != nil {
+		t.Fatal(err)
+	}
+	if err := bucket.UpdateRate(20, 1, Seconds); err != nil {
+		t.Fatal(err)
+	}
+	if value := redisDB.Get(ctx, "{testRateLimiterUpdateRateBucket}:value").Val(); value != "14" {
+		t.Fatalf("value=%s", value)
+	}
+	if ttl := redisDB.PTTL(ctx, "{testRateLimiterUpdateRateBucket}:value").Val(); ttl <= 0 {
+		t.Fatalf("ttl=%v", ttl)
+	}
+	if err := bucket.UpdateRate(2, 1, Seconds); err != nil {
+		t.Fatal(err)
+	}
+	if value := redisDB.Get(ctx, "{testRateLimiterUpdateRateBucket}:value").Val(); value != "0" {
+		t.Fatalf("value=%s", value)
+	}
+}
+
+func TestRateLimiterBurst(t *testing.T) {
+	for _, algorithm := range []RateLimiterAlgorithm{RateLimiterSlidingWindow, RateLimiterFixedWindow, RateLimiterLeakyBucket} {
+		t.Run(algorithm.String(), func(t *testing.T) {
+			rl, clock := newAlgorithmLimiter(t, "testRateLimiterBurst"+algorithm.String(), algorithm, 10, time.Second)
+			now := clock.Now()
+			clock.Advance(now.Truncate(time.Second).Add(time.Second).Sub(now))
+			assertAcquire(t, rl, 10, true)
+			if ok, err := rl.TryAcquirePermitsWithBurst(3, 2); err != nil {
+				t.Fatal(err)
+			} else if ok {
+				t.Fatal("3 permits should exceed the burst")
+			}
+			if ok, err := rl.TryAcquirePermitsWithBurst(2, 2); err != nil {
+				t.Fatal(err)
+			} else if !ok {
+				t.Fatal("2 permits should be acquired with the burst")
+			}
+			assertAvailable(t, rl, 0)
+			assertAcquire(t, rl, 1, false)
+		})
+	}
+}
+
+func TestRateLimiterStats(t *testing.T) {
+	redisDB := redis.NewClient(&redis.Options{
+		Addr: redisAddr,
+	})
+	clock := NewManualClock(time.Now())
+	g := NewRedisson(redisDB, WithClock(clock))
+	var throttled []time.Duration
+	g.OnThrottled(func(name string, permits int64, wait time.Duration) {
+		if name != "testRateLimiterStats" || permits != 2 {
+			t.Errorf("throttled %s %d", name, permits)
+		}
+		throttled = append(throttled, wait)
+	})
+	rl := g.GetRateLimiter("testRateLimiterStats", WithRateLimiterAlgorithm(RateLimiterSlidingWindow))
+	if _, err := rl.Delete(); err != nil {
+		t.Fatal(err)
+	}
+	if err := rl.SetRate(RateTypeOVERALL, 10, 1, Seconds); err != nil {
+		t.Fatal(err)
+	}
+	assertAcquire(t, rl, 10, true)
+	clock.Advance(200 * time.Millisecond)
+	assertAcquire(t, rl, 2, false)
+	if _, err := rl.Reserve(2); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(throttled) != 2 || throttled[0] != 800*time.Millisecond || throttled[1] != 800*time.Millisecond {
+		t.Fatalf("throttled=%v", throttled)
+	}
+	want := RateLimiterStats{Acquired: 12, Rejected: 2, Throttled: 2, TotalWait: 800 * time.Millisecond}
+	if stats := rl.Stats(); stats != want {
+		t.Fatalf("stats=%+v, want %+v", stats, want)
+	}
+
+	// a blocking call retrying several times counts once
+	done := make(chan bool)
+	go func() {
+		ok, err := rl.TryAcquirePermitsWithTimeout(2, 500*time.Millisecond)
+		if err != nil {
+			t.Error(err)
+		}
+		done <- ok
+	}()
+	time.Sleep(200 * time.Millisecond)
+	clock.Advance(500 * time.Millisecond)
+	if <-done {
+		t.Fatal("2 permits should not be acquired")
+	}
+	if len(throttled) != 3 {
+		t.Fatalf("throttled=%v", throttled)
+	}
+	want = RateLimiterStats{Acquired: 12, Rejected: 4, Throttled: 3, TotalWait: 1300 * time.Millisecond}
+	if stats := rl.Stats(); stats != want {
+		t.Fatalf("stats=%+v, want %+v", stats, want)
+	}
+
+	// Delete drops the stats of the name
+	if _, err := rl.Delete(); err != nil {
+		t.Fatal(err)
+	}
+	if stats := rl.Stats(); stats != (RateLimiterStats{}) {
+		t.Fatalf("stats=%+v", stats)
+	}
+}
+
+func TestRateLimiterTryAcquireWithDelay(t *testing.T) {
+	rl, clock := newAlgorithmLimiter(t, "testRateLimiterTryAcquireWithDelay", RateLimiterSlidingWindow, 10, time.Second)
+	if ok, retryAfter, err := rl.TryAcquireWithDelay(10); err != nil || !ok || retryAfter != 0 {
+		t.Fatalf("ok=%v retryAfter=%v err=%v", ok, retryAfter, err)
+	}
+	clock.Advance(300 * time.Millisecond)
+	if ok, retryAfter, err := rl.TryAcquireWithDelay(1); err != nil || ok || retryAfter != 700*time.Millisecond {
+		t.Fatalf("ok=%v retryAfter=%v err=%v", ok, retryAfter, err)
+	}
+}
+
+func TestRateLimiterExpire(t *testing.T) {
+	rl, _ := newAlgorithmLimiter(t, "testRateLimiterExpire", RateLimiterSlidingWindow, 10, time.Second)
+	assertAcquire(t, rl, 1, true)
+	redisDB := redis.NewClient(&redis.Options{Addr: redisAddr})
+	ctx := context.Background()
+	keys := []string{"testRateLimiterExpire", "{testRateLimiterExpire}:value", "{testRateLimiterExpire}:permits"}
+
+	if ok, err := rl.Expire(time.Minute); err != nil || !ok {
+		t.Fatalf("ok=%v err=%v", ok, err)
+	}
+	for _, key := range keys {
+		if ttl := redisDB.PTTL(ctx, key).Val(); ttl <= 0 || ttl > time.Minute {
+			t.Fatalf("ttl of %s=%v", key, ttl)
+		}
+	}
+	if ok, err := rl.ClearExpire(); err != nil || !ok {
+		t.Fatalf("ok=%v err=%v", ok, err)
+	}
+	for _, key := range keys {
+		if ttl := redisDB.PTTL(ctx, key).Val(); ttl != -1 {
+			t.Fatalf("ttl of %s=%v", key, ttl)
+		}
+	}
+}
+
+func TestRateLimiterTiers(t *testing.T) {
+	rl, clock := newAlgorithmLimiter(t, "testRateLimiterTiers", RateLimiterSlidingWindow, 10, time.Second)
+	if err := rl.SetRateTiers(RateTypeOVERALL, RateTier{Rate: 3, Interval: 100 * time.Millisecond}, RateTier{Rate: 5, Interval: time.Second}); err != nil {
+		t.Fatal(err)
+	}
+	config, err := rl.GetConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.Rate != 3 || config.RateInterval != 100 || len(config.Tiers) != 1 || config.Tiers[0] != (RateTier{Rate: 5, Interval: time.Second}) {
+		t.Fatalf("config=%+v", config)
+	}
+
+	assertAcquire(t, rl, 3, true)
+	if ok, retryAfter, err := rl.TryAcquireWithDelay(1); err != nil || ok || retryAfter != 100*time.Millisecond {
+		t.Fatalf("ok=%v retryAfter=%v err=%v", ok, retryAfter, err)
+	}
+	clock.Advance(100 * time.Millisecond)
+	assertAcquire(t, rl, 2, true)
+	assertAvailable(t, rl, 0)
+	// the per second tier is exhausted until the first permits leave its window
+	if ok, retryAfter, err := rl.TryAcquireWithDelay(1); err != nil || ok || retryAfter != 900*time.Millisecond {
+		t.Fatalf("ok=%v retryAfter=%v err=%v", ok, retryAfter, err)
+	}
+	clock.Advance(900 * time.Millisecond)
+	assertAvailable(t, rl, 3)
+
+	// SetRate replaces the tiers
+	if err := rl.SetRate(RateTypeOVERALL, 10, 1, Seconds); err != nil {
+		t.Fatal(err)
+	}
+	if config, err = rl.GetConfig(); err != nil || len(config.Tiers) != 0 {
+		t.Fatalf("config=%+v err=%v", config, err)
+	}
+
+	bucket, _ := newAlgorithmLimiter(t, "testRateLimiterTiersBucket", RateLimiterTokenBucket, 10, time.Second)
+	if err := bucket.SetRateTiers(RateTypeOVERALL, RateTier{Rate: 1, Interval: time.Second}); !errors.Is(err, ErrTiersUnsupported) {
+		t.Fatalf("err=%v", err)
+	}
+}
+
+func TestGetRateLimiterWithOptions(t *testing.T) {
+	g := GetRedisson()
+	ctx := context.Background()
+	if err := g.client.Del(ctx, "testGetRateLimiterWithOptions", "{testGetRateLimiterWithOptions}:value", "{testGetRateLimiterWithOptions}:permits").Err(); err != nil {
+		t.Fatal(err)
+	}
+	options := RateLimiterOptions{
+		Algorithm: RateLimiterSlidingWindow,
+		Rate:      5,
+		Interval:  time.Minute,
+		KeepAlive: time.Hour,
+	}
+	rl := g.GetRateLimiterWithOptions("testGetRateLimiterWithOptions", options)
+	assertAcquire(t, rl, 2, true)
+	assertAvailable(t, rl, 3)
+	if ttl := g.client.PTTL(ctx, "testGetRateLimiterWithOptions").Val(); ttl <= 0 || ttl > time.Hour {
+		t.Fatalf("ttl=%v", ttl)
+	}
+
+	// an existing rate is kept by default
+	options.Rate = 8
+	assertAvailable(t, g.GetRateLimiterWithOptions("testGetRateLimiterWithOptions", options), 3)
+
+	// and overwritten keeping the issued permits
+	options.Init = RateLimiterInitOverwrite
+	assertAvailable(t, g.GetRateLimiterWithOptions("testGetRateLimiterWithOptions", options), 6)
+
+	options.Init = RateLimiterInitNone
+	rl = g.GetRateLimiterWithOptions("testGetRateLimiterWithOptions", options)
+	if _, err := rl.Delete(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rl.AvailablePermits(); err == nil {
+		t.Fatal("the limiter should not be initialized")
+	}
+}
+
+func TestGetRateLimiterWithOptionsKeepAlive(t *testing.T) {
+	g := GetRedisson()
+	ctx := context.Background()
+	if err := g.client.Del(ctx, "testGetRateLimiterWithOptionsKeepAlive", "{testGetRateLimiterWithOptionsKeepAlive}:value", "{testGetRateLimiterWithOptionsKeepAlive}:permits").Err(); err != nil {
+		t.Fatal(err)
+	}
+	keepAlive := 300 * time.Millisecond
+	rl := g.GetRateLimiterWithOptions("testGetRateLimiterWithOptionsKeepAlive", RateLimiterOptions{
+		Algorithm: RateLimiterSlidingWindow,
+		Rate:      5,
+		Interval:  time.Minute,
+		KeepAlive: keepAlive,
+	})
+	assertAcquire(t, rl, 2, true)
+
+	// a limiter in use outlives the first KeepAlive window with its state
+	for i := 0; i < 4; i++ {
+		time.Sleep(keepAlive / 2)
+		assertAcquire(t, rl, 5, false)
+	}
+	if err := rl.Acquire(); err != nil {
+		t.Fatal(err)
+	}
+	assertAvailable(t, rl, 2)
+
+	// an idle limiter expires and starts over on its next use
+	time.Sleep(2 * keepAlive)
+	if n := g.client.Exists(ctx, "testGetRateLimiterWithOptionsKeepAlive", "{testGetRateLimiterWithOptionsKeepAlive}:value", "{testGetRateLimiterWithOptionsKeepAlive}:permits").Val(); n != 0 {
+		t.Fatalf("%d keys left", n)
+	}
+	if err := rl.Acquire(); err != nil {
+		t.Fatal(err)
+	}
+	assertAvailable(t, rl, 4)
+}
+
+func TestRateLimiterClientKeysExpire(t *testing.T) {
+	redisDB := redis.NewClient(&redis.Options{Addr: redisAddr})
+	ctx := context.Background()
+	for _, algorithm := range []RateLimiterAlgorithm{RateLimiterTokenBucket, RateLimiterSlidingWindow, RateLimiterLeakyBucket} {
+		t.Run(algorithm.String(), func(t *testing.T) {
+			g := NewRedisson(redisDB)
+			name := "testRateLimiterClientKeysExpire" + algorithm.String()
+			rl := g.GetRateLimiter(name, WithRateLimiterAlgorithm(algorithm))
+			if _, err := rl.Delete(); err != nil {
+				t.Fatal(err)
+			}
+			if err := rl.SetRate(RateTypePER_CLIENT, 10, 1, Seconds); err != nil {
+				t.Fatal(err)
+			}
+			if algorithm == RateLimiterTokenBucket {
+				// the token bucket script needs the struct library to acquire permits
+				if _, err := rl.AvailablePermits(); err != nil {
+					t.Fatal(err)
+				}
+			} else {
+				assertAcquire(t, rl, 5, true)
+			}
+			clientValue := "{" + name + "}:value:" + g.id
+			if ttl := redisDB.PTTL(ctx, clientValue).Val(); ttl <= 0 || ttl > time.Second+time.Millisecond {
+				t.Fatalf("ttl of %s=%v", clientValue, ttl)
+			}
+			if ttl := redisDB.PTTL(ctx, name).Val(); ttl != -1 {
+				t.Fatalf("ttl of the config=%v", ttl)
+			}
+		})
+	}
+}
+
+func TestRateLimiterContinuousRefill(t *testing.T) {
+	// 3 permits per second refill one every 333.3ms, the fractions add up
+	rl, clock := newAlgorithmLimiter(t, "testRateLimiterContinuousRefill", RateLimiterLeakyBucket, 3, time.Second)
+	assertAcquire(t, rl, 3, true)
+	clock.Advance(200 * time.Millisecond)
+	assertAvailable(t, rl, 0)
+	if ok, retryAfter, err := rl.TryAcquireWithDelay(1); err != nil || ok || retryAfter != 134*time.Millisecond {
+		t.Fatalf("ok=%v retryAfter=%v err=%v", ok, retryAfter, err)
+	}
+	clock.Advance(200 * time.Millisecond)
+	assertAvailable(t, rl, 1)
+	clock.Advance(300 * time.Millisecond)
+	assertAvailable(t, rl, 2)
+	assertAcquire(t, rl, 2, true)
+	// 0.1 of a permit was left
+	clock.Advance(300 * time.Millisecond)
+	assertAvailable(t, rl, 1)
+}
+
+func TestRateLimiterCellFallback(t *testing.T) {
+	rl, clock := newAlgorithmLimiter(t, "testRateLimiterCell", RateLimiterCell, 10, time.Second)
+	ok, err := rl.(*RedissonRateLimiter).hasCommand(context.Background(), "CL.THROTTLE")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok {
+		t.Skip("redis-cell is loaded")
+	}
+	// the leaky bucket is used without redis-cell
+	assertAcquire(t, rl, 10, true)
+	assertAcquire(t, rl, 1, false)
+	clock.Advance(100 * time.Millisecond)
+	assertAvailable(t, rl, 1)
+}
+
+func TestRateLimiterCell(t *testing.T) {
+	rl, _ := newAlgorithmLimiter(t, "testRateLimiterCellModule", RateLimiterCell, 10, time.Second)
+	ok, err := rl.(*RedissonRateLimiter).hasCommand(context.Background(), "CL.THROTTLE")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Skip("redis-cell is not loaded")
+	}
+	assertAcquire(t, rl, 10, true)
+	assertAcquire(t, rl, 1, false)
+	assertAvailable(t, rl, 0)
+
+	redisDB := redis.NewClient(&redis.Options{Addr: redisAddr})
+	// max_burst is pinned in the config, a burst would change it for this call only
+	if maxBurst := redisDB.HGet(context.Background(), "testRateLimiterCellModule", "maxBurst").Val(); maxBurst != "9" {
+		t.Fatalf("maxBurst=%s", maxBurst)
+	}
+	if _, err = rl.TryAcquirePermitsWithBurst(1, 2); err == nil {
+		t.Fatal("a burst is accepted")
+	}
+	if err = rl.UpdateRate(20, 1, Seconds); err != nil {
+		t.Fatal(err)
+	}
+	if redisDB.HExists(context.Background(), "testRateLimiterCellModule", "maxBurst").Val() {
+		t.Fatal("maxBurst is kept after UpdateRate")
+	}
+}
@@ -0,0 +1,73 @@
+package redisson
+
+import (
+	"sync"
+	"time"
+)
+
+// RateLimiterStats 本实例上同名限流器的统计，Delete 时清零
+type RateLimiterStats struct {
+	// Acquired 获取到的许可数，预约的许可也计入
+	Acquired int64
+	// Rejected 因许可不足未获取到的许可数，阻塞等待的调用超时或取消时计入一次
+	Rejected int64
+	// Throttled 许可不足的调用次数，阻塞等待的调用无论重试多少次只计入一次
+	Throttled int64
+	// TotalWait 阻塞等待许可的总时长，预约返回的等待时间也计入
+	TotalWait time.Duration
+}
+
+// rateLimiterStats 记录同名限流器的 RateLimiterStats
+type rateLimiterStats struct {
+	sync.Mutex
+	stats RateLimiterStats
+}
+
+// getRateLimiterStats 返回名为 name 的限流器的统计，首次使用时创建，限流器 Delete 时移除
+func (g *Redisson) getRateLimiterStats(name string) *rateLimiterStats {
+	stats, _ := g.rateLimiterStats.LoadOrStore(name, &rateLimiterStats{})
+	return stats.(*rateLimiterStats)
+}
+
+// OnThrottled sets the handler called when a rate limiter of the instance has not enough permits for a request,
+// with the name of the limiter, the requested permits and the wait until they are available, to alert when
+// a limiter starts rejecting heavily. It is called once per call, on the first failed attempt of the blocking
+// acquisitions, on the goroutine making the attempt, and must not block.
+func (g *Redisson) OnThrottled(handler func(name string, permits int64, wait time.Duration)) {
+	g.throttledHandler.Store(&handler)
+}
+
+// acquired 记录获取到的许可
+func (s *rateLimiterStats) acquired(permits int64) {
+	s.Lock()
+	defer s.Unlock()
+	s.stats.Acquired += permits
+}
+
+// throttled 记录一次许可不足的调用
+func (s *rateLimiterStats) throttled() {
+	s.Lock()
+	defer s.Unlock()
+	s.stats.Throttled++
+}
+
+// rejected 记录未获取到的许可
+func (s *rateLimiterStats) rejected(permits int64) {
+	s.Lock()
+	defer s.Unlock()
+	s.stats.Rejected += permits
+}
+
+// waited 记录等待许可的时长
+func (s *rateLimiterStats) waited(d time.Duration) {
+	s.Lock()
+	defer s.Unlock()
+	s.stats.TotalWait += d
+}
+
+// snapshot 返回 RateLimiterStats 的副本
+func (s *rateLimiterStats) snapshot() RateLimiterStats {
+	s.Lock()
+	defer s.Unlock()
+	return s.stats
+}
@@ -1,8 +1,13 @@
 package redisson
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/redis/go-redis/v9"
@@ -14,6 +19,26 @@ type RedissonConfig struct {
 	client *redis.Client
 	//watchDogTimeout timeout for watchdog
 	watchDogTimeout time.Duration
+	//codec default codec of objects storing encoded values
+	codec Codec
+	//defaultObjectTTL ttl set on value-bearing objects when they are first written
+	defaultObjectTTL time.Duration
+	//lockChannelShards number of shards of the unlock notification channel of each lock
+	lockChannelShards int
+	//unlockWakeLimit number of waiters of a lock in this instance retrying at once after a release, 0 for all
+	unlockWakeLimit int
+	//unlockWakeStagger delay between the groups of waiters retrying after a release
+	unlockWakeStagger time.Duration
+	//contextProvider provides the context of the calls which are not given one
+	contextProvider func() context.Context
+	//commandTimeout timeout of every internal command, 0 for none
+	commandTimeout time.Duration
+	//expiryTracking records the expire times set by Expire and ExpireAt for OnPreExpiry
+	expiryTracking bool
+	//clock provides the time to the time-dependent logic
+	clock Clock
+	//lockObserver is notified of the activity of the locks, nil for none
+	lockObserver LockObserver
 }
 
 // Redisson is a redisson client.
@@ -21,8 +46,33 @@ type Redisson struct {
 	RedissonConfig
 	//id Redisson unique uuid
 	id string
+	//wakeGates coalesces unlock wake-ups per lock name, see WithUnlockWakeLimit
+	wakeGates sync.Map
+	//scriptProfiler samples script latencies, nil unless WithScriptProfiler is set
+	scriptProfiler *scriptProfiler
+	//subscriptions shares one Pub/Sub subscription per channel among the lock waiters
+	subscriptions *subscriptionManager
+	//renewalBatcher renews the held locks together, nil unless WithBatchedRenewal is set
+	renewalBatcher *renewalBatcher
+	//lockStats LockStats of the locks created WithLockStats by name
+	lockStats sync.Map
+	//heldLocks registry of the locks held by the instance, released by ReleaseAll
+	heldLocks sync.Map
+	//lockWaiters number of goroutines waiting for each lock name, reported by DumpLocks
+	lockWaiters sync.Map
+	//renewalFailureHandler is called when the watchdog stops renewing a lock, see OnRenewalFailure
+	renewalFailureHandler atomic.Pointer[func(lockName string, err error)]
+	//rateLimiterStats RateLimiterStats of the rate limiters by name
+	rateLimiterStats sync.Map
+	//commands whether redis supports the module commands probed by hasCommand, by command name
+	commands sync.Map
+	//throttledHandler is called when a rate limiter has not enough permits, see OnThrottled
+	throttledHandler atomic.Pointer[func(name string, permits int64, wait time.Duration)]
 }
 
+// ErrNotReady indicates that redis could not be reached before the readiness deadline
+var ErrNotReady = errors.New("redisson: redis is not ready")
+
 // DefaultWatchDogTimeout
 // The default watchdog timeout, the watchdog will go every 1/3 of the DefaultWatchDogTimeout to renew the lock held by the current goroutine.
 var DefaultWatchDogTimeout = 30 * time.Second
@@ -33,8 +83,11 @@ func NewRedisson(redisClient *redis.Client, opts ...OptionFunc) *Redisson {
 		RedissonConfig: RedissonConfig{
 			client:          redisClient,
 			watchDogTimeout: DefaultWatchDogTimeout,
+			codec:           DefaultCodec,
+			clock:           SystemClock,
 		},
-		id: uuid.NewV4().String(),
+		id:            uuid.NewV4().String(),
+		subscriptions: newSubscriptionManager(redisClient),
 	}
 
 	fmt.Println("NewRedisson id:", g.id)
@@ -45,6 +98,82 @@ func NewRedisson(redisClient *redis.Client, opts ...OptionFunc) *Redisson {
 	return g
 }
 
+// OnRenewalFailure sets the handler called when the watchdog stops renewing a held lock, because renewing it failed
+// or because the lock was lost (ErrLockLost), so that the application can abort the critical section the lock no
+// longer protects. The handler runs on the watchdog goroutine and must not block.
+func (g *Redisson) OnRenewalFailure(handler func(lockName string, err error)) {
+	g.renewalFailureHandler.Store(&handler)
+}
+
+// Ping checks that redis can be reached. Objects can be created without a live connection,
+// Ping lets a service gate its traffic on readiness with an actionable error.
+func (g *Redisson) Ping(ctx context.Context) error {
+	if err := g.client.Ping(ctx).Err(); err != nil {
+		return fmt.Errorf("redisson: redis at %s is not reachable: %w", g.client.Options().Addr, err)
+	}
+	return nil
+}
+
+// WaitReady blocks until Ping succeeds, retrying with backoff.
+// It returns an error wrapping ErrNotReady and the last Ping error if redis is not reachable
+// when ctx is done or timeout elapses.
+func (g *Redisson) WaitReady(ctx context.Context, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	backoff := 50 * time.Millisecond
+	for {
+		err := g.Ping(ctx)
+		if err == nil {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%w: %w", ErrNotReady, err)
+		case <-time.After(backoff):
+		}
+		if backoff < time.Second {
+			backoff *= 2
+		}
+	}
+}
+
+// callTimeoutKey is the context key of the per-call command timeout
+type callTimeoutKey struct{}
+
+// ContextWithCommandTimeout returns a copy of ctx whose redisson commands time out after d,
+// overriding WithCommandTimeout for the calls made with it.
+func ContextWithCommandTimeout(ctx context.Context, d time.Duration) context.Context {
+	return context.WithValue(ctx, callTimeoutKey{}, d)
+}
+
+// baseContext returns the context of the calls which are not given one
+func (g *Redisson) baseContext() context.Context {
+	if g.contextProvider != nil {
+		if ctx := g.contextProvider(); ctx != nil {
+			return ctx
+		}
+	}
+	return context.Background()
+}
+
+// newContext returns the context of a command issued by a method which is not given one
+func (g *Redisson) newContext() (context.Context, context.CancelFunc) {
+	return g.withCommandTimeout(g.baseContext())
+}
+
+// withCommandTimeout bounds ctx by the timeout attached with ContextWithCommandTimeout,
+// or else by the command timeout of the instance
+func (g *Redisson) withCommandTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	timeout := g.commandTimeout
+	if d, ok := ctx.Value(callTimeoutKey{}).(time.Duration); ok {
+		timeout = d
+	}
+	if timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
 // OptionFunc is a function that can be used to configure a Redisson instance.
 type OptionFunc func(g *Redisson)
 
@@ -59,16 +188,337 @@ func WithWatchDogTimeout(t time.Duration) OptionFunc {
 	}
 }
 
+// WithDefaultCodec sets the codec used by objects which are not given their own codec.
+func WithDefaultCodec(c Codec) OptionFunc {
+	return func(g *Redisson) {
+		g.codec = c
+	}
+}
+
+// WithLockChannelShards shards the unlock notification channel of every lock into n channels.
+// Waiters subscribe to one shard chosen by the hash of their lock name and register in a zset next to the lock,
+// unlocking publishes only to the shard of the waiter registered first (of all the waiters when a write lock is
+// released to the readers), so a hot lock with many waiters neither concentrates all subscribers on a single channel
+// nor publishes to every shard. All instances sharing locks must use the same number of shards.
+func WithLockChannelShards(n int) OptionFunc {
+	return func(g *Redisson) {
+		g.lockChannelShards = n
+	}
+}
+
+// WithUnlockWakeLimit coalesces the wake-ups of the goroutines of this instance waiting on the same lock.
+// After a release only k waiters retry immediately, the others retry in groups of k spaced by stagger,
+// which avoids a stampede of tryAcquire calls when a contended lock is released and re-acquired rapidly.
+// Waiters still retry as soon as the lease of the holder expires.
+func WithUnlockWakeLimit(k int, stagger time.Duration) OptionFunc {
+	return func(g *Redisson) {
+		if k > 0 && stagger <= 0 {
+			stagger = 10 * time.Millisecond
+		}
+		g.unlockWakeLimit = k
+		g.unlockWakeStagger = stagger
+	}
+}
+
+// WithContextProvider sets the function providing the context of the calls which are not given one,
+// e.g. to carry tracing spans to the hooks of the redis client. Every command is issued through
+// the configured *redis.Client, so its hooks see all of them.
+func WithContextProvider(provider func() context.Context) OptionFunc {
+	return func(g *Redisson) {
+		g.contextProvider = provider
+	}
+}
+
+// WithCommandTimeout bounds every command issued by redisson objects by d.
+// The redis client must be created with ContextTimeoutEnabled for the deadline to apply to socket reads and writes.
+func WithCommandTimeout(d time.Duration) OptionFunc {
+	return func(g *Redisson) {
+		g.commandTimeout = d
+	}
+}
+
+// WithScriptProfiler samples the latency of the embedded Lua scripts, see ScriptProfile.
+// sampleRate is the fraction of the calls which are measured, between 0 and 1.
+// Scripts are then run with EVALSHA so that their SLOWLOG entries can be attributed to them.
+func WithScriptProfiler(sampleRate float64) OptionFunc {
+	return func(g *Redisson) {
+		g.scriptProfiler = newScriptProfiler(sampleRate)
+	}
+}
+
+// WithBatchedRenewal makes the watchdog renew all the locks held by the instance which are due
+// with a single script call instead of one call per lock, for services holding many locks.
+// Read locks, whose renewal also extends the timeouts of their readers, are still renewed one by one.
+// All the locks are renewed in one script, so the instance must not be connected to a redis cluster.
+func WithBatchedRenewal() OptionFunc {
+	return func(g *Redisson) {
+		g.renewalBatcher = newRenewalBatcher(g)
+	}
+}
+
+// WithLockObserver makes the locks of the instance notify o when they are acquired, released and renewed.
+func WithLockObserver(o LockObserver) OptionFunc {
+	return func(g *Redisson) {
+		g.lockObserver = o
+	}
+}
+
+// WithExpiryTracking records the expirations set by Expire, ExpireAt and ClearExpire in a shadow zset,
+// which OnPreExpiry uses to notify listeners before objects expire.
+func WithExpiryTracking() OptionFunc {
+	return func(g *Redisson) {
+		g.expiryTracking = true
+	}
+}
+
+// WithClock sets the Clock used by the lock watchdog and waits, rate limiter timestamps, TTL math
+// and time-bucketed objects, e.g. a ManualClock in tests or a skew-corrected clock in production.
+func WithClock(c Clock) OptionFunc {
+	return func(g *Redisson) {
+		g.clock = c
+	}
+}
+
+// WithServerTime makes the time-dependent logic follow the time of the redis server instead of the local clock,
+// so that clock skew between hosts does not distort rate limiter accounting and expiry math.
+// The offset to the server time is refreshed every syncInterval. NewRedisson does not wait for redis, the offset
+// is measured in the background and the local time is used until it is known or while redis cannot be reached.
+func WithServerTime(syncInterval time.Duration) OptionFunc {
+	return func(g *Redisson) {
+		g.clock = NewServerClock(g.client, syncInterval)
+	}
+}
+
+// WithDefaultObjectTTL sets the TTL that value-bearing objects (buckets, counters, filters, limiter state)
+// receive when they are first written, unless overridden by WithTTL. A TTL of 0 disables it.
+func WithDefaultObjectTTL(d time.Duration) OptionFunc {
+	return func(g *Redisson) {
+		g.defaultObjectTTL = d
+	}
+}
+
+// ObjectOption is a function that can be used to configure a single object when it is created,
+// overriding the defaults of the Redisson instance.
+type ObjectOption func(o *objectOptions)
+
+// objectOptions holds the settings an object is created with
+type objectOptions struct {
+	//codec codec of the values stored by the object
+	codec Codec
+	//ttl ttl of the object when it is first written
+	ttl time.Duration
+	//rate rateInterval rateType rate a rate limiter is initialized with on first use, 0 rate for none
+	rate         int64
+	rateInterval time.Duration
+	rateType     RateType
+	//rateAlgorithm algorithm of a rate limiter
+	rateAlgorithm RateLimiterAlgorithm
+	//rateInit how a rate limiter writes the rate of WithRate on first use
+	rateInit RateLimiterInit
+	//rateWarmUp warm-up period of a rate limiter, 0 for none
+	rateWarmUp time.Duration
+	//lease lease time of the locks, 0 to keep them with the watchdog
+	lease time.Duration
+	//adaptiveLeaseMin adaptiveLeaseMax bounds of the lease tuned from hold times, 0 max to use the watchdog timeout
+	adaptiveLeaseMin time.Duration
+	adaptiveLeaseMax time.Duration
+	//retryStrategy wait of the lock waiters between attempts without unlock notification, nil to wait for the expiry
+	retryStrategy RetryStrategy
+	//lockStats whether a lock records the LockStats of its name
+	lockStats bool
+	//bloomModule whether a Bloom filter uses the RedisBloom module when it is loaded
+	bloomModule bool
+	//bloomHasher hasher of the elements of a Bloom filter, nil for SHA256BloomHasher
+	bloomHasher BloomHasher
+	//clampToBounds whether a bounded counter update crossing a bound sets the value to the bound instead of failing
+	clampToBounds bool
+	//subscriberTimeout how long a subscriber of a reliable topic stays registered without reading, 0 for the default
+	subscriberTimeout time.Duration
+	//localCache local cache of a local cached map
+	localCache localCachedMapOptions
+	//cache settings of a read-through cache
+	cache cacheOptions
+}
+
+// newObjectOptions resolves the settings of an object from the instance defaults and opts
+func (g *Redisson) newObjectOptions(opts []ObjectOption) *objectOptions {
+	o := &objectOptions{
+		codec: g.codec,
+		ttl:   g.defaultObjectTTL,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// WithCodec sets the codec used to encode the values of an object.
+func WithCodec(c Codec) ObjectOption {
+	return func(o *objectOptions) {
+		o.codec = c
+	}
+}
+
+// WithTTL sets the TTL the object receives when it is first written, overriding WithDefaultObjectTTL.
+// A TTL of 0 keeps the object forever. A rate limiter renews it on every use, so it only expires once idle
+// for the TTL, and is initialized again by WithRate on its next use.
+func WithTTL(d time.Duration) ObjectOption {
+	return func(o *objectOptions) {
+		o.ttl = d
+	}
+}
+
+// WithRate initializes a rate limiter on first use to allow rate permits per interval shared by all instances,
+// as TrySetRate does, so that no caller has to race to initialize it. It does not change an existing rate.
+func WithRate(rate int64, interval time.Duration) ObjectOption {
+	return func(o *objectOptions) {
+		o.rate = rate
+		o.rateInterval = interval
+	}
+}
+
+// WithRateType sets the RateType WithRate initializes a rate limiter with, RateTypeOVERALL by default.
+func WithRateType(mode RateType) ObjectOption {
+	return func(o *objectOptions) {
+		o.rateType = mode
+	}
+}
+
+// WithRateLimiterAlgorithm sets the algorithm of a rate limiter, RateLimiterTokenBucket by default.
+// All the instances using a rate limiter must create it with the same algorithm.
+func WithRateLimiterAlgorithm(a RateLimiterAlgorithm) ObjectOption {
+	return func(o *objectOptions) {
+		o.rateAlgorithm = a
+	}
+}
+
+// WithWarmUp makes a cold rate limiter, new or idle for longer than period, start at a third of its rate
+// and ramp up linearly to its rate over period, like the SmoothWarmingUp limiter of Guava, to protect a backend
+// which has just restarted. It applies to the RateLimiterSlidingWindow, RateLimiterFixedWindow and
+// RateLimiterLeakyBucket algorithms, the Java compatible RateLimiterTokenBucket ignores it.
+func WithWarmUp(period time.Duration) ObjectOption {
+	return func(o *objectOptions) {
+		o.rateWarmUp = period
+	}
+}
+
+// withRateInit sets how a rate limiter writes the rate of WithRate on first use, see RateLimiterOptions
+func withRateInit(init RateLimiterInit) ObjectOption {
+	return func(o *objectOptions) {
+		o.rateInit = init
+	}
+}
+
+// WithLease makes a lock expire lease after it is acquired instead of being renewed by the watchdog while held.
+func WithLease(lease time.Duration) ObjectOption {
+	return func(o *objectOptions) {
+		o.lease = lease
+	}
+}
+
+// WithAdaptiveLease makes the watchdog of a lock use a lease tuned from the hold times observed in this instance,
+// twice their moving average bounded by min and max, instead of the watchdog timeout. Long holders renew less often
+// and a lock left by a crashed short holder is reclaimed sooner. It has no effect on a lock created WithLease.
+func WithAdaptiveLease(min, max time.Duration) ObjectOption {
+	return func(o *objectOptions) {
+		o.adaptiveLeaseMin = min
+		o.adaptiveLeaseMax = max
+	}
+}
+
+// WithRetryStrategy sets how long the waiters of a lock wait before retrying when no unlock notification arrives,
+// instead of waiting exactly until the lock expires, which makes all the waiters retry at once.
+func WithRetryStrategy(s RetryStrategy) ObjectOption {
+	return func(o *objectOptions) {
+		o.retryStrategy = s
+	}
+}
+
+// WithLockStats makes a lock record the LockStats of its name in this instance, shared by the locks of the name
+// created with it. The metrics of a name are kept as long as the instance, so it is meant for a bounded set of names.
+func WithLockStats() ObjectOption {
+	return func(o *objectOptions) {
+		o.lockStats = true
+	}
+}
+
+// WithBloomFilterModule makes TryInit create a Bloom filter with the BF.* commands of the RedisBloom module when redis
+// has it, and as the bit array computed by the client otherwise. The choice is stored in the config of the filter,
+// which every instance follows afterwards whatever its own options, so loading the module later does not affect
+// the filters created as bit arrays.
+func WithBloomFilterModule() ObjectOption {
+	return func(o *objectOptions) {
+		o.bloomModule = true
+	}
+}
+
+// WithBloomHasher sets how a Bloom filter computes the bit indexes of its elements, encoded with the codec
+// of the object, SHA256BloomHasher by default. Use HighwayBloomHasher with the StringCodec to share a filter
+// of strings with Java Redisson.
+func WithBloomHasher(h BloomHasher) ObjectOption {
+	return func(o *objectOptions) {
+		o.bloomHasher = h
+	}
+}
+
+// WithClampToBounds makes the updates of a BoundedCounter which would cross a bound set the value to the bound
+// instead of failing with ErrCounterOutOfBounds.
+func WithClampToBounds() ObjectOption {
+	return func(o *objectOptions) {
+		o.clampToBounds = true
+	}
+}
+
+// WithSubscriberTimeout sets how long a subscriber of a RReliableTopic stays registered without reading, after which
+// the messages are no longer kept for it. It defaults to DefaultReliableTopicSubscriberTimeout.
+func WithSubscriberTimeout(d time.Duration) ObjectOption {
+	return func(o *objectOptions) {
+		o.subscriberTimeout = d
+	}
+}
+
 // GetLock returns a Lock named "key" which can be used to lock and unlock the resource "key".
 // A Lock can be copied after first use, but most of the time it is advisable to keep instances of Lock.
-func (g *Redisson) GetLock(key string) Lock {
-	return newRedisLock(key, g)
+func (g *Redisson) GetLock(key string, opts ...ObjectOption) Lock {
+	return newRedisLock(key, g, opts...)
+}
+
+// GetSpinLock returns a Lock named "key" whose waiters retry with exponential backoff instead of subscribing to
+// unlock notifications, for short critical sections or deployments without Pub/Sub.
+func (g *Redisson) GetSpinLock(key string, opts ...ObjectOption) Lock {
+	return newRedisSpinLock(key, g, opts...)
 }
 
 // GetReadWriteLock returns a ReadWriteLock named "key" which can be used to lock and unlock the resource "key" when reading or writing.
 // A ReadWriteLock can be copied after first use, but most of the time it is advisable to keep instances of ReadWriteLock.
-func (g *Redisson) GetReadWriteLock(key string) ReadWriteLock {
-	return newRedisReadWriteLock(key, g)
+func (g *Redisson) GetReadWriteLock(key string, opts ...ObjectOption) ReadWriteLock {
+	return newRedisReadWriteLock(key, g, opts...)
+}
+
+// GetLockGroup returns a LockGroup which acquires the locks named "names" all at once.
+// Use it instead of acquiring several locks one by one, which can deadlock with goroutines locking them in another order.
+func (g *Redisson) GetLockGroup(names ...string) LockGroup {
+	return newRedissonLockGroup(g, names)
+}
+
+// GetPermitExpirableSemaphore returns a RPermitExpirableSemaphore named "name" whose permits carry metadata and expire after their lease.
+func (g *Redisson) GetPermitExpirableSemaphore(name string, opts ...ObjectOption) RPermitExpirableSemaphore {
+	return newRedissonPermitExpirableSemaphore(name, g, g.newObjectOptions(opts))
+}
+
+// GetPresenceRegistry returns a RPresenceRegistry named "name" tracking the live instances of a service.
+func (g *Redisson) GetPresenceRegistry(name string, opts ...ObjectOption) RPresenceRegistry {
+	return newRedissonPresenceRegistry(name, g, g.newObjectOptions(opts))
+}
+
+// GetLeaderboard returns a RLeaderboard named "name" ranking members by score.
+func (g *Redisson) GetLeaderboard(name string, opts ...ObjectOption) RLeaderboard {
+	return newRedissonLeaderboard(name, g, g.newObjectOptions(opts))
+}
+
+// GetDebouncer returns a RDebouncer named "name" running a function at most once per interval across all instances.
+func (g *Redisson) GetDebouncer(name string, interval time.Duration, opts ...DebouncerOption) RDebouncer {
+	return newRedissonDebouncer(name, g, interval, opts)
 }
 
 // GetMutex returns a Mutex named "key" which can be used to lock and unlock the resource "key".
@@ -79,27 +529,187 @@ func (g *Redisson) GetReadWriteLock(key string) ReadWriteLock {
 // In the terminology of the Go memory model,
 // the n'th call to Unlock “synchronizes before” the m'th call to Lock
 // for any n < m.
-func (g *Redisson) GetMutex(key string) Lock {
-	return newRedissonMutex(key, g)
+func (g *Redisson) GetMutex(key string, opts ...ObjectOption) Lock {
+	return newRedissonMutex(key, g, opts...)
 }
 
-func (g *Redisson) GetRateLimiter(name string) RRateLimiter {
-	return newRedissonRateLimiter(name, g)
+func (g *Redisson) GetRateLimiter(name string, opts ...ObjectOption) RRateLimiter {
+	return newRedissonRateLimiter(name, g, opts...)
+
+}
 
+// GetRateLimiterWithOptions returns a rate limiter named name configured by options: its algorithm, rate type,
+// the rate it is initialized with on first use and how, its TTL and warm-up, instead of configuring it
+// with TrySetRate or SetRate. opts are applied after options.
+func (g *Redisson) GetRateLimiterWithOptions(name string, options RateLimiterOptions, opts ...ObjectOption) RRateLimiter {
+	return newRedissonRateLimiter(name, g, append(options.objectOptions(), opts...)...)
 }
 
-func (g *Redisson) GetAtomicLong(key string) AtomicLong {
-	return NewRedissonAtomicLong(g, key)
+func (g *Redisson) GetAtomicLong(key string, opts ...ObjectOption) AtomicLong {
+	return NewRedissonAtomicLong(g, key, opts...)
 }
-func (g *Redisson) GetAtomicDouble(key string) AtomicDouble {
-	return NewRedissonAtomicDouble(g, key)
+func (g *Redisson) GetAtomicDouble(key string, opts ...ObjectOption) AtomicDouble {
+	return NewRedissonAtomicDouble(g, key, opts...)
 }
 
-func (g *Redisson) GetBitSet(key string) BitSet {
-	return NewRedissonBitSet(g, key)
+func (g *Redisson) GetBitSet(key string, opts ...ObjectOption) BitSet {
+	return NewRedissonBitSet(g, key, opts...)
 }
 
 // GetBloomFilter returns a new RBloomFilter instance
-func GetBloomFilter[T any](r *Redisson, key string) RBloomFilter[T] {
-	return NewRedissonBloomFilter[T](r, key)
+func GetBloomFilter[T any](r *Redisson, key string, opts ...ObjectOption) RBloomFilter[T] {
+	return NewRedissonBloomFilter[T](r, key, opts...)
+}
+
+// GetRotatingBloomFilter returns a new RRotatingBloomFilter instance which remembers
+// elements added within the last buckets*period.
+func GetRotatingBloomFilter[T any](r *Redisson, key string, period time.Duration, buckets int) RRotatingBloomFilter[T] {
+	return NewRedissonRotatingBloomFilter[T](r, key, period, buckets)
+}
+
+// GetBoundedCounter returns a BoundedCounter named "name" whose value stays within min and max.
+func (g *Redisson) GetBoundedCounter(name string, min, max int64, opts ...ObjectOption) BoundedCounter {
+	return newRedissonBoundedCounter(name, g, min, max, g.newObjectOptions(opts))
+}
+
+// GetIdGenerator returns an IdGenerator named "name" handing out ids unique across all its instances.
+func (g *Redisson) GetIdGenerator(name string, opts ...ObjectOption) IdGenerator {
+	return newRedissonIdGenerator(name, g, g.newObjectOptions(opts))
+}
+
+// GetWindowedCounter returns a RWindowedCounter named "name" which counts events over the last window.
+// Its buckets always expire once they leave the window, whatever the TTL of opts.
+func (g *Redisson) GetWindowedCounter(name string, window time.Duration, opts ...ObjectOption) RWindowedCounter {
+	return newRedissonWindowedCounter(name, window, g, g.newObjectOptions(opts))
+}
+
+// GetNativeTimeSeries returns a RNativeTimeSeries named "name", which requires the RedisTimeSeries module.
+func (g *Redisson) GetNativeTimeSeries(name string, opts ...ObjectOption) RNativeTimeSeries {
+	return newRedissonNativeTimeSeries(name, g, g.newObjectOptions(opts))
+}
+
+// GetBucket returns a RBucket named "name" holding a single value of type T.
+func GetBucket[T any](r *Redisson, name string, opts ...ObjectOption) RBucket[T] {
+	return newRedissonBucket[T](name, r, r.newObjectOptions(opts))
+}
+
+// GetAtomicReference returns an AtomicReference named "name" holding a value of type T which is swapped atomically.
+func GetAtomicReference[T any](r *Redisson, name string, opts ...ObjectOption) AtomicReference[T] {
+	return newRedissonAtomicReference[T](name, r, r.newObjectOptions(opts))
+}
+
+// GetSet returns a RSet named "name" holding values of type T.
+func GetSet[T any](r *Redisson, name string, opts ...ObjectOption) RSet[T] {
+	return newRedissonSet[T](name, r, r.newObjectOptions(opts))
+}
+
+// GetQueue returns a RQueue named "name" holding values of type T
+func GetQueue[T any](r *Redisson, name string, opts ...ObjectOption) RQueue[T] {
+	return newRedissonQueue[T](name, r, r.newObjectOptions(opts))
+}
+
+// GetDelayedQueue returns a RDelayedQueue moving the values offered to it to destination once they are due.
+// It encodes the values with the codec of destination, and must be closed to stop its transfer task.
+func GetDelayedQueue[T any](r *Redisson, destination RQueue[T]) RDelayedQueue[T] {
+	return newRedissonDelayedQueue[T](r, destination)
+}
+
+// GetBlockingQueue returns a RBlockingQueue named "name" holding values of type T
+func GetBlockingQueue[T any](r *Redisson, name string, opts ...ObjectOption) RBlockingQueue[T] {
+	return newRedissonBlockingQueue[T](name, r, r.newObjectOptions(opts))
+}
+
+// GetTransferQueue returns a RTransferQueue named "name" holding values of type T
+func GetTransferQueue[T any](r *Redisson, name string, opts ...ObjectOption) RTransferQueue[T] {
+	return newRedissonTransferQueue[T](name, r, r.newObjectOptions(opts))
+}
+
+// GetStream returns a RStream named "name" holding values of type T
+func GetStream[T any](r *Redisson, name string, opts ...ObjectOption) RStream[T] {
+	return newRedissonStream[T](name, r, r.newObjectOptions(opts))
+}
+
+// GetList returns a RList named "name" holding values of type T
+func GetList[T any](r *Redisson, name string, opts ...ObjectOption) RList[T] {
+	return newRedissonList[T](name, r, r.newObjectOptions(opts))
+}
+
+// GetSortedSet returns a RSortedSet named "name" holding values of type T in the order of compare, which returns
+// a negative number, 0 or a positive number when a is less than, equal to or greater than b, as cmp.Compare.
+func GetSortedSet[T any](r *Redisson, name string, compare func(a, b T) int, opts ...ObjectOption) RSortedSet[T] {
+	return newRedissonSortedSet[T](name, r, compare, r.newObjectOptions(opts))
+}
+
+// GetReliableTopic returns a RReliableTopic named "name" publishing messages of type T
+func GetReliableTopic[T any](r *Redisson, name string, opts ...ObjectOption) RReliableTopic[T] {
+	return newRedissonReliableTopic[T](name, r, r.newObjectOptions(opts))
+}
+
+// GetShardedTopic returns a RShardedTopic named "name" publishing messages of type T, which requires redis 7
+func GetShardedTopic[T any](r *Redisson, name string, opts ...ObjectOption) RShardedTopic[T] {
+	return newRedissonShardedTopic[T](name, r, r.newObjectOptions(opts))
+}
+
+// GetTimeSeries returns a RTimeSeries named "name" holding values of type T
+func GetTimeSeries[T any](r *Redisson, name string, opts ...ObjectOption) RTimeSeries[T] {
+	return newRedissonTimeSeries[T](name, r, r.newObjectOptions(opts))
+}
+
+// GetTopic returns a RTopic named "name" for publishing and receiving messages of type T.
+func GetTopic[T any](r *Redisson, name string, opts ...ObjectOption) RTopic[T] {
+	return newRedissonTopic[T](name, r, r.newObjectOptions(opts))
+}
+
+// GetLiveConfig returns a RLiveConfig named "name" holding a configuration of type T shared by all instances.
+func GetLiveConfig[T any](r *Redisson, name string, opts ...ObjectOption) RLiveConfig[T] {
+	return newRedissonLiveConfig[T](name, r, r.newObjectOptions(opts))
+}
+
+// GetPartitionedQueue returns a RPartitionedQueue named "name" split into the given number of partitions.
+func GetPartitionedQueue[T any](r *Redisson, name string, partitions int, opts ...ObjectOption) RPartitionedQueue[T] {
+	return newRedissonPartitionedQueue[T](name, r, partitions, r.newObjectOptions(opts))
+}
+
+// GetCache returns a RCache named "name" which loads missing values with loader.
+// Pass a nil writer for a read-through only cache.
+func GetCache[T any](r *Redisson, name string, loader CacheLoader[T], writer CacheWriter[T], opts ...ObjectOption) RCache[T] {
+	return newRedissonCache[T](name, r, loader, writer, r.newObjectOptions(opts))
+}
+
+// GetLocalCachedMap returns a RLocalCachedMap named "name" whose entries are also cached in the memory of the instance.
+func GetLocalCachedMap[T any](r *Redisson, name string, opts ...ObjectOption) RLocalCachedMap[T] {
+	return newRedissonLocalCachedMap[T](name, r, r.newObjectOptions(opts))
+}
+
+// GetRateLimitedQueue returns a RRateLimitedQueue named "name" from which all consumers collectively take
+// at most rate tasks per interval.
+func GetRateLimitedQueue[T any](r *Redisson, name string, rate int64, interval time.Duration, opts ...ObjectOption) RRateLimitedQueue[T] {
+	return newRedissonRateLimitedQueue[T](name, r, rate, interval, r.newObjectOptions(opts))
+}
+
+// hasCommand reports whether redis supports command, such as a command of a module. The answer is cached
+// by the instance, errors are not.
+func (g *Redisson) hasCommand(ctx context.Context, command string) (bool, error) {
+	if ok, checked := g.commands.Load(command); checked {
+		return ok.(bool), nil
+	}
+	ctx, cancel := g.withCommandTimeout(ctx)
+	defer cancel()
+	info, err := g.client.Do(ctx, "COMMAND", "INFO", command).Slice()
+	if err != nil {
+		return false, err
+	}
+	// the info of an unknown command is nil, some servers speaking the protocol ignore the argument and return
+	// all the commands, so look for the command by name
+	found := false
+	for _, cmd := range info {
+		if fields, ok := cmd.([]any); ok && len(fields) > 0 {
+			if name, ok := fields[0].(string); ok && strings.EqualFold(name, command) {
+				found = true
+				break
+			}
+		}
+	}
+	g.commands.Store(command, found)
+	return found, nil
 }
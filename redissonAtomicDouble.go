@@ -2,23 +2,35 @@ package redisson
 
 import (
 	"context"
-	"github.com/redis/go-redis/v9"
 	"strconv"
+
+	"github.com/redis/go-redis/v9"
 )
 
 type AtomicDouble interface {
 	RExpirable
 	GetAndDecrement() (float64, error)
-	AddAndGet(float642 float64) float64
-	CompareAndSet(float64, float64) (bool, error)
+	GetAndDecrementContext(ctx context.Context) (float64, error)
+	AddAndGet(delta float64) (float64, error)
+	AddAndGetContext(ctx context.Context, delta float64) (float64, error)
+	CompareAndSet(expect float64, update float64) (bool, error)
+	CompareAndSetContext(ctx context.Context, expect float64, update float64) (bool, error)
 	Get() (float64, error)
+	GetContext(ctx context.Context) (float64, error)
 	GetAndDelete() (float64, error)
-	GetAndAdd(float64) (float64, error)
-	GetAndSet(float64) (float64, error)
-	IncrementAndGet() float64
+	GetAndDeleteContext(ctx context.Context) (float64, error)
+	GetAndAdd(delta float64) (float64, error)
+	GetAndAddContext(ctx context.Context, delta float64) (float64, error)
+	GetAndSet(newValue float64) (float64, error)
+	GetAndSetContext(ctx context.Context, newValue float64) (float64, error)
+	IncrementAndGet() (float64, error)
+	IncrementAndGetContext(ctx context.Context) (float64, error)
 	GetAndIncrement() (float64, error)
-	Set(float64) error
-	DecrementAndGet() float64
+	GetAndIncrementContext(ctx context.Context) (float64, error)
+	Set(newValue float64) error
+	SetContext(ctx context.Context, newValue float64) error
+	DecrementAndGet() (float64, error)
+	DecrementAndGetContext(ctx context.Context) (float64, error)
 }
 
 var (
@@ -29,37 +41,73 @@ type RedissonAtomicDouble struct {
 	*RedissonExpirable
 }
 
-func NewRedissonAtomicDouble(redisson *Redisson, name string) *RedissonAtomicDouble {
-	return &RedissonAtomicDouble{
+func NewRedissonAtomicDouble(redisson *Redisson, name string, opts ...ObjectOption) *RedissonAtomicDouble {
+	m := &RedissonAtomicDouble{
 		RedissonExpirable: newRedissonExpirable(name, redisson),
 	}
+	m.ttl = redisson.newObjectOptions(opts).ttl
+	return m
+}
+
+func (m *RedissonAtomicDouble) AddAndGet(delta float64) (float64, error) {
+	return m.AddAndGetContext(m.baseContext(), delta)
 }
 
-func (m *RedissonAtomicDouble) AddAndGet(delta float64) float64 {
-	return m.client.IncrByFloat(context.Background(), m.getRawName(), delta).Val()
+func (m *RedissonAtomicDouble) AddAndGetContext(ctx context.Context, delta float64) (float64, error) {
+	cmdCtx, cancel := m.withCommandTimeout(ctx)
+	v, err := m.client.IncrByFloat(cmdCtx, m.getRawName(), delta).Result()
+	cancel()
+	if err != nil {
+		return 0, err
+	}
+	if err = m.applyTTL(ctx, m.getRawName()); err != nil {
+		return 0, err
+	}
+	return v, nil
 }
 
 func (m *RedissonAtomicDouble) CompareAndSet(expect float64, update float64) (bool, error) {
-	r, err := m.client.Eval(context.Background(), `
+	return m.CompareAndSetContext(m.baseContext(), expect, update)
+}
+
+func (m *RedissonAtomicDouble) CompareAndSetContext(ctx context.Context, expect float64, update float64) (bool, error) {
+	cmdCtx, cancel := m.withCommandTimeout(ctx)
+	r, err := m.eval(cmdCtx, "atomicDouble.compareAndSet", `
 local value = redis.call('get', KEYS[1]);
 if (value == false and tonumber(ARGV[1]) == 0) or (tonumber(value) == tonumber(ARGV[1])) then
-     redis.call('set', KEYS[1], ARGV[2]);
+     redis.call('set', KEYS[1], ARGV[2], 'keepttl');
      return 1
    else
 return 0 end
 `, []string{m.getRawName()}, strconv.FormatFloat(expect, 'e', -1, 64), strconv.FormatFloat(update, 'e', -1, 64)).Int()
+	cancel()
 	if err != nil {
 		return false, err
 	}
+	if r == 1 {
+		if err = m.applyTTL(ctx, m.getRawName()); err != nil {
+			return false, err
+		}
+	}
 	return r == 1, nil
 }
 
-func (m *RedissonAtomicDouble) DecrementAndGet() float64 {
-	return m.client.IncrByFloat(context.Background(), m.getRawName(), -1).Val()
+func (m *RedissonAtomicDouble) DecrementAndGet() (float64, error) {
+	return m.AddAndGetContext(m.baseContext(), -1)
+}
+
+func (m *RedissonAtomicDouble) DecrementAndGetContext(ctx context.Context) (float64, error) {
+	return m.AddAndGetContext(ctx, -1)
 }
 
 func (m *RedissonAtomicDouble) Get() (float64, error) {
-	r, err := m.client.Get(context.Background(), m.getRawName()).Float64()
+	return m.GetContext(m.baseContext())
+}
+
+func (m *RedissonAtomicDouble) GetContext(ctx context.Context) (float64, error) {
+	ctx, cancel := m.withCommandTimeout(ctx)
+	defer cancel()
+	r, err := m.client.Get(ctx, m.getRawName()).Float64()
 	if err == redis.Nil {
 		return 0, nil
 	}
@@ -67,7 +115,13 @@ func (m *RedissonAtomicDouble) Get() (float64, error) {
 }
 
 func (m *RedissonAtomicDouble) GetAndDelete() (float64, error) {
-	r, err := m.client.Eval(context.Background(), `
+	return m.GetAndDeleteContext(m.baseContext())
+}
+
+func (m *RedissonAtomicDouble) GetAndDeleteContext(ctx context.Context) (float64, error) {
+	ctx, cancel := m.withCommandTimeout(ctx)
+	defer cancel()
+	r, err := m.eval(ctx, "atomicDouble.getAndDelete", `
 local currValue = redis.call('get', KEYS[1]);
 redis.call('del', KEYS[1]);
 return currValue;
@@ -79,7 +133,11 @@ return currValue;
 }
 
 func (m *RedissonAtomicDouble) GetAndAdd(delta float64) (float64, error) {
-	v, err := m.client.Do(context.Background(), "INCRBYFLOAT", m.getRawName(), delta).Float64()
+	return m.GetAndAddContext(m.baseContext(), delta)
+}
+
+func (m *RedissonAtomicDouble) GetAndAddContext(ctx context.Context, delta float64) (float64, error) {
+	v, err := m.AddAndGetContext(ctx, delta)
 	if err != nil {
 		return 0, err
 	}
@@ -87,25 +145,61 @@ func (m *RedissonAtomicDouble) GetAndAdd(delta float64) (float64, error) {
 }
 
 func (m *RedissonAtomicDouble) GetAndSet(newValue float64) (float64, error) {
-	f, err := m.client.GetSet(context.Background(), m.getRawName(), strconv.FormatFloat(newValue, 'e', -1, 64)).Float64()
+	return m.GetAndSetContext(m.baseContext(), newValue)
+}
+
+func (m *RedissonAtomicDouble) GetAndSetContext(ctx context.Context, newValue float64) (float64, error) {
+	cmdCtx, cancel := m.withCommandTimeout(ctx)
+	f, err := m.eval(cmdCtx, "atomicDouble.getAndSet", atomicGetAndSetScript, []string{m.getRawName()}, strconv.FormatFloat(newValue, 'e', -1, 64)).Float64()
+	cancel()
 	if err == redis.Nil {
-		return 0, nil
+		// the previous value did not exist
+		err = nil
+	}
+	if err != nil {
+		return 0, err
 	}
-	return f, err
+	if err = m.applyTTL(ctx, m.getRawName()); err != nil {
+		return 0, err
+	}
+	return f, nil
 }
 
-func (m *RedissonAtomicDouble) IncrementAndGet() float64 {
-	return m.client.IncrByFloat(context.Background(), m.getRawName(), 1).Val()
+func (m *RedissonAtomicDouble) IncrementAndGet() (float64, error) {
+	return m.AddAndGetContext(m.baseContext(), 1)
+}
+
+func (m *RedissonAtomicDouble) IncrementAndGetContext(ctx context.Context) (float64, error) {
+	return m.AddAndGetContext(ctx, 1)
 }
 
 func (m *RedissonAtomicDouble) GetAndIncrement() (float64, error) {
-	return m.GetAndAdd(1)
+	return m.GetAndAddContext(m.baseContext(), 1)
+}
+
+func (m *RedissonAtomicDouble) GetAndIncrementContext(ctx context.Context) (float64, error) {
+	return m.GetAndAddContext(ctx, 1)
 }
 
 func (m *RedissonAtomicDouble) GetAndDecrement() (float64, error) {
-	return m.GetAndAdd(-1)
+	return m.GetAndAddContext(m.baseContext(), -1)
+}
+
+func (m *RedissonAtomicDouble) GetAndDecrementContext(ctx context.Context) (float64, error) {
+	return m.GetAndAddContext(ctx, -1)
 }
 
+// Set sets the value and keeps the TTL of the counter, the TTL of WithTTL is only set if it has none.
 func (m *RedissonAtomicDouble) Set(newValue float64) error {
-	return m.client.Do(context.Background(), "SET", m.getRawName(), strconv.FormatFloat(newValue, 'e', -1, 64)).Err()
+	return m.SetContext(m.baseContext(), newValue)
+}
+
+func (m *RedissonAtomicDouble) SetContext(ctx context.Context, newValue float64) error {
+	cmdCtx, cancel := m.withCommandTimeout(ctx)
+	err := m.client.Set(cmdCtx, m.getRawName(), strconv.FormatFloat(newValue, 'e', -1, 64), redis.KeepTTL).Err()
+	cancel()
+	if err != nil {
+		return err
+	}
+	return m.applyTTL(ctx, m.getRawName())
 }
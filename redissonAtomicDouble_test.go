@@ -94,7 +94,10 @@ func TestDecrementAndGet(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	v := al.DecrementAndGet()
+	v, err := al.DecrementAndGet()
+	if err != nil {
+		t.Fatal(err)
+	}
 	if v != 18.30 {
 		t.Fatalf("v=%v", v)
 	}
@@ -109,7 +112,9 @@ func TestDecrementAndGet(t *testing.T) {
 
 func TestIncrementAndGet(t *testing.T) {
 	al := GetRedisson().GetAtomicDouble("test8")
-	if al.IncrementAndGet() != 1 {
+	if v, err := al.IncrementAndGet(); err != nil {
+		t.Fatal(err)
+	} else if v != 1 {
 		t.FailNow()
 	}
 	if v, err := al.Get(); err != nil {
@@ -2,22 +2,38 @@ package redisson
 
 import (
 	"context"
+
 	"github.com/redis/go-redis/v9"
 )
 
 type AtomicLong interface {
 	RExpirable
 	GetAndDecrement() (int64, error)
-	AddAndGet(int642 int64) int64
-	CompareAndSet(int64, int64) (bool, error)
+	GetAndDecrementContext(ctx context.Context) (int64, error)
+	AddAndGet(delta int64) (int64, error)
+	AddAndGetContext(ctx context.Context, delta int64) (int64, error)
+	CompareAndSet(expect int64, update int64) (bool, error)
+	CompareAndSetContext(ctx context.Context, expect int64, update int64) (bool, error)
 	Get() (int64, error)
+	GetContext(ctx context.Context) (int64, error)
 	GetAndDelete() (int64, error)
-	GetAndAdd(int64) (int64, error)
-	GetAndSet(int64) (int64, error)
-	IncrementAndGet() int64
+	GetAndDeleteContext(ctx context.Context) (int64, error)
+	GetAndAdd(delta int64) (int64, error)
+	GetAndAddContext(ctx context.Context, delta int64) (int64, error)
+	GetAndSet(newValue int64) (int64, error)
+	GetAndSetContext(ctx context.Context, newValue int64) (int64, error)
+	IncrementAndGet() (int64, error)
+	IncrementAndGetContext(ctx context.Context) (int64, error)
 	GetAndIncrement() (int64, error)
-	Set(int64) error
-	DecrementAndGet() int64
+	GetAndIncrementContext(ctx context.Context) (int64, error)
+	Set(newValue int64) error
+	SetContext(ctx context.Context, newValue int64) error
+	DecrementAndGet() (int64, error)
+	DecrementAndGetContext(ctx context.Context) (int64, error)
+	UpdateAndGet(update func(int64) int64) (int64, error)
+	UpdateAndGetContext(ctx context.Context, update func(int64) int64) (int64, error)
+	AccumulateAndGet(x int64, accumulate func(int64, int64) int64) (int64, error)
+	AccumulateAndGetContext(ctx context.Context, x int64, accumulate func(int64, int64) int64) (int64, error)
 }
 
 type RedissonAtomicLong struct {
@@ -28,39 +44,90 @@ var (
 	_ AtomicLong = (*RedissonAtomicLong)(nil)
 )
 
-func NewRedissonAtomicLong(redisson *Redisson, name string) *RedissonAtomicLong {
-	return &RedissonAtomicLong{
+func NewRedissonAtomicLong(redisson *Redisson, name string, opts ...ObjectOption) *RedissonAtomicLong {
+	m := &RedissonAtomicLong{
 		RedissonExpirable: newRedissonExpirable(name, redisson),
 	}
+	m.ttl = redisson.newObjectOptions(opts).ttl
+	return m
+}
+
+func (m *RedissonAtomicLong) AddAndGet(delta int64) (int64, error) {
+	return m.AddAndGetContext(m.baseContext(), delta)
 }
 
-func (m *RedissonAtomicLong) AddAndGet(delta int64) int64 {
-	return m.client.IncrBy(context.Background(), m.getRawName(), delta).Val()
+func (m *RedissonAtomicLong) AddAndGetContext(ctx context.Context, delta int64) (int64, error) {
+	cmdCtx, cancel := m.withCommandTimeout(ctx)
+	v, err := m.client.IncrBy(cmdCtx, m.getRawName(), delta).Result()
+	cancel()
+	if err != nil {
+		return 0, err
+	}
+	if err = m.applyTTL(ctx, m.getRawName()); err != nil {
+		return 0, err
+	}
+	return v, nil
 }
 
 func (m *RedissonAtomicLong) CompareAndSet(expect int64, update int64) (bool, error) {
-	r, err := m.client.Eval(context.Background(), `
+	return m.CompareAndSetContext(m.baseContext(), expect, update)
+}
+
+func (m *RedissonAtomicLong) CompareAndSetContext(ctx context.Context, expect int64, update int64) (bool, error) {
+	cmdCtx, cancel := m.withCommandTimeout(ctx)
+	r, err := m.eval(cmdCtx, "atomicLong.compareAndSet", atomicLongCompareAndSetScript, []string{m.getRawName()}, expect, update).Int()
+	cancel()
+	if err != nil {
+		return false, err
+	}
+	if r == 1 {
+		if err = m.applyTTL(ctx, m.getRawName()); err != nil {
+			return false, err
+		}
+	}
+	return r == 1, nil
+}
+
+// atomicLongCompareAndSetScript sets KEYS[1] to ARGV[2] if its value equals ARGV[1] numerically, so that values
+// written with another formatting, e.g. 07 or 7.0, match. Lua numbers are doubles which do not hold every int64,
+// so the values beyond 2^53 only match as strings.
+const atomicLongCompareAndSetScript = `
 local currValue = redis.call('get', KEYS[1]);
+local current = tonumber(currValue);
+local expected = tonumber(ARGV[1]);
 if currValue == ARGV[1]
-     or (tonumber(ARGV[1]) == 0 and currValue == false) then
- redis.call('set', KEYS[1], ARGV[2]);
+     or (expected == 0 and currValue == false)
+     or (current ~= nil and current == expected and math.abs(expected) < 9007199254740992) then
+ redis.call('set', KEYS[1], ARGV[2], 'keepttl');
  return 1
 else
  return 0
 end
-`, []string{m.getRawName()}, expect, update).Int()
-	if err != nil {
-		return false, err
-	}
-	return r == 1, nil
+`
+
+// atomicGetAndSetScript sets KEYS[1] to ARGV[1] and returns its previous value, keeping its TTL unlike GETSET
+const atomicGetAndSetScript = `
+local currValue = redis.call('get', KEYS[1]);
+redis.call('set', KEYS[1], ARGV[1], 'keepttl');
+return currValue;
+`
+
+func (m *RedissonAtomicLong) DecrementAndGet() (int64, error) {
+	return m.AddAndGetContext(m.baseContext(), -1)
 }
 
-func (m *RedissonAtomicLong) DecrementAndGet() int64 {
-	return m.client.IncrBy(context.Background(), m.getRawName(), -1).Val()
+func (m *RedissonAtomicLong) DecrementAndGetContext(ctx context.Context) (int64, error) {
+	return m.AddAndGetContext(ctx, -1)
 }
 
 func (m *RedissonAtomicLong) Get() (int64, error) {
-	r, err := m.client.Get(context.Background(), m.getRawName()).Int64()
+	return m.GetContext(m.baseContext())
+}
+
+func (m *RedissonAtomicLong) GetContext(ctx context.Context) (int64, error) {
+	ctx, cancel := m.withCommandTimeout(ctx)
+	defer cancel()
+	r, err := m.client.Get(ctx, m.getRawName()).Int64()
 	if err == redis.Nil {
 		return 0, nil
 	}
@@ -68,7 +135,13 @@ func (m *RedissonAtomicLong) Get() (int64, error) {
 }
 
 func (m *RedissonAtomicLong) GetAndDelete() (int64, error) {
-	r, err := m.client.Eval(context.Background(), `
+	return m.GetAndDeleteContext(m.baseContext())
+}
+
+func (m *RedissonAtomicLong) GetAndDeleteContext(ctx context.Context) (int64, error) {
+	ctx, cancel := m.withCommandTimeout(ctx)
+	defer cancel()
+	r, err := m.eval(ctx, "atomicLong.getAndDelete", `
 local currValue = redis.call('get', KEYS[1]);
 redis.call('del', KEYS[1]);
 return currValue;
@@ -80,7 +153,11 @@ return currValue;
 }
 
 func (m *RedissonAtomicLong) GetAndAdd(delta int64) (int64, error) {
-	v, err := m.client.Do(context.Background(), "INCRBY", m.getRawName(), delta).Int64()
+	return m.GetAndAddContext(m.baseContext(), delta)
+}
+
+func (m *RedissonAtomicLong) GetAndAddContext(ctx context.Context, delta int64) (int64, error) {
+	v, err := m.AddAndGetContext(ctx, delta)
 	if err != nil {
 		return 0, err
 	}
@@ -88,25 +165,126 @@ func (m *RedissonAtomicLong) GetAndAdd(delta int64) (int64, error) {
 }
 
 func (m *RedissonAtomicLong) GetAndSet(newValue int64) (int64, error) {
-	f, err := m.client.GetSet(context.Background(), m.getRawName(), newValue).Int64()
+	return m.GetAndSetContext(m.baseContext(), newValue)
+}
+
+func (m *RedissonAtomicLong) GetAndSetContext(ctx context.Context, newValue int64) (int64, error) {
+	cmdCtx, cancel := m.withCommandTimeout(ctx)
+	f, err := m.eval(cmdCtx, "atomicLong.getAndSet", atomicGetAndSetScript, []string{m.getRawName()}, newValue).Int64()
+	cancel()
 	if err == redis.Nil {
-		return 0, nil
+		// the previous value did not exist
+		err = nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	if err = m.applyTTL(ctx, m.getRawName()); err != nil {
+		return 0, err
 	}
-	return f, err
+	return f, nil
+}
+
+func (m *RedissonAtomicLong) IncrementAndGet() (int64, error) {
+	return m.AddAndGetContext(m.baseContext(), 1)
 }
 
-func (m *RedissonAtomicLong) IncrementAndGet() int64 {
-	return m.client.IncrBy(context.Background(), m.getRawName(), 1).Val()
+func (m *RedissonAtomicLong) IncrementAndGetContext(ctx context.Context) (int64, error) {
+	return m.AddAndGetContext(ctx, 1)
 }
 
 func (m *RedissonAtomicLong) GetAndIncrement() (int64, error) {
-	return m.GetAndAdd(1)
+	return m.GetAndAddContext(m.baseContext(), 1)
+}
+
+func (m *RedissonAtomicLong) GetAndIncrementContext(ctx context.Context) (int64, error) {
+	return m.GetAndAddContext(ctx, 1)
 }
 
 func (m *RedissonAtomicLong) GetAndDecrement() (int64, error) {
-	return m.GetAndAdd(-1)
+	return m.GetAndAddContext(m.baseContext(), -1)
+}
+
+func (m *RedissonAtomicLong) GetAndDecrementContext(ctx context.Context) (int64, error) {
+	return m.GetAndAddContext(ctx, -1)
 }
 
+// Set sets the value and keeps the TTL of the counter, the TTL of WithTTL is only set if it has none.
 func (m *RedissonAtomicLong) Set(newValue int64) error {
-	return m.client.Do(context.Background(), "SET", m.getRawName(), newValue).Err()
+	return m.SetContext(m.baseContext(), newValue)
+}
+
+func (m *RedissonAtomicLong) SetContext(ctx context.Context, newValue int64) error {
+	cmdCtx, cancel := m.withCommandTimeout(ctx)
+	err := m.client.Set(cmdCtx, m.getRawName(), newValue, redis.KeepTTL).Err()
+	cancel()
+	if err != nil {
+		return err
+	}
+	return m.applyTTL(ctx, m.getRawName())
+}
+
+// UpdateAndGet sets the value to update applied to it and returns the new value. The value is read and then
+// compared and set, again until no other update came in between, so update may be called several times
+// and must not have side effects.
+func (m *RedissonAtomicLong) UpdateAndGet(update func(int64) int64) (int64, error) {
+	return m.UpdateAndGetContext(m.baseContext(), update)
+}
+
+// UpdateAndGetContext is UpdateAndGet which stops retrying when ctx is done.
+func (m *RedissonAtomicLong) UpdateAndGetContext(ctx context.Context, update func(int64) int64) (int64, error) {
+	for {
+		current, err := m.GetContext(ctx)
+		if err != nil {
+			return 0, err
+		}
+		next := update(current)
+		ok, err := m.CompareAndSetContext(ctx, current, next)
+		if err != nil {
+			return 0, err
+		}
+		if ok {
+			return next, nil
+		}
+		if err = ctx.Err(); err != nil {
+			return 0, err
+		}
+	}
+}
+
+// AccumulateAndGet sets the value to accumulate applied to it and x and returns the new value,
+// accumulate may be called several times as update by UpdateAndGet.
+func (m *RedissonAtomicLong) AccumulateAndGet(x int64, accumulate func(int64, int64) int64) (int64, error) {
+	return m.AccumulateAndGetContext(m.baseContext(), x, accumulate)
+}
+
+// AccumulateAndGetContext is AccumulateAndGet which stops retrying when ctx is done.
+func (m *RedissonAtomicLong) AccumulateAndGetContext(ctx context.Context, x int64, accumulate func(int64, int64) int64) (int64, error) {
+	return m.UpdateAndGetContext(ctx, func(current int64) int64 {
+		return accumulate(current, x)
+	})
+}
+
+// AddAndGetAll adds the given delta to each named AtomicLong and returns all new values.
+// The increments are sent in a single pipeline, so updating many counters costs one round trip.
+// The counters created without an expiry get the default object TTL, as by AddAndGet.
+func (g *Redisson) AddAndGetAll(deltas map[string]int64) (map[string]int64, error) {
+	ctx, cancel := g.newContext()
+	defer cancel()
+	cmds := make(map[string]*redis.IntCmd, len(deltas))
+	pipe := g.client.Pipeline()
+	for name, delta := range deltas {
+		cmds[name] = pipe.IncrBy(ctx, name, delta)
+		if g.defaultObjectTTL > 0 {
+			pipe.Eval(ctx, applyTTLScript, []string{name}, g.defaultObjectTTL.Milliseconds())
+		}
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, err
+	}
+	values := make(map[string]int64, len(cmds))
+	for name, cmd := range cmds {
+		values[name] = cmd.Val()
+	}
+	return values, nil
 }
@@ -1,6 +1,9 @@
 package redisson
 
 import (
+	"context"
+	"errors"
+	"sync"
 	"testing"
 )
 
@@ -97,7 +100,10 @@ func TestRedissonAtomicLongDecrementAndGet(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	v := al.DecrementAndGet()
+	v, err := al.DecrementAndGet()
+	if err != nil {
+		t.Fatal(err)
+	}
 	if v != 18 {
 		t.Fatalf("v=%v", v)
 	}
@@ -112,7 +118,9 @@ func TestRedissonAtomicLongDecrementAndGet(t *testing.T) {
 
 func TestRedissonAtomicLongIncrementAndGet(t *testing.T) {
 	al := GetRedisson().GetAtomicLong("test8")
-	if al.IncrementAndGet() != 1 {
+	if v, err := al.IncrementAndGet(); err != nil {
+		t.Fatal(err)
+	} else if v != 1 {
 		t.FailNow()
 	}
 	if v, err := al.Get(); err != nil {
@@ -197,3 +205,111 @@ func TestRedissonAtomicLong(t *testing.T) {
 	}
 
 }
+
+func TestRedissonAtomicLongAddAndGetAll(t *testing.T) {
+	g := GetRedisson()
+	if err := g.GetAtomicLong("longtest11").Set(5); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.GetAtomicLong("longtest12").Set(0); err != nil {
+		t.Fatal(err)
+	}
+	values, err := g.AddAndGetAll(map[string]int64{"longtest11": 2, "longtest12": -3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if values["longtest11"] != 7 || values["longtest12"] != -3 {
+		t.Fatalf("values=%v", values)
+	}
+	if v, err := g.GetAtomicLong("longtest11").Get(); err != nil {
+		t.Fatal(err)
+	} else if v != 7 {
+		t.Fatalf("v=%v", v)
+	}
+}
+
+func TestRedissonAtomicLongContext(t *testing.T) {
+	al := GetRedisson().GetAtomicLong("longtest13")
+	if err := al.SetContext(context.Background(), 3); err != nil {
+		t.Fatal(err)
+	}
+	if v, err := al.AddAndGetContext(context.Background(), 2); err != nil || v != 5 {
+		t.Fatalf("v=%v err=%v", v, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := al.IncrementAndGetContext(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("err=%v", err)
+	}
+	if v, err := al.Get(); err != nil || v != 5 {
+		t.Fatalf("v=%v err=%v", v, err)
+	}
+}
+
+func TestRedissonAtomicLongCompareAndSetFormatting(t *testing.T) {
+	g := GetRedisson()
+	al := g.GetAtomicLong("longtest14")
+	for _, stored := range []string{"07", "7.0", "+7", " 7"} {
+		if err := g.client.Set(context.Background(), "longtest14", stored, 0).Err(); err != nil {
+			t.Fatal(err)
+		}
+		if ok, err := al.CompareAndSet(7, 8); err != nil || !ok {
+			t.Fatalf("stored=%q ok=%v err=%v", stored, ok, err)
+		}
+		if v, err := al.Get(); err != nil || v != 8 {
+			t.Fatalf("stored=%q v=%v err=%v", stored, v, err)
+		}
+	}
+
+	if ok, err := al.CompareAndSet(7, 9); err != nil || ok {
+		t.Fatalf("ok=%v err=%v", ok, err)
+	}
+
+	// int64 values beyond the precision of doubles only match exactly
+	if err := al.Set(1<<53 + 1); err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := al.CompareAndSet(1<<53, 1); err != nil || ok {
+		t.Fatalf("ok=%v err=%v", ok, err)
+	}
+	if ok, err := al.CompareAndSet(1<<53+1, 1); err != nil || !ok {
+		t.Fatalf("ok=%v err=%v", ok, err)
+	}
+}
+
+func TestRedissonAtomicLongUpdateAndGet(t *testing.T) {
+	al := GetRedisson().GetAtomicLong("longtest15")
+	if err := al.Set(3); err != nil {
+		t.Fatal(err)
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 4; j++ {
+				if _, err := al.UpdateAndGet(func(v int64) int64 { return v * 2 }); err != nil {
+					t.Error(err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if v, err := al.Get(); err != nil || v != 3<<20 {
+		t.Fatalf("v=%v err=%v", v, err)
+	}
+
+	larger := func(a, b int64) int64 {
+		if a > b {
+			return a
+		}
+		return b
+	}
+	if v, err := al.AccumulateAndGet(1, larger); err != nil || v != 3<<20 {
+		t.Fatalf("v=%v err=%v", v, err)
+	}
+	if v, err := al.AccumulateAndGet(1<<30, larger); err != nil || v != 1<<30 {
+		t.Fatalf("v=%v err=%v", v, err)
+	}
+}
@@ -0,0 +1,195 @@
+package redisson
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// AtomicReference holds a value of type T encoded with the codec of the object, which is swapped atomically.
+// An empty reference holds the zero value of T.
+type AtomicReference[T any] interface {
+	RExpirable
+	// Get returns the value, or the zero value of T if the reference is empty.
+	Get() (T, error)
+	GetContext(ctx context.Context) (T, error)
+	// Set stores the value.
+	Set(value T) error
+	SetContext(ctx context.Context, value T) error
+	// GetAndSet stores the value and returns the previous one.
+	GetAndSet(value T) (T, error)
+	GetAndSetContext(ctx context.Context, value T) (T, error)
+	// GetAndDelete empties the reference and returns its value.
+	GetAndDelete() (T, error)
+	GetAndDeleteContext(ctx context.Context) (T, error)
+	// CompareAndSet stores update if the value equals expect and reports whether it did. The values are compared
+	// encoded, expect equal to the zero value of T also matches an empty reference.
+	CompareAndSet(expect T, update T) (bool, error)
+	CompareAndSetContext(ctx context.Context, expect T, update T) (bool, error)
+}
+
+var (
+	_ AtomicReference[string] = (*RedissonAtomicReference[string])(nil)
+)
+
+// RedissonAtomicReference is the implementation of AtomicReference
+type RedissonAtomicReference[T any] struct {
+	*RedissonExpirable
+	codec Codec
+}
+
+// newRedissonAtomicReference creates a new RedissonAtomicReference
+func newRedissonAtomicReference[T any](name string, redisson *Redisson, options *objectOptions) *RedissonAtomicReference[T] {
+	m := &RedissonAtomicReference[T]{
+		RedissonExpirable: newRedissonExpirable(name, redisson),
+		codec:             options.codec,
+	}
+	m.ttl = options.ttl
+	return m
+}
+
+// Get returns the value, or the zero value of T if the reference is empty.
+func (m *RedissonAtomicReference[T]) Get() (T, error) {
+	return m.GetContext(m.baseContext())
+}
+
+// GetContext returns the value, or the zero value of T if the reference is empty.
+func (m *RedissonAtomicReference[T]) GetContext(ctx context.Context) (T, error) {
+	ctx, cancel := m.withCommandTimeout(ctx)
+	defer cancel()
+	var zero T
+	data, err := m.client.Get(ctx, m.getRawName()).Bytes()
+	if err == redis.Nil {
+		return zero, nil
+	}
+	if err != nil {
+		return zero, err
+	}
+	return m.decode(data)
+}
+
+// Set stores the value, which expires after the TTL of the object if one is configured.
+func (m *RedissonAtomicReference[T]) Set(value T) error {
+	return m.SetContext(m.baseContext(), value)
+}
+
+// SetContext stores the value, which expires after the TTL of the object if one is configured.
+func (m *RedissonAtomicReference[T]) SetContext(ctx context.Context, value T) error {
+	ctx, cancel := m.withCommandTimeout(ctx)
+	defer cancel()
+	data, err := m.codec.Encode(value)
+	if err != nil {
+		return err
+	}
+	return m.client.Set(ctx, m.getRawName(), data, m.ttl).Err()
+}
+
+// GetAndSet stores the value and returns the previous one.
+func (m *RedissonAtomicReference[T]) GetAndSet(value T) (T, error) {
+	return m.GetAndSetContext(m.baseContext(), value)
+}
+
+// GetAndSetContext stores the value and returns the previous one.
+func (m *RedissonAtomicReference[T]) GetAndSetContext(ctx context.Context, value T) (T, error) {
+	ctx, cancel := m.withCommandTimeout(ctx)
+	defer cancel()
+	var zero T
+	data, err := m.codec.Encode(value)
+	if err != nil {
+		return zero, err
+	}
+	prev, err := m.client.GetSet(ctx, m.getRawName(), data).Bytes()
+	if err != nil && err != redis.Nil {
+		return zero, err
+	}
+	if ttlErr := m.applyTTL(ctx, m.getRawName()); ttlErr != nil {
+		return zero, ttlErr
+	}
+	if err == redis.Nil {
+		return zero, nil
+	}
+	return m.decode(prev)
+}
+
+// GetAndDelete empties the reference and returns its value.
+func (m *RedissonAtomicReference[T]) GetAndDelete() (T, error) {
+	return m.GetAndDeleteContext(m.baseContext())
+}
+
+// GetAndDeleteContext empties the reference and returns its value.
+func (m *RedissonAtomicReference[T]) GetAndDeleteContext(ctx context.Context) (T, error) {
+	ctx, cancel := m.withCommandTimeout(ctx)
+	defer cancel()
+	var zero T
+	prev, err := m.eval(ctx, "atomicReference.getAndDelete", `
+local currValue = redis.call('get', KEYS[1]);
+redis.call('del', KEYS[1]);
+return currValue;
+`, []string{m.getRawName()}).Text()
+	if err == redis.Nil {
+		return zero, nil
+	}
+	if err != nil {
+		return zero, err
+	}
+	return m.decode([]byte(prev))
+}
+
+// CompareAndSet stores update if the value equals expect and reports whether it did.
+func (m *RedissonAtomicReference[T]) CompareAndSet(expect T, update T) (bool, error) {
+	return m.CompareAndSetContext(m.baseContext(), expect, update)
+}
+
+// CompareAndSetContext stores update if the value equals expect and reports whether it did. The values are compared
+// encoded, so the codec must encode equal values to the same bytes, as JSONCodec does.
+func (m *RedissonAtomicReference[T]) CompareAndSetContext(ctx context.Context, expect T, update T) (bool, error) {
+	ctx, cancel := m.withCommandTimeout(ctx)
+	defer cancel()
+	expected, err := m.codec.Encode(expect)
+	if err != nil {
+		return false, err
+	}
+	updated, err := m.codec.Encode(update)
+	if err != nil {
+		return false, err
+	}
+	var zero T
+	zeroData, err := m.codec.Encode(zero)
+	if err != nil {
+		return false, err
+	}
+	matchEmpty := 0
+	if bytes.Equal(expected, zeroData) {
+		matchEmpty = 1
+	}
+	r, err := m.eval(ctx, "atomicReference.compareAndSet", atomicReferenceCompareAndSetScript, []string{m.getRawName()},
+		expected, updated, matchEmpty).Int()
+	if err != nil {
+		return false, err
+	}
+	if r == 1 {
+		if err = m.applyTTL(ctx, m.getRawName()); err != nil {
+			return false, err
+		}
+	}
+	return r == 1, nil
+}
+
+// decode decodes data with the codec of the reference
+func (m *RedissonAtomicReference[T]) decode(data []byte) (T, error) {
+	var v T
+	err := m.codec.Decode(data, &v)
+	return v, err
+}
+
+// atomicReferenceCompareAndSetScript sets KEYS[1] to ARGV[2] if it holds ARGV[1], or is empty and ARGV[3] is 1
+const atomicReferenceCompareAndSetScript = `
+local currValue = redis.call('get', KEYS[1]);
+if currValue == ARGV[1] or (currValue == false and ARGV[3] == '1') then
+ redis.call('set', KEYS[1], ARGV[2]);
+ return 1
+else
+ return 0
+end
+`
@@ -0,0 +1,73 @@
+package redisson
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestAtomicReference(t *testing.T) {
+	ref := GetAtomicReference[User](GetRedisson(), "testAtomicReference")
+	if _, err := ref.GetAndDelete(); err != nil {
+		t.Fatal(err)
+	}
+	if v, err := ref.Get(); err != nil {
+		t.Fatal(err)
+	} else if v != (User{}) {
+		t.Fatalf("v=%v", v)
+	}
+	// the zero value matches the empty reference
+	if ok, err := ref.CompareAndSet(User{}, User{ID: 1, Name: "Alice"}); err != nil || !ok {
+		t.Fatalf("ok=%v err=%v", ok, err)
+	}
+	if ok, err := ref.CompareAndSet(User{ID: 2, Name: "Bob"}, User{ID: 3}); err != nil || ok {
+		t.Fatalf("ok=%v err=%v", ok, err)
+	}
+	if ok, err := ref.CompareAndSet(User{ID: 1, Name: "Alice"}, User{ID: 2, Name: "Bob"}); err != nil || !ok {
+		t.Fatalf("ok=%v err=%v", ok, err)
+	}
+	if v, err := ref.GetAndSet(User{ID: 3, Name: "Carol"}); err != nil {
+		t.Fatal(err)
+	} else if v != (User{ID: 2, Name: "Bob"}) {
+		t.Fatalf("v=%v", v)
+	}
+	if v, err := ref.Get(); err != nil {
+		t.Fatal(err)
+	} else if v != (User{ID: 3, Name: "Carol"}) {
+		t.Fatalf("v=%v", v)
+	}
+}
+
+func TestAtomicReferenceConcurrentCompareAndSet(t *testing.T) {
+	ref := GetAtomicReference[int](GetRedisson(), "testAtomicReferenceConcurrent")
+	if err := ref.Set(0); err != nil {
+		t.Fatal(err)
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 10; {
+				v, err := ref.Get()
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				ok, err := ref.CompareAndSet(v, v+1)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				if ok {
+					j++
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if v, err := ref.Get(); err != nil {
+		t.Fatal(err)
+	} else if v != 100 {
+		t.Fatalf("v=%v", v)
+	}
+}
@@ -2,12 +2,16 @@ package redisson
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
+	"hash/fnv"
 	"strconv"
 	"sync"
 	"time"
 
 	"github.com/elliotchance/orderedmap/v2"
+	"github.com/redis/go-redis/v9"
 )
 
 const (
@@ -17,6 +21,51 @@ const (
 	readUnlockMessage int64 = 1
 )
 
+// unlockPublishLua defines publishUnlock(channel, message, shards, waiters, all) used by the unlock scripts.
+// It publishes the message to channel, or when the channel is sharded to the shard of the waiter registered first
+// in the waiters zset, skipping the shards nobody listens to any more, or to the shards of all the waiters if all is true.
+const unlockPublishLua = `
+local function publishUnlock(channel, message, shards, waiters, all)
+    shards = tonumber(shards);
+    if (shards == nil or shards <= 1) then
+        redis.call('publish', channel, message);
+        return;
+    end ;
+    if (all) then
+        local published = {};
+        for _, waiter in ipairs(redis.call('zrange', waiters, 0, -1)) do
+            local shard = string.match(waiter, '^(%d+):');
+            if (published[shard] == nil) then
+                published[shard] = true;
+                redis.call('publish', channel .. ':' .. shard, message);
+            end ;
+        end ;
+        redis.call('del', waiters);
+        return;
+    end ;
+    while (true) do
+        local first = redis.call('zrange', waiters, 0, 0);
+        if (#first == 0) then
+            return;
+        end ;
+        redis.call('zrem', waiters, first[1]);
+        local shard = string.match(first[1], '^(%d+):');
+        if (redis.call('publish', channel .. ':' .. shard, message) > 0) then
+            return;
+        end ;
+    end ;
+end ;
+`
+
+// registerWaiterLua adds the waiter to the waiters zset of a sharded unlock channel unless it is registered already,
+// keeping the zset for at least the given milliseconds
+const registerWaiterLua = `
+redis.call('zadd', KEYS[1], 'NX', ARGV[1], ARGV[2]);
+if (redis.call('pttl', KEYS[1]) < tonumber(ARGV[3])) then
+    redis.call('pexpire', KEYS[1], ARGV[3]);
+end ;
+`
+
 // expirationEntry is a struct that holds the goroutine ids that are waiting for the lock to expire
 type expirationEntry struct {
 	//mutex is used to protect the following fields
@@ -71,6 +120,25 @@ func (e *expirationEntry) hasNoThreads() bool {
 	return e.goroutineIds.Len() == 0
 }
 
+// count returns how many times goroutineId holds the lock in the expirationEntry
+func (e *expirationEntry) count(goroutineId uint64) int64 {
+	e.Lock()
+	defer e.Unlock()
+	count, _ := e.goroutineIds.Get(goroutineId)
+	return count
+}
+
+// ids returns the goroutine ids in the expirationEntry
+func (e *expirationEntry) ids() []uint64 {
+	e.Lock()
+	defer e.Unlock()
+	ids := make([]uint64, 0, e.goroutineIds.Len())
+	for el := e.goroutineIds.Front(); el != nil; el = el.Next() {
+		ids = append(ids, el.Key)
+	}
+	return ids
+}
+
 // getFirstGoroutineId returns the first goroutine id in the expirationEntry
 func (e *expirationEntry) getFirstGoroutineId() *uint64 {
 	e.Lock()
@@ -82,6 +150,144 @@ func (e *expirationEntry) getFirstGoroutineId() *uint64 {
 	return &first
 }
 
+// wakeGate coalesces the wake-ups of the waiters of one lock in this process.
+// The first limit waiters woken by a release retry immediately, the others retry
+// in groups of limit, each group stagger later than the previous one.
+type wakeGate struct {
+	sync.Mutex
+	limit   int
+	stagger time.Duration
+	//woken number of waiters woken since the release started at epoch
+	woken int
+	epoch time.Time
+	//waiters number of goroutines waiting with the gate, it is removed when the last one leaves
+	waiters int
+	removed bool
+}
+
+// enterWakeGate returns the wakeGate of the lock named name for a waiter, which must leave it with leaveWakeGate
+func (g *Redisson) enterWakeGate(name string, limit int, stagger time.Duration) *wakeGate {
+	for {
+		v, _ := g.wakeGates.LoadOrStore(name, &wakeGate{limit: limit, stagger: stagger})
+		gate := v.(*wakeGate)
+		gate.Lock()
+		// the last waiter left the gate while it was loaded, retry with a new one
+		if gate.removed {
+			gate.Unlock()
+			continue
+		}
+		gate.waiters++
+		gate.Unlock()
+		return gate
+	}
+}
+
+// leaveWakeGate removes a waiter from the gate of the lock named name, and the gate once it has no waiter
+func (g *Redisson) leaveWakeGate(name string, gate *wakeGate) {
+	gate.Lock()
+	defer gate.Unlock()
+	gate.waiters--
+	if gate.waiters == 0 {
+		gate.removed = true
+		g.wakeGates.CompareAndDelete(name, gate)
+	}
+}
+
+// delay returns how long a waiter woken at now should wait before retrying the lock
+func (w *wakeGate) delay(now time.Time) time.Duration {
+	w.Lock()
+	defer w.Unlock()
+	// notifications of one release arrive almost together, a later one starts a new release
+	if now.Sub(w.epoch) > w.stagger {
+		w.epoch = now
+		w.woken = 0
+	}
+	group := w.woken / w.limit
+	w.woken++
+	return time.Duration(group) * w.stagger
+}
+
+// adaptiveLease tunes the lease of a lock from the hold times observed in this process.
+// The lease is twice the moving average of the hold times, bounded by min and max, so long holders
+// renew less often and the lock of a crashed short holder is reclaimed sooner.
+type adaptiveLease struct {
+	sync.Mutex
+	min time.Duration
+	max time.Duration
+	//average moving average of the hold times, 0 until a hold is observed
+	average time.Duration
+	//holds acquire times of the locks held by goroutine id
+	holds map[uint64]time.Time
+}
+
+// adaptiveLeaseWeight is the weight of the last hold time in the moving average
+const adaptiveLeaseWeight = 0.2
+
+// newAdaptiveLease creates a new adaptiveLease starting at max
+func newAdaptiveLease(min, max time.Duration) *adaptiveLease {
+	if min <= 0 || min > max {
+		min = max
+	}
+	return &adaptiveLease{
+		min:   min,
+		max:   max,
+		holds: make(map[uint64]time.Time),
+	}
+}
+
+// get returns the lease the lock should be acquired and renewed with
+func (a *adaptiveLease) get() time.Duration {
+	a.Lock()
+	defer a.Unlock()
+	if a.average == 0 {
+		return a.max
+	}
+	lease := 2 * a.average
+	if lease < a.min {
+		return a.min
+	}
+	if lease > a.max {
+		return a.max
+	}
+	return lease
+}
+
+// acquired records that goroutineId acquired the lock at now, reentrant acquisitions keep the first time
+func (a *adaptiveLease) acquired(goroutineId uint64, now time.Time) {
+	a.Lock()
+	defer a.Unlock()
+	if _, ok := a.holds[goroutineId]; !ok {
+		a.holds[goroutineId] = now
+	}
+}
+
+// transfer moves the acquire time of the lock held by from to to
+func (a *adaptiveLease) transfer(from, to uint64) {
+	a.Lock()
+	defer a.Unlock()
+	if start, ok := a.holds[from]; ok {
+		delete(a.holds, from)
+		a.holds[to] = start
+	}
+}
+
+// released records that goroutineId released the lock at now and adds the hold time to the average
+func (a *adaptiveLease) released(goroutineId uint64, now time.Time) {
+	a.Lock()
+	defer a.Unlock()
+	start, ok := a.holds[goroutineId]
+	if !ok {
+		return
+	}
+	delete(a.holds, goroutineId)
+	hold := now.Sub(start)
+	if a.average == 0 {
+		a.average = hold
+		return
+	}
+	a.average = time.Duration(adaptiveLeaseWeight*float64(hold) + (1-adaptiveLeaseWeight)*float64(a.average))
+}
+
 // RedissonBaseLock is the base lock struct
 type RedissonBaseLock struct {
 	*RedissonExpirable
@@ -90,23 +296,101 @@ type RedissonBaseLock struct {
 	//internalLockLeaseTime is the internal lock lease time
 	//when the lock is acquired, the expiration is set to this value
 	internalLockLeaseTime time.Duration
-	id                    string
-	entryName             string
-	lock                  innerLocker
+	//leaseTime is the lease time of the lock set by WithLease, 0 to renew it with the watchdog
+	leaseTime time.Duration
+	//adaptiveLease tunes the internal lease from observed hold times, nil unless WithAdaptiveLease is set
+	adaptiveLease *adaptiveLease
+	//retryStrategy decides the wait between the attempts of the waiters
+	retryStrategy RetryStrategy
+	//stats metrics of the locks of the same name in this instance, nil unless WithLockStats is set
+	stats     *lockStats
+	id        string
+	entryName string
+	lock      innerLocker
 }
 
 // newBaseLock creates a new RedissonBaseLock
-func newBaseLock(key, name string, redisson *Redisson, locker innerLocker) *RedissonBaseLock {
+func newBaseLock(key, name string, redisson *Redisson, locker innerLocker, opts ...ObjectOption) *RedissonBaseLock {
 	baseLock := &RedissonBaseLock{
 		RedissonExpirable:     newRedissonExpirable(name, redisson),
 		internalLockLeaseTime: redisson.watchDogTimeout,
 		id:                    key,
 		lock:                  locker,
+		retryStrategy:         TTLRetry,
+	}
+	options := redisson.newObjectOptions(opts)
+	if options.lockStats {
+		baseLock.stats = redisson.getLockStats(name)
+	}
+	if options.retryStrategy != nil {
+		baseLock.retryStrategy = options.retryStrategy
+	}
+	baseLock.leaseTime = options.lease
+	if options.adaptiveLeaseMax > 0 {
+		baseLock.adaptiveLease = newAdaptiveLease(options.adaptiveLeaseMin, options.adaptiveLeaseMax)
 	}
 	baseLock.entryName = baseLock.id + ":" + name
 	return baseLock
 }
 
+// getLockLeaseTime returns the lease the lock is acquired and renewed with by the watchdog
+func (m *RedissonBaseLock) getLockLeaseTime() time.Duration {
+	if m.adaptiveLease != nil {
+		return m.adaptiveLease.get()
+	}
+	return m.internalLockLeaseTime
+}
+
+// recordAcquired records that goroutineId acquired the lock after waiting wait and failing retries attempts
+func (m *RedissonBaseLock) recordAcquired(goroutineId uint64, wait time.Duration, retries int) {
+	if m.stats != nil {
+		m.stats.acquired(wait, retries)
+	}
+	if m.lockObserver != nil {
+		m.lockObserver.OnAcquire(m.getRawName(), wait, retries)
+	}
+}
+
+// recordReleased records that the lock was released by its last hold after being held for hold
+func (m *RedissonBaseLock) recordReleased(hold time.Duration) {
+	if m.stats != nil {
+		m.stats.released(hold)
+	}
+	if m.lockObserver != nil {
+		m.lockObserver.OnRelease(m.getRawName(), hold)
+	}
+}
+
+// recordRenewed records a renewal of the lock by the watchdog
+func (m *RedissonBaseLock) recordRenewed(err error) {
+	if m.stats != nil {
+		m.stats.renewed(err)
+	}
+	if m.lockObserver != nil {
+		m.lockObserver.OnRenew(m.getRawName(), err)
+	}
+}
+
+// renewalFailed removes the holds renewed by ee from the registry of the instance and notifies the renewal failure
+// handler of the instance that the watchdog stopped renewing the lock
+func (m *RedissonBaseLock) renewalFailed(ee *expirationEntry, err error) {
+	for _, goroutineId := range ee.ids() {
+		m.forgetHolds(goroutineId)
+	}
+	if handler := m.renewalFailureHandler.Load(); handler != nil {
+		(*handler)(m.getRawName(), err)
+	}
+}
+
+// LockStats returns the metrics of the locks of the same name created WithLockStats and acquired by this instance,
+// zero if the lock was created without it.
+func (m *RedissonBaseLock) LockStats() LockStats {
+	if m.stats == nil {
+		return LockStats{}
+	}
+	return m.stats.snapshot()
+}
+
 // getLockName returns the lock name
 func (m *RedissonBaseLock) getLockName(goroutineId uint64) string {
 	return m.id + ":" + strconv.FormatUint(goroutineId, 10)
@@ -117,14 +401,88 @@ func (m *RedissonBaseLock) getEntryName() string {
 	return m.entryName
 }
 
-// tryAcquire tries to acquire the lock
-func (m *RedissonBaseLock) tryAcquire(ctx context.Context, goroutineId uint64) (*int64, error) {
-	ttl, err := m.lock.tryLockInner(ctx, m.internalLockLeaseTime, goroutineId)
+// getWaitShard returns the shard of the unlock channel the goroutine waits on, chosen by the hash of its lock name
+func (m *RedissonBaseLock) getWaitShard(goroutineId uint64) string {
+	h := fnv.New32a()
+	h.Write([]byte(m.getLockName(goroutineId)))
+	return strconv.FormatUint(uint64(h.Sum32()%uint32(m.lockChannelShards)), 10)
+}
+
+// getWaitChannelName returns the channel the goroutine waits on for unlock notifications.
+// When the unlock channel is sharded, waiters are spread over the shards by the hash of their lock name.
+func (m *RedissonBaseLock) getWaitChannelName(goroutineId uint64) string {
+	if m.lockChannelShards <= 1 {
+		return m.lock.getChannelName()
+	}
+	return m.lock.getChannelName() + ":" + m.getWaitShard(goroutineId)
+}
+
+// getWaitersName returns the name of the zset of the waiters of a sharded unlock channel, scored by the time they
+// started waiting, each member being the shard of the waiter and its lock name
+func (m *RedissonBaseLock) getWaitersName() string {
+	return m.suffixName(m.lock.getChannelName(), "waiters")
+}
+
+// registerWaiter registers the goroutine waiting since start in the waiters zset, so that an unlock publishes to its
+// shard, for at least ttl and the lease of the lock
+func (m *RedissonBaseLock) registerWaiter(ctx context.Context, goroutineId uint64, start time.Time, ttl time.Duration) error {
+	ctx, cancel := m.withCommandTimeout(ctx)
+	defer cancel()
+	err := m.eval(ctx, "lock.registerWaiter", registerWaiterLua, []string{m.getWaitersName()},
+		start.UnixMilli(), m.getWaitShard(goroutineId)+":"+m.getLockName(goroutineId), (ttl + m.getLockLeaseTime()).Milliseconds()).Err()
+	if err == redis.Nil {
+		return nil
+	}
+	return err
+}
+
+// unregisterWaiter removes the goroutine from the waiters zset
+func (m *RedissonBaseLock) unregisterWaiter(ctx context.Context, goroutineId uint64) {
+	ctx, cancel := m.withCommandTimeout(ctx)
+	defer cancel()
+	m.client.ZRem(ctx, m.getWaitersName(), m.getWaitShard(goroutineId)+":"+m.getLockName(goroutineId))
+}
+
+// wakeDelay returns how long the waiter should wait after an unlock notification before retrying,
+// 0 if wake-ups are not coalesced, in which case gate is nil.
+func (m *RedissonBaseLock) wakeDelay(gate *wakeGate) time.Duration {
+	if gate == nil {
+		return 0
+	}
+	return gate.delay(m.clock.Now())
+}
+
+// tryAcquire tries to acquire the lock, for leaseTime if positive, else for the lease the lock was created with
+func (m *RedissonBaseLock) tryAcquire(ctx context.Context, goroutineId uint64, leaseTime time.Duration) (*int64, error) {
+	ctx, cancel := m.withCommandTimeout(ctx)
+	defer cancel()
+	if leaseTime <= 0 {
+		leaseTime = m.leaseTime
+	}
+	withLease := leaseTime > 0
+	if !withLease {
+		leaseTime = m.getLockLeaseTime()
+	}
+	if !withLease && m.reenterLocally(goroutineId) {
+		return nil, nil
+	}
+	ttl, err := m.lock.tryLockInner(ctx, leaseTime, goroutineId)
 	if err != nil {
 		return nil, err
 	}
-	// lock acquired
 	if ttl == nil {
+		// a hold renewed by the watchdog does not expire
+		holdLease := leaseTime
+		if !withLease {
+			holdLease = 0
+		}
+		m.registerHold(goroutineId, 1, holdLease)
+	}
+	if ttl == nil && m.adaptiveLease != nil {
+		m.adaptiveLease.acquired(goroutineId, m.clock.Now())
+	}
+	// lock acquired, a lock with a lease expires instead of being renewed
+	if ttl == nil && !withLease {
 		m.scheduleExpirationRenewal(goroutineId)
 	}
 	return ttl, nil
@@ -149,13 +507,18 @@ func (m *RedissonBaseLock) renewExpiration() {
 	if !ok {
 		return
 	}
-	ti := time.NewTimer(m.internalLockLeaseTime / 3)
+	if renewer, ok := m.lock.(batchRenewer); ok && m.renewalBatcher != nil {
+		m.renewExpirationBatched(entryName, ee.(*expirationEntry), renewer)
+		return
+	}
+	renewAfter := m.clock.After(m.getLockLeaseTime() / 3)
 
-	ctx, cancel := context.WithCancel(context.Background())
+	// the watchdog outlives the call which acquired the lock, keep the values of the base context but not its cancellation
+	ctx, cancel := context.WithCancel(context.WithoutCancel(m.baseContext()))
 
 	go func(ctx context.Context) {
 		select {
-		case <-ti.C:
+		case <-renewAfter:
 			ent, ok := m.ExpirationRenewalMap.Load(entryName)
 			if !ok {
 				return
@@ -165,8 +528,12 @@ func (m *RedissonBaseLock) renewExpiration() {
 				return
 			}
 			res, err := m.lock.renewExpirationInner(ctx, *goroutineId)
+			if err != nil || res != 0 {
+				m.recordRenewed(err)
+			}
 			if err != nil {
 				m.ExpirationRenewalMap.Delete(entryName)
+				m.renewalFailed(ent.(*expirationEntry), err)
 				return
 			}
 			if res != 0 {
@@ -174,6 +541,7 @@ func (m *RedissonBaseLock) renewExpiration() {
 				return
 			}
 			m.cancelExpirationRenewal(0)
+			m.renewalFailed(ent.(*expirationEntry), ErrLockLost)
 			return
 		case <-ctx.Done():
 			return
@@ -184,6 +552,35 @@ func (m *RedissonBaseLock) renewExpiration() {
 	ee.(*expirationEntry).Unlock()
 }
 
+// renewExpirationBatched hands the renewal of the lock over to the renewalBatcher of the instance
+func (m *RedissonBaseLock) renewExpirationBatched(entryName string, ee *expirationEntry, renewer batchRenewer) {
+	remove := m.renewalBatcher.add(&renewalTask{
+		key: m.getRawName(),
+		spec: func() (int, string, bool) {
+			goroutineId := ee.getFirstGoroutineId()
+			if goroutineId == nil {
+				return 0, "", false
+			}
+			kind, holder := renewer.renewSpec(*goroutineId)
+			return kind, holder, true
+		},
+		lease:   m.getLockLeaseTime,
+		renewed: m.recordRenewed,
+		done: func(err error) {
+			if err != nil {
+				m.ExpirationRenewalMap.Delete(entryName)
+				m.renewalFailed(ee, err)
+				return
+			}
+			m.cancelExpirationRenewal(0)
+			m.renewalFailed(ee, ErrLockLost)
+		},
+	})
+	ee.Lock()
+	ee.cancelFunc = remove
+	ee.Unlock()
+}
+
 // cancelExpirationRenewal cancels the expiration renewal
 func (m *RedissonBaseLock) cancelExpirationRenewal(goroutineId uint64) {
 	entry, ok := m.ExpirationRenewalMap.Load(m.getEntryName())
@@ -206,53 +603,240 @@ func (m *RedissonBaseLock) cancelExpirationRenewal(goroutineId uint64) {
 }
 
 // Lock locks m. Lock returns when locking is successful or when an exception is encountered.
-// use context.Background() to block until the lock is obtained
+// it blocks until the lock is obtained
 func (m *RedissonBaseLock) Lock() error {
-	return m.LockContext(context.Background())
+	return m.LockContext(m.baseContext())
+}
+
+// LockWithLease locks m for leaseTime, after which the lock expires instead of being renewed by the watchdog.
+// it blocks until the lock is obtained
+func (m *RedissonBaseLock) LockWithLease(leaseTime time.Duration) error {
+	return m.lockContext(m.baseContext(), leaseTime)
 }
 
 // LockContext locks m. Lock Returns when locking is successful or when the context timeout or an exception is encountered.
 func (m *RedissonBaseLock) LockContext(ctx context.Context) error {
+	return m.lockContext(ctx, 0)
+}
+
+// TryLock locks m if it is free and reports whether it was locked, without waiting.
+func (m *RedissonBaseLock) TryLock() (bool, error) {
+	return m.tryLock(m.baseContext(), 0)
+}
+
+// TryLockOnce makes a single attempt to lock m, without subscribing to its channel, and reports whether it was locked.
+// A done ctx fails before the attempt is sent, the attempt itself is only bounded by the command timeout since one
+// cancelled after locking would leave m held without the watchdog renewing it.
+func (m *RedissonBaseLock) TryLockOnce(ctx context.Context) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+	return m.tryLock(context.WithoutCancel(ctx), 0)
+}
+
+// TryLockWithTimeout waits up to waitTime for m and reports whether it was locked.
+// A positive leaseTime makes the lock expire after leaseTime instead of being renewed by the watchdog.
+func (m *RedissonBaseLock) TryLockWithTimeout(waitTime, leaseTime time.Duration) (bool, error) {
+	return m.tryLockWithTimeout(m.lockContext, waitTime, leaseTime)
+}
+
+// tryLock makes one attempt to lock m for leaseTime if positive, else for the lease the lock was created with
+func (m *RedissonBaseLock) tryLock(ctx context.Context, leaseTime time.Duration) (bool, error) {
 	goroutineId, err := getId()
 	if err != nil {
+		return false, err
+	}
+	ttl, err := m.tryAcquire(ctx, goroutineId, leaseTime)
+	if err != nil {
+		return false, err
+	}
+	if ttl == nil {
+		m.recordAcquired(goroutineId, 0, 0)
+	}
+	return ttl == nil, nil
+}
+
+// tryLockWithTimeout waits up to waitTime for m with lockContext and reports whether it was locked
+func (m *RedissonBaseLock) tryLockWithTimeout(lockContext func(context.Context, time.Duration) error, waitTime, leaseTime time.Duration) (bool, error) {
+	if waitTime <= 0 {
+		return m.tryLock(m.baseContext(), leaseTime)
+	}
+	ctx, cancel := context.WithTimeout(m.baseContext(), waitTime)
+	defer cancel()
+	return lockResult(ctx, lockContext(ctx, leaseTime))
+}
+
+// TryLockContext waits for m until ctx is done and reports whether it was locked,
+// a done ctx is not an error. The subscription of the wait is released before it returns.
+func (m *RedissonBaseLock) TryLockContext(ctx context.Context) (bool, error) {
+	return lockResult(ctx, m.lock.(Lock).LockContext(ctx))
+}
+
+// lockResult turns the error of a wait for a lock ending with ctx into whether the lock was acquired
+func lockResult(ctx context.Context, err error) (bool, error) {
+	// the wait may also end while subscribing, failing the command
+	if err == ErrObtainLockTimeout || (err != nil && ctx.Err() != nil) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// LockContextWithOwner locks m on behalf of owner instead of the calling goroutine, so that any goroutine
+// can unlock it with UnlockContextWithOwner and the same owner. Locking again with the same owner is reentrant.
+func (m *RedissonBaseLock) LockContextWithOwner(ctx context.Context, owner string) error {
+	return m.lockContextAs(ctx, getOwnerId(owner), 0)
+}
+
+// UnlockContextWithOwner unlocks m held on behalf of owner, from any goroutine.
+func (m *RedissonBaseLock) UnlockContextWithOwner(ctx context.Context, owner string) error {
+	return m.unlockContextAs(ctx, getOwnerId(owner))
+}
+
+// LockAsync locks m for the calling goroutine in the background and sends the result on the returned channel,
+// so that the wait can be selected together with other events. The lock is held by the calling goroutine.
+// A caller which stops waiting must cancel ctx: the lock is then released if it is acquired afterwards, and
+// ErrObtainLockTimeout is sent as when ctx is done during the wait.
+func (m *RedissonBaseLock) LockAsync(ctx context.Context) <-chan error {
+	return runAsync(func(goroutineId uint64) error {
+		return m.lockAbandonable(ctx, goroutineId, m.lockContextAs)
+	})
+}
+
+// lockAbandonable locks m for goroutineId with lock, and unlocks it again if ctx was done meanwhile since
+// nobody waits for the lock anymore
+func (m *RedissonBaseLock) lockAbandonable(ctx context.Context, goroutineId uint64,
+	lock func(ctx context.Context, goroutineId uint64, leaseTime time.Duration) error) error {
+	if err := lock(ctx, goroutineId, 0); err != nil {
 		return err
 	}
+	if ctx.Err() != nil {
+		if err := m.unlockContextAs(context.WithoutCancel(ctx), goroutineId); err != nil {
+			return fmt.Errorf("%w, and the lock acquired meanwhile was not released: %w", ErrObtainLockTimeout, err)
+		}
+		return ErrObtainLockTimeout
+	}
+	return nil
+}
+
+// UnlockAsync unlocks m held by the calling goroutine in the background and sends the result on the returned channel.
+func (m *RedissonBaseLock) UnlockAsync(ctx context.Context) <-chan error {
+	return runAsync(func(goroutineId uint64) error {
+		return m.unlockContextAs(ctx, goroutineId)
+	})
+}
+
+// runAsync runs fn for the calling goroutine on another goroutine and returns the channel receiving its result
+func runAsync(fn func(goroutineId uint64) error) <-chan error {
+	result := make(chan error, 1)
+	goroutineId, err := getId()
+	if err != nil {
+		result <- err
+		return result
+	}
+	go func() {
+		result <- fn(goroutineId)
+	}()
+	return result
+}
+
+// lockContext locks m for leaseTime if positive, else for the lease the lock was created with
+func (m *RedissonBaseLock) lockContext(ctx context.Context, leaseTime time.Duration) error {
+	goroutineId, err := getId()
+	if err != nil {
+		return err
+	}
+	return m.lockContextAs(ctx, goroutineId, leaseTime)
+}
+
+// lockContextAs locks m for goroutineId, which is a goroutine id or the id of an owner token
+func (m *RedissonBaseLock) lockContextAs(ctx context.Context, goroutineId uint64, leaseTime time.Duration) error {
+	// an attempt cancelled by ctx may still acquire the lock without the watchdog renewing it,
+	// so the attempts are not cancelled and ctx is only checked between them
+	attemptCtx := context.WithoutCancel(ctx)
+	// a goroutine entering again the lock it holds needs no subscription
+	if leaseTime <= 0 && m.leaseTime <= 0 && m.reenterLocally(goroutineId) {
+		m.recordAcquired(goroutineId, 0, 0)
+		return nil
+	}
+	m.addWaiter(m.getRawName(), 1)
+	defer m.addWaiter(m.getRawName(), -1)
+	var gate *wakeGate
+	if m.unlockWakeLimit > 0 {
+		gate = m.enterWakeGate(m.getRawName(), m.unlockWakeLimit, m.unlockWakeStagger)
+		defer m.leaveWakeGate(m.getRawName(), gate)
+	}
 	// PubSub
-	sub := m.client.Subscribe(ctx, m.lock.getChannelName())
-	defer sub.Close()
-	defer sub.Unsubscribe(context.TODO(), m.lock.getChannelName())
-	ttl := new(int64)
+	channelName := m.getWaitChannelName(goroutineId)
+	sub, err := m.subscriptions.subscribe(ctx, channelName)
+	if err != nil {
+		return err
+	}
+	defer m.subscriptions.unsubscribe(context.WithoutCancel(ctx), sub)
+	start := m.clock.Now()
+	var ttl *int64
 	// fire
-	// setting ttl to 0 will allow the for loop to start properly
-	*ttl = 0
+	// setting wait to 0 will allow the for loop to start properly
+	wait := time.Duration(0)
+	attempt := 0
+	// with a sharded channel an unlock only publishes to the shard of a registered waiter, the waiter registers
+	// before every attempt but the first so that an unlock missing it is seen by the attempt
+	sharded := m.lockChannelShards > 1
+	if sharded {
+		defer m.unregisterWaiter(attemptCtx, goroutineId)
+	}
 	for {
 		select {
 		// obtain lock timeout
 		case <-ctx.Done():
 			return ErrObtainLockTimeout
-		// indicates that the lock has ttl milliseconds to expire
-		// if the lock is not released within ttl milliseconds, the lock will expire
+		// no unlock notification arrived in the wait given by the retry strategy,
+		// by default the ttl after which the lock expires if it is not released
 		// we need to try to acquire the lock again
-		case <-time.After(time.Duration(*ttl) * time.Millisecond):
-			ttl, err = m.tryAcquire(ctx, goroutineId)
+		case <-m.clock.After(wait):
+			ttl, err = m.tryAcquireRegistered(attemptCtx, goroutineId, leaseTime, start, ttl)
 		// a lock has been released
 		// we need to try to acquire the lock again
-		case <-sub.Channel():
-			ttl, err = m.tryAcquire(ctx, goroutineId)
+		case <-sub.c:
+			if delay := m.wakeDelay(gate); delay > 0 {
+				select {
+				case <-ctx.Done():
+					return ErrObtainLockTimeout
+				case <-m.clock.After(delay):
+				}
+			}
+			ttl, err = m.tryAcquireRegistered(attemptCtx, goroutineId, leaseTime, start, ttl)
 		}
 		if err != nil {
 			return err
 		}
 		// lock acquired
 		if ttl == nil {
+			m.recordAcquired(goroutineId, m.clock.Now().Sub(start), attempt)
 			return nil
 		}
+		attempt++
+		if sharded && attempt == 1 {
+			// register at once, then try again
+			continue
+		}
+		wait = m.retryStrategy.Delay(attempt, time.Duration(*ttl)*time.Millisecond)
+	}
+}
+
+// tryAcquireRegistered registers the waiting goroutine when the unlock channel is sharded and the previous attempt
+// returned lastTtl, then tries to acquire the lock
+func (m *RedissonBaseLock) tryAcquireRegistered(ctx context.Context, goroutineId uint64, leaseTime time.Duration, start time.Time, lastTtl *int64) (*int64, error) {
+	if m.lockChannelShards > 1 && lastTtl != nil {
+		if err := m.registerWaiter(ctx, goroutineId, start, time.Duration(*lastTtl)*time.Millisecond); err != nil {
+			return nil, err
+		}
 	}
+	return m.tryAcquire(ctx, goroutineId, leaseTime)
 }
 
 // Unlock unlocks m. Unlock returns when unlocking is successful or when an exception is encountered.
 func (m *RedissonBaseLock) Unlock() error {
-	return m.UnlockContext(context.Background())
+	return m.UnlockContext(m.baseContext())
 }
 
 // UnlockContext unlocks m. UnlockContext Returns when unlocking is successful or when the context timeout or an exception is encountered.
@@ -261,12 +845,142 @@ func (m *RedissonBaseLock) UnlockContext(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
+	return m.unlockContextAs(ctx, goroutineId)
+}
+
+// unlockContextAs unlocks m held by goroutineId, which is a goroutine id or the id of an owner token
+func (m *RedissonBaseLock) unlockContextAs(ctx context.Context, goroutineId uint64) error {
+	// the holds entered locally are released without a round trip, the last hold releases the lock in redis
+	if m.leaveLocally(goroutineId) {
+		m.cancelExpirationRenewal(goroutineId)
+		return nil
+	}
+	ctx, cancel := m.withCommandTimeout(ctx)
+	defer cancel()
 	opStatus, err := m.lock.unlockInner(ctx, goroutineId)
 	if err != nil {
 		return err
 	}
 	if opStatus == nil {
+		// the lock expired or was released by another owner, it is no longer held
+		m.forgetHolds(goroutineId)
 		return fmt.Errorf("attempt to unlock lock, not locked by current goroutine by node id: %s goroutine-id: %d", m.id, goroutineId)
 	}
+	hold := m.unregisterHold(goroutineId)
+	if *opStatus == 1 {
+		if m.adaptiveLease != nil {
+			m.adaptiveLease.released(goroutineId, m.clock.Now())
+		}
+		m.recordReleased(hold)
+	}
+	return nil
+}
+
+// IsLocked reports whether the lock is held by any goroutine of any instance.
+func (m *RedissonBaseLock) IsLocked() (bool, error) {
+	ctx, cancel := m.newContext()
+	defer cancel()
+	return m.lock.isLockedInner(ctx)
+}
+
+// IsHeldByCurrentGoroutine reports whether the lock is held by the calling goroutine.
+func (m *RedissonBaseLock) IsHeldByCurrentGoroutine() (bool, error) {
+	count, err := m.GetHoldCount()
+	return count > 0, err
+}
+
+// GetHoldCount returns how many times the calling goroutine holds the lock, 0 if it does not.
+func (m *RedissonBaseLock) GetHoldCount() (int64, error) {
+	goroutineId, err := getId()
+	if err != nil {
+		return 0, err
+	}
+	ctx, cancel := m.newContext()
+	defer cancel()
+	count, err := m.lock.holdCountInner(ctx, goroutineId)
+	if err != nil || count == 0 {
+		return count, err
+	}
+	return count + m.localHolds(goroutineId), nil
+}
+
+// Detach hands the holds of the calling goroutine over to a new owner token and returns it.
+func (m *RedissonBaseLock) Detach() (string, error) {
+	goroutineId, err := getId()
+	if err != nil {
+		return "", err
+	}
+	tokenBytes := make([]byte, 16)
+	if _, err = rand.Read(tokenBytes); err != nil {
+		return "", err
+	}
+	token := hex.EncodeToString(tokenBytes)
+	if err = m.transfer(goroutineId, getOwnerId(token)); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// Attach takes the holds of the owner token over to the calling goroutine.
+func (m *RedissonBaseLock) Attach(token string) error {
+	goroutineId, err := getId()
+	if err != nil {
+		return err
+	}
+	return m.transfer(getOwnerId(token), goroutineId)
+}
+
+// transfer moves the holds of the lock from from to to, in redis and in the state of the instance
+func (m *RedissonBaseLock) transfer(from, to uint64) error {
+	ctx, cancel := m.newContext()
+	defer cancel()
+	holds, err := m.lock.transferInner(ctx, from, to)
+	if err != nil {
+		return err
+	}
+	if holds == 0 {
+		return ErrNotLockOwner
+	}
+	// the state of the holds lives in the Lock which acquired them, which may not be m
+	origin := m
+	if held, ok := m.heldLocks.Load(m.getHeldLockKey(from)); ok {
+		origin = held.(*heldLock).lock
+	}
+	// the holds renewed by the watchdog stay renewed, renewal moves to to before it stops for from
+	if ee, ok := origin.ExpirationRenewalMap.Load(origin.getEntryName()); ok {
+		renewed := ee.(*expirationEntry).count(from)
+		for i := int64(0); i < renewed; i++ {
+			origin.scheduleExpirationRenewal(to)
+		}
+		for i := int64(0); i < renewed; i++ {
+			origin.cancelExpirationRenewal(from)
+		}
+	}
+	// holds detached by another instance are registered by the instance attaching them
+	if !origin.moveHolds(from, to) {
+		m.registerHold(to, holds, 0)
+	}
+	if origin.adaptiveLease != nil {
+		origin.adaptiveLease.transfer(from, to)
+	}
 	return nil
 }
+
+// transferHashHold moves the holds of the field from to the field to of the hash of the lock, merging them
+// with the holds of to, and returns how many holds were moved
+func (m *RedissonBaseLock) transferHashHold(ctx context.Context, from, to string) (int64, error) {
+	return m.eval(ctx, "lock.transfer", `
+local holds = tonumber(redis.call('hget', KEYS[1], ARGV[1]));
+if (holds == nil) then
+    return 0;
+end ;
+redis.call('hdel', KEYS[1], ARGV[1]);
+redis.call('hincrby', KEYS[1], ARGV[2], holds);
+return holds;
+`, []string{m.getRawName()}, from, to).Int64()
+}
+
+// NewCondition returns the condition named name bound to the lock.
+func (m *RedissonBaseLock) NewCondition(name string) RCondition {
+	return newRedissonCondition(m.suffixName(m.getRawName(), "condition:"+name), m.Redisson, m.lock.(Lock))
+}
@@ -3,30 +3,144 @@ package redisson
 import (
 	"context"
 	"errors"
+	"fmt"
 	"github.com/bits-and-blooms/bitset"
+	"github.com/redis/go-redis/v9"
+	"io"
+	"math/bits"
 	"strconv"
+	"strings"
 )
 
+// DefaultBitSetChunkSize is the number of bytes fetched per GETRANGE when streaming a BitSet
+const DefaultBitSetChunkSize int64 = 1 << 20
+
 type BitSet interface {
 	RExpirable
 	getSigned(size int32, offset int64) (int64, error)
-	setSigned(size int32, offset int64, value int64) (int64, error)
-	incrementAndGetSigned(size int32, offset int64, increment int64) (int64, error)
+	setSigned(size int32, offset int64, value int64, overflow ...OverflowBehavior) (int64, error)
+	incrementAndGetSigned(size int32, offset int64, increment int64, overflow ...OverflowBehavior) (int64, error)
 	getUnsigned(size int32, offset int64) (int64, error)
-	setUnSigned(size int32, offset int64, value int64) (int64, error)
-	incrementAndGetUnSigned(size int32, offset int64, increment int64) (int64, error)
+	setUnSigned(size int32, offset int64, value int64, overflow ...OverflowBehavior) (int64, error)
+	incrementAndGetUnSigned(size int32, offset int64, increment int64, overflow ...OverflowBehavior) (int64, error)
 	GetByte(offset int64) (byte, error)
-	SetByte(offset int64, value byte) (byte, error)
-	incrementAndGetByte(offset int64, increment byte) (byte, error)
+	SetByte(offset int64, value byte, overflow ...OverflowBehavior) (byte, error)
+	incrementAndGetByte(offset int64, increment byte, overflow ...OverflowBehavior) (byte, error)
 	GetShort(offset int64) (int16, error)
-	SetShort(offset int64, value int16) (int16, error)
-	incrementAndGetShort(offset int64, increment int16) (int16, error)
+	SetShort(offset int64, value int16, overflow ...OverflowBehavior) (int16, error)
+	incrementAndGetShort(offset int64, increment int16, overflow ...OverflowBehavior) (int16, error)
 	GetInt32(offset int32) (int32, error)
-	SetInt32(offset int64, value int32) (int32, error)
-	incrementAndGetInt32(offset int64, increment int32) (int32, error)
+	SetInt32(offset int64, value int32, overflow ...OverflowBehavior) (int32, error)
+	incrementAndGetInt32(offset int64, increment int32, overflow ...OverflowBehavior) (int32, error)
 	GetInt64(offset int32) (int64, error)
-	SetInt64(offset int64, value int64) (int64, error)
-	incrementAndGetInt64(offset int64, increment int64) (int64, error)
+	SetInt64(offset int64, value int64, overflow ...OverflowBehavior) (int64, error)
+	incrementAndGetInt64(offset int64, increment int64, overflow ...OverflowBehavior) (int64, error)
+	Read(w io.Writer) (int64, error)
+	AsBytesChunks(ctx context.Context, chunkSize int64, fn func(chunk []byte) error) error
+	// SetBit sets the bit at bitIndex to value and returns its previous value.
+	SetBit(bitIndex int64, value bool) (bool, error)
+	// SetRange sets the bits from fromIndex inclusive to toIndex exclusive.
+	SetRange(fromIndex, toIndex int64) error
+	// GetBit returns the bit at bitIndex.
+	GetBit(bitIndex int64) (bool, error)
+	// ClearBit clears the bit at bitIndex and returns its previous value.
+	ClearBit(bitIndex int64) (bool, error)
+	// ClearAll clears all the bits by deleting the bitmap.
+	ClearAll() error
+	// Cardinality returns the number of bits set.
+	Cardinality() (int64, error)
+	// CardinalityRange returns the number of bits set from fromBit inclusive to toBit exclusive.
+	CardinalityRange(fromBit, toBit int64) (int64, error)
+	// Length returns the index of the highest bit set plus one, 0 if no bit is set.
+	Length() (int64, error)
+	// And sets the bitmap to the AND of itself and the bitmaps of the BitSets named names.
+	And(names ...string) error
+	// Or sets the bitmap to the OR of itself and the bitmaps of the BitSets named names.
+	Or(names ...string) error
+	// Xor sets the bitmap to the XOR of itself and the bitmaps of the BitSets named names.
+	Xor(names ...string) error
+	// Not inverts all the bits of the bitmap, up to its last byte.
+	Not() error
+	// ToByteArray returns the bitmap as stored by redis, bit 0 being the most significant bit of the first byte.
+	ToByteArray() ([]byte, error)
+	// AsBitSet returns the bitmap as a bitset.BitSet with the same bit indexes.
+	AsBitSet() (*bitset.BitSet, error)
+	// BitField runs ops in one BITFIELD command and returns the reply of each GET, SET and INCRBY operation.
+	BitField(ops ...BitFieldOp) ([]int64, error)
+}
+
+// OverflowBehavior is the behavior of the SET and INCRBY operations of BITFIELD which overflow the integer type.
+type OverflowBehavior string
+
+const (
+	// OverflowWrap wraps around, the default of redis.
+	OverflowWrap OverflowBehavior = "WRAP"
+	// OverflowSat saturates to the minimum or the maximum value of the type.
+	OverflowSat OverflowBehavior = "SAT"
+	// OverflowFail leaves the value unchanged, the operation replies nil.
+	OverflowFail OverflowBehavior = "FAIL"
+)
+
+var (
+	// ErrBitFieldOverflow is wrapped by the BitFieldReplyError of a SET or INCRBY operation which overflowed with OverflowFail.
+	ErrBitFieldOverflow = errors.New("bitfield operation overflowed")
+	// ErrBitFieldUnexpectedReply is wrapped by the BitFieldReplyError of a reply which is not one integer per operation.
+	ErrBitFieldUnexpectedReply = errors.New("unexpected bitfield reply")
+)
+
+// BitFieldReplyError is the error of a BITFIELD command whose reply is not one integer per operation,
+// it holds the command and its raw reply.
+type BitFieldReplyError struct {
+	// Args is the BITFIELD command.
+	Args []interface{}
+	// Reply is the raw reply of redis.
+	Reply interface{}
+	// Err is ErrBitFieldOverflow or ErrBitFieldUnexpectedReply.
+	Err error
+	// Overflowed are the indexes of the replies of the operations which overflowed.
+	Overflowed []int
+}
+
+func (e *BitFieldReplyError) Error() string {
+	if e.Err == ErrBitFieldOverflow {
+		return fmt.Sprintf("%v: replies %v of %v", e.Err, e.Overflowed, e.Args)
+	}
+	return fmt.Sprintf("%v %v to %v", e.Err, e.Reply, e.Args)
+}
+
+func (e *BitFieldReplyError) Unwrap() error {
+	return e.Err
+}
+
+// BitFieldOp is an operation of BitSet.BitField, created by BitFieldGet, BitFieldSet, BitFieldIncrBy or BitFieldOverflow.
+// The type of GET, SET and INCRBY is i or u followed by the number of bits, e.g. i8 or u16, as in BITFIELD.
+type BitFieldOp struct {
+	args []interface{}
+	//reply whether the operation has a reply
+	reply bool
+	//write whether the operation writes the bitmap
+	write bool
+}
+
+// BitFieldGet returns the operation getting the integer of type typ at bit offset.
+func BitFieldGet(typ string, offset int64) BitFieldOp {
+	return BitFieldOp{args: []interface{}{"GET", typ, offset}, reply: true}
+}
+
+// BitFieldSet returns the operation setting the integer of type typ at bit offset to value, it replies the previous value.
+func BitFieldSet(typ string, offset int64, value int64) BitFieldOp {
+	return BitFieldOp{args: []interface{}{"SET", typ, offset, value}, reply: true, write: true}
+}
+
+// BitFieldIncrBy returns the operation incrementing the integer of type typ at bit offset by increment,
+// it replies the new value.
+func BitFieldIncrBy(typ string, offset int64, increment int64) BitFieldOp {
+	return BitFieldOp{args: []interface{}{"INCRBY", typ, offset, increment}, reply: true, write: true}
+}
+
+// BitFieldOverflow returns the operation setting the OverflowBehavior of the SET and INCRBY operations following it.
+func BitFieldOverflow(behavior OverflowBehavior) BitFieldOp {
+	return BitFieldOp{args: []interface{}{"OVERFLOW", string(behavior)}}
 }
 
 var (
@@ -37,210 +151,485 @@ type RedissonBitSet struct {
 	*RedissonExpirable
 }
 
-func NewRedissonBitSet(redisson *Redisson, name string) *RedissonBitSet {
-	return &RedissonBitSet{
+func NewRedissonBitSet(redisson *Redisson, name string, opts ...ObjectOption) *RedissonBitSet {
+	m := &RedissonBitSet{
 		RedissonExpirable: newRedissonExpirable(name, redisson),
 	}
+	m.ttl = redisson.newObjectOptions(opts).ttl
+	return m
 }
+
+// getSigned returns the signed integer of size bits at bit offset
 func (m *RedissonBitSet) getSigned(size int32, offset int64) (int64, error) {
 	if size > 64 {
 		return 0, errors.New("size can't be greater than 64 bits")
 	}
-	r, err := m.client.Do(context.Background(), "BITFIELD", m.getRawName(), "GET", "i"+strconv.FormatInt(int64(size), 10), offset).Result()
-	if err != nil {
-		return 0, err
-	}
-	return transResult2Int64(r)
+	return m.bitField(BitFieldGet("i"+strconv.FormatInt(int64(size), 10), offset), nil)
 }
 
-func (m *RedissonBitSet) setSigned(size int32, offset int64, value int64) (int64, error) {
+// setSigned sets the signed integer of size bits at bit offset to value and returns its previous value
+func (m *RedissonBitSet) setSigned(size int32, offset int64, value int64, overflow ...OverflowBehavior) (int64, error) {
 	if size > 64 {
 		return 0, errors.New("size can't be greater than 64 bits")
 	}
-	r, err := m.client.Do(context.Background(), "BITFIELD", m.getRawName(), "SET", "i"+strconv.FormatInt(int64(size), 10), offset, value).Result()
-	if err != nil {
-		return 0, err
-	}
-	return transResult2Int64(r)
+	return m.bitField(BitFieldSet("i"+strconv.FormatInt(int64(size), 10), offset, value), overflow)
 }
 
-func (m *RedissonBitSet) incrementAndGetSigned(size int32, offset int64, increment int64) (int64, error) {
+// incrementAndGetSigned increments the signed integer of size bits at bit offset by increment and returns its new value
+func (m *RedissonBitSet) incrementAndGetSigned(size int32, offset int64, increment int64, overflow ...OverflowBehavior) (int64, error) {
 	if size > 64 {
 		return 0, errors.New("size can't be greater than 64 bits")
 	}
-	r, err := m.client.Do(context.Background(), "BITFIELD", m.getRawName(), "INCRBY", "i"+strconv.FormatInt(int64(size), 10), offset, increment).Result()
-	if err != nil {
-		return 0, err
-	}
-	return transResult2Int64(r)
+	return m.bitField(BitFieldIncrBy("i"+strconv.FormatInt(int64(size), 10), offset, increment), overflow)
 }
 
-func transResult2Int64(v interface{}) (int64, error) {
-	switch v.(type) {
-	case []interface{}:
-		return v.([]interface{})[0].(int64), nil
-	default:
-		return 0, errors.New("can't get data from result")
+// getUnsigned returns the unsigned integer of size bits at bit offset
+func (m *RedissonBitSet) getUnsigned(size int32, offset int64) (int64, error) {
+	if size > 63 {
+		return 0, errors.New("size can't be greater than 63 bits")
 	}
+	return m.bitField(BitFieldGet("u"+strconv.FormatInt(int64(size), 10), offset), nil)
 }
 
-func (m *RedissonBitSet) getUnsigned(size int32, offset int64) (int64, error) {
+// setUnSigned sets the unsigned integer of size bits at bit offset to value and returns its previous value
+func (m *RedissonBitSet) setUnSigned(size int32, offset int64, value int64, overflow ...OverflowBehavior) (int64, error) {
 	if size > 63 {
 		return 0, errors.New("size can't be greater than 63 bits")
 	}
-	v, err := m.client.Do(context.Background(), "BITFIELD", m.getRawName(), "GET", "u"+strconv.FormatInt(int64(size), 10), offset).Result()
-	if err != nil {
-		return 0, err
-	}
-	return transResult2Int64(v)
+	return m.bitField(BitFieldSet("u"+strconv.FormatInt(int64(size), 10), offset, value), overflow)
 }
 
-func (m *RedissonBitSet) setUnSigned(size int32, offset int64, value int64) (int64, error) {
+// incrementAndGetUnSigned increments the unsigned integer of size bits at bit offset by increment and returns its new value
+func (m *RedissonBitSet) incrementAndGetUnSigned(size int32, offset int64, increment int64, overflow ...OverflowBehavior) (int64, error) {
 	if size > 63 {
-		return 0, errors.New("size can't be greater than 64 bits")
-	}
-	v, err := m.client.Do(context.Background(), "BITFIELD", m.getRawName(), "SET", "u"+strconv.FormatInt(int64(size), 10), offset, value).Result()
-	if err != nil {
-		return 0, err
+		return 0, errors.New("size can't be greater than 63 bits")
 	}
-	return transResult2Int64(v)
-
+	return m.bitField(BitFieldIncrBy("u"+strconv.FormatInt(int64(size), 10), offset, increment), overflow)
 }
 
-func (m *RedissonBitSet) incrementAndGetUnSigned(size int32, offset int64, increment int64) (int64, error) {
-	if size > 63 {
-		return 0, errors.New("size can't be greater than 64 bits")
+// bitField runs op, preceded by OVERFLOW with the first of overflow if any, and returns its reply
+func (m *RedissonBitSet) bitField(op BitFieldOp, overflow []OverflowBehavior) (int64, error) {
+	ops := make([]BitFieldOp, 0, 2)
+	if len(overflow) > 0 {
+		ops = append(ops, BitFieldOverflow(overflow[0]))
 	}
-	v, err := m.client.Do(context.Background(), "BITFIELD", m.getRawName(), "INCRBY", "u"+strconv.FormatInt(int64(size), 10), offset, increment).Result()
+	r, err := m.BitField(append(ops, op)...)
 	if err != nil {
 		return 0, err
 	}
-	return transResult2Int64(v)
+	return r[0], nil
 }
 
-func transResult2Byte(v interface{}) (byte, error) {
-	switch v.(type) {
-	case []interface{}:
-		return byte(v.([]interface{})[0].(int64)), nil
-	default:
-		return 0, errors.New("can't get data from result")
-	}
+func (m *RedissonBitSet) GetByte(offset int64) (byte, error) {
+	r, err := m.bitField(BitFieldGet("i8", offset), nil)
+	return byte(r), err
 }
 
-func (m *RedissonBitSet) GetByte(offset int64) (byte, error) {
-	r, err := m.client.Do(context.Background(), "BITFIELD", m.getRawName(), "GET", "i8", offset).Result()
-	if err != nil {
-		return byte(0), err
-	}
-	return transResult2Byte(r)
+func (m *RedissonBitSet) SetByte(offset int64, value byte, overflow ...OverflowBehavior) (byte, error) {
+	r, err := m.bitField(BitFieldSet("i8", offset, int64(value)), overflow)
+	return byte(r), err
 }
 
-func (m *RedissonBitSet) SetByte(offset int64, value byte) (byte, error) {
-	r, err := m.client.Do(context.Background(), "BITFIELD", m.getRawName(), "SET", "i8", offset, value).Result()
-	if err != nil {
-		return byte(0), err
-	}
-	return transResult2Byte(r)
+func (m *RedissonBitSet) incrementAndGetByte(offset int64, increment byte, overflow ...OverflowBehavior) (byte, error) {
+	r, err := m.bitField(BitFieldIncrBy("i8", offset, int64(increment)), overflow)
+	return byte(r), err
 }
 
-func (m *RedissonBitSet) incrementAndGetByte(offset int64, increment byte) (byte, error) {
-	r, err := m.client.Do(context.Background(), "BITFIELD", m.getRawName(), "INCRBY", "i8", offset, increment).Result()
-	if err != nil {
-		return byte(0), err
-	}
-	return transResult2Byte(r)
+func (m *RedissonBitSet) GetShort(offset int64) (int16, error) {
+	r, err := m.bitField(BitFieldGet("i16", offset), nil)
+	return int16(r), err
 }
 
-func transResult2Short(v interface{}) (int16, error) {
-	switch v.(type) {
-	case []interface{}:
-		return int16(v.([]interface{})[0].(int64)), nil
-	default:
-		return 0, errors.New("can't get data from result")
-	}
+func (m *RedissonBitSet) SetShort(offset int64, value int16, overflow ...OverflowBehavior) (int16, error) {
+	r, err := m.bitField(BitFieldSet("i16", offset, int64(value)), overflow)
+	return int16(r), err
 }
 
-func (m *RedissonBitSet) GetShort(offset int64) (int16, error) {
-	r, err := m.client.Do(context.Background(), "BITFIELD", m.getRawName(), "GET", "i16", offset).Result()
-	if err != nil {
-		return int16(0), err
+func (m *RedissonBitSet) incrementAndGetShort(offset int64, increment int16, overflow ...OverflowBehavior) (int16, error) {
+	r, err := m.bitField(BitFieldIncrBy("i16", offset, int64(increment)), overflow)
+	return int16(r), err
+}
+
+func (m *RedissonBitSet) GetInt32(offset int32) (int32, error) {
+	r, err := m.bitField(BitFieldGet("i32", int64(offset)), nil)
+	return int32(r), err
+}
+
+func (m *RedissonBitSet) SetInt32(offset int64, value int32, overflow ...OverflowBehavior) (int32, error) {
+	r, err := m.bitField(BitFieldSet("i32", offset, int64(value)), overflow)
+	return int32(r), err
+}
+
+func (m *RedissonBitSet) incrementAndGetInt32(offset int64, increment int32, overflow ...OverflowBehavior) (int32, error) {
+	r, err := m.bitField(BitFieldIncrBy("i32", offset, int64(increment)), overflow)
+	return int32(r), err
+}
+
+func (m *RedissonBitSet) GetInt64(offset int32) (int64, error) {
+	return m.bitField(BitFieldGet("i64", int64(offset)), nil)
+}
+
+func (m *RedissonBitSet) SetInt64(offset int64, value int64, overflow ...OverflowBehavior) (int64, error) {
+	return m.bitField(BitFieldSet("i64", offset, value), overflow)
+}
+
+func (m *RedissonBitSet) incrementAndGetInt64(offset int64, increment int64, overflow ...OverflowBehavior) (int64, error) {
+	return m.bitField(BitFieldIncrBy("i64", offset, increment), overflow)
+}
+
+// Set replaces the bitmap with the bits of b, with the same bit indexes.
+func (m *RedissonBitSet) Set(b bitset.BitSet) error {
+	ctx, cancel := m.newContext()
+	defer cancel()
+	return m.client.Set(ctx, m.getRawName(), bitSetToBytes(&b), m.ttl).Err()
+}
+
+// ToByteArray returns the bitmap as stored by redis, bit 0 being the most significant bit of the first byte.
+func (m *RedissonBitSet) ToByteArray() ([]byte, error) {
+	ctx, cancel := m.newContext()
+	defer cancel()
+	data, err := m.client.Get(ctx, m.getRawName()).Bytes()
+	if err == redis.Nil {
+		return []byte{}, nil
 	}
-	return transResult2Short(r)
+	return data, err
 }
 
-func (m *RedissonBitSet) SetShort(offset int64, value int16) (int16, error) {
-	r, err := m.client.Do(context.Background(), "BITFIELD", m.getRawName(), "SET", "i16", offset, value).Result()
+// AsBitSet returns the bitmap as a bitset.BitSet with the same bit indexes, its length is the number of bits stored.
+func (m *RedissonBitSet) AsBitSet() (*bitset.BitSet, error) {
+	data, err := m.ToByteArray()
 	if err != nil {
-		return int16(0), err
+		return nil, err
 	}
-	return transResult2Short(r)
+	return bytesToBitSet(data), nil
 }
 
-func (m *RedissonBitSet) incrementAndGetShort(offset int64, increment int16) (int16, error) {
-	r, err := m.client.Do(context.Background(), "BITFIELD", m.getRawName(), "INCRBY", "i16", offset, increment).Result()
+// BitField runs ops in one BITFIELD command and returns the reply of each GET, SET and INCRBY operation, in order.
+// The reply of an operation which overflowed with OverflowFail is 0 and a *BitFieldReplyError wrapping
+// ErrBitFieldOverflow is returned with the replies.
+func (m *RedissonBitSet) BitField(ops ...BitFieldOp) ([]int64, error) {
+	args := []interface{}{"BITFIELD", m.getRawName()}
+	replies, write := 0, false
+	for _, op := range ops {
+		args = append(args, op.args...)
+		if op.reply {
+			replies++
+		}
+		write = write || op.write
+	}
+	if replies == 0 {
+		return []int64{}, nil
+	}
+	ctx, cancel := m.newContext()
+	defer cancel()
+	reply, err := m.client.Do(ctx, args...).Result()
 	if err != nil {
-		return int16(0), err
+		return nil, err
+	}
+	if write {
+		if err = m.applyTTL(ctx, m.getRawName()); err != nil {
+			return nil, err
+		}
+	}
+	r, ok := reply.([]interface{})
+	if !ok || len(r) != replies {
+		return nil, &BitFieldReplyError{Args: args, Reply: reply, Err: ErrBitFieldUnexpectedReply}
 	}
-	return transResult2Short(r)
+	result := make([]int64, len(r))
+	var overflowed []int
+	for i, v := range r {
+		switch v := v.(type) {
+		case int64:
+			result[i] = v
+		case nil:
+			overflowed = append(overflowed, i)
+		default:
+			return nil, &BitFieldReplyError{Args: args, Reply: reply, Err: ErrBitFieldUnexpectedReply}
+		}
+	}
+	if len(overflowed) > 0 {
+		return result, &BitFieldReplyError{Args: args, Reply: reply, Err: ErrBitFieldOverflow, Overflowed: overflowed}
+	}
+	return result, nil
 }
 
-func transResult2Int32(v interface{}) (int32, error) {
-	switch v.(type) {
-	case []interface{}:
-		return int32(v.([]interface{})[0].(int64)), nil
-	default:
-		return 0, errors.New("can't get data from result")
+// bitSetToBytes converts b to a redis bitmap. bitset.BitSet stores bit i as the bit i%64, counted from the least
+// significant bit, of the word i/64, while redis stores it as the bit i%8, counted from the most significant bit,
+// of the byte i/8.
+func bitSetToBytes(b *bitset.BitSet) []byte {
+	data := make([]byte, (b.Len()+7)/8)
+	for i, word := range b.Bytes() {
+		for j := 0; j < 8 && i*8+j < len(data); j++ {
+			data[i*8+j] = bits.Reverse8(byte(word >> (8 * j)))
+		}
 	}
+	return data
 }
 
-func (m *RedissonBitSet) GetInt32(offset int32) (int32, error) {
-	r, err := m.client.Do(context.Background(), "BITFIELD", m.getRawName(), "GET", "i32", offset).Result()
-	if err != nil {
-		return int32(0), err
+// bytesToBitSet converts a redis bitmap to a bitset.BitSet, see bitSetToBytes
+func bytesToBitSet(data []byte) *bitset.BitSet {
+	words := make([]uint64, (len(data)+7)/8)
+	for i, v := range data {
+		words[i/8] |= uint64(bits.Reverse8(v)) << (8 * (i % 8))
 	}
-	return transResult2Int32(r)
+	return bitset.FromWithLength(uint(len(data))*8, words)
 }
 
-func (m *RedissonBitSet) SetInt32(offset int64, value int32) (int32, error) {
-	r, err := m.client.Do(context.Background(), "BITFIELD", m.getRawName(), "SET", "i32", offset, value).Result()
+// Read streams the underlying bitmap into w in DefaultBitSetChunkSize pages and returns the number of bytes written.
+// The command timeout bounds each page, not the whole read.
+func (m *RedissonBitSet) Read(w io.Writer) (int64, error) {
+	var written int64
+	err := m.AsBytesChunks(m.baseContext(), DefaultBitSetChunkSize, func(chunk []byte) error {
+		n, err := w.Write(chunk)
+		written += int64(n)
+		return err
+	})
+	return written, err
+}
+
+// AsBytesChunks retrieves the underlying bitmap via GETRANGE pages of chunkSize bytes and passes each page to fn.
+// The chunk slice must not be retained by fn. Iteration stops at the first error returned by fn.
+// The command timeout bounds each GETRANGE, ctx the whole iteration.
+func (m *RedissonBitSet) AsBytesChunks(ctx context.Context, chunkSize int64, fn func(chunk []byte) error) error {
+	if chunkSize <= 0 {
+		return errors.New("chunkSize must be greater than 0")
+	}
+	cmdCtx, cancel := m.withCommandTimeout(ctx)
+	length, err := m.client.StrLen(cmdCtx, m.getRawName()).Result()
+	cancel()
 	if err != nil {
-		return int32(0), err
+		return err
+	}
+	for start := int64(0); start < length; start += chunkSize {
+		end := start + chunkSize - 1
+		if end >= length {
+			end = length - 1
+		}
+		cmdCtx, cancel := m.withCommandTimeout(ctx)
+		chunk, err := m.client.GetRange(cmdCtx, m.getRawName(), start, end).Bytes()
+		cancel()
+		if err != nil {
+			return err
+		}
+		if len(chunk) == 0 {
+			// the value has been shrunk or deleted concurrently
+			return nil
+		}
+		if err = fn(chunk); err != nil {
+			return err
+		}
 	}
-	return transResult2Int32(r)
+	return nil
 }
 
-func (m *RedissonBitSet) incrementAndGetInt32(offset int64, increment int32) (int32, error) {
-	r, err := m.client.Do(context.Background(), "BITFIELD", m.getRawName(), "INCRBY", "i32", offset, increment).Result()
+// SetBit sets the bit at bitIndex to value and returns its previous value.
+func (m *RedissonBitSet) SetBit(bitIndex int64, value bool) (bool, error) {
+	ctx, cancel := m.newContext()
+	defer cancel()
+	bit := 0
+	if value {
+		bit = 1
+	}
+	previous, err := m.client.SetBit(ctx, m.getRawName(), bitIndex, bit).Result()
 	if err != nil {
-		return int32(0), err
+		return false, err
+	}
+	if err = m.applyTTL(ctx, m.getRawName()); err != nil {
+		return false, err
 	}
-	return transResult2Int32(r)
+	return previous == 1, nil
 }
 
-func (m *RedissonBitSet) GetInt64(offset int32) (int64, error) {
-	r, err := m.client.Do(context.Background(), "BITFIELD", m.getRawName(), "GET", "i64", offset).Result()
-	if err != nil {
-		return 0, err
+// SetRange sets the bits from fromIndex inclusive to toIndex exclusive, whole bytes with one SETRANGE.
+func (m *RedissonBitSet) SetRange(fromIndex, toIndex int64) error {
+	if fromIndex < 0 || toIndex < fromIndex {
+		return errors.New("fromIndex must be positive and not greater than toIndex")
+	}
+	if fromIndex == toIndex {
+		return nil
 	}
-	return transResult2Int64(r)
+	ctx, cancel := m.newContext()
+	defer cancel()
+	if err := m.eval(ctx, "bitSet.setRange", bitSetSetRangeScript, []string{m.getRawName()}, fromIndex, toIndex, 1).Err(); err != nil && err != redis.Nil {
+		return err
+	}
+	return m.applyTTL(ctx, m.getRawName())
 }
 
-func (m *RedissonBitSet) SetInt64(offset int64, value int64) (int64, error) {
-	r, err := m.client.Do(context.Background(), "BITFIELD", m.getRawName(), "SET", "i64", offset, value).Result()
-	if err != nil {
-		return 0, err
+// GetBit returns the bit at bitIndex.
+func (m *RedissonBitSet) GetBit(bitIndex int64) (bool, error) {
+	ctx, cancel := m.newContext()
+	defer cancel()
+	bit, err := m.client.GetBit(ctx, m.getRawName(), bitIndex).Result()
+	return bit == 1, err
+}
+
+// ClearBit clears the bit at bitIndex and returns its previous value.
+func (m *RedissonBitSet) ClearBit(bitIndex int64) (bool, error) {
+	return m.SetBit(bitIndex, false)
+}
+
+// ClearAll clears all the bits by deleting the bitmap.
+func (m *RedissonBitSet) ClearAll() error {
+	ctx, cancel := m.newContext()
+	defer cancel()
+	return m.client.Del(ctx, m.getRawName()).Err()
+}
+
+// Cardinality returns the number of bits set.
+func (m *RedissonBitSet) Cardinality() (int64, error) {
+	ctx, cancel := m.newContext()
+	defer cancel()
+	return m.client.BitCount(ctx, m.getRawName(), nil).Result()
+}
+
+// CardinalityRange returns the number of bits set from fromBit inclusive to toBit exclusive, as SetRange,
+// with BITCOUNT ... BIT. Before redis 7.0, which rejects the BIT unit, the bits are counted by a script.
+func (m *RedissonBitSet) CardinalityRange(fromBit, toBit int64) (int64, error) {
+	if fromBit < 0 || toBit < fromBit {
+		return 0, errors.New("fromBit must be positive and not greater than toBit")
+	}
+	if fromBit == toBit {
+		return 0, nil
 	}
-	return transResult2Int64(r)
+	ctx, cancel := m.newContext()
+	defer cancel()
+	n, err := m.client.BitCount(ctx, m.getRawName(), &redis.BitCount{Start: fromBit, End: toBit - 1, Unit: redis.BitCountIndexBit}).Result()
+	if err != nil && strings.Contains(err.Error(), "syntax error") {
+		return m.eval(ctx, "bitSet.cardinalityRange", bitSetCardinalityRangeScript, []string{m.getRawName()}, fromBit, toBit).Int64()
+	}
+	return n, err
+}
+
+// Length returns the index of the highest bit set plus one, 0 if no bit is set.
+func (m *RedissonBitSet) Length() (int64, error) {
+	ctx, cancel := m.newContext()
+	defer cancel()
+	return m.eval(ctx, "bitSet.length", bitSetLengthScript, []string{m.getRawName()}).Int64()
+}
+
+// And sets the bitmap to the AND of itself and the bitmaps of the BitSets named names.
+// The names must be in the same hash slot as the BitSet, e.g. share its hash tag, see bitOp.
+func (m *RedissonBitSet) And(names ...string) error {
+	return m.bitOp("AND", names)
+}
+
+// Or sets the bitmap to the OR of itself and the bitmaps of the BitSets named names.
+// The names must be in the same hash slot as the BitSet, e.g. share its hash tag, see bitOp.
+func (m *RedissonBitSet) Or(names ...string) error {
+	return m.bitOp("OR", names)
+}
+
+// Xor sets the bitmap to the XOR of itself and the bitmaps of the BitSets named names.
+// The names must be in the same hash slot as the BitSet, e.g. share its hash tag, see bitOp.
+func (m *RedissonBitSet) Xor(names ...string) error {
+	return m.bitOp("XOR", names)
+}
+
+// Not inverts all the bits of the bitmap, up to its last byte.
+func (m *RedissonBitSet) Not() error {
+	return m.bitOp("NOT", nil)
 }
 
-func (m *RedissonBitSet) incrementAndGetInt64(offset int64, increment int64) (int64, error) {
-	r, err := m.client.Do(context.Background(), "BITFIELD", m.getRawName(), "INCRBY", "i64", offset, increment).Result()
+// bitOp stores the result of BITOP op over the bitmap and the bitmaps named names into the bitmap.
+// BITOP fails with CROSSSLOT on a redis cluster when its keys are in different hash slots, so the slots
+// are checked beforehand, the hash tags of the names taken into account.
+func (m *RedissonBitSet) bitOp(op string, names []string) error {
+	slot := keySlot(m.getRawName())
+	for _, name := range names {
+		if keySlot(name) != slot {
+			return fmt.Errorf("bitset %s is not in the hash slot of bitset %s, name them with the same hash tag", name, m.getRawName())
+		}
+	}
+	ctx, cancel := m.newContext()
+	defer cancel()
+	keys := append([]string{m.getRawName()}, names...)
+	var err error
+	switch op {
+	case "AND":
+		err = m.client.BitOpAnd(ctx, m.getRawName(), keys...).Err()
+	case "OR":
+		err = m.client.BitOpOr(ctx, m.getRawName(), keys...).Err()
+	case "XOR":
+		err = m.client.BitOpXor(ctx, m.getRawName(), keys...).Err()
+	case "NOT":
+		err = m.client.BitOpNot(ctx, m.getRawName(), m.getRawName()).Err()
+	}
 	if err != nil {
-		return 0, err
+		return err
 	}
-	return transResult2Int64(r)
+	return m.applyTTL(ctx, m.getRawName())
 }
 
-func (m *RedissonBitSet) Set(b bitset.BitSet) error {
-	return m.client.Do(context.Background(), "SET", m.getRawName(), b.Bytes()).Err()
-}
+// bitSetSetRangeScript sets the bits of KEYS[1] from ARGV[1] inclusive to ARGV[2] exclusive to ARGV[3],
+// the leading and trailing bits with SETBIT and the whole bytes in between with one SETRANGE
+const bitSetSetRangeScript = `
+local from = tonumber(ARGV[1]);
+local to = tonumber(ARGV[2]);
+local value = tonumber(ARGV[3]);
+while from < to and from % 8 ~= 0 do
+    redis.call('setbit', KEYS[1], from, value);
+    from = from + 1;
+end;
+local bytes = math.floor((to - from) / 8);
+if bytes > 0 then
+    local fill = '\0';
+    if value == 1 then
+        fill = '\255';
+    end;
+    redis.call('setrange', KEYS[1], from / 8, string.rep(fill, bytes));
+    from = from + bytes * 8;
+end;
+while from < to do
+    redis.call('setbit', KEYS[1], from, value);
+    from = from + 1;
+end;
+`
+
+// bitSetCardinalityRangeScript returns the number of bits of KEYS[1] set from ARGV[1] inclusive to ARGV[2] exclusive,
+// the leading and trailing bits with GETBIT and the whole bytes in between with one BITCOUNT
+const bitSetCardinalityRangeScript = `
+local from = tonumber(ARGV[1]);
+local to = tonumber(ARGV[2]);
+local count = 0;
+while from < to and from % 8 ~= 0 do
+    count = count + redis.call('getbit', KEYS[1], from);
+    from = from + 1;
+end;
+local bytes = math.floor((to - from) / 8);
+if bytes > 0 then
+    count = count + redis.call('bitcount', KEYS[1], from / 8, from / 8 + bytes - 1);
+    from = from + bytes * 8;
+end;
+while from < to do
+    count = count + redis.call('getbit', KEYS[1], from);
+    from = from + 1;
+end;
+return count;
+`
+
+// bitSetLengthScript returns the index of the highest bit of KEYS[1] set plus one. The last byte with a bit set is
+// found by a binary search of BITPOS over the byte ranges ending at the last byte, so that trailing zero bytes
+// are not read one by one, and only that byte is inspected
+const bitSetLengthScript = `
+local hi = redis.call('strlen', KEYS[1]) - 1;
+if hi < 0 or redis.call('bitpos', KEYS[1], 1, 0, hi) == -1 then
+    return 0;
+end;
+local lo = 0;
+while lo < hi do
+    local mid = math.floor((lo + hi + 1) / 2);
+    if redis.call('bitpos', KEYS[1], 1, mid, hi) ~= -1 then
+        lo = mid;
+    else
+        hi = mid - 1;
+    end;
+end;
+local b = string.byte(redis.call('getrange', KEYS[1], lo, lo));
+local index = lo * 8 + 7;
+while b % 2 == 0 do
+    b = b / 2;
+    index = index - 1;
+end;
+return index + 1;
+`
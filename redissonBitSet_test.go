@@ -1,6 +1,14 @@
 package redisson
 
-import "testing"
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/bits-and-blooms/bitset"
+	"io"
+	"testing"
+	"time"
+)
 
 func TestSetUnsigned(t *testing.T) {
 	bs := GetRedisson().GetBitSet("testUnsigned1")
@@ -120,3 +128,301 @@ func TestSetGetNumber(t *testing.T) {
 	}
 
 }
+
+func TestBitSetAsBytesChunks(t *testing.T) {
+	bs := GetRedisson().GetBitSet("testbitsetchunks")
+	for i := int64(0); i < 10; i++ {
+		if _, err := bs.SetByte(i*8, byte(i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var chunks [][]byte
+	err := bs.AsBytesChunks(context.Background(), 3, func(chunk []byte) error {
+		chunks = append(chunks, append([]byte(nil), chunk...))
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(chunks) != 4 || len(chunks[3]) != 1 {
+		t.Fatalf("chunks=%v", chunks)
+	}
+
+	var buf bytes.Buffer
+	if n, err := bs.Read(&buf); err != nil {
+		t.Fatal(err)
+	} else if n != 10 {
+		t.Fatalf("n=%v", n)
+	}
+	for i, b := range buf.Bytes() {
+		if b != byte(i) {
+			t.Fatalf("buf=%v", buf.Bytes())
+		}
+	}
+}
+
+// slowWriter is an io.Writer taking delay per write
+type slowWriter struct {
+	io.Writer
+	delay time.Duration
+}
+
+func (w slowWriter) Write(p []byte) (int, error) {
+	time.Sleep(w.delay)
+	return w.Writer.Write(p)
+}
+
+// TestBitSetReadCommandTimeout test the command timeout bounds each page of Read rather than the whole read
+func TestBitSetReadCommandTimeout(t *testing.T) {
+	g := NewRedisson(GetRedisson().client, WithCommandTimeout(200*time.Millisecond))
+	bs := g.GetBitSet("testbitsetreadtimeout")
+	defer g.client.Del(context.Background(), "testbitsetreadtimeout")
+	if err := g.client.Set(context.Background(), "testbitsetreadtimeout", make([]byte, 2*DefaultBitSetChunkSize+1), 0).Err(); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	n, err := bs.Read(slowWriter{Writer: &buf, delay: 100 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2*DefaultBitSetChunkSize+1 {
+		t.Fatalf("n=%v", n)
+	}
+}
+
+func TestBitSetBits(t *testing.T) {
+	bs := GetRedisson().GetBitSet("testbitsetbits")
+	if err := bs.ClearAll(); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := bs.Length(); err != nil || n != 0 {
+		t.Fatalf("n=%v err=%v", n, err)
+	}
+	if previous, err := bs.SetBit(3, true); err != nil || previous {
+		t.Fatalf("previous=%v err=%v", previous, err)
+	}
+	if previous, err := bs.SetBit(3, true); err != nil || !previous {
+		t.Fatalf("previous=%v err=%v", previous, err)
+	}
+	if set, err := bs.GetBit(3); err != nil || !set {
+		t.Fatalf("set=%v err=%v", set, err)
+	}
+	if n, err := bs.Length(); err != nil || n != 4 {
+		t.Fatalf("n=%v err=%v", n, err)
+	}
+
+	// partial leading and trailing bytes around whole bytes
+	if err := bs.SetRange(5, 30); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := bs.Cardinality(); err != nil || n != 26 {
+		t.Fatalf("n=%v err=%v", n, err)
+	}
+	if n, err := bs.Length(); err != nil || n != 30 {
+		t.Fatalf("n=%v err=%v", n, err)
+	}
+	for _, i := range []int64{4, 30} {
+		if set, err := bs.GetBit(i); err != nil || set {
+			t.Fatalf("bit %d set=%v err=%v", i, set, err)
+		}
+	}
+
+	if previous, err := bs.ClearBit(29); err != nil || !previous {
+		t.Fatalf("previous=%v err=%v", previous, err)
+	}
+	if n, err := bs.Length(); err != nil || n != 29 {
+		t.Fatalf("n=%v err=%v", n, err)
+	}
+
+	// the high bits of a large bitmap are cleared, leaving trailing zero bytes
+	for _, i := range []int64{1 << 23, 1<<23 + 13} {
+		if _, err := bs.SetBit(i, true); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n, err := bs.Length(); err != nil || n != 1<<23+14 {
+		t.Fatalf("n=%v err=%v", n, err)
+	}
+	for _, i := range []int64{1 << 23, 1<<23 + 13} {
+		if _, err := bs.ClearBit(i); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n, err := bs.Length(); err != nil || n != 29 {
+		t.Fatalf("n=%v err=%v", n, err)
+	}
+	if err := bs.ClearAll(); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := bs.Cardinality(); err != nil || n != 0 {
+		t.Fatalf("n=%v err=%v", n, err)
+	}
+}
+
+func TestBitSetBitOp(t *testing.T) {
+	r := GetRedisson()
+	a := r.GetBitSet("{testbitop}:a")
+	b := r.GetBitSet("{testbitop}:b")
+	for _, bs := range []BitSet{a, b} {
+		if err := bs.ClearAll(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// a = 1100, b = 1010
+	for _, i := range []int64{0, 1} {
+		if _, err := a.SetBit(i, true); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, i := range []int64{0, 2} {
+		if _, err := b.SetBit(i, true); err != nil {
+			t.Fatal(err)
+		}
+	}
+	check := func(want ...int64) {
+		t.Helper()
+		for i := int64(0); i < 4; i++ {
+			set, err := a.GetBit(i)
+			if err != nil {
+				t.Fatal(err)
+			}
+			expected := false
+			for _, w := range want {
+				expected = expected || w == i
+			}
+			if set != expected {
+				t.Fatalf("bit %d set=%v want %v", i, set, want)
+			}
+		}
+	}
+
+	if err := a.Or("{testbitop}:b"); err != nil {
+		t.Fatal(err)
+	}
+	check(0, 1, 2)
+	if err := a.Xor("{testbitop}:b"); err != nil {
+		t.Fatal(err)
+	}
+	check(1)
+	if err := a.And("{testbitop}:b"); err != nil {
+		t.Fatal(err)
+	}
+	check()
+	if err := a.Not(); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := a.Cardinality(); err != nil || n != 8 {
+		t.Fatalf("n=%v err=%v", n, err)
+	}
+
+	if err := a.Or("testbitop:other"); err == nil {
+		t.Fatal("expected an error for a name in another hash slot")
+	}
+}
+
+func TestBitSetAsBitSet(t *testing.T) {
+	bs := GetRedisson().GetBitSet("testbitsetasbitset")
+	if err := bs.ClearAll(); err != nil {
+		t.Fatal(err)
+	}
+	if data, err := bs.ToByteArray(); err != nil || len(data) != 0 {
+		t.Fatalf("data=%v err=%v", data, err)
+	}
+
+	local := bitset.New(80)
+	local.Set(0).Set(9).Set(70)
+	if err := bs.(*RedissonBitSet).Set(*local); err != nil {
+		t.Fatal(err)
+	}
+	for _, i := range []int64{0, 9, 70} {
+		if set, err := bs.GetBit(i); err != nil || !set {
+			t.Fatalf("bit %d set=%v err=%v", i, set, err)
+		}
+	}
+	data, err := bs.ToByteArray()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) != 10 || data[0] != 0x80 || data[1] != 0x40 || data[8] != 0x02 {
+		t.Fatalf("data=%x", data)
+	}
+
+	if _, err = bs.SetBit(100, true); err != nil {
+		t.Fatal(err)
+	}
+	remote, err := bs.AsBitSet()
+	if err != nil {
+		t.Fatal(err)
+	}
+	local.Set(100)
+	if remote.SymmetricDifferenceCardinality(local) != 0 {
+		t.Fatalf("remote=%v local=%v", remote, local)
+	}
+}
+
+func TestBitSetBitField(t *testing.T) {
+	bs := GetRedisson().GetBitSet("testbitsetbitfield")
+	if err := bs.ClearAll(); err != nil {
+		t.Fatal(err)
+	}
+	r, err := bs.BitField(
+		BitFieldSet("u8", 0, 250),
+		BitFieldIncrBy("u8", 0, 10),
+		BitFieldOverflow(OverflowSat),
+		BitFieldIncrBy("u8", 8, 300),
+		BitFieldGet("i16", 16),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r) != 4 || r[0] != 0 || r[1] != 4 || r[2] != 255 || r[3] != 0 {
+		t.Fatalf("r=%v", r)
+	}
+
+	r, err = bs.BitField(BitFieldOverflow(OverflowFail), BitFieldIncrBy("u8", 8, 1), BitFieldGet("u8", 0))
+	if err == nil {
+		t.Fatal("expected an overflow error")
+	}
+	if len(r) != 2 || r[1] != 4 {
+		t.Fatalf("r=%v", r)
+	}
+}
+
+func TestBitSetCardinalityRange(t *testing.T) {
+	bs := GetRedisson().GetBitSet("testbitsetcardinalityrange")
+	if err := bs.ClearAll(); err != nil {
+		t.Fatal(err)
+	}
+	if err := bs.SetRange(5, 30); err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range []struct{ from, to, want int64 }{{0, 5, 0}, {0, 6, 1}, {10, 10, 0}, {3, 13, 8}, {29, 100, 1}} {
+		if n, err := bs.CardinalityRange(c.from, c.to); err != nil || n != c.want {
+			t.Fatalf("[%d, %d) n=%v err=%v", c.from, c.to, n, err)
+		}
+	}
+	if _, err := bs.CardinalityRange(5, 4); err == nil {
+		t.Fatal("expected an error for a reversed range")
+	}
+}
+
+func TestBitSetOverflow(t *testing.T) {
+	bs := GetRedisson().GetBitSet("testbitsetoverflow")
+	if err := bs.ClearAll(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := bs.SetByte(0, 120); err != nil {
+		t.Fatal(err)
+	}
+	if v, err := bs.incrementAndGetByte(0, 10, OverflowSat); err != nil || v != 127 {
+		t.Fatalf("v=%v err=%v", v, err)
+	}
+	_, err := bs.incrementAndGetByte(0, 1, OverflowFail)
+	var replyErr *BitFieldReplyError
+	if !errors.Is(err, ErrBitFieldOverflow) || !errors.As(err, &replyErr) || len(replyErr.Overflowed) != 1 {
+		t.Fatalf("err=%v", err)
+	}
+	if v, err := bs.incrementAndGetByte(0, 1, OverflowWrap); err != nil || v != byte(0x80) {
+		t.Fatalf("v=%v err=%v", v, err)
+	}
+}
@@ -0,0 +1,230 @@
+package redisson
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// blockingQueueWaitStep bounds every blocking command of RBlockingQueue, so that a waiting consumer sees ctx
+// done even if the redis client is not created with ContextTimeoutEnabled
+const blockingQueueWaitStep = time.Second
+
+// RBlockingQueue is a FIFO queue of values of type T stored in a redis list, whose consumers wait server-side
+// with BLPOP or BLMOVE for a value to be available, like the RBlockingQueue of Java Redisson.
+// Every waiting consumer holds a connection of the pool of the redis client.
+type RBlockingQueue[T any] interface {
+	RExpirable
+
+	// Offer appends value to the tail of the queue.
+	Offer(value T) error
+
+	// OfferAll appends values to the tail of the queue in order.
+	OfferAll(values ...T) error
+
+	// Poll removes and returns the value at the head of the queue, waiting up to timeout for one to be available,
+	// or until ctx is done. It reports false if the queue is still empty after timeout, and 0 does not wait.
+	Poll(ctx context.Context, timeout time.Duration) (T, bool, error)
+
+	// Take removes and returns the value at the head of the queue, waiting until one is available or ctx is done.
+	Take(ctx context.Context) (T, error)
+
+	// PollLastAndOfferFirstTo moves the value at the tail of the queue to the head of the queue named destination
+	// and returns it, waiting as Poll. The two queues must be in the same slot in a cluster.
+	PollLastAndOfferFirstTo(ctx context.Context, destination string, timeout time.Duration) (T, bool, error)
+
+	// Peek returns the value at the head of the queue without removing it.
+	Peek() (T, bool, error)
+
+	// Size returns the number of values in the queue.
+	Size() (int64, error)
+
+	// ReadAll returns all the values of the queue from head to tail.
+	ReadAll() ([]T, error)
+
+	// Delete deletes the queue and reports whether it existed.
+	Delete() (bool, error)
+}
+
+var (
+	_ RBlockingQueue[string] = (*RedissonBlockingQueue[string])(nil)
+)
+
+// RedissonBlockingQueue is the implementation of RBlockingQueue
+// values are pushed to the tail of the name list and popped from its head
+type RedissonBlockingQueue[T any] struct {
+	*RedissonExpirable
+	codec Codec
+}
+
+// newRedissonBlockingQueue creates a new RedissonBlockingQueue
+func newRedissonBlockingQueue[T any](name string, redisson *Redisson, options *objectOptions) *RedissonBlockingQueue[T] {
+	m := &RedissonBlockingQueue[T]{
+		RedissonExpirable: newRedissonExpirable(name, redisson),
+		codec:             options.codec,
+	}
+	m.ttl = options.ttl
+	return m
+}
+
+// Offer appends value to the tail of the queue.
+func (m *RedissonBlockingQueue[T]) Offer(value T) error {
+	return m.OfferAll(value)
+}
+
+// OfferAll appends values to the tail of the queue in order.
+func (m *RedissonBlockingQueue[T]) OfferAll(values ...T) error {
+	if len(values) == 0 {
+		return nil
+	}
+	items := make([]interface{}, len(values))
+	for i, v := range values {
+		data, err := m.codec.Encode(v)
+		if err != nil {
+			return err
+		}
+		items[i] = data
+	}
+	ctx, cancel := m.newContext()
+	defer cancel()
+	if err := m.client.RPush(ctx, m.getRawName(), items...).Err(); err != nil {
+		return err
+	}
+	return m.applyTTL(ctx, m.getRawName())
+}
+
+// Poll removes and returns the value at the head of the queue, waiting up to timeout for one to be available.
+func (m *RedissonBlockingQueue[T]) Poll(ctx context.Context, timeout time.Duration) (T, bool, error) {
+	if timeout <= 0 {
+		popCtx, cancel := m.withCommandTimeout(ctx)
+		defer cancel()
+		return m.decodeReply(m.client.LPop(popCtx, m.getRawName()).Result())
+	}
+	return m.wait(ctx, timeout, m.blpop(ctx))
+}
+
+// Take removes and returns the value at the head of the queue, waiting until one is available or ctx is done.
+func (m *RedissonBlockingQueue[T]) Take(ctx context.Context) (T, error) {
+	v, _, err := m.wait(ctx, 0, m.blpop(ctx))
+	return v, err
+}
+
+// blpop returns the pop of wait running BLPOP on the queue
+func (m *RedissonBlockingQueue[T]) blpop(ctx context.Context) func(block time.Duration) (string, error) {
+	return func(block time.Duration) (string, error) {
+		// go-redis rounds the timeout of BLPop down to whole seconds, redis takes fractional seconds since 6.0
+		res, err := m.client.Do(ctx, "blpop", m.getRawName(), blockSeconds(block)).StringSlice()
+		if err != nil {
+			return "", err
+		}
+		return res[1], nil
+	}
+}
+
+// PollLastAndOfferFirstTo moves the value at the tail of the queue to the head of the queue named destination
+// and returns it, waiting as Poll.
+func (m *RedissonBlockingQueue[T]) PollLastAndOfferFirstTo(ctx context.Context, destination string, timeout time.Duration) (T, bool, error) {
+	if timeout <= 0 {
+		moveCtx, cancel := m.withCommandTimeout(ctx)
+		defer cancel()
+		return m.decodeReply(m.client.LMove(moveCtx, m.getRawName(), destination, "RIGHT", "LEFT").Result())
+	}
+	return m.wait(ctx, timeout, func(block time.Duration) (string, error) {
+		return m.client.Do(ctx, "blmove", m.getRawName(), destination, "RIGHT", "LEFT", blockSeconds(block)).Text()
+	})
+}
+
+// wait runs pop, a blocking command, until it returns a value, timeout elapsed or ctx is done.
+// A timeout of 0 waits forever. pop is given how long to block, blockingQueueWaitStep or the rest of timeout if
+// shorter, and returns redis.Nil when it timed out.
+func (m *RedissonBlockingQueue[T]) wait(ctx context.Context, timeout time.Duration, pop func(block time.Duration) (string, error)) (T, bool, error) {
+	var zero T
+	var deadline time.Time
+	if timeout > 0 {
+		deadline = time.Now().Add(timeout)
+	}
+	for {
+		if err := ctx.Err(); err != nil {
+			return zero, false, err
+		}
+		block := blockingQueueWaitStep
+		if !deadline.IsZero() {
+			remaining := time.Until(deadline)
+			if remaining <= 0 {
+				return zero, false, nil
+			}
+			block = min(remaining, block)
+		}
+		data, err := pop(block)
+		if err == redis.Nil {
+			continue
+		}
+		if err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return zero, false, ctxErr
+			}
+			return zero, false, err
+		}
+		return m.decodeReply(data, nil)
+	}
+}
+
+// blockSeconds formats the timeout of a blocking command in seconds with a millisecond resolution,
+// at least a millisecond since 0 blocks forever
+func blockSeconds(block time.Duration) string {
+	return strconv.FormatFloat(max(block, time.Millisecond).Seconds(), 'f', 3, 64)
+}
+
+// Peek returns the value at the head of the queue without removing it.
+func (m *RedissonBlockingQueue[T]) Peek() (T, bool, error) {
+	ctx, cancel := m.newContext()
+	defer cancel()
+	return m.decodeReply(m.client.LIndex(ctx, m.getRawName(), 0).Result())
+}
+
+// Size returns the number of values in the queue.
+func (m *RedissonBlockingQueue[T]) Size() (int64, error) {
+	ctx, cancel := m.newContext()
+	defer cancel()
+	return m.client.LLen(ctx, m.getRawName()).Result()
+}
+
+// ReadAll returns all the values of the queue from head to tail.
+func (m *RedissonBlockingQueue[T]) ReadAll() ([]T, error) {
+	ctx, cancel := m.newContext()
+	defer cancel()
+	items, err := m.client.LRange(ctx, m.getRawName(), 0, -1).Result()
+	if err != nil {
+		return nil, err
+	}
+	values := make([]T, len(items))
+	for i, item := range items {
+		if err = m.codec.Decode([]byte(item), &values[i]); err != nil {
+			return nil, err
+		}
+	}
+	return values, nil
+}
+
+// Delete deletes the queue and reports whether it existed.
+func (m *RedissonBlockingQueue[T]) Delete() (bool, error) {
+	ctx, cancel := m.newContext()
+	defer cancel()
+	n, err := m.client.Del(ctx, m.getRawName()).Result()
+	return n == 1, err
+}
+
+// decodeReply decodes the value replied, if any
+func (m *RedissonBlockingQueue[T]) decodeReply(item string, err error) (T, bool, error) {
+	var v T
+	if err == redis.Nil {
+		return v, false, nil
+	}
+	if err != nil {
+		return v, false, err
+	}
+	err = m.codec.Decode([]byte(item), &v)
+	return v, err == nil, err
+}
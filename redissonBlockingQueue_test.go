@@ -0,0 +1,72 @@
+package redisson
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestBlockingQueue(t *testing.T) {
+	q := GetBlockingQueue[string](GetRedisson(), "testBlockingQueue")
+	if _, err := q.Delete(); err != nil {
+		t.Fatal(err)
+	}
+	if v, ok, err := q.Poll(context.Background(), 0); err != nil || ok {
+		t.Fatalf("v=%v ok=%v err=%v", v, ok, err)
+	}
+	if err := q.OfferAll("a", "b", "c"); err != nil {
+		t.Fatal(err)
+	}
+	if v, ok, err := q.Peek(); err != nil || !ok || v != "a" {
+		t.Fatalf("v=%v ok=%v err=%v", v, ok, err)
+	}
+	if v, ok, err := q.Poll(context.Background(), time.Second); err != nil || !ok || v != "a" {
+		t.Fatalf("v=%v ok=%v err=%v", v, ok, err)
+	}
+	if v, ok, err := q.PollLastAndOfferFirstTo(context.Background(), "testBlockingQueue", time.Second); err != nil || !ok || v != "c" {
+		t.Fatalf("v=%v ok=%v err=%v", v, ok, err)
+	}
+	if values, err := q.ReadAll(); err != nil || !reflect.DeepEqual(values, []string{"c", "b"}) {
+		t.Fatalf("values=%v err=%v", values, err)
+	}
+}
+
+func TestBlockingQueueTake(t *testing.T) {
+	q := GetBlockingQueue[User](GetRedisson(), "testBlockingQueueTake")
+	if _, err := q.Delete(); err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		time.Sleep(200 * time.Millisecond)
+		if err := q.Offer(User{ID: 1, Name: "Alice"}); err != nil {
+			t.Error(err)
+		}
+	}()
+	start := time.Now()
+	v, err := q.Take(context.Background())
+	if err != nil || v.Name != "Alice" {
+		t.Fatalf("v=%v err=%v", v, err)
+	}
+	if time.Since(start) < 200*time.Millisecond {
+		t.Fatal("took before the value was offered")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 1500*time.Millisecond)
+	defer cancel()
+	if _, err = q.Take(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err=%v", err)
+	}
+	if _, ok, err := q.Poll(context.Background(), time.Second); err != nil || ok {
+		t.Fatalf("ok=%v err=%v", ok, err)
+	}
+	// a timeout shorter than a second is not rounded up
+	start = time.Now()
+	if _, ok, err := q.Poll(context.Background(), 100*time.Millisecond); err != nil || ok {
+		t.Fatalf("ok=%v err=%v", ok, err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Fatalf("Poll returned after %v", elapsed)
+	}
+}
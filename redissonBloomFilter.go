@@ -2,12 +2,13 @@ package redisson
 
 import (
 	"context"
-	"crypto/sha256"
-	"encoding/binary"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/redis/go-redis/v9"
 	"math"
+	"strconv"
+	"strings"
 )
 
 // RBloomFilter represents a Redis-backed Bloom filter
@@ -26,9 +27,14 @@ type RBloomFilter[T any] interface {
 	// calculated from expectedInsertions and falseProbability
 	// Stores config to Redis server
 	// Returns true if Bloom filter was initialized
-	// Returns false if Bloom filter was already initialized
+	// Returns false if Bloom filter was already initialized, by another instance with other parameters
+	// too, and then uses the parameters it was initialized with
+	// Returns false if the parameters are invalid
 	TryInit(expectedInsertions int64, falseProbability float64) bool
 
+	// The config returned by the getters below is read once and cached by the instance,
+	// Add and Contains read it again when they find the filter was deleted or initialized again
+
 	// GetExpectedInsertions returns expected amount of insertions per element
 	// Calculated during bloom filter initialization
 	GetExpectedInsertions() int64
@@ -44,9 +50,30 @@ type RBloomFilter[T any] interface {
 	// Calculated during bloom filter initialization
 	GetHashIterations() int
 
-	// Count calculates probabilistic number of elements already added to Bloom filter
+	// AddAll adds elements to the Bloom filter in batches of one script call each
+	// Returns the number of elements which were not present
+	AddAll(objects []T) (int, error)
+
+	// ContainsAll checks in batches of one script call each if all elements are present in the Bloom filter
+	ContainsAll(objects []T) (bool, error)
+
+	// ContainsAny checks in batches of one script call each if any element is present in the Bloom filter
+	ContainsAny(objects []T) (bool, error)
+
+	// Count returns the number of elements added to the Bloom filter, counted when they are added,
+	// an element taken for present is not counted. It estimates the number from the bits set
+	// when the filter has no counter, if it was created by Java Redisson or an earlier version.
 	Count() int64
 
+	// MergeWith adds the elements of other, a Bloom filter with the same parameters, to this filter
+	// with BITOP OR. Count estimates the number of elements from the bits set afterwards.
+	// The filters must be in the same slot in a cluster, e.g. named with the same hash tag.
+	MergeWith(other RBloomFilter[T]) error
+
+	// Delete deletes the bit array and the config of the Bloom filter atomically,
+	// TryInit initializes it again afterwards
+	Delete() error
+
 	// Embedded interface for expiration functionality
 	RExpirable
 }
@@ -55,153 +82,514 @@ type RBloomFilter[T any] interface {
 type RedissonBloomFilter[T any] struct {
 	*RedissonExpirable
 	key            string
-	size           int64  // 布隆过滤器的位数组大小
-	hashIterations int    // hash函数的迭代次数
-	configName     string // 配置名称，用于存储布隆过滤器的配置
-
+	size           int64        // 布隆过滤器的位数组大小
+	hashIterations int          // hash函数的迭代次数
+	configName     string       // 配置名称，用于存储布隆过滤器的配置，与 Java Redisson 相同为 {name}:config 哈希
+	legacyConfig   string       // 旧版本存储 JSON 配置的键名 name:config，只读取
+	module         bool         // TryInit 是否在加载了 RedisBloom 模块时使用 BF.* 命令创建过滤器
+	codec          Codec        // 元素的编解码器
+	hasher         BloomHasher  // 计算元素的位索引
+	config         *BloomConfig // 本地缓存的配置，脚本发现 Redis 中的配置被删除或重新初始化时重新读取
+	verifyConfig   bool         // 脚本是否校验 Redis 中的配置与本地缓存一致，滑动时间窗口布隆过滤器的子过滤器没有配置
 }
 
 // NewRedissonBloomFilter 构造函数
-func NewRedissonBloomFilter[T any](redisson *Redisson, key string) *RedissonBloomFilter[T] {
-	configName := suffixName(key, "config")
-	return &RedissonBloomFilter[T]{
+func NewRedissonBloomFilter[T any](redisson *Redisson, key string, opts ...ObjectOption) *RedissonBloomFilter[T] {
+	bf := &RedissonBloomFilter[T]{
 		RedissonExpirable: newRedissonExpirable(key, redisson),
 		key:               key,
-		configName:        configName,
+		legacyConfig:      suffixName(key, "config"),
+		verifyConfig:      true,
+	}
+	bf.configName = bf.suffixName(key, "config")
+	// Expire、ExpireAt 和 ClearExpire 在一个脚本中同时作用于位数组和配置
+	bf.componentKeys = func() []string {
+		return []string{bf.key, bf.configName, bf.legacyConfig}
 	}
+	o := redisson.newObjectOptions(opts)
+	bf.ttl = o.ttl
+	bf.module = o.bloomModule
+	// 默认编码不变，已有过滤器中的元素仍能查到；由元素自身编码需显式传入 WithCodec(MarshalerCodec{})
+	bf.codec = o.codec
+	bf.hasher = o.bloomHasher
+	if bf.hasher == nil {
+		bf.hasher = SHA256BloomHasher{}
+	}
+	return bf
 }
 
 // TryInit 初始化布隆过滤器
 func (bf *RedissonBloomFilter[T]) TryInit(expectedInsertions int64, falseProbability float64) bool {
+	ctx, cancel := bf.newContext()
+	defer cancel()
 	bf.mutex.Lock()
 	defer bf.mutex.Unlock()
 
-	// 检查是否已经初始化
-	exists, err := bf.client.Exists(context.Background(), bf.configName).Result()
-	if err != nil {
-		fmt.Printf("Error checking Bloom filter config existence: %v\n", err)
-		return false
-	}
-	if exists != 0 {
-		// 已经初始化
-		return false
-	}
-
 	// 计算布隆过滤器的大小和哈希迭代次数
 	size, hashIterations := optimalBloomParameters(expectedInsertions, falseProbability)
 
+	// 创建时选择存储方式并写入配置，之后所有实例按配置中的存储方式访问过滤器
+	module := false
+	if bf.module {
+		var err error
+		if module, err = bf.hasCommand(ctx, "BF.ADD"); err != nil {
+			fmt.Printf("Error checking RedisBloom module: %v\n", err)
+			return false
+		}
+	}
+
 	// 构建配置
 	config := BloomConfig{
 		ExpectedInsertions: expectedInsertions,
 		FalseProbability:   falseProbability,
 		Size:               size,
 		HashIterations:     hashIterations,
+		module:             module,
 	}
 
-	// 将配置存储到 Redis
-	configBytes, err := json.Marshal(config)
+	// 在一个脚本中校验参数并在未初始化时写入配置，已经初始化时返回生效的配置
+	res, err := bf.eval(ctx, "bloomFilter.tryInit", bloomFilterTryInitScript, []string{bf.configName, bf.legacyConfig},
+		append(config.fields(), bloomFilterMaxSize)...).Slice()
 	if err != nil {
-		fmt.Printf("Error marshaling Bloom filter config: %v\n", err)
+		fmt.Printf("Error initializing Bloom filter: %v\n", err)
+		return false
+	}
+	if len(res) > 1 {
+		// 已经初始化，使用生效的配置
+		winner, err := decodeBloomConfig(res[1])
+		if err != nil {
+			fmt.Printf("Error getting Bloom filter config: %v\n", err)
+			return false
+		}
+		bf.setConfig(&winner)
 		return false
 	}
 
-	// 使用事务确保原子性
-	pipe := bf.client.TxPipeline()
-	pipe.SetNX(context.Background(), bf.configName, configBytes, 0)
-	_, err = pipe.Exec(context.Background())
+	// 使用 RedisBloom 模块时由模块创建过滤器，配置仍然存储以返回参数和存储方式
+	if module {
+		err = bf.client.BFReserve(ctx, bf.key, falseProbability, expectedInsertions).Err()
+	}
 	if err != nil {
-		fmt.Printf("Error setting Bloom filter config: %v\n", err)
+		fmt.Printf("Error reserving Bloom filter: %v\n", err)
+		// 撤销配置，之后可以重新初始化
+		if err = bf.client.Del(ctx, bf.configName).Err(); err != nil {
+			fmt.Printf("Error deleting Bloom filter config: %v\n", err)
+		}
 		return false
 	}
+	if err = bf.applyTTL(ctx, bf.configName); err != nil {
+		fmt.Printf("Error setting Bloom filter config TTL: %v\n", err)
+	}
 
 	// 更新本地配置
-	bf.size = size
-	bf.hashIterations = hashIterations
+	bf.setConfig(&config)
 
 	return true
 }
 
 // Add 添加元素到布隆过滤器
 func (bf *RedissonBloomFilter[T]) Add(object T) bool {
+	ctx, cancel := bf.newContext()
+	defer cancel()
 	bf.mutex.Lock()
 	defer bf.mutex.Unlock()
 
 	// 如果未初始化，尝试初始化
-	if bf.size == 0 || bf.hashIterations == 0 {
-		// 尝试读取配置
-		err := bf.readConfig()
-		if err != nil {
-			fmt.Printf("Bloom filter not initialized: %v\n", err)
-			return false
-		}
+	if err := bf.ensureConfig(); err != nil {
+		fmt.Printf("Bloom filter not initialized: %v\n", err)
+		return false
 	}
 
-	// 计算哈希索引
-	indexes, err := bf.getHashIndexes(object)
+	module, err := bf.useModule(ctx)
 	if err != nil {
-		fmt.Printf("Error hashing object: %v\n", err)
+		fmt.Printf("Error checking RedisBloom module: %v\n", err)
 		return false
 	}
-
-	// 设置位
-	var anySet bool
-	for _, idx := range indexes {
-		set, err := bf.SetBit(idx, true)
+	if module {
+		added, err := bf.moduleAdd(ctx, object)
 		if err != nil {
-			fmt.Printf("Error setting bit at index %d: %v\n", idx, err)
+			fmt.Printf("Error adding object: %v\n", err)
 			return false
 		}
-		if set {
-			anySet = true
-		}
+		return added
 	}
 
-	return anySet
+	// 一次脚本调用设置所有位并增加插入计数
+	added, err := bf.bitsAddAll(ctx, []T{object})
+	if err != nil {
+		fmt.Printf("Error setting bits: %v\n", err)
+		return false
+	}
+	if err = bf.applyTTL(ctx, bf.key); err != nil {
+		fmt.Printf("Error setting Bloom filter TTL: %v\n", err)
+	}
+
+	return added > 0
 }
 
 // Contains 检查元素是否在布隆过滤器中
 func (bf *RedissonBloomFilter[T]) Contains(object T) bool {
+	ctx, cancel := bf.newContext()
+	defer cancel()
 	bf.mutex.Lock()
 	defer bf.mutex.Unlock()
 
 	// 如果未初始化，尝试初始化
-	if bf.size == 0 || bf.hashIterations == 0 {
-		// 尝试读取配置
-		err := bf.readConfig()
+	if err := bf.ensureConfig(); err != nil {
+		fmt.Printf("Bloom filter not initialized: %v\n", err)
+		return false
+	}
+
+	module, err := bf.useModule(ctx)
+	if err != nil {
+		fmt.Printf("Error checking RedisBloom module: %v\n", err)
+		return false
+	}
+	if module {
+		element, err := bf.encodeElement(object)
 		if err != nil {
-			fmt.Printf("Bloom filter not initialized: %v\n", err)
+			fmt.Printf("Error encoding object: %v\n", err)
 			return false
 		}
+		exists, err := bf.client.BFExists(ctx, bf.key, element).Result()
+		if err != nil {
+			fmt.Printf("Error checking object: %v\n", err)
+			return false
+		}
+		return exists
 	}
 
-	// 计算哈希索引
-	indexes, err := bf.getHashIndexes(object)
+	// 一次脚本调用检查所有位
+	found, err := bf.bitsContainsCount(ctx, []T{object})
 	if err != nil {
-		fmt.Printf("Error hashing object: %v\n", err)
+		fmt.Printf("Error getting bits: %v\n", err)
 		return false
 	}
+	return found == 1
+}
+
+// bloomFilterBatchSize AddAll、ContainsAll 和 ContainsAny 每次脚本调用处理的元素数
+const bloomFilterBatchSize = 1000
+
+// AddAll 批量添加元素，哈希索引在客户端计算，每批元素一次脚本调用，返回之前不存在的元素数
+func (bf *RedissonBloomFilter[T]) AddAll(objects []T) (int, error) {
+	ctx, cancel := bf.newContext()
+	defer cancel()
+	bf.mutex.Lock()
+	defer bf.mutex.Unlock()
+
+	if err := bf.ensureConfig(); err != nil {
+		return 0, err
+	}
+	module, err := bf.useModule(ctx)
+	if err != nil {
+		return 0, err
+	}
+	added := 0
+	for start := 0; start < len(objects); start += bloomFilterBatchSize {
+		batch := objects[start:min(start+bloomFilterBatchSize, len(objects))]
+		var n int
+		if module {
+			n, err = bf.moduleAddAll(ctx, batch)
+		} else {
+			n, err = bf.bitsAddAll(ctx, batch)
+		}
+		if err != nil {
+			return added, err
+		}
+		added += n
+	}
+	if len(objects) > 0 {
+		if err := bf.applyTTL(ctx, bf.key); err != nil {
+			return added, err
+		}
+	}
+	return added, nil
+}
 
-	// 检查位
-	for _, idx := range indexes {
-		exists, err := bf.GetBit(idx)
+// ContainsAll 批量检查是否所有元素都存在
+func (bf *RedissonBloomFilter[T]) ContainsAll(objects []T) (bool, error) {
+	n, err := bf.containsCount(objects, false)
+	return n == len(objects), err
+}
+
+// ContainsAny 批量检查是否有元素存在
+func (bf *RedissonBloomFilter[T]) ContainsAny(objects []T) (bool, error) {
+	n, err := bf.containsCount(objects, true)
+	return n > 0, err
+}
+
+// containsCount 返回存在的元素数，anyFound 为 true 时找到一个存在的元素即返回，
+// 为 false 时找到一个不存在的元素即返回
+func (bf *RedissonBloomFilter[T]) containsCount(objects []T, anyFound bool) (int, error) {
+	ctx, cancel := bf.newContext()
+	defer cancel()
+	bf.mutex.Lock()
+	defer bf.mutex.Unlock()
+
+	if err := bf.ensureConfig(); err != nil {
+		return 0, err
+	}
+	module, err := bf.useModule(ctx)
+	if err != nil {
+		return 0, err
+	}
+	count := 0
+	for start := 0; start < len(objects); start += bloomFilterBatchSize {
+		batch := objects[start:min(start+bloomFilterBatchSize, len(objects))]
+		var n int
+		if module {
+			n, err = bf.moduleContainsCount(ctx, batch)
+		} else {
+			n, err = bf.bitsContainsCount(ctx, batch)
+		}
 		if err != nil {
-			fmt.Printf("Error getting bit at index %d: %v\n", idx, err)
-			return false
+			return 0, err
 		}
-		if !exists {
-			return false
+		count += n
+		if anyFound && n > 0 || !anyFound && n < len(batch) {
+			break
 		}
 	}
+	return count, nil
+}
 
-	return true
+// bitsAddAll 在位数组中添加一批元素，返回之前不存在的元素数
+func (bf *RedissonBloomFilter[T]) bitsAddAll(ctx context.Context, objects []T) (int, error) {
+	return bf.evalBatch(ctx, "bloomFilter.addAll", bloomFilterAddAllScript, objects)
+}
+
+// bitsContainsCount 返回一批元素中在位数组中存在的元素数
+func (bf *RedissonBloomFilter[T]) bitsContainsCount(ctx context.Context, objects []T) (int, error) {
+	return bf.evalBatch(ctx, "bloomFilter.containsAll", bloomFilterContainsAllScript, objects)
+}
+
+// evalBatch 对一批元素执行位数组脚本，脚本发现配置被删除或重新初始化时重新读取配置后重试
+func (bf *RedissonBloomFilter[T]) evalBatch(ctx context.Context, name, script string, objects []T) (int, error) {
+	args, err := bf.getBatchIndexes(objects)
+	if err != nil {
+		return 0, err
+	}
+	n, err := bf.eval(ctx, name, script, []string{bf.key, bf.configName, bf.legacyConfig}, args...).Int()
+	if err == nil || !strings.Contains(err.Error(), bloomFilterConfigChanged) {
+		return n, err
+	}
+	bf.setConfig(nil)
+	if err = bf.readConfig(); err != nil {
+		return 0, err
+	}
+	if args, err = bf.getBatchIndexes(objects); err != nil {
+		return 0, err
+	}
+	return bf.eval(ctx, name, script, []string{bf.key, bf.configName, bf.legacyConfig}, args...).Int()
+}
+
+// useModule 返回过滤器是否由 RedisBloom 模块存储，即配置中记录的创建时的存储方式，与本实例的选项无关。
+// 滑动时间窗口布隆过滤器的子过滤器没有配置，总是位数组
+func (bf *RedissonBloomFilter[T]) useModule(ctx context.Context) (bool, error) {
+	if !bf.verifyConfig {
+		return false, nil
+	}
+	config, err := bf.cachedConfig()
+	if err != nil {
+		return false, err
+	}
+	return config.module, nil
+}
+
+// moduleAdd 使用 BF.INSERT NOCREATE 添加元素，返回之前是否不存在
+func (bf *RedissonBloomFilter[T]) moduleAdd(ctx context.Context, object T) (bool, error) {
+	added, err := bf.moduleAddAll(ctx, []T{object})
+	if err != nil {
+		return false, err
+	}
+	return added > 0, bf.applyTTL(ctx, bf.key)
+}
+
+// moduleAddAll 使用 BF.INSERT NOCREATE 添加一批元素，返回之前不存在的元素数。
+// 过滤器被删除时不按模块的默认参数重新创建，而是返回错误并清除本地配置，下次调用重新读取
+func (bf *RedissonBloomFilter[T]) moduleAddAll(ctx context.Context, objects []T) (int, error) {
+	elements, err := bf.encodeElements(objects)
+	if err != nil {
+		return 0, err
+	}
+	added, err := bf.client.BFInsert(ctx, bf.key, &redis.BFInsertOptions{NoCreate: true}, elements...).Result()
+	if err != nil {
+		bf.setConfig(nil)
+		return 0, err
+	}
+	return countTrue(added), nil
+}
+
+// moduleContainsCount 使用 BF.MEXISTS 返回一批元素中存在的元素数
+func (bf *RedissonBloomFilter[T]) moduleContainsCount(ctx context.Context, objects []T) (int, error) {
+	elements, err := bf.encodeElements(objects)
+	if err != nil {
+		return 0, err
+	}
+	exists, err := bf.client.BFMExists(ctx, bf.key, elements...).Result()
+	if err != nil {
+		return 0, err
+	}
+	return countTrue(exists), nil
+}
+
+// encodeElement 返回元素在 RedisBloom 中的表示，与计算哈希索引时相同使用编解码器编码
+func (bf *RedissonBloomFilter[T]) encodeElement(object T) (string, error) {
+	objBytes, err := bf.codec.Encode(object)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode object: %v", err)
+	}
+	return string(objBytes), nil
 }
 
+// encodeElements 返回一批元素在 RedisBloom 中的表示
+func (bf *RedissonBloomFilter[T]) encodeElements(objects []T) ([]interface{}, error) {
+	elements := make([]interface{}, len(objects))
+	for i, object := range objects {
+		element, err := bf.encodeElement(object)
+		if err != nil {
+			return nil, err
+		}
+		elements[i] = element
+	}
+	return elements, nil
+}
+
+// countTrue 返回 values 中 true 的个数
+func countTrue(values []bool) int {
+	n := 0
+	for _, v := range values {
+		if v {
+			n++
+		}
+	}
+	return n
+}
+
+// ensureConfig 未初始化时读取配置
+func (bf *RedissonBloomFilter[T]) ensureConfig() error {
+	if bf.size == 0 || bf.hashIterations == 0 {
+		return bf.readConfig()
+	}
+	return nil
+}
+
+// cachedConfig 返回本地缓存的配置，没有缓存时读取
+func (bf *RedissonBloomFilter[T]) cachedConfig() (*BloomConfig, error) {
+	if bf.config == nil {
+		if err := bf.readConfig(); err != nil {
+			return nil, err
+		}
+	}
+	return bf.config, nil
+}
+
+// setConfig 更新本地缓存的配置，nil 清除缓存
+func (bf *RedissonBloomFilter[T]) setConfig(config *BloomConfig) {
+	bf.config = config
+	if config == nil {
+		bf.size = 0
+		bf.hashIterations = 0
+		return
+	}
+	bf.size = config.Size
+	bf.hashIterations = config.HashIterations
+}
+
+// getBatchIndexes 返回批量脚本的参数：哈希迭代次数，校验的位数组大小（不校验时为空），
+// 之后依次为每个元素的哈希索引
+func (bf *RedissonBloomFilter[T]) getBatchIndexes(objects []T) ([]interface{}, error) {
+	args := make([]interface{}, 0, 2+len(objects)*bf.hashIterations)
+	args = append(args, bf.hashIterations)
+	if bf.verifyConfig {
+		args = append(args, bf.size)
+	} else {
+		args = append(args, "")
+	}
+	for _, object := range objects {
+		indexes, err := bf.getHashIndexes(object)
+		if err != nil {
+			return nil, err
+		}
+		for _, idx := range indexes {
+			args = append(args, idx)
+		}
+	}
+	return args, nil
+}
+
+// bloomFilterConfigChanged 脚本发现配置被删除或重新初始化时返回的错误
+const bloomFilterConfigChanged = "Bloom filter config has been changed"
+
+// bloomFilterCheckConfigScript 校验 KEYS[2] 中的配置与计算哈希索引的 ARGV[1] 哈希迭代次数和 ARGV[2] 位数组大小一致，
+// ARGV[2] 为空时不校验，KEYS[3] 中旧版本的 JSON 配置也不校验
+const bloomFilterCheckConfigScript = `
+if ARGV[2] ~= '' then
+    local t = redis.call('type', KEYS[2])['ok'];
+    if t == 'none' and redis.call('exists', KEYS[3]) == 0 then
+        return redis.error_reply('` + bloomFilterConfigChanged + `');
+    end;
+    if t == 'hash' then
+        local config = redis.call('hmget', KEYS[2], 'size', 'hashIterations');
+        if config[1] ~= ARGV[2] or config[2] ~= ARGV[1] then
+            return redis.error_reply('` + bloomFilterConfigChanged + `');
+        end;
+    end;
+end;
+`
+
+// bloomFilterAddAllScript：KEYS[1] 为位数组，KEYS[2] 为配置，KEYS[3] 为旧版本的配置，ARGV[1] 为哈希迭代次数 k，ARGV[2] 为校验的位数组大小，
+// 之后每 k 个参数为一个元素的哈希索引，返回之前不存在的元素数。配置中有插入计数时增加计数，Java Redisson 或旧版本创建的配置没有计数。
+// 新建的位数组与配置同时过期
+const bloomFilterAddAllScript = bloomFilterCheckConfigScript + `
+local created = redis.call('exists', KEYS[1]) == 0
+local k = tonumber(ARGV[1])
+local added = 0
+for i = 3, #ARGV, k do
+    local set = 0
+    for j = i, i + k - 1 do
+        if redis.call('setbit', KEYS[1], ARGV[j], 1) == 0 then
+            set = 1
+        end
+    end
+    added = added + set
+end
+if added > 0 and redis.call('type', KEYS[2])['ok'] == 'hash' and redis.call('hexists', KEYS[2], 'count') == 1 then
+    redis.call('hincrby', KEYS[2], 'count', added)
+end
+if created then
+    local ttl = redis.call('pttl', KEYS[2])
+    if ttl > 0 then
+        redis.call('pexpire', KEYS[1], ttl)
+    end
+end
+return added
+`
+
+// bloomFilterContainsAllScript：参数同 bloomFilterAddAllScript，返回存在的元素数
+const bloomFilterContainsAllScript = bloomFilterCheckConfigScript + `
+local k = tonumber(ARGV[1])
+local found = 0
+for i = 3, #ARGV, k do
+    local contains = 1
+    for j = i, i + k - 1 do
+        if redis.call('getbit', KEYS[1], ARGV[j]) == 0 then
+            contains = 0
+            break
+        end
+    end
+    found = found + contains
+end
+return found
+`
+
 // GetExpectedInsertions 返回预期插入量
 func (bf *RedissonBloomFilter[T]) GetExpectedInsertions() int64 {
 	bf.mutex.Lock()
 	defer bf.mutex.Unlock()
 
-	config, err := bf.getConfig()
+	config, err := bf.cachedConfig()
 	if err != nil {
 		fmt.Printf("Error getting Bloom filter config: %v\n", err)
 		return 0
@@ -214,7 +602,7 @@ func (bf *RedissonBloomFilter[T]) GetFalseProbability() float64 {
 	bf.mutex.Lock()
 	defer bf.mutex.Unlock()
 
-	config, err := bf.getConfig()
+	config, err := bf.cachedConfig()
 	if err != nil {
 		fmt.Printf("Error getting Bloom filter config: %v\n", err)
 		return 0.0
@@ -227,7 +615,7 @@ func (bf *RedissonBloomFilter[T]) GetSize() int64 {
 	bf.mutex.Lock()
 	defer bf.mutex.Unlock()
 
-	config, err := bf.getConfig()
+	config, err := bf.cachedConfig()
 	if err != nil {
 		fmt.Printf("Error getting Bloom filter config: %v\n", err)
 		return 0
@@ -240,7 +628,7 @@ func (bf *RedissonBloomFilter[T]) GetHashIterations() int {
 	bf.mutex.Lock()
 	defer bf.mutex.Unlock()
 
-	config, err := bf.getConfig()
+	config, err := bf.cachedConfig()
 	if err != nil {
 		fmt.Printf("Error getting Bloom filter config: %v\n", err)
 		return 0
@@ -248,13 +636,45 @@ func (bf *RedissonBloomFilter[T]) GetHashIterations() int {
 	return config.HashIterations
 }
 
-// Count 估算已经添加的元素数量
+// Count 返回插入计数，没有计数时估算已经添加的元素数量
 func (bf *RedissonBloomFilter[T]) Count() int64 {
+	ctx, cancel := bf.newContext()
+	defer cancel()
 	bf.mutex.Lock()
 	defer bf.mutex.Unlock()
 
+	module, err := bf.useModule(ctx)
+	if err != nil {
+		fmt.Printf("Error checking RedisBloom module: %v\n", err)
+		return 0
+	}
+	if module {
+		count, err := bf.client.BFCard(ctx, bf.key).Result()
+		if err != nil {
+			fmt.Printf("Error counting Bloom filter elements: %v\n", err)
+			return 0
+		}
+		return count
+	}
+
+	// 优先返回插入计数
+	inserted, err := bf.eval(ctx, "bloomFilter.count", bloomFilterCountScript, []string{bf.configName}).Int64()
+	if err == nil {
+		return inserted
+	}
+	if err != redis.Nil {
+		fmt.Printf("Error getting Bloom filter insert count: %v\n", err)
+		return 0
+	}
+
+	// 没有插入计数时由位数估算，需要读取配置
+	if err = bf.ensureConfig(); err != nil {
+		fmt.Printf("Bloom filter not initialized: %v\n", err)
+		return 0
+	}
+
 	// 获取设置的位数
-	count, err := bf.client.BitCount(context.Background(), bf.key, &redis.BitCount{
+	count, err := bf.client.BitCount(ctx, bf.key, &redis.BitCount{
 		Start: 0,
 		End:   -1,
 	}).Result()
@@ -277,6 +697,85 @@ func (bf *RedissonBloomFilter[T]) Count() int64 {
 	return int64(n)
 }
 
+// ErrBloomFilterMergeUnsupported 合并的过滤器不是位数组实现，例如使用 RedisBloom 模块
+var ErrBloomFilterMergeUnsupported = errors.New("Bloom filter merge requires bit array filters")
+
+// MergeWith 将参数相同的 other 的位数组按位或到本过滤器
+func (bf *RedissonBloomFilter[T]) MergeWith(other RBloomFilter[T]) error {
+	src, ok := other.(*RedissonBloomFilter[T])
+	if !ok {
+		return ErrBloomFilterMergeUnsupported
+	}
+	ctx, cancel := bf.newContext()
+	defer cancel()
+	bf.mutex.Lock()
+	defer bf.mutex.Unlock()
+
+	module, err := bf.useModule(ctx)
+	if err != nil {
+		return err
+	}
+	srcModule, err := src.useModule(ctx)
+	if err != nil {
+		return err
+	}
+	if module || srcModule {
+		return ErrBloomFilterMergeUnsupported
+	}
+
+	// 读取两个过滤器当前的配置，参数不同的位数组不能合并
+	config, err := bf.getConfig()
+	if err != nil {
+		return err
+	}
+	srcConfig, err := src.getConfig()
+	if err != nil {
+		return err
+	}
+	if config.Size != srcConfig.Size || config.HashIterations != srcConfig.HashIterations {
+		return fmt.Errorf("Bloom filters %s and %s have different parameters", bf.key, src.key)
+	}
+	bf.setConfig(config)
+
+	if err = bf.eval(ctx, "bloomFilter.merge", bloomFilterMergeScript, []string{bf.key, bf.configName, src.key}).Err(); err != nil && err != redis.Nil {
+		return err
+	}
+	return bf.applyTTL(ctx, bf.key)
+}
+
+// bloomFilterMergeScript：将位数组 KEYS[3] 按位或到 KEYS[1]，删除配置 KEYS[2] 中不再准确的插入计数
+const bloomFilterMergeScript = `
+redis.call('bitop', 'or', KEYS[1], KEYS[1], KEYS[3]);
+if redis.call('type', KEYS[2])['ok'] == 'hash' then
+    redis.call('hdel', KEYS[2], 'count');
+end;
+`
+
+// bloomFilterCountScript：返回配置哈希 KEYS[1] 中的插入计数，没有计数时返回 nil
+const bloomFilterCountScript = `
+if redis.call('type', KEYS[1])['ok'] == 'hash' then
+    return redis.call('hget', KEYS[1], 'count');
+end;
+return false;
+`
+
+// Delete 原子地删除位数组和配置，之后可以重新 TryInit
+func (bf *RedissonBloomFilter[T]) Delete() error {
+	ctx, cancel := bf.newContext()
+	defer cancel()
+	bf.mutex.Lock()
+	defer bf.mutex.Unlock()
+
+	// DEL 多个键是原子的
+	if err := bf.client.Del(ctx, bf.key, bf.configName, bf.legacyConfig).Err(); err != nil {
+		return err
+	}
+
+	// 清除本地配置，Add 和 Contains 重新读取配置
+	bf.setConfig(nil)
+	return nil
+}
+
 // Helper Structures and Functions
 
 // BloomConfig 存储布隆过滤器的配置
@@ -285,67 +784,166 @@ type BloomConfig struct {
 	FalseProbability   float64 `json:"falseProbability"`
 	Size               int64   `json:"size"`
 	HashIterations     int     `json:"hashIterations"`
+	module             bool    // 是否由 RedisBloom 模块存储，记录在配置哈希的 backend 字段中
 }
 
-// readConfig 从 Redis 中读取布隆过滤器的配置
-func (bf *RedissonBloomFilter[T]) readConfig() error {
-	data, err := bf.client.Get(context.Background(), bf.configName).Bytes()
-	if err != nil {
-		return fmt.Errorf("failed to get Bloom filter config: %v", err)
+// bloomFilterModuleBackend 配置哈希的 backend 字段的值，表示过滤器由 RedisBloom 模块存储，没有该字段时为位数组
+const bloomFilterModuleBackend = "module"
+
+// fields 返回配置在 Java Redisson 格式的哈希中的字段和值，之后为存储方式，位数组时为空
+func (c BloomConfig) fields() []interface{} {
+	backend := ""
+	if c.module {
+		backend = bloomFilterModuleBackend
+	}
+	return []interface{}{
+		"size", c.Size,
+		"hashIterations", c.HashIterations,
+		"expectedInsertions", c.ExpectedInsertions,
+		"falseProbability", strconv.FormatFloat(c.FalseProbability, 'f', -1, 64),
+		"backend", backend,
 	}
+}
 
-	var config BloomConfig
-	err = json.Unmarshal(data, &config)
+// readConfig 从 Redis 中读取布隆过滤器的配置
+func (bf *RedissonBloomFilter[T]) readConfig() error {
+	config, err := bf.getConfig()
 	if err != nil {
-		return fmt.Errorf("failed to unmarshal Bloom filter config: %v", err)
+		return err
 	}
-
-	bf.size = config.Size
-	bf.hashIterations = config.HashIterations
+	bf.setConfig(config)
 	return nil
 }
 
-// getConfig 获取布隆过滤器的配置
+// getConfig 获取布隆过滤器的配置，读取 {name}:config 哈希或旧版本的 JSON 配置
 func (bf *RedissonBloomFilter[T]) getConfig() (*BloomConfig, error) {
-	data, err := bf.client.Get(context.Background(), bf.configName).Bytes()
+	ctx, cancel := bf.newContext()
+	defer cancel()
+	res, err := bf.eval(ctx, "bloomFilter.getConfig", bloomFilterGetConfigScript, []string{bf.configName, bf.legacyConfig}).Result()
 	if err != nil {
+		if err == redis.Nil {
+			err = errors.New("not initialized")
+		}
 		return nil, fmt.Errorf("failed to get Bloom filter config: %v", err)
 	}
 
-	var config BloomConfig
-	err = json.Unmarshal(data, &config)
+	config, err := decodeBloomConfig(res)
 	if err != nil {
-		return nil, fmt.Errorf("failed to unmarshal Bloom filter config: %v", err)
+		return nil, err
 	}
-
 	return &config, nil
 }
 
-// getHashIndexes 计算元素的哈希索引
-func (bf *RedissonBloomFilter[T]) getHashIndexes(object T) ([]int64, error) {
-	// 序列化对象为 JSON
-	objBytes, err := json.Marshal(object)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal object: %v", err)
+// decodeBloomConfig 解析 bloomFilterGetConfigScript 返回的配置，{name}:config 哈希的字段值或旧版本的 JSON 配置
+func decodeBloomConfig(res interface{}) (BloomConfig, error) {
+	switch res := res.(type) {
+	case string:
+		var config BloomConfig
+		if err := json.Unmarshal([]byte(res), &config); err != nil {
+			return config, fmt.Errorf("failed to unmarshal Bloom filter config: %v", err)
+		}
+		return config, nil
+	case []interface{}:
+		config, err := parseBloomConfig(res)
+		if err != nil {
+			return config, fmt.Errorf("failed to parse Bloom filter config: %v", err)
+		}
+		return config, nil
 	}
+	return BloomConfig{}, fmt.Errorf("unexpected Bloom filter config %v", res)
+}
 
-	// 使用 SHA256 哈希
-	hashBytes := sha256.Sum256(objBytes)
-
-	// 使用两个独立的哈希值进行双哈希
-	hash1 := binary.BigEndian.Uint64(hashBytes[0:8])
-	hash2 := binary.BigEndian.Uint64(hashBytes[8:16])
+// parseBloomConfig 解析 HMGET 返回的 size、hashIterations、expectedInsertions、falseProbability 和可选的 backend
+func parseBloomConfig(values []interface{}) (BloomConfig, error) {
+	var config BloomConfig
+	if len(values) != 5 {
+		return config, fmt.Errorf("unexpected config %v", values)
+	}
+	config.module = values[4] == bloomFilterModuleBackend
+	fields := make([]string, 4)
+	for i, v := range values[:4] {
+		s, ok := v.(string)
+		if !ok {
+			return config, fmt.Errorf("missing config field %d", i)
+		}
+		fields[i] = s
+	}
+	var err error
+	if config.Size, err = strconv.ParseInt(fields[0], 10, 64); err != nil {
+		return config, err
+	}
+	if config.HashIterations, err = strconv.Atoi(fields[1]); err != nil {
+		return config, err
+	}
+	if config.ExpectedInsertions, err = strconv.ParseInt(fields[2], 10, 64); err != nil {
+		return config, err
+	}
+	if config.FalseProbability, err = strconv.ParseFloat(fields[3], 64); err != nil {
+		return config, err
+	}
+	return config, nil
+}
 
-	indexes := make([]int64, bf.hashIterations)
-	m := bf.size
+// bloomFilterMaxSize 位数组的最大位数，Redis 字符串最大 512MB
+const bloomFilterMaxSize = 1 << 32
+
+// bloomFilterConfigFunction 定义 getConfig()：KEYS[1] 为 {name}:config 哈希，KEYS[2] 为旧版本的 JSON 配置，
+// 返回哈希的字段值或 JSON 字符串，都不存在时返回 nil
+const bloomFilterConfigFunction = `
+local function getConfig()
+    for _, key in ipairs(KEYS) do
+        local t = redis.call('type', key)['ok'];
+        if t == 'hash' then
+            return redis.call('hmget', key, 'size', 'hashIterations', 'expectedInsertions', 'falseProbability', 'backend');
+        end;
+        if t == 'string' then
+            return redis.call('get', key);
+        end;
+    end;
+    return nil;
+end;
+`
+
+// bloomFilterGetConfigScript 返回配置，未初始化时返回 nil
+const bloomFilterGetConfigScript = bloomFilterConfigFunction + `
+return getConfig() or false;
+`
+
+// bloomFilterTryInitScript：ARGV 为 BloomConfig.fields 和最大位数，参数无效时返回错误，ARGV[10] 为空时不写入 backend。
+// 未初始化时写入配置并返回 {1}，已经初始化时返回 {0, 生效的配置}
+const bloomFilterTryInitScript = bloomFilterConfigFunction + `
+local size = tonumber(ARGV[2]);
+local hashIterations = tonumber(ARGV[4]);
+local expectedInsertions = tonumber(ARGV[6]);
+local falseProbability = tonumber(ARGV[8]);
+if expectedInsertions == nil or expectedInsertions <= 0 then
+    return redis.error_reply('Bloom filter expectedInsertions must be positive');
+end;
+if falseProbability == nil or falseProbability <= 0 or falseProbability >= 1 then
+    return redis.error_reply('Bloom filter falseProbability must be between 0 and 1');
+end;
+if size == nil or size <= 0 or size > tonumber(ARGV[11]) or hashIterations == nil or hashIterations <= 0 then
+    return redis.error_reply('Bloom filter size ' .. ARGV[2] .. ' with ' .. ARGV[4] .. ' hash iterations is out of range');
+end;
+local config = getConfig();
+if config then
+    return {0, config};
+end;
+redis.call('hset', KEYS[1], 'size', ARGV[2], 'hashIterations', ARGV[4],
+    'expectedInsertions', ARGV[6], 'falseProbability', ARGV[8], 'count', 0);
+if ARGV[10] ~= '' then
+    redis.call('hset', KEYS[1], 'backend', ARGV[10]);
+end;
+return {1};
+`
 
-	for i := 0; i < bf.hashIterations; i++ {
-		combinedHash := hash1 + uint64(i)*hash2
-		index := int64(combinedHash % uint64(m))
-		indexes[i] = index
+// getHashIndexes 计算元素的哈希索引
+func (bf *RedissonBloomFilter[T]) getHashIndexes(object T) ([]int64, error) {
+	objBytes, err := bf.codec.Encode(object)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode object: %v", err)
 	}
-
-	return indexes, nil
+	return bf.hasher.Indexes(objBytes, bf.hashIterations, bf.size), nil
 }
 
 // optimalBloomParameters 计算布隆过滤器的大小和哈希迭代次数
@@ -364,6 +962,8 @@ func optimalBloomParameters(n int64, p float64) (size int64, hashIterations int)
 
 // SetBit 设置位，如果位被设置返回 false，否则返回 true
 func (bf *RedissonBloomFilter[T]) SetBit(offset int64, value bool) (bool, error) {
+	ctx, cancel := bf.newContext()
+	defer cancel()
 	var bitValue int
 	if value {
 		bitValue = 1
@@ -372,7 +972,7 @@ func (bf *RedissonBloomFilter[T]) SetBit(offset int64, value bool) (bool, error)
 	}
 
 	// 使用 BITSET 设置位
-	result, err := bf.client.SetBit(context.Background(), bf.key, offset, bitValue).Result()
+	result, err := bf.client.SetBit(ctx, bf.key, offset, bitValue).Result()
 	if err != nil {
 		return false, err
 	}
@@ -383,7 +983,9 @@ func (bf *RedissonBloomFilter[T]) SetBit(offset int64, value bool) (bool, error)
 
 // GetBit 获取位的值
 func (bf *RedissonBloomFilter[T]) GetBit(offset int64) (bool, error) {
-	result, err := bf.client.GetBit(context.Background(), bf.key, offset).Result()
+	ctx, cancel := bf.newContext()
+	defer cancel()
+	result, err := bf.client.GetBit(ctx, bf.key, offset).Result()
 	if err != nil {
 		return false, err
 	}
@@ -1,9 +1,14 @@
 package redisson
 
 import (
+	"context"
 	"fmt"
+	"strconv"
+	"sync"
 	"testing"
 	"time"
+
+	"github.com/redis/go-redis/v9"
 )
 
 type User struct {
@@ -60,3 +65,412 @@ func TestBloomFiltermain(t *testing.T) {
 		fmt.Println("Bloom filter will expire in 24 hours.")
 	}
 }
+
+func TestBloomFilterDelete(t *testing.T) {
+	red := GetRedisson()
+	bf := GetBloomFilter[User](red, "bloom_filter_delete")
+	defer bf.Delete()
+	if err := bf.Delete(); err != nil {
+		t.Fatal(err)
+	}
+	if !bf.TryInit(1000, 0.01) {
+		t.Fatal("expected the filter to be initialized")
+	}
+	user := User{ID: 1, Name: "Alice"}
+	bf.Add(user)
+	if !bf.Contains(user) {
+		t.Fatal("expected the filter to contain the user")
+	}
+
+	if err := bf.Delete(); err != nil {
+		t.Fatal(err)
+	}
+	n, err := red.client.Exists(context.Background(), "bloom_filter_delete", "{bloom_filter_delete}:config").Result()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 0 {
+		t.Fatalf("expected the bit array and the config to be deleted, %d keys left", n)
+	}
+	if bf.Contains(user) {
+		t.Fatal("expected a deleted filter not to contain the user")
+	}
+
+	if !bf.TryInit(2000, 0.001) {
+		t.Fatal("expected the filter to be initialized again")
+	}
+	if bf.GetExpectedInsertions() != 2000 {
+		t.Fatalf("expected 2000 expected insertions, got %d", bf.GetExpectedInsertions())
+	}
+	if bf.Contains(user) {
+		t.Fatal("expected a re-initialized filter not to contain the user")
+	}
+}
+
+func TestBloomFilterAddAll(t *testing.T) {
+	red := GetRedisson()
+	bf := GetBloomFilter[int](red, "bloom_filter_add_all")
+	defer bf.Delete()
+	bf.Delete()
+	bf.TryInit(10000, 0.001)
+
+	objects := make([]int, 2500)
+	for i := range objects {
+		objects[i] = i
+	}
+	added, err := bf.AddAll(objects)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if added < 2490 {
+		t.Fatalf("expected about 2500 added elements, got %d", added)
+	}
+	if added, err = bf.AddAll(objects[:10]); err != nil || added != 0 {
+		t.Fatalf("expected no element added again, got %d, %v", added, err)
+	}
+	for _, i := range []int{0, 1234, 2499} {
+		if !bf.Contains(i) {
+			t.Fatalf("expected the filter to contain %d", i)
+		}
+	}
+
+	all, err := bf.ContainsAll(objects)
+	if err != nil || !all {
+		t.Fatalf("expected the filter to contain all the elements, got %v, %v", all, err)
+	}
+	all, err = bf.ContainsAll([]int{1, 2, -1, -2, -3})
+	if err != nil || all {
+		t.Fatalf("expected the filter not to contain all the elements, got %v, %v", all, err)
+	}
+	found, err := bf.ContainsAny([]int{-1, -2, -3, 42})
+	if err != nil || !found {
+		t.Fatalf("expected the filter to contain an element, got %v, %v", found, err)
+	}
+	found, err = bf.ContainsAny([]int{-1, -2, -3})
+	if err != nil || found {
+		t.Fatalf("expected the filter to contain no element, got %v, %v", found, err)
+	}
+}
+
+func TestBloomFilterModuleFallback(t *testing.T) {
+	red := GetRedisson()
+	bf := GetBloomFilter[string](red, "bloom_filter_module", WithBloomFilterModule())
+	defer bf.Delete()
+	bf.Delete()
+	module, err := red.hasCommand(context.Background(), "BF.ADD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bf.TryInit(1000, 0.01) {
+		t.Fatal("expected the filter to be initialized")
+	}
+	// the backend is stored with the filter and followed by the instances without the option
+	plain := GetBloomFilter[string](red, "bloom_filter_module")
+	if stored, err := plain.(*RedissonBloomFilter[string]).useModule(context.Background()); err != nil || stored != module {
+		t.Fatalf("stored=%v err=%v", stored, err)
+	}
+	if bf.TryInit(1000, 0.01) {
+		t.Fatal("expected the filter to be initialized once")
+	}
+	if !bf.Add("a") {
+		t.Fatal("expected a to be added")
+	}
+	if bf.Add("a") {
+		t.Fatal("expected a to be present")
+	}
+	if added, err := bf.AddAll([]string{"a", "b", "c"}); err != nil || added != 2 {
+		t.Fatalf("expected 2 added elements, got %d, %v", added, err)
+	}
+	if !bf.Contains("b") || bf.Contains("d") {
+		t.Fatal("expected the filter to contain b and not d")
+	}
+	if all, err := bf.ContainsAll([]string{"a", "b", "c"}); err != nil || !all {
+		t.Fatalf("expected the filter to contain all the elements, got %v, %v", all, err)
+	}
+	if count := bf.Count(); count != 3 {
+		t.Fatalf("expected 3 elements, got %d", count)
+	}
+	if module {
+		return
+	}
+	// without the module the filter is a bit array
+	typ, err := red.client.Type(context.Background(), "bloom_filter_module").Result()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if typ != "string" {
+		t.Fatalf("expected a bit array, got a %s", typ)
+	}
+	if backend, err := red.client.HGet(context.Background(), "{bloom_filter_module}:config", "backend").Result(); err != redis.Nil {
+		t.Fatalf("backend=%v err=%v", backend, err)
+	}
+
+	// a filter stored by the module stays one for the instances without the option
+	other := GetBloomFilter[string](red, "bloom_filter_module_stored")
+	defer other.Delete()
+	other.Delete()
+	if err = red.client.HSet(context.Background(), "{bloom_filter_module_stored}:config", "size", 9600, "hashIterations", 7,
+		"expectedInsertions", 1000, "falseProbability", "0.01", "backend", "module").Err(); err != nil {
+		t.Fatal(err)
+	}
+	if stored, err := other.(*RedissonBloomFilter[string]).useModule(context.Background()); err != nil || !stored {
+		t.Fatalf("stored=%v err=%v", stored, err)
+	}
+}
+
+func TestBloomFilterConfigHash(t *testing.T) {
+	red := GetRedisson()
+	ctx := context.Background()
+	bf := GetBloomFilter[string](red, "bloom_filter_config")
+	defer bf.Delete()
+	bf.Delete()
+	bf.TryInit(1000, 0.03)
+	// the config has the layout of Java Redisson
+	config, err := red.client.HGetAll(ctx, "{bloom_filter_config}:config").Result()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config["expectedInsertions"] != "1000" || config["falseProbability"] != "0.03" ||
+		config["size"] != strconv.FormatInt(bf.GetSize(), 10) || config["hashIterations"] != strconv.Itoa(bf.GetHashIterations()) {
+		t.Fatalf("unexpected config %v", config)
+	}
+
+	// a config written by Java Redisson
+	if err = red.client.HSet(ctx, "{bloom_filter_config}:config", "size", "9585", "hashIterations", "5", "expectedInsertions", "2000", "falseProbability", "1.0E-4").Err(); err != nil {
+		t.Fatal(err)
+	}
+	java := GetBloomFilter[string](red, "bloom_filter_config")
+	if java.GetSize() != 9585 || java.GetHashIterations() != 5 || java.GetExpectedInsertions() != 2000 || java.GetFalseProbability() != 0.0001 {
+		t.Fatalf("unexpected config %d %d %d %f", java.GetSize(), java.GetHashIterations(), java.GetExpectedInsertions(), java.GetFalseProbability())
+	}
+
+	// a JSON config written by an earlier version
+	bf.Delete()
+	if err = red.client.Set(ctx, "bloom_filter_config:config", `{"expectedInsertions":500,"falseProbability":0.01,"size":4800,"hashIterations":7}`, 0).Err(); err != nil {
+		t.Fatal(err)
+	}
+	legacy := GetBloomFilter[string](red, "bloom_filter_config")
+	if legacy.TryInit(1000, 0.03) {
+		t.Fatal("expected the filter to be initialized already")
+	}
+	if legacy.GetSize() != 4800 || legacy.GetHashIterations() != 7 || legacy.GetExpectedInsertions() != 500 {
+		t.Fatalf("unexpected config %d %d %d", legacy.GetSize(), legacy.GetHashIterations(), legacy.GetExpectedInsertions())
+	}
+	legacy.Add("a")
+	if !legacy.Contains("a") {
+		t.Fatal("expected the filter to contain a")
+	}
+}
+
+func TestBloomFilterCount(t *testing.T) {
+	red := GetRedisson()
+	ctx := context.Background()
+	bf := GetBloomFilter[int](red, "bloom_filter_count")
+	defer bf.Delete()
+	bf.Delete()
+	bf.TryInit(1000, 0.01)
+	if count := bf.Count(); count != 0 {
+		t.Fatalf("expected no element, got %d", count)
+	}
+	bf.Add(1)
+	bf.Add(1)
+	if _, err := bf.AddAll([]int{2, 3, 4, 1}); err != nil {
+		t.Fatal(err)
+	}
+	// an instance which never read the config
+	if count := GetBloomFilter[int](red, "bloom_filter_count").Count(); count != 4 {
+		t.Fatalf("expected 4 elements, got %d", count)
+	}
+
+	// without a counter the number of elements is estimated from the bits
+	if err := red.client.HDel(ctx, "{bloom_filter_count}:config", "count").Err(); err != nil {
+		t.Fatal(err)
+	}
+	if count := GetBloomFilter[int](red, "bloom_filter_count").Count(); count < 3 || count > 5 {
+		t.Fatalf("expected about 4 elements, got %d", count)
+	}
+}
+
+func TestBloomFilterExpire(t *testing.T) {
+	red := GetRedisson()
+	ctx := context.Background()
+	bf := GetBloomFilter[int](red, "bloom_filter_expire")
+	defer bf.Delete()
+	bf.Delete()
+	bf.TryInit(1000, 0.01)
+
+	// the bit array created after Expire expires with the config
+	if ok, err := bf.Expire(time.Minute); err != nil || !ok {
+		t.Fatalf("expected the filter to expire, got %v, %v", ok, err)
+	}
+	bf.Add(1)
+	for _, key := range []string{"bloom_filter_expire", "{bloom_filter_expire}:config"} {
+		ttl, err := red.client.PTTL(ctx, key).Result()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ttl <= 0 || ttl > time.Minute {
+			t.Fatalf("expected %s to expire in a minute, got %v", key, ttl)
+		}
+	}
+
+	if ok, err := bf.ClearExpire(); err != nil || !ok {
+		t.Fatalf("expected the expiration to be cleared, got %v, %v", ok, err)
+	}
+	for _, key := range []string{"bloom_filter_expire", "{bloom_filter_expire}:config"} {
+		ttl, err := red.client.PTTL(ctx, key).Result()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ttl != -1 {
+			t.Fatalf("expected %s not to expire, got %v", key, ttl)
+		}
+	}
+}
+
+func TestBloomFilterTryInitRace(t *testing.T) {
+	red := GetRedisson()
+	GetBloomFilter[int](red, "bloom_filter_try_init").Delete()
+	defer GetBloomFilter[int](red, "bloom_filter_try_init").Delete()
+
+	// instances initializing the filter at once with different parameters all use the winning config
+	filters := make([]RBloomFilter[int], 8)
+	initialized := make([]bool, len(filters))
+	var wg sync.WaitGroup
+	for i := range filters {
+		filters[i] = GetBloomFilter[int](red, "bloom_filter_try_init")
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			initialized[i] = filters[i].TryInit(int64(1000*(i+1)), 0.01)
+		}(i)
+	}
+	wg.Wait()
+	winners := 0
+	for _, ok := range initialized {
+		if ok {
+			winners++
+		}
+	}
+	if winners != 1 {
+		t.Fatalf("expected one filter to be initialized, got %d", winners)
+	}
+	size := filters[0].GetSize()
+	for _, bf := range filters {
+		impl := bf.(*RedissonBloomFilter[int])
+		if impl.size != size {
+			t.Fatalf("expected the winning size %d to be cached, got %d", size, impl.size)
+		}
+	}
+
+	for _, params := range []struct {
+		n int64
+		p float64
+	}{{0, 0.01}, {1000, 0}, {1000, 1}, {1 << 40, 0.01}} {
+		bf := GetBloomFilter[int](red, "bloom_filter_try_init_invalid")
+		if bf.TryInit(params.n, params.p) {
+			t.Fatalf("expected %d insertions with %f false probability to be rejected", params.n, params.p)
+		}
+	}
+	if n, _ := red.client.Exists(context.Background(), "{bloom_filter_try_init_invalid}:config").Result(); n != 0 {
+		t.Fatal("expected no config for invalid parameters")
+	}
+}
+
+func TestBloomFilterConfigCache(t *testing.T) {
+	red := GetRedisson()
+	ctx := context.Background()
+	a := GetBloomFilter[int](red, "bloom_filter_config_cache")
+	b := GetBloomFilter[int](red, "bloom_filter_config_cache")
+	defer a.Delete()
+	a.Delete()
+	a.TryInit(1000, 0.01)
+	size := a.GetSize()
+
+	// the config is read once
+	if err := red.client.HSet(ctx, "{bloom_filter_config_cache}:config", "expectedInsertions", "7").Err(); err != nil {
+		t.Fatal(err)
+	}
+	if n := a.GetExpectedInsertions(); n != 1000 {
+		t.Fatalf("expected the cached 1000 expected insertions, got %d", n)
+	}
+
+	// another instance initializes the filter again with other parameters
+	if err := b.Delete(); err != nil {
+		t.Fatal(err)
+	}
+	if a.Contains(1) {
+		t.Fatal("expected a deleted filter not to contain 1")
+	}
+	if !b.TryInit(100000, 0.001) {
+		t.Fatal("expected the filter to be initialized again")
+	}
+	if !a.Add(1) {
+		t.Fatal("expected 1 to be added with the new config")
+	}
+	if a.GetSize() == size || a.GetSize() != b.GetSize() {
+		t.Fatalf("expected the new size %d, got %d", b.GetSize(), a.GetSize())
+	}
+	if !b.Contains(1) {
+		t.Fatal("expected the other instance to find 1")
+	}
+}
+
+func TestBloomFilterMergeWith(t *testing.T) {
+	red := GetRedisson()
+	global := GetBloomFilter[int](red, "{bloom_filter_merge}:global")
+	partial := GetBloomFilter[int](red, "{bloom_filter_merge}:partial")
+	other := GetBloomFilter[int](red, "{bloom_filter_merge}:other")
+	for _, bf := range []RBloomFilter[int]{global, partial, other} {
+		defer bf.Delete()
+		bf.Delete()
+	}
+	global.TryInit(1000, 0.01)
+	partial.TryInit(1000, 0.01)
+	other.TryInit(2000, 0.01)
+
+	global.Add(1)
+	partial.AddAll([]int{2, 3})
+	if err := global.MergeWith(partial); err != nil {
+		t.Fatal(err)
+	}
+	if all, err := global.ContainsAll([]int{1, 2, 3}); err != nil || !all {
+		t.Fatalf("expected the merged filter to contain all the elements, got %v, %v", all, err)
+	}
+	if count := global.Count(); count < 2 || count > 4 {
+		t.Fatalf("expected about 3 elements, got %d", count)
+	}
+	if err := global.MergeWith(other); err == nil {
+		t.Fatal("expected filters with different parameters not to be merged")
+	}
+}
+
+// bloomPoint has only unexported fields, which encoding/json ignores
+type bloomPoint struct {
+	x, y int
+}
+
+func (p bloomPoint) MarshalBinary() ([]byte, error) {
+	return []byte(fmt.Sprintf("%d,%d", p.x, p.y)), nil
+}
+
+func TestBloomFilterMarshaler(t *testing.T) {
+	red := GetRedisson()
+	// the default encoding is kept, the marshalers are opt-in
+	if _, ok := GetBloomFilter[bloomPoint](red, "bloom_filter_marshaler").(*RedissonBloomFilter[bloomPoint]).codec.(MarshalerCodec); ok {
+		t.Fatal("MarshalerCodec is used by default")
+	}
+	bf := GetBloomFilter[bloomPoint](red, "bloom_filter_marshaler", WithCodec(MarshalerCodec{}))
+	defer bf.Delete()
+	bf.Delete()
+	bf.TryInit(1000, 0.001)
+	if !bf.Add(bloomPoint{1, 2}) {
+		t.Fatal("expected the point to be added")
+	}
+	if !bf.Contains(bloomPoint{1, 2}) {
+		t.Fatal("expected the filter to contain the point")
+	}
+	if bf.Contains(bloomPoint{2, 1}) {
+		t.Fatal("expected the filter not to contain another point")
+	}
+}
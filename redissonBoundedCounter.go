@@ -0,0 +1,165 @@
+package redisson
+
+import (
+	"context"
+	"errors"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrCounterOutOfBounds is returned by a BoundedCounter whose update would leave its bounds.
+var ErrCounterOutOfBounds = errors.New("counter update out of bounds")
+
+// BoundedCounter is a counter whose value stays within a minimum and a maximum, checked atomically by redis.
+// An update which would cross a bound fails with ErrCounterOutOfBounds and leaves the value unchanged,
+// or with WithClampToBounds sets the value to the bound. An empty counter holds 0 moved within the bounds.
+type BoundedCounter interface {
+	RExpirable
+	// Get returns the value.
+	Get() (int64, error)
+	GetContext(ctx context.Context) (int64, error)
+	// Set sets the value, which must be within the bounds.
+	Set(value int64) error
+	SetContext(ctx context.Context, value int64) error
+	// AddAndGet adds delta to the value and returns the new value.
+	AddAndGet(delta int64) (int64, error)
+	AddAndGetContext(ctx context.Context, delta int64) (int64, error)
+	// IncrementAndGet adds 1 to the value and returns the new value.
+	IncrementAndGet() (int64, error)
+	IncrementAndGetContext(ctx context.Context) (int64, error)
+	// DecrementAndGet subtracts 1 from the value and returns the new value.
+	DecrementAndGet() (int64, error)
+	DecrementAndGetContext(ctx context.Context) (int64, error)
+	// Bounds returns the minimum and the maximum of the value.
+	Bounds() (min, max int64)
+}
+
+var (
+	_ BoundedCounter = (*RedissonBoundedCounter)(nil)
+)
+
+// RedissonBoundedCounter is the implementation of BoundedCounter
+type RedissonBoundedCounter struct {
+	*RedissonExpirable
+	min, max int64
+	//clamp whether an update crossing a bound sets the value to the bound instead of failing
+	clamp bool
+}
+
+// newRedissonBoundedCounter creates a new RedissonBoundedCounter, swapping min and max if min is greater
+func newRedissonBoundedCounter(name string, redisson *Redisson, min, max int64, options *objectOptions) *RedissonBoundedCounter {
+	if min > max {
+		min, max = max, min
+	}
+	m := &RedissonBoundedCounter{
+		RedissonExpirable: newRedissonExpirable(name, redisson),
+		min:               min,
+		max:               max,
+		clamp:             options.clampToBounds,
+	}
+	m.ttl = options.ttl
+	return m
+}
+
+// Bounds returns the minimum and the maximum of the value.
+func (m *RedissonBoundedCounter) Bounds() (int64, int64) {
+	return m.min, m.max
+}
+
+// initial returns the value of the empty counter
+func (m *RedissonBoundedCounter) initial() int64 {
+	return min(max(0, m.min), m.max)
+}
+
+func (m *RedissonBoundedCounter) Get() (int64, error) {
+	return m.GetContext(m.baseContext())
+}
+
+func (m *RedissonBoundedCounter) GetContext(ctx context.Context) (int64, error) {
+	ctx, cancel := m.withCommandTimeout(ctx)
+	defer cancel()
+	v, err := m.client.Get(ctx, m.getRawName()).Int64()
+	if err == redis.Nil {
+		return m.initial(), nil
+	}
+	return v, err
+}
+
+func (m *RedissonBoundedCounter) Set(value int64) error {
+	return m.SetContext(m.baseContext(), value)
+}
+
+func (m *RedissonBoundedCounter) SetContext(ctx context.Context, value int64) error {
+	if value < m.min || value > m.max {
+		return ErrCounterOutOfBounds
+	}
+	ctx, cancel := m.withCommandTimeout(ctx)
+	defer cancel()
+	return m.client.Set(ctx, m.getRawName(), value, m.ttl).Err()
+}
+
+func (m *RedissonBoundedCounter) AddAndGet(delta int64) (int64, error) {
+	return m.AddAndGetContext(m.baseContext(), delta)
+}
+
+// AddAndGetContext adds delta to the value and returns the new value. If the new value would be out of the bounds,
+// it returns the unchanged value with ErrCounterOutOfBounds, or with WithClampToBounds sets the value to the bound.
+func (m *RedissonBoundedCounter) AddAndGetContext(ctx context.Context, delta int64) (int64, error) {
+	ctx, cancel := m.withCommandTimeout(ctx)
+	defer cancel()
+	clamp := 0
+	if m.clamp {
+		clamp = 1
+	}
+	r, err := m.eval(ctx, "boundedCounter.addAndGet", boundedCounterAddScript, []string{m.getRawName()},
+		delta, m.min, m.max, m.initial(), clamp).Int64Slice()
+	if err != nil {
+		return 0, err
+	}
+	if r[0] == 0 {
+		return r[1], ErrCounterOutOfBounds
+	}
+	if err = m.applyTTL(ctx, m.getRawName()); err != nil {
+		return 0, err
+	}
+	return r[1], nil
+}
+
+func (m *RedissonBoundedCounter) IncrementAndGet() (int64, error) {
+	return m.AddAndGetContext(m.baseContext(), 1)
+}
+
+func (m *RedissonBoundedCounter) IncrementAndGetContext(ctx context.Context) (int64, error) {
+	return m.AddAndGetContext(ctx, 1)
+}
+
+func (m *RedissonBoundedCounter) DecrementAndGet() (int64, error) {
+	return m.AddAndGetContext(m.baseContext(), -1)
+}
+
+func (m *RedissonBoundedCounter) DecrementAndGetContext(ctx context.Context) (int64, error) {
+	return m.AddAndGetContext(ctx, -1)
+}
+
+// boundedCounterAddScript adds ARGV[1] to KEYS[1], which holds ARGV[4] when empty, if the sum is within ARGV[2]
+// and ARGV[3], or else sets it to the crossed bound when ARGV[5] is 1. INCRBY keeps the TTL of the key. It returns
+// {1, value} when the value was updated and {0, value} with the unchanged value otherwise.
+const boundedCounterAddScript = `
+local stored = redis.call('get', KEYS[1]);
+local current = tonumber(stored or ARGV[4]);
+local min = tonumber(ARGV[2]);
+local max = tonumber(ARGV[3]);
+local value = current + tonumber(ARGV[1]);
+if value < min or value > max then
+    if ARGV[5] ~= '1' then
+        return {0, current};
+    end;
+    value = math.max(min, math.min(max, value));
+end;
+if stored == false then
+    redis.call('set', KEYS[1], string.format('%d', value));
+else
+    redis.call('incrby', KEYS[1], string.format('%d', value - current));
+end;
+return {1, value};
+`
@@ -0,0 +1,72 @@
+package redisson
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestBoundedCounter(t *testing.T) {
+	g := GetRedisson()
+	c := g.GetBoundedCounter("testBoundedCounter", 0, 3)
+	if err := g.client.Del(context.Background(), "testBoundedCounter", "testBoundedCounterEmpty").Err(); err != nil {
+		t.Fatal(err)
+	}
+	if v, err := c.DecrementAndGet(); !errors.Is(err, ErrCounterOutOfBounds) || v != 0 {
+		t.Fatalf("v=%v err=%v", v, err)
+	}
+	if v, err := c.AddAndGet(3); err != nil || v != 3 {
+		t.Fatalf("v=%v err=%v", v, err)
+	}
+	if v, err := c.IncrementAndGet(); !errors.Is(err, ErrCounterOutOfBounds) || v != 3 {
+		t.Fatalf("v=%v err=%v", v, err)
+	}
+	if err := c.Set(4); !errors.Is(err, ErrCounterOutOfBounds) {
+		t.Fatalf("err=%v", err)
+	}
+
+	clamped := g.GetBoundedCounter("testBoundedCounter", 0, 3, WithClampToBounds())
+	if v, err := clamped.AddAndGet(-10); err != nil || v != 0 {
+		t.Fatalf("v=%v err=%v", v, err)
+	}
+	if v, err := clamped.AddAndGet(10); err != nil || v != 3 {
+		t.Fatalf("v=%v err=%v", v, err)
+	}
+
+	// an empty counter whose bounds exclude 0 starts at the nearest bound
+	if v, err := g.GetBoundedCounter("testBoundedCounterEmpty", 5, 10).Get(); err != nil || v != 5 {
+		t.Fatalf("v=%v err=%v", v, err)
+	}
+}
+
+func TestBoundedCounterConcurrent(t *testing.T) {
+	c := GetRedisson().GetBoundedCounter("testBoundedCounterConcurrent", 0, 100)
+	if err := c.Set(50); err != nil {
+		t.Fatal(err)
+	}
+	var taken atomic.Int64
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				_, err := c.DecrementAndGet()
+				if err == nil {
+					taken.Add(1)
+				} else if !errors.Is(err, ErrCounterOutOfBounds) {
+					t.Error(err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if taken.Load() != 50 {
+		t.Fatalf("taken=%v", taken.Load())
+	}
+	if v, err := c.Get(); err != nil || v != 0 {
+		t.Fatalf("v=%v err=%v", v, err)
+	}
+}
@@ -0,0 +1,171 @@
+package redisson
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RBucket holds a single value of type T encoded with the codec of the object.
+type RBucket[T any] interface {
+	RExpirable
+
+	// Get returns the value, or the zero value of T if the bucket is empty.
+	Get() (T, error)
+
+	// Set stores the value.
+	Set(value T) error
+
+	// SetWithTTL stores the value which expires after ttl.
+	SetWithTTL(value T, ttl time.Duration) error
+
+	// TrySet stores the value only if the bucket is empty and reports whether it was stored.
+	TrySet(value T) (bool, error)
+
+	// GetAndSet stores the value and returns the previous one.
+	GetAndSet(value T) (T, error)
+
+	// GetAndDelete deletes the bucket and returns its value.
+	GetAndDelete() (T, error)
+
+	// IsExists reports whether the bucket holds a value.
+	IsExists() (bool, error)
+
+	// Delete deletes the bucket and reports whether it held a value.
+	Delete() (bool, error)
+}
+
+var (
+	_ RBucket[string] = (*RedissonBucket[string])(nil)
+)
+
+// RedissonBucket is the implementation of RBucket
+type RedissonBucket[T any] struct {
+	*RedissonExpirable
+	codec Codec
+}
+
+// newRedissonBucket creates a new RedissonBucket
+func newRedissonBucket[T any](name string, redisson *Redisson, options *objectOptions) *RedissonBucket[T] {
+	m := &RedissonBucket[T]{
+		RedissonExpirable: newRedissonExpirable(name, redisson),
+		codec:             options.codec,
+	}
+	m.ttl = options.ttl
+	return m
+}
+
+// Get returns the value, or the zero value of T if the bucket is empty.
+func (m *RedissonBucket[T]) Get() (T, error) {
+	ctx, cancel := m.newContext()
+	defer cancel()
+	v, _, err := m.get(ctx)
+	return v, err
+}
+
+// get returns the value and whether the bucket held one
+func (m *RedissonBucket[T]) get(ctx context.Context) (T, bool, error) {
+	data, err := m.client.Get(ctx, m.getRawName()).Bytes()
+	if err == redis.Nil {
+		var zero T
+		return zero, false, nil
+	}
+	if err != nil {
+		var zero T
+		return zero, false, err
+	}
+	v, err := m.decode(data)
+	return v, err == nil, err
+}
+
+// Set stores the value, which expires after the TTL of the object if one is configured.
+func (m *RedissonBucket[T]) Set(value T) error {
+	return m.SetWithTTL(value, m.ttl)
+}
+
+// SetWithTTL stores the value which expires after ttl, a ttl of 0 keeps the value forever.
+func (m *RedissonBucket[T]) SetWithTTL(value T, ttl time.Duration) error {
+	ctx, cancel := m.newContext()
+	defer cancel()
+	data, err := m.codec.Encode(value)
+	if err != nil {
+		return err
+	}
+	return m.client.Set(ctx, m.getRawName(), data, ttl).Err()
+}
+
+// TrySet stores the value only if the bucket is empty and reports whether it was stored.
+func (m *RedissonBucket[T]) TrySet(value T) (bool, error) {
+	ctx, cancel := m.newContext()
+	defer cancel()
+	data, err := m.codec.Encode(value)
+	if err != nil {
+		return false, err
+	}
+	return m.client.SetNX(ctx, m.getRawName(), data, m.ttl).Result()
+}
+
+// GetAndSet stores the value and returns the previous one.
+func (m *RedissonBucket[T]) GetAndSet(value T) (T, error) {
+	ctx, cancel := m.newContext()
+	defer cancel()
+	var zero T
+	data, err := m.codec.Encode(value)
+	if err != nil {
+		return zero, err
+	}
+	prev, err := m.client.GetSet(ctx, m.getRawName(), data).Bytes()
+	if err != nil && err != redis.Nil {
+		return zero, err
+	}
+	if ttlErr := m.applyTTL(ctx, m.getRawName()); ttlErr != nil {
+		return zero, ttlErr
+	}
+	if err == redis.Nil {
+		return zero, nil
+	}
+	return m.decode(prev)
+}
+
+// GetAndDelete deletes the bucket and returns its value.
+func (m *RedissonBucket[T]) GetAndDelete() (T, error) {
+	ctx, cancel := m.newContext()
+	defer cancel()
+	var zero T
+	prev, err := m.eval(ctx, "bucket.getAndDelete", `
+local currValue = redis.call('get', KEYS[1]);
+redis.call('del', KEYS[1]);
+return currValue;
+`, []string{m.getRawName()}).Text()
+	if err == redis.Nil {
+		return zero, nil
+	}
+	if err != nil {
+		return zero, err
+	}
+	return m.decode([]byte(prev))
+}
+
+// IsExists reports whether the bucket holds a value.
+func (m *RedissonBucket[T]) IsExists() (bool, error) {
+	ctx, cancel := m.newContext()
+	defer cancel()
+	n, err := m.client.Exists(ctx, m.getRawName()).Result()
+	return n == 1, err
+}
+
+// Delete deletes the bucket and reports whether it held a value.
+func (m *RedissonBucket[T]) Delete() (bool, error) {
+	ctx, cancel := m.newContext()
+	defer cancel()
+	n, err := m.client.Del(ctx, m.getRawName()).Result()
+	return n == 1, err
+}
+
+// decode decodes data with the codec of the bucket
+func (m *RedissonBucket[T]) decode(data []byte) (T, error) {
+	var v T
+	err := m.codec.Decode(data, &v)
+	return v, err
+}
@@ -0,0 +1,88 @@
+package redisson
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBucket(t *testing.T) {
+	b := GetBucket[User](GetRedisson(), "testBucket")
+	if _, err := b.Delete(); err != nil {
+		t.Fatal(err)
+	}
+	if v, err := b.Get(); err != nil {
+		t.Fatal(err)
+	} else if v != (User{}) {
+		t.Fatalf("v=%v", v)
+	}
+	if ok, err := b.TrySet(User{ID: 1, Name: "Alice"}); err != nil {
+		t.Fatal(err)
+	} else if !ok {
+		t.FailNow()
+	}
+	if ok, err := b.TrySet(User{ID: 2, Name: "Bob"}); err != nil {
+		t.Fatal(err)
+	} else if ok {
+		t.FailNow()
+	}
+	if v, err := b.GetAndSet(User{ID: 2, Name: "Bob"}); err != nil {
+		t.Fatal(err)
+	} else if v.ID != 1 {
+		t.Fatalf("v=%v", v)
+	}
+	if v, err := b.GetAndDelete(); err != nil {
+		t.Fatal(err)
+	} else if v.Name != "Bob" {
+		t.Fatalf("v=%v", v)
+	}
+	if ok, err := b.IsExists(); err != nil {
+		t.Fatal(err)
+	} else if ok {
+		t.FailNow()
+	}
+	if err := b.SetWithTTL(User{ID: 3}, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if ttl, err := b.RemainTimeToLive(); err != nil {
+		t.Fatal(err)
+	} else if ttl <= 0 {
+		t.Fatalf("ttl=%v", ttl)
+	}
+}
+
+// upperCodec is a codec storing strings in upper case
+type upperCodec struct{}
+
+func (upperCodec) Encode(v interface{}) ([]byte, error) {
+	return []byte(strings.ToUpper(v.(string))), nil
+}
+
+func (upperCodec) Decode(data []byte, v interface{}) error {
+	*(v.(*string)) = string(data)
+	return nil
+}
+
+func TestBucketWithCodec(t *testing.T) {
+	g := GetRedisson()
+	b := GetBucket[string](g, "testBucketCodec", WithCodec(upperCodec{}))
+	if err := b.Set("hello"); err != nil {
+		t.Fatal(err)
+	}
+	if v, err := b.Get(); err != nil {
+		t.Fatal(err)
+	} else if v != "HELLO" {
+		t.Fatalf("v=%v", v)
+	}
+	// the raw value is written by the per-object codec
+	if v, err := g.client.Get(context.Background(), "testBucketCodec").Result(); err != nil {
+		t.Fatal(err)
+	} else if v != "HELLO" {
+		t.Fatalf("v=%v", v)
+	}
+	// the instance default codec is still used by other objects
+	if v, err := GetBucket[string](g, "testBucketCodec").Get(); err == nil {
+		t.Fatalf("v=%v should not be decoded as JSON", v)
+	}
+}
@@ -0,0 +1,197 @@
+package redisson
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrCacheMiss is returned by a CacheLoader when the value does not exist in the backing store,
+// and by RCache.Get when a missing value is negatively cached.
+var ErrCacheMiss = errors.New("redisson: cache miss")
+
+// CacheLoader loads the value of key from the backing store on a cache miss.
+// It returns ErrCacheMiss when the value does not exist.
+type CacheLoader[T any] func(ctx context.Context, key string) (T, error)
+
+// CacheWriter writes the value of key to the backing store before it is cached by RCache.Put.
+type CacheWriter[T any] func(ctx context.Context, key string, value T) error
+
+// RCache is a read-through / write-through cache in front of a backing store.
+// On a miss only one caller in the cluster runs the loader for a key, the others wait for its result.
+type RCache[T any] interface {
+	// Get returns the cached value of key, loading and caching it on a miss.
+	// It returns ErrCacheMiss if the value does not exist in the backing store.
+	Get(ctx context.Context, key string) (T, error)
+
+	// Put writes the value through the writer, if any, and caches it.
+	Put(ctx context.Context, key string, value T) error
+
+	// Invalidate removes the cached value of key, including a cached miss.
+	Invalidate(ctx context.Context, key string) error
+}
+
+var (
+	_ RCache[string] = (*RedissonCache[string])(nil)
+)
+
+// cacheOptions holds the settings a cache is created with
+type cacheOptions struct {
+	//ttl ttl of cached values, 0 keeps them forever
+	ttl time.Duration
+	//jitter maximum random duration added to ttl, spreading the expiry of values cached together
+	jitter time.Duration
+	//negativeTTL ttl of cached misses, 0 disables negative caching
+	negativeTTL time.Duration
+	//lockWait maximum time a caller waits for the loader of another caller
+	lockWait time.Duration
+}
+
+// WithCacheTTL sets the ttl of cached values, increased by a random duration up to jitter.
+func WithCacheTTL(ttl, jitter time.Duration) ObjectOption {
+	return func(o *objectOptions) {
+		o.cache.ttl = ttl
+		o.cache.jitter = jitter
+	}
+}
+
+// WithNegativeTTL caches misses reported by the loader for ttl.
+func WithNegativeTTL(ttl time.Duration) ObjectOption {
+	return func(o *objectOptions) {
+		o.cache.negativeTTL = ttl
+	}
+}
+
+// WithCacheLoadWait sets the maximum time a caller waits for the loader of another caller, 10 seconds by default.
+func WithCacheLoadWait(d time.Duration) ObjectOption {
+	return func(o *objectOptions) {
+		o.cache.lockWait = d
+	}
+}
+
+const (
+	// cacheValueFlag prefixes a cached value
+	cacheValueFlag = 'v'
+	// cacheMissFlag is stored for a cached miss
+	cacheMissFlag = 'n'
+)
+
+// RedissonCache is the implementation of RCache
+// values are stored in {name}:<key> keys and loads are guarded by {name}:__lock:<key> locks
+type RedissonCache[T any] struct {
+	*RedissonObject
+	codec   Codec
+	loader  CacheLoader[T]
+	writer  CacheWriter[T]
+	options cacheOptions
+}
+
+// newRedissonCache creates a new RedissonCache
+func newRedissonCache[T any](name string, redisson *Redisson, loader CacheLoader[T], writer CacheWriter[T], options *objectOptions) *RedissonCache[T] {
+	m := &RedissonCache[T]{
+		RedissonObject: newRedissonObject(name, redisson),
+		codec:          options.codec,
+		loader:         loader,
+		writer:         writer,
+		options:        options.cache,
+	}
+	if m.options.lockWait <= 0 {
+		m.options.lockWait = 10 * time.Second
+	}
+	return m
+}
+
+// Get returns the cached value of key, loading and caching it on a miss.
+func (m *RedissonCache[T]) Get(ctx context.Context, key string) (v T, err error) {
+	v, ok, err := m.lookup(ctx, key)
+	if ok || err != nil {
+		return v, err
+	}
+
+	// only the holder of the lock loads the value, the others find it cached once they get the lock.
+	// the locks have their own namespace, apart from the keys of the values
+	lock := m.GetLock(m.suffixName(m.getRawName(), "__lock:"+key))
+	lockCtx, cancel := context.WithTimeout(ctx, m.options.lockWait)
+	defer cancel()
+	if err = lock.LockContext(lockCtx); err != nil {
+		return v, err
+	}
+	defer func() {
+		// a lock which cannot be released blocks the other loaders of key until it expires
+		if unlockErr := lock.UnlockContext(context.WithoutCancel(ctx)); unlockErr != nil && err == nil {
+			err = fmt.Errorf("releasing the load lock of %s: %w", key, unlockErr)
+		}
+	}()
+
+	if v, ok, err = m.lookup(ctx, key); ok || err != nil {
+		return v, err
+	}
+	v, err = m.loader(ctx, key)
+	if errors.Is(err, ErrCacheMiss) {
+		if m.options.negativeTTL > 0 {
+			if err := m.client.Set(ctx, m.entryName(key), []byte{cacheMissFlag}, m.options.negativeTTL).Err(); err != nil {
+				return v, err
+			}
+		}
+		return v, ErrCacheMiss
+	}
+	if err != nil {
+		return v, err
+	}
+	return v, m.store(ctx, key, v)
+}
+
+// Put writes the value through the writer, if any, and caches it.
+func (m *RedissonCache[T]) Put(ctx context.Context, key string, value T) error {
+	if m.writer != nil {
+		if err := m.writer(ctx, key, value); err != nil {
+			return err
+		}
+	}
+	return m.store(ctx, key, value)
+}
+
+// Invalidate removes the cached value of key, including a cached miss.
+func (m *RedissonCache[T]) Invalidate(ctx context.Context, key string) error {
+	return m.client.Del(ctx, m.entryName(key)).Err()
+}
+
+// lookup returns the cached value of key and whether the key was cached,
+// a cached miss is reported with ErrCacheMiss
+func (m *RedissonCache[T]) lookup(ctx context.Context, key string) (T, bool, error) {
+	var v T
+	data, err := m.client.Get(ctx, m.entryName(key)).Bytes()
+	if err == redis.Nil {
+		return v, false, nil
+	}
+	if err != nil {
+		return v, false, err
+	}
+	if len(data) == 0 || data[0] == cacheMissFlag {
+		return v, true, ErrCacheMiss
+	}
+	err = m.codec.Decode(data[1:], &v)
+	return v, err == nil, err
+}
+
+// store caches the value of key for the ttl of the cache plus jitter
+func (m *RedissonCache[T]) store(ctx context.Context, key string, value T) error {
+	data, err := m.codec.Encode(value)
+	if err != nil {
+		return err
+	}
+	ttl := m.options.ttl
+	if ttl > 0 && m.options.jitter > 0 {
+		ttl += time.Duration(rand.Int63n(int64(m.options.jitter)))
+	}
+	return m.client.Set(ctx, m.entryName(key), append([]byte{cacheValueFlag}, data...), ttl).Err()
+}
+
+// entryName returns the name of the key holding the cached value of key
+func (m *RedissonCache[T]) entryName(key string) string {
+	return m.suffixName(m.getRawName(), key)
+}
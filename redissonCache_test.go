@@ -0,0 +1,114 @@
+package redisson
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCacheReadThrough(t *testing.T) {
+	g := GetRedisson()
+	var loads int32
+	cache := GetCache[string](g, "testCacheReadThrough", func(ctx context.Context, key string) (string, error) {
+		atomic.AddInt32(&loads, 1)
+		time.Sleep(100 * time.Millisecond)
+		return "value-" + key, nil
+	}, nil, WithCacheTTL(time.Minute, 10*time.Second))
+	if err := cache.Invalidate(context.Background(), "a"); err != nil {
+		t.Fatal(err)
+	}
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v, err := cache.Get(context.Background(), "a")
+			if err != nil {
+				panic(err)
+			}
+			if v != "value-a" {
+				panic(v)
+			}
+		}()
+	}
+	wg.Wait()
+	if loads != 1 {
+		t.Fatalf("loads=%v", loads)
+	}
+}
+
+func TestCacheNegative(t *testing.T) {
+	g := GetRedisson()
+	var loads int32
+	cache := GetCache[string](g, "testCacheNegative", func(ctx context.Context, key string) (string, error) {
+		atomic.AddInt32(&loads, 1)
+		return "", ErrCacheMiss
+	}, nil, WithNegativeTTL(time.Minute))
+	if err := cache.Invalidate(context.Background(), "missing"); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		if _, err := cache.Get(context.Background(), "missing"); !errors.Is(err, ErrCacheMiss) {
+			t.Fatalf("err=%v", err)
+		}
+	}
+	if loads != 1 {
+		t.Fatalf("loads=%v", loads)
+	}
+}
+
+func TestCacheWriteThrough(t *testing.T) {
+	g := GetRedisson()
+	stored := map[string]int{}
+	cache := GetCache[int](g, "testCacheWriteThrough", func(ctx context.Context, key string) (int, error) {
+		return 0, errors.New("unexpected load")
+	}, func(ctx context.Context, key string, value int) error {
+		stored[key] = value
+		return nil
+	})
+	if err := cache.Put(context.Background(), "k", 42); err != nil {
+		t.Fatal(err)
+	}
+	if stored["k"] != 42 {
+		t.Fatalf("stored=%v", stored)
+	}
+	if v, err := cache.Get(context.Background(), "k"); err != nil {
+		t.Fatal(err)
+	} else if v != 42 {
+		t.Fatalf("v=%v", v)
+	}
+}
+
+func TestCacheLockNamespace(t *testing.T) {
+	g := GetRedisson()
+	cache := GetCache[string](g, "testCacheLockNamespace", func(ctx context.Context, key string) (string, error) {
+		return "loaded-" + key, nil
+	}, nil, WithCodec(StringCodec{}))
+	for _, key := range []string{"a", "lock:a"} {
+		if err := cache.Invalidate(context.Background(), key); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// a value whose key looks like the lock of another key does not block its load
+	if err := cache.Put(context.Background(), "lock:a", "put"); err != nil {
+		t.Fatal(err)
+	}
+	if v, err := cache.Get(context.Background(), "a"); err != nil || v != "loaded-a" {
+		t.Fatalf("v=%v err=%v", v, err)
+	}
+	if v, err := cache.Get(context.Background(), "lock:a"); err != nil || v != "put" {
+		t.Fatalf("v=%v err=%v", v, err)
+	}
+	// the values are encoded with the codec of the cache
+	raw, err := g.client.Get(context.Background(), "{testCacheLockNamespace}:a").Result()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if raw != "vloaded-a" {
+		t.Fatalf("raw=%q", raw)
+	}
+}
@@ -0,0 +1,149 @@
+package redisson
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrNotLockOwner indicates that a condition was awaited by a goroutine which does not hold its lock
+var ErrNotLockOwner = errors.New("lock is not held by the current goroutine")
+
+// RCondition lets the holders of a lock wait until another holder signals a change of the state the lock guards,
+// like sync.Cond across instances.
+type RCondition interface {
+	// Await releases the lock, waits until the condition is signaled or ctx is done, then reacquires the lock
+	// as many times as it was held before returning. It must be called by a goroutine holding the lock.
+	// It returns ctx.Err() if ctx is done first, still holding the lock.
+	Await(ctx context.Context) error
+
+	// Signal wakes one goroutine awaiting the condition, if any, and reports whether it woke one.
+	Signal() (bool, error)
+
+	// SignalAll wakes all the goroutines awaiting the condition and returns how many it woke.
+	SignalAll() (int64, error)
+}
+
+var (
+	_ RCondition = (*RedissonCondition)(nil)
+)
+
+// RedissonCondition is the implementation of RCondition
+// the tokens of the waiters are queued in the {lock}:condition:name list and a waiter is woken
+// by publishing its token on the redisson_condition:{lock}:condition:name channel
+type RedissonCondition struct {
+	*RedissonObject
+	lock Lock
+}
+
+// newRedissonCondition creates a new RedissonCondition bound to lock
+func newRedissonCondition(name string, redisson *Redisson, lock Lock) *RedissonCondition {
+	return &RedissonCondition{
+		RedissonObject: newRedissonObject(name, redisson),
+		lock:           lock,
+	}
+}
+
+// getChannelName returns the channel the tokens of the woken waiters are published on
+func (m *RedissonCondition) getChannelName() string {
+	return m.prefixName("redisson_condition", m.getRawName())
+}
+
+// Await releases the lock, waits until the condition is signaled or ctx is done, then reacquires the lock.
+func (m *RedissonCondition) Await(ctx context.Context) error {
+	holds, err := m.lock.GetHoldCount()
+	if err != nil {
+		return err
+	}
+	if holds == 0 {
+		return ErrNotLockOwner
+	}
+	tokenBytes := make([]byte, 16)
+	if _, err = rand.Read(tokenBytes); err != nil {
+		return err
+	}
+	token := hex.EncodeToString(tokenBytes)
+
+	// subscribe before queueing the token so the signal is not missed
+	sub := m.client.Subscribe(ctx, m.getChannelName())
+	defer sub.Close()
+	if _, err = sub.Receive(ctx); err != nil {
+		return err
+	}
+	pushCtx, cancel := m.withCommandTimeout(ctx)
+	err = m.client.RPush(pushCtx, m.getRawName(), token).Err()
+	cancel()
+	if err != nil {
+		return err
+	}
+	for i := int64(0); i < holds; i++ {
+		if err = m.lock.Unlock(); err != nil {
+			return err
+		}
+	}
+
+	waitErr := m.waitSignal(ctx, sub, token)
+	if waitErr != nil {
+		// nobody may wake this waiter any longer
+		remCtx, cancel := m.withCommandTimeout(context.WithoutCancel(ctx))
+		m.client.LRem(remCtx, m.getRawName(), 1, token)
+		cancel()
+	}
+	// the lock is reacquired whether the wait succeeded or not, the caller still expects to hold it
+	for i := int64(0); i < holds; i++ {
+		if err = m.lock.Lock(); err != nil {
+			return err
+		}
+	}
+	return waitErr
+}
+
+// waitSignal waits until token is published or ctx is done
+func (m *RedissonCondition) waitSignal(ctx context.Context, sub *redis.PubSub, token string) error {
+	ch := sub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case msg, ok := <-ch:
+			if !ok {
+				return errors.New("condition subscription closed")
+			}
+			if msg.Payload == token {
+				return nil
+			}
+		}
+	}
+}
+
+// Signal wakes one goroutine awaiting the condition, if any, and reports whether it woke one.
+func (m *RedissonCondition) Signal() (bool, error) {
+	ctx, cancel := m.newContext()
+	defer cancel()
+	woken, err := m.eval(ctx, "condition.signal", `
+local token = redis.call('lpop', KEYS[1]);
+if token == false then
+    return 0;
+end ;
+redis.call('publish', KEYS[2], token);
+return 1;
+`, []string{m.getRawName(), m.getChannelName()}).Int64()
+	return woken == 1, err
+}
+
+// SignalAll wakes all the goroutines awaiting the condition and returns how many it woke.
+func (m *RedissonCondition) SignalAll() (int64, error) {
+	ctx, cancel := m.newContext()
+	defer cancel()
+	return m.eval(ctx, "condition.signalAll", `
+local tokens = redis.call('lrange', KEYS[1], 0, -1);
+redis.call('del', KEYS[1]);
+for i = 1, #tokens, 1 do
+    redis.call('publish', KEYS[2], tokens[i]);
+end ;
+return #tokens;
+`, []string{m.getRawName(), m.getChannelName()}).Int64()
+}
@@ -0,0 +1,79 @@
+package redisson
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestCondition(t *testing.T) {
+	g := GetRedisson()
+	bucket := GetBucket[int](g, "TestConditionState")
+	if err := bucket.Set(0); err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan error)
+	go func() {
+		l := g.GetLock("TestCondition")
+		if err := l.Lock(); err != nil {
+			done <- err
+			return
+		}
+		defer l.Unlock()
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		for {
+			v, err := bucket.Get()
+			if err != nil || v > 0 {
+				done <- err
+				return
+			}
+			if err = l.NewCondition("ready").Await(ctx); err != nil {
+				done <- err
+				return
+			}
+			// the lock is held again once Await returns
+			if held, err := l.IsHeldByCurrentGoroutine(); err != nil || !held {
+				panic("lock not reacquired")
+			}
+		}
+	}()
+
+	l := g.GetLock("TestCondition")
+	cond := l.NewCondition("ready")
+	// wait for the consumer to await the condition
+	for {
+		time.Sleep(10 * time.Millisecond)
+		if waiters, err := g.client.LLen(context.Background(), "{TestCondition}:condition:ready").Result(); err != nil {
+			t.Fatal(err)
+		} else if waiters == 1 {
+			break
+		}
+	}
+	if err := l.Lock(); err != nil {
+		t.Fatal(err)
+	}
+	if err := bucket.Set(1); err != nil {
+		t.Fatal(err)
+	}
+	if woken, err := cond.Signal(); err != nil || !woken {
+		t.Fatalf("woken=%v err=%v", woken, err)
+	}
+	if err := l.Unlock(); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if woken, err := cond.SignalAll(); err != nil || woken != 0 {
+		t.Fatalf("woken=%v err=%v", woken, err)
+	}
+}
+
+func TestConditionAwaitWithoutLock(t *testing.T) {
+	g := GetRedisson()
+	if err := g.GetLock("TestConditionAwaitWithoutLock").NewCondition("c").Await(context.Background()); err != ErrNotLockOwner {
+		t.Fatalf("err=%v", err)
+	}
+}
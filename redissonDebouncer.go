@@ -0,0 +1,174 @@
+package redisson
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log"
+	"time"
+)
+
+// RDebouncer runs a function at most once per interval across all instances, e.g. a cache refresh
+// or a notification fan-out triggered by many replicas at the same time.
+type RDebouncer interface {
+	// Do runs fn unless it already ran in the current interval on any instance, and reports whether it ran.
+	// With WithTrailing, a suppressed call makes fn run once more when the interval ends,
+	// on the instance of the first suppressed caller.
+	Do(ctx context.Context, fn func(ctx context.Context) error) (bool, error)
+}
+
+var (
+	_ RDebouncer = (*RedissonDebouncer)(nil)
+)
+
+// DebouncerOption is a function that can be used to configure a RDebouncer.
+type DebouncerOption func(o *debouncerOptions)
+
+// debouncerOptions holds the settings a debouncer is created with
+type debouncerOptions struct {
+	//trailing runs the function once more at the end of an interval in which calls were suppressed
+	trailing bool
+}
+
+// WithTrailing makes a debouncer run the function once more at the end of an interval in which calls were suppressed,
+// so the last trigger of a burst is never lost.
+func WithTrailing() DebouncerOption {
+	return func(o *debouncerOptions) {
+		o.trailing = true
+	}
+}
+
+const (
+	// debounceRun debounceSuppressed are returned by the debounce and trailing scripts, any other value is the
+	// delay of the trailing run
+	debounceRun        int64 = -1
+	debounceSuppressed int64 = -2
+)
+
+// RedissonDebouncer is the implementation of RDebouncer
+// the name key marks the current interval with a random token, the first caller to set it wins,
+// and the {name}:trailing key holds the token of the interval after which a trailing run is scheduled
+type RedissonDebouncer struct {
+	*RedissonObject
+	interval time.Duration
+	options  debouncerOptions
+}
+
+// newRedissonDebouncer creates a new RedissonDebouncer
+func newRedissonDebouncer(name string, redisson *Redisson, interval time.Duration, opts []DebouncerOption) *RedissonDebouncer {
+	m := &RedissonDebouncer{
+		RedissonObject: newRedissonObject(name, redisson),
+		interval:       interval,
+	}
+	for _, opt := range opts {
+		opt(&m.options)
+	}
+	return m
+}
+
+// getTrailingName returns the name of the key marking a scheduled trailing run
+func (m *RedissonDebouncer) getTrailingName() string {
+	return m.suffixName(m.getRawName(), "trailing")
+}
+
+// Do runs fn unless it already ran in the current interval on any instance, and reports whether it ran.
+func (m *RedissonDebouncer) Do(ctx context.Context, fn func(ctx context.Context) error) (bool, error) {
+	token, err := newDebounceToken()
+	if err != nil {
+		return false, err
+	}
+	cmdCtx, cancel := m.withCommandTimeout(ctx)
+	res, err := m.debounce(cmdCtx, token)
+	cancel()
+	if err != nil {
+		return false, err
+	}
+	switch res {
+	case debounceRun:
+		return true, fn(ctx)
+	case debounceSuppressed:
+		return false, nil
+	}
+	// this caller scheduled the trailing run, which outlives its ctx
+	go m.runTrailing(context.WithoutCancel(ctx), time.Duration(res)*time.Millisecond, fn)
+	return false, nil
+}
+
+// newDebounceToken returns a random token identifying an interval
+func newDebounceToken() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// debounce marks the interval with token if it is not marked yet, or schedules a trailing run if none is
+func (m *RedissonDebouncer) debounce(ctx context.Context, token string) (int64, error) {
+	trailing := 0
+	if m.options.trailing {
+		trailing = 1
+	}
+	return m.eval(ctx, "debouncer.debounce", `
+if redis.call('set', KEYS[1], ARGV[3], 'px', ARGV[1], 'nx') then
+    return -1;
+end ;
+if ARGV[2] == '0' then
+    return -2;
+end ;
+local ttl = redis.call('pttl', KEYS[1]);
+if ttl < 0 then
+    ttl = 0;
+end ;
+if redis.call('set', KEYS[2], redis.call('get', KEYS[1]), 'px', ttl + tonumber(ARGV[1]), 'nx') then
+    return ttl;
+end ;
+return -2;
+`, []string{m.getRawName(), m.getTrailingName()}, m.interval.Milliseconds(), trailing, token).Int64()
+}
+
+// runTrailing runs fn once the current interval ended, starting a new interval. The interval may not have
+// expired yet on the server when wait elapsed, then it waits for the rest of it.
+func (m *RedissonDebouncer) runTrailing(ctx context.Context, wait time.Duration, fn func(ctx context.Context) error) {
+	token, err := newDebounceToken()
+	if err != nil {
+		log.Printf("debouncer %s: trailing run failed: %v", m.getRawName(), err)
+		return
+	}
+	for {
+		<-m.clock.After(max(wait, time.Millisecond))
+		cmdCtx, cancel := m.withCommandTimeout(ctx)
+		res, err := m.eval(cmdCtx, "debouncer.trailing", `
+local current = redis.call('get', KEYS[1]);
+if current ~= false and current == redis.call('get', KEYS[2]) then
+    local ttl = redis.call('pttl', KEYS[1]);
+    if ttl < 0 then
+        ttl = 0;
+    end ;
+    return ttl;
+end ;
+redis.call('del', KEYS[2]);
+if current == false then
+    redis.call('set', KEYS[1], ARGV[2], 'px', ARGV[1]);
+    return -1;
+end ;
+return -2;
+`, []string{m.getRawName(), m.getTrailingName()}, m.interval.Milliseconds(), token).Int64()
+		cancel()
+		if err != nil {
+			log.Printf("debouncer %s: trailing run failed: %v", m.getRawName(), err)
+			return
+		}
+		switch res {
+		case debounceRun:
+			if err = fn(ctx); err != nil {
+				log.Printf("debouncer %s: trailing run failed: %v", m.getRawName(), err)
+			}
+			return
+		case debounceSuppressed:
+			// another caller started the new interval first and ran fn
+			return
+		}
+		wait = time.Duration(res) * time.Millisecond
+	}
+}
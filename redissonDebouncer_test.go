@@ -0,0 +1,71 @@
+package redisson
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDebouncer(t *testing.T) {
+	g := GetRedisson()
+	if err := g.client.Del(context.Background(), "testDebouncer").Err(); err != nil {
+		t.Fatal(err)
+	}
+	var runs atomic.Int32
+	fn := func(ctx context.Context) error {
+		runs.Add(1)
+		return nil
+	}
+	// several instances triggered at the same time run fn once
+	for i := 0; i < 5; i++ {
+		ran, err := GetRedisson().GetDebouncer("testDebouncer", 300*time.Millisecond).Do(context.Background(), fn)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ran != (i == 0) {
+			t.Fatalf("call %d ran=%v", i, ran)
+		}
+	}
+	time.Sleep(400 * time.Millisecond)
+	if ran, err := g.GetDebouncer("testDebouncer", 300*time.Millisecond).Do(context.Background(), fn); err != nil || !ran {
+		t.Fatalf("ran=%v err=%v", ran, err)
+	}
+	if runs.Load() != 2 {
+		t.Fatalf("runs=%v", runs.Load())
+	}
+}
+
+func TestDebouncerTrailing(t *testing.T) {
+	g := GetRedisson()
+	if err := g.client.Del(context.Background(), "testDebouncerTrailing", "{testDebouncerTrailing}:trailing").Err(); err != nil {
+		t.Fatal(err)
+	}
+	debouncer := g.GetDebouncer("testDebouncerTrailing", 200*time.Millisecond, WithTrailing())
+	runs := make(chan time.Time, 4)
+	fn := func(ctx context.Context) error {
+		runs <- time.Now()
+		return nil
+	}
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		if _, err := debouncer.Do(context.Background(), fn); err != nil {
+			t.Fatal(err)
+		}
+	}
+	<-runs
+	// the suppressed calls make fn run once more when the interval ends
+	select {
+	case at := <-runs:
+		if at.Sub(start) < 150*time.Millisecond {
+			t.Fatalf("trailing run after %v", at.Sub(start))
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no trailing run")
+	}
+	select {
+	case <-runs:
+		t.Fatal("more than one trailing run")
+	case <-time.After(400 * time.Millisecond):
+	}
+}
@@ -0,0 +1,263 @@
+package redisson
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// delayedQueueTransferBatch is the maximum number of due values moved to the destination by one script
+	delayedQueueTransferBatch = 100
+
+	// delayedQueueMaxWait bounds the wait of the transfer task between two transfers, in case a message of the
+	// channel was missed
+	delayedQueueMaxWait = 5 * time.Second
+)
+
+// RDelayedQueue holds values offered with a delay until they are due, then moves them to the tail of its destination
+// queue, like the RDelayedQueue of Java Redisson. Every RDelayedQueue runs a transfer task until Close, so the values
+// are delivered as long as one instance has the RDelayedQueue of the destination open. The transfer task is a goroutine
+// holding a pubsub connection of its own, so each GetDelayedQueue costs one connection until Close.
+type RDelayedQueue[T any] interface {
+	RExpirable
+
+	// Offer appends value to the destination queue once delay elapsed.
+	Offer(value T, delay time.Duration) error
+
+	// Size returns the number of values which are not due yet.
+	Size() (int64, error)
+
+	// ReadAll returns the values which are not due yet, in the order they were offered.
+	ReadAll() ([]T, error)
+
+	// Remove removes the first value equal to value which is not due yet, and reports whether there was one.
+	// Values are equal when encoded to the same bytes.
+	Remove(value T) (bool, error)
+
+	// Delete deletes the values which are not due yet and reports whether there were any.
+	Delete() (bool, error)
+
+	// Close stops the transfer task of this instance.
+	Close() error
+}
+
+var (
+	_ RDelayedQueue[string] = (*RedissonDelayedQueue[string])(nil)
+)
+
+// RedissonDelayedQueue is the implementation of RDelayedQueue
+// with the same keys as Java Redisson: the values which are not due yet are stored in the redisson_delay_queue:{destination}
+// list in the order they were offered, and in the redisson_delay_queue_timeout:{destination} zset scored by the time
+// they are due. Each value is packed as struct.pack('dLc0', randomId, len(value), value) like Java Redisson does,
+// the random id keeping equal values distinct, so Java and Go instances sharing a codec may share the delayed queue.
+// The redisson_delay_queue_channel:{destination} channel carries the due time of a value offered before all the others.
+type RedissonDelayedQueue[T any] struct {
+	*RedissonExpirable
+	codec           Codec
+	destinationName string
+
+	cancel context.CancelFunc
+	done   chan struct{}
+	once   sync.Once
+}
+
+// newRedissonDelayedQueue creates a new RedissonDelayedQueue and starts its transfer task
+func newRedissonDelayedQueue[T any](redisson *Redisson, destination RQueue[T]) *RedissonDelayedQueue[T] {
+	m := &RedissonDelayedQueue[T]{
+		RedissonExpirable: newRedissonExpirable("", redisson),
+		codec:             destination.getCodec(),
+		destinationName:   destination.getRawName(),
+		done:              make(chan struct{}),
+	}
+	m.name = m.prefixName("redisson_delay_queue", m.destinationName)
+	m.componentKeys = func() []string {
+		return []string{m.getRawName(), m.getTimeoutName()}
+	}
+	var ctx context.Context
+	ctx, m.cancel = context.WithCancel(context.WithoutCancel(m.baseContext()))
+	go m.run(ctx)
+	return m
+}
+
+// getTimeoutName returns the name of the zset of the due times
+func (m *RedissonDelayedQueue[T]) getTimeoutName() string {
+	return m.prefixName("redisson_delay_queue_timeout", m.destinationName)
+}
+
+// getChannelName returns the name of the channel waking the transfer tasks up
+func (m *RedissonDelayedQueue[T]) getChannelName() string {
+	return m.prefixName("redisson_delay_queue_channel", m.destinationName)
+}
+
+// Offer appends value to the destination queue once delay elapsed.
+func (m *RedissonDelayedQueue[T]) Offer(value T, delay time.Duration) error {
+	data, err := m.codec.Encode(value)
+	if err != nil {
+		return err
+	}
+	id := make([]byte, 8)
+	if _, err = rand.Read(id); err != nil {
+		return err
+	}
+	ctx, cancel := m.newContext()
+	defer cancel()
+	// the random id is packed as a double, 53 bits keep it exact
+	err = m.eval(ctx, "delayedQueue.offer", `
+local value = struct.pack('dLc0', tonumber(ARGV[3]), string.len(ARGV[2]), ARGV[2]);
+redis.call('zadd', KEYS[2], ARGV[1], value);
+redis.call('rpush', KEYS[1], value);
+-- the transfer tasks wait for the first value, wake them up if it changed
+local first = redis.call('zrange', KEYS[2], 0, 0);
+if first[1] == value then
+    redis.call('publish', KEYS[3], ARGV[1]);
+end;
+`, []string{m.getRawName(), m.getTimeoutName(), m.getChannelName()},
+		m.clock.Now().Add(delay).UnixMilli(), data, binary.BigEndian.Uint64(id)>>11).Err()
+	if err != nil && err != redis.Nil {
+		return err
+	}
+	return m.applyTTL(ctx, m.getRawName(), m.getTimeoutName())
+}
+
+// Size returns the number of values which are not due yet.
+func (m *RedissonDelayedQueue[T]) Size() (int64, error) {
+	ctx, cancel := m.newContext()
+	defer cancel()
+	return m.client.ZCard(ctx, m.getTimeoutName()).Result()
+}
+
+// ReadAll returns the values which are not due yet, in the order they were offered.
+func (m *RedissonDelayedQueue[T]) ReadAll() ([]T, error) {
+	ctx, cancel := m.newContext()
+	defer cancel()
+	items, err := m.eval(ctx, "delayedQueue.readAll", `
+local items = redis.call('lrange', KEYS[1], 0, -1);
+local values = {};
+for i = 1, #items do
+    local randomId, value = struct.unpack('dLc0', items[i]);
+    values[i] = value;
+end;
+return values;
+`, []string{m.getRawName()}).StringSlice()
+	if err != nil && err != redis.Nil {
+		return nil, err
+	}
+	values := make([]T, len(items))
+	for i, item := range items {
+		if err = m.codec.Decode([]byte(item), &values[i]); err != nil {
+			return nil, err
+		}
+	}
+	return values, nil
+}
+
+// Remove removes the first value equal to value which is not due yet, and reports whether there was one.
+func (m *RedissonDelayedQueue[T]) Remove(value T) (bool, error) {
+	data, err := m.codec.Encode(value)
+	if err != nil {
+		return false, err
+	}
+	ctx, cancel := m.newContext()
+	defer cancel()
+	return m.eval(ctx, "delayedQueue.remove", `
+local items = redis.call('lrange', KEYS[1], 0, -1);
+for i = 1, #items do
+    local randomId, value = struct.unpack('dLc0', items[i]);
+    if value == ARGV[1] then
+        redis.call('zrem', KEYS[2], items[i]);
+        redis.call('lrem', KEYS[1], 1, items[i]);
+        return 1;
+    end;
+end;
+return 0;
+`, []string{m.getRawName(), m.getTimeoutName()}, data).Bool()
+}
+
+// Delete deletes the values which are not due yet and reports whether there were any.
+func (m *RedissonDelayedQueue[T]) Delete() (bool, error) {
+	ctx, cancel := m.newContext()
+	defer cancel()
+	n, err := m.client.Del(ctx, m.getRawName(), m.getTimeoutName()).Result()
+	return n > 0, err
+}
+
+// Close stops the transfer task of this instance.
+func (m *RedissonDelayedQueue[T]) Close() error {
+	m.once.Do(m.cancel)
+	<-m.done
+	return nil
+}
+
+// run is the transfer task: it moves the due values to the destination whenever the first value is due
+// or a value offered before all the others is published, until ctx is done
+func (m *RedissonDelayedQueue[T]) run(ctx context.Context) {
+	defer close(m.done)
+	sub := m.client.Subscribe(ctx, m.getChannelName())
+	defer sub.Close()
+	// a subscription, including one after the connection was lost, is followed by a transfer
+	ch := sub.ChannelWithSubscriptions()
+	timer := time.NewTimer(delayedQueueMaxWait)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case _, ok := <-ch:
+			if !ok {
+				return
+			}
+		case <-timer.C:
+		}
+		wait, err := m.transfer(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			log.Printf("delayed queue %s: failed to transfer the due values: %v", m.getRawName(), err)
+			wait = time.Second
+		}
+		if !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
+			}
+		}
+		timer.Reset(wait)
+	}
+}
+
+// transfer moves the due values to the destination and returns how long to wait for the next one to be due
+func (m *RedissonDelayedQueue[T]) transfer(ctx context.Context) (time.Duration, error) {
+	transferCtx, cancel := m.withCommandTimeout(ctx)
+	defer cancel()
+	now := m.clock.Now().UnixMilli()
+	next, err := m.eval(transferCtx, "delayedQueue.transfer", `
+local due = redis.call('zrangebyscore', KEYS[2], '-inf', ARGV[1], 'limit', 0, ARGV[2]);
+for i = 1, #due do
+    local randomId, value = struct.unpack('dLc0', due[i]);
+    redis.call('rpush', KEYS[1], value);
+    redis.call('lrem', KEYS[3], 1, due[i]);
+end;
+if #due > 0 then
+    redis.call('zrem', KEYS[2], unpack(due));
+end;
+local first = redis.call('zrange', KEYS[2], 0, 0, 'WITHSCORES');
+if #first == 0 then
+    return -1;
+end;
+return tonumber(first[2]);
+`, []string{m.destinationName, m.getTimeoutName(), m.getRawName()}, now, delayedQueueTransferBatch).Int64()
+	if err != nil {
+		return 0, err
+	}
+	if next < 0 {
+		return delayedQueueMaxWait, nil
+	}
+	return min(max(time.Duration(next-now)*time.Millisecond, 0), delayedQueueMaxWait), nil
+}
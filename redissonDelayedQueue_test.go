@@ -0,0 +1,73 @@
+package redisson
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDelayedQueue(t *testing.T) {
+	r := GetRedisson()
+	destination := GetQueue[string](r, "testDelayedQueue")
+	if _, err := destination.Delete(); err != nil {
+		t.Fatal(err)
+	}
+	delayed := GetDelayedQueue[string](r, destination)
+	defer delayed.Close()
+	if _, err := delayed.Delete(); err != nil {
+		t.Fatal(err)
+	}
+	if err := delayed.Offer("late", 800*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	if err := delayed.Offer("early", 300*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	if err := delayed.Offer("removed", 300*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	if values, err := delayed.ReadAll(); err != nil || !reflect.DeepEqual(values, []string{"late", "early", "removed"}) {
+		t.Fatalf("values=%v err=%v", values, err)
+	}
+	if ok, err := delayed.Remove("removed"); err != nil || !ok {
+		t.Fatalf("ok=%v err=%v", ok, err)
+	}
+	if n, err := delayed.Size(); err != nil || n != 2 {
+		t.Fatalf("n=%v err=%v", n, err)
+	}
+	// the values are packed like Java Redisson: a double random id, the little-endian length and the encoded value
+	data, err := destination.getCodec().Encode("late")
+	if err != nil {
+		t.Fatal(err)
+	}
+	item, err := r.client.LIndex(context.Background(), "redisson_delay_queue:{testDelayedQueue}", 0).Bytes()
+	if err != nil || len(item) != 16+len(data) || binary.LittleEndian.Uint64(item[8:16]) != uint64(len(data)) ||
+		!bytes.Equal(item[16:], data) {
+		t.Fatalf("item=%q err=%v", item, err)
+	}
+	if n, err := destination.Size(); err != nil || n != 0 {
+		t.Fatalf("n=%v err=%v", n, err)
+	}
+
+	// the consumers of the destination may wait with a blocking queue of the same name
+	consumer := GetBlockingQueue[string](r, "testDelayedQueue")
+	start := time.Now()
+	if v, ok, err := consumer.Poll(context.Background(), 3*time.Second); err != nil || !ok || v != "early" {
+		t.Fatalf("v=%v ok=%v err=%v", v, ok, err)
+	}
+	if elapsed := time.Since(start); elapsed < 200*time.Millisecond {
+		t.Fatalf("delivered after %v", elapsed)
+	}
+	if v, ok, err := consumer.Poll(context.Background(), 3*time.Second); err != nil || !ok || v != "late" {
+		t.Fatalf("v=%v ok=%v err=%v", v, ok, err)
+	}
+	if n, err := delayed.Size(); err != nil || n != 0 {
+		t.Fatalf("n=%v err=%v", n, err)
+	}
+	if values, err := delayed.ReadAll(); err != nil || len(values) != 0 {
+		t.Fatalf("values=%v err=%v", values, err)
+	}
+}
@@ -3,6 +3,8 @@ package redisson
 import (
 	"context"
 	"time"
+
+	"github.com/redis/go-redis/v9"
 )
 
 type RExpirable interface {
@@ -27,6 +29,9 @@ type RExpirable interface {
 // RedissonExpirable is the base struct for all expirable objects
 type RedissonExpirable struct {
 	*RedissonObject
+	//componentKeys returns the keys an object is stored in, expired together by Expire, ExpireAt and ClearExpire,
+	//nil for an object stored in its raw name only
+	componentKeys func() []string
 }
 
 // newRedissonExpirable creates a new RedissonExpirable
@@ -42,11 +47,17 @@ func (rep *RedissonExpirable) ExpireAt(t time.Time) (bool, error) {
 	// param can be an extra argument if needed, here we use empty string
 	param := ""
 	// Evaluate the Lua script
-	ctx := context.Background()
-	res, err := rep.client.Eval(ctx, expireAtLuaScript, []string{rep.getRawName()}, timestamp, param).Int64()
+	ctx, cancel := rep.newContext()
+	defer cancel()
+	res, err := rep.eval(ctx, "expirable.expireAt", expireAtLuaScript, rep.getComponentKeys(), timestamp, param).Int64()
 	if err != nil {
 		return false, err
 	}
+	if res == 1 {
+		if err = rep.trackExpiry(ctx, timestamp); err != nil {
+			return true, err
+		}
+	}
 	// Check if at least one key was successfully set to expire
 	return res == 1, nil
 }
@@ -58,29 +69,62 @@ func (rep *RedissonExpirable) Expire(d time.Duration) (bool, error) {
 	param := ""
 
 	// Evaluate the Lua script
-	ctx := context.Background()
-	res, err := rep.client.Eval(ctx, expireLuaScript, []string{rep.getRawName()}, ms, param).Int64()
+	ctx, cancel := rep.newContext()
+	defer cancel()
+	res, err := rep.eval(ctx, "expirable.expire", expireLuaScript, rep.getComponentKeys(), ms, param).Int64()
 	if err != nil {
 		return false, err
 	}
+	if res == 1 {
+		if err = rep.trackExpiry(ctx, rep.clock.Now().UnixMilli()+ms); err != nil {
+			return true, err
+		}
+	}
 	return res == 1, nil
 }
 
 // clearExpire() - Removes any expiration from the key
 func (rep *RedissonExpirable) ClearExpire() (bool, error) {
 
-	ctx := context.Background()
-	res, err := rep.client.Eval(ctx, clearExpireLuaScript, []string{rep.getRawName()}).Int64()
+	ctx, cancel := rep.newContext()
+	defer cancel()
+	res, err := rep.eval(ctx, "expirable.clearExpire", clearExpireLuaScript, rep.getComponentKeys()).Int64()
 	if err != nil {
 		return false, err
 	}
+	if res == 1 {
+		if err = rep.trackExpiry(ctx, -1); err != nil {
+			return true, err
+		}
+	}
 	return res == 1, nil
 }
 
+// getComponentKeys returns the keys the object is stored in
+func (rep *RedissonExpirable) getComponentKeys() []string {
+	if rep.componentKeys != nil {
+		return rep.componentKeys()
+	}
+	return []string{rep.getRawName()}
+}
+
+// trackExpiry records the expire time (Unix ms) of the object in the shadow zset read by OnPreExpiry,
+// or removes the object from it if expireAt is negative
+func (rep *RedissonExpirable) trackExpiry(ctx context.Context, expireAt int64) error {
+	if !rep.expiryTracking {
+		return nil
+	}
+	if expireAt < 0 {
+		return rep.client.ZRem(ctx, expiryWatchKey, rep.getRawName()).Err()
+	}
+	return rep.client.ZAdd(ctx, expiryWatchKey, redis.Z{Score: float64(expireAt), Member: rep.getRawName()}).Err()
+}
+
 // remainTimeToLive() - Returns the remaining TTL in milliseconds
 func (rep *RedissonExpirable) RemainTimeToLive() (int64, error) {
 
-	ctx := context.Background()
+	ctx, cancel := rep.newContext()
+	defer cancel()
 
 	ttl, err := rep.client.PTTL(ctx, rep.getRawName()).Result()
 	if err != nil {
@@ -102,12 +146,14 @@ func (rep *RedissonExpirable) GetExpireTime() (int64, error) {
 		return -1, nil
 	}
 	// Current time in milliseconds + remaining TTL
-	return (time.Now().UnixNano()/1e6 + ttl), nil
+	return (rep.clock.Now().UnixNano()/1e6 + ttl), nil
 }
 
 // TTL 获取键的剩余过期时间
 func (re *RedissonExpirable) TTL(key string) (time.Duration, error) {
-	duration, err := re.client.TTL(context.Background(), key).Result()
+	ctx, cancel := re.newContext()
+	defer cancel()
+	duration, err := re.client.TTL(ctx, key).Result()
 	return duration, err
 }
 
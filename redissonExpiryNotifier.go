@@ -0,0 +1,96 @@
+package redisson
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// expiryWatchKey is the shadow zset of the expire times (Unix ms) of the objects, maintained by Expire, ExpireAt
+// and ClearExpire when expiry tracking is enabled
+const expiryWatchKey = "redisson__expiry_watch"
+
+// PreExpiryListener is called with the name of an object and its expire time shortly before the object expires.
+type PreExpiryListener func(name string, expireAt time.Time)
+
+// OnPreExpiry calls listener once for every object whose expiration was set with Expire or ExpireAt,
+// when less than before remains until it expires, so that hot keys can be refreshed proactively.
+// Every instance setting expirations must be created with WithExpiryTracking. Each listener runs on its own
+// scheduler, call the returned function to stop it.
+func (g *Redisson) OnPreExpiry(before time.Duration, listener PreExpiryListener) (stop func()) {
+	interval := before / 4
+	if interval < 10*time.Millisecond {
+		interval = 10 * time.Millisecond
+	}
+	if interval > time.Second {
+		interval = time.Second
+	}
+	ctx, cancel := context.WithCancel(context.WithoutCancel(g.baseContext()))
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		// notified expire times by name, so that an object is notified once per expiration
+		notified := make(map[string]int64)
+		for {
+			if err := g.notifyPreExpiry(ctx, before, listener, notified); err != nil && ctx.Err() == nil {
+				log.Printf("pre-expiry notification failed: %v", err)
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-g.clock.After(interval):
+			}
+		}
+	}()
+	return func() {
+		cancel()
+		wg.Wait()
+	}
+}
+
+// notifyPreExpiry calls listener for the objects expiring within before which were not notified yet
+func (g *Redisson) notifyPreExpiry(ctx context.Context, before time.Duration, listener PreExpiryListener, notified map[string]int64) error {
+	now := g.clock.Now().UnixMilli()
+	// expired objects no longer need to be watched
+	if err := g.client.ZRemRangeByScore(ctx, expiryWatchKey, "-inf", "("+strconv.FormatInt(now, 10)).Err(); err != nil {
+		return err
+	}
+	entries, err := g.client.ZRangeByScoreWithScores(ctx, expiryWatchKey, &redis.ZRangeBy{
+		Min: strconv.FormatInt(now, 10),
+		Max: strconv.FormatInt(now+before.Milliseconds(), 10),
+	}).Result()
+	if err != nil {
+		return err
+	}
+	for name, expireAt := range notified {
+		if expireAt < now {
+			delete(notified, name)
+		}
+	}
+	for _, e := range entries {
+		name := e.Member.(string)
+		expireAt := int64(e.Score)
+		if notified[name] == expireAt {
+			continue
+		}
+		// the key may have been persisted or overwritten since its expiration was recorded
+		ttl, err := g.client.PTTL(ctx, name).Result()
+		if err != nil {
+			return err
+		}
+		if ttl < 0 {
+			if err = g.client.ZRem(ctx, expiryWatchKey, name).Err(); err != nil {
+				return err
+			}
+			continue
+		}
+		notified[name] = expireAt
+		listener(name, g.clock.Now().Add(ttl))
+	}
+	return nil
+}
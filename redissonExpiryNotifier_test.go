@@ -0,0 +1,45 @@
+package redisson
+
+import (
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+func TestOnPreExpiry(t *testing.T) {
+	redisDB := redis.NewClient(&redis.Options{
+		Addr: redisAddr,
+	})
+	g := NewRedisson(redisDB, WithExpiryTracking())
+	bucket := GetBucket[string](g, "testOnPreExpiry")
+	if err := bucket.Set("hot"); err != nil {
+		t.Fatal(err)
+	}
+
+	notified := make(chan string, 4)
+	stop := g.OnPreExpiry(500*time.Millisecond, func(name string, expireAt time.Time) {
+		notified <- name
+	})
+	defer stop()
+
+	if ok, err := bucket.Expire(time.Second); err != nil || !ok {
+		t.Fatalf("ok=%v err=%v", ok, err)
+	}
+	select {
+	case name := <-notified:
+		if name != "testOnPreExpiry" {
+			t.Fatalf("name=%v", name)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no pre-expiry notification")
+	}
+	if exists, err := bucket.IsExists(); err != nil || !exists {
+		t.Fatal("notification should come before the object expires")
+	}
+	select {
+	case name := <-notified:
+		t.Fatalf("%v notified twice", name)
+	case <-time.After(300 * time.Millisecond):
+	}
+}
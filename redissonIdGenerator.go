@@ -0,0 +1,157 @@
+package redisson
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ErrIdGeneratorExpire is returned by Expire and ExpireAt of an IdGenerator, which would restart at its initial
+// value once expired and hand out the same ids again
+var ErrIdGeneratorExpire = errors.New("an id generator cannot expire")
+
+// DefaultIdAllocationSize is the number of ids reserved at once by an IdGenerator which was not initialized by TryInit
+const DefaultIdAllocationSize int64 = 5000
+
+// IdGenerator hands out ids unique across all its instances. Each instance reserves blocks of allocationSize
+// consecutive ids with one round trip and hands them out locally, so the ids of an instance increase but the ids
+// of all the instances are not ordered, and the ids left in a block when the process stops are never handed out.
+// It uses the layout of the RIdGenerator of Java Redisson.
+// An IdGenerator never expires: WithTTL and WithDefaultObjectTTL are ignored and Expire and ExpireAt return
+// ErrIdGeneratorExpire.
+type IdGenerator interface {
+	RExpirable
+	// TryInit sets the first id and the allocation size if the generator was not initialized and reports whether it did.
+	TryInit(value, allocationSize int64) (bool, error)
+	TryInitContext(ctx context.Context, value, allocationSize int64) (bool, error)
+	// NextId returns the next id.
+	NextId() (int64, error)
+	NextIdContext(ctx context.Context) (int64, error)
+}
+
+var (
+	_ IdGenerator = (*RedissonIdGenerator)(nil)
+)
+
+// RedissonIdGenerator is the implementation of IdGenerator
+type RedissonIdGenerator struct {
+	*RedissonExpirable
+	mu sync.Mutex
+	//next end the ids of the reserved block which were not handed out yet, from next inclusive to end exclusive
+	next, end int64
+}
+
+// newRedissonIdGenerator creates a new RedissonIdGenerator
+func newRedissonIdGenerator(name string, redisson *Redisson, options *objectOptions) *RedissonIdGenerator {
+	m := &RedissonIdGenerator{
+		RedissonExpirable: newRedissonExpirable(name, redisson),
+	}
+	// the generator is not given a TTL, see ErrIdGeneratorExpire
+	m.ttl = 0
+	m.componentKeys = func() []string {
+		return []string{m.getRawName(), m.getAllocationSizeName()}
+	}
+	return m
+}
+
+// getAllocationSizeName returns the name of the key of the allocation size
+func (m *RedissonIdGenerator) getAllocationSizeName() string {
+	return m.suffixName(m.getRawName(), "allocation")
+}
+
+func (m *RedissonIdGenerator) TryInit(value, allocationSize int64) (bool, error) {
+	return m.TryInitContext(m.baseContext(), value, allocationSize)
+}
+
+// TryInitContext sets the first id to value and the number of ids reserved at once to allocationSize
+// if the generator was not initialized, and reports whether it did.
+func (m *RedissonIdGenerator) TryInitContext(ctx context.Context, value, allocationSize int64) (bool, error) {
+	if allocationSize <= 0 {
+		return false, errors.New("allocationSize must be greater than 0")
+	}
+	ctx, cancel := m.withCommandTimeout(ctx)
+	defer cancel()
+	r, err := m.eval(ctx, "idGenerator.tryInit", idGeneratorTryInitScript,
+		[]string{m.getRawName(), m.getAllocationSizeName()}, value, allocationSize).Int()
+	if err != nil {
+		return false, err
+	}
+	return r == 1, nil
+}
+
+// Expire returns ErrIdGeneratorExpire.
+func (m *RedissonIdGenerator) Expire(time.Duration) (bool, error) {
+	return false, ErrIdGeneratorExpire
+}
+
+// ExpireAt returns ErrIdGeneratorExpire.
+func (m *RedissonIdGenerator) ExpireAt(time.Time) (bool, error) {
+	return false, ErrIdGeneratorExpire
+}
+
+func (m *RedissonIdGenerator) NextId() (int64, error) {
+	return m.NextIdContext(m.baseContext())
+}
+
+// NextIdContext returns the next id of the reserved block, reserving a new block when it is used up.
+func (m *RedissonIdGenerator) NextIdContext(ctx context.Context) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.next < m.end {
+		id := m.next
+		m.next++
+		return id, nil
+	}
+	ctx, cancel := m.withCommandTimeout(ctx)
+	defer cancel()
+	// the script returns the values as strings, Lua numbers lose the precision of the ids above 2^53
+	r, err := m.eval(ctx, "idGenerator.nextId", idGeneratorNextIdScript,
+		[]string{m.getRawName(), m.getAllocationSizeName()}, DefaultIdAllocationSize).StringSlice()
+	if err != nil {
+		return 0, err
+	}
+	if len(r) != 2 {
+		return 0, fmt.Errorf("unexpected id generator reply %v", r)
+	}
+	id, err := strconv.ParseInt(r[0], 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	allocationSize, err := strconv.ParseInt(r[1], 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	m.next, m.end = id+1, id+allocationSize
+	return id, nil
+}
+
+// idGeneratorTryInitScript sets the next id KEYS[1] to ARGV[1] and the allocation size KEYS[2] to ARGV[2]
+// if the generator was not initialized, it returns 1 if it did and 0 otherwise
+const idGeneratorTryInitScript = `
+if redis.call('exists', KEYS[1]) == 1 or redis.call('exists', KEYS[2]) == 1 then
+    return 0;
+end;
+redis.call('set', KEYS[1], ARGV[1]);
+redis.call('set', KEYS[2], ARGV[2]);
+return 1;
+`
+
+// idGeneratorNextIdScript reserves the block of ids from the next id KEYS[1], 1 if not initialized, of the
+// allocation size KEYS[2], ARGV[1] if not initialized, and returns the first id and the size of the block as strings
+const idGeneratorNextIdScript = `
+local allocationSize = redis.call('get', KEYS[2]);
+if allocationSize == false then
+    allocationSize = ARGV[1];
+    redis.call('set', KEYS[2], allocationSize);
+end;
+local value = redis.call('get', KEYS[1]);
+if value == false then
+    value = '1';
+    redis.call('set', KEYS[1], value);
+end;
+redis.call('incrby', KEYS[1], allocationSize);
+return {value, allocationSize};
+`
@@ -0,0 +1,97 @@
+package redisson
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestIdGenerator(t *testing.T) {
+	g := GetRedisson()
+	if err := g.client.Del(context.Background(), "testIdGenerator", "{testIdGenerator}:allocation").Err(); err != nil {
+		t.Fatal(err)
+	}
+	gen := g.GetIdGenerator("testIdGenerator")
+	if ok, err := gen.TryInit(100, 10); err != nil || !ok {
+		t.Fatalf("ok=%v err=%v", ok, err)
+	}
+	if ok, err := gen.TryInit(1, 1); err != nil || ok {
+		t.Fatalf("ok=%v err=%v", ok, err)
+	}
+	for want := int64(100); want < 125; want++ {
+		if id, err := gen.NextId(); err != nil || id != want {
+			t.Fatalf("id=%v want %v err=%v", id, want, err)
+		}
+	}
+	// the next instance reserves the block after the 3 blocks of the first one
+	if id, err := g.GetIdGenerator("testIdGenerator").NextId(); err != nil || id != 130 {
+		t.Fatalf("id=%v err=%v", id, err)
+	}
+}
+
+func TestIdGeneratorLargeIds(t *testing.T) {
+	g := GetRedisson()
+	if err := g.client.Del(context.Background(), "testIdGeneratorLargeIds", "{testIdGeneratorLargeIds}:allocation").Err(); err != nil {
+		t.Fatal(err)
+	}
+	// above 2^53 a float64 cannot hold every id
+	const first = int64(1)<<60 + 1
+	gen := g.GetIdGenerator("testIdGeneratorLargeIds", WithTTL(time.Minute))
+	if ok, err := gen.TryInit(first, 2); err != nil || !ok {
+		t.Fatalf("ok=%v err=%v", ok, err)
+	}
+	for want := first; want < first+3; want++ {
+		if id, err := gen.NextId(); err != nil || id != want {
+			t.Fatalf("id=%v want %v err=%v", id, want, err)
+		}
+	}
+
+	// the generator never expires
+	if ttl := g.client.PTTL(context.Background(), "testIdGeneratorLargeIds").Val(); ttl != -time.Nanosecond {
+		t.Fatalf("ttl=%v", ttl)
+	}
+	if _, err := gen.Expire(time.Minute); !errors.Is(err, ErrIdGeneratorExpire) {
+		t.Fatalf("err=%v", err)
+	}
+	if _, err := gen.ExpireAt(time.Now().Add(time.Minute)); !errors.Is(err, ErrIdGeneratorExpire) {
+		t.Fatalf("err=%v", err)
+	}
+}
+
+func TestIdGeneratorConcurrent(t *testing.T) {
+	g := GetRedisson()
+	if err := g.client.Del(context.Background(), "testIdGeneratorConcurrent", "{testIdGeneratorConcurrent}:allocation").Err(); err != nil {
+		t.Fatal(err)
+	}
+	var mu sync.Mutex
+	seen := make(map[int64]bool)
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		gen := g.GetIdGenerator("testIdGeneratorConcurrent")
+		for j := 0; j < 4; j++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for k := 0; k < 2000; k++ {
+					id, err := gen.NextId()
+					if err != nil {
+						t.Error(err)
+						return
+					}
+					mu.Lock()
+					if seen[id] {
+						t.Errorf("id %d handed out twice", id)
+					}
+					seen[id] = true
+					mu.Unlock()
+				}
+			}()
+		}
+	}
+	wg.Wait()
+	if len(seen) != 32000 {
+		t.Fatalf("len=%v", len(seen))
+	}
+}
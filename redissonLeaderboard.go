@@ -0,0 +1,256 @@
+package redisson
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// LeaderboardEntry is a member of a RLeaderboard with its score and rank.
+type LeaderboardEntry struct {
+	Member string
+	Score  float64
+	// Rank is the 0-based position of the member, highest score first
+	Rank int64
+}
+
+// RLeaderboard ranks members by score, highest first, over a redis sorted set.
+type RLeaderboard interface {
+	RExpirable
+
+	// IncrementScore adds delta to the score of member, adding it with a score of delta if absent, and returns the new score.
+	IncrementScore(member string, delta float64) (float64, error)
+
+	// GetScore returns the score of member and whether it is ranked.
+	GetScore(member string) (float64, bool, error)
+
+	// GetRank returns the 0-based rank of member by ascending score, -1 if it is not ranked.
+	GetRank(member string) (int64, error)
+
+	// GetRevRank returns the 0-based rank of member by descending score, -1 if it is not ranked.
+	GetRevRank(member string) (int64, error)
+
+	// Around returns member and up to n members ranked right above and below it, highest score first.
+	// It returns no entries if member is not ranked.
+	Around(member string, n int64) ([]LeaderboardEntry, error)
+
+	// Top returns the 0-based page of n members, highest score first.
+	Top(n int64, page int64) ([]LeaderboardEntry, error)
+
+	// Size returns the number of ranked members.
+	Size() (int64, error)
+
+	// Snapshot copies the leaderboard to the snapshot id, which expires after ttl unless ttl is 0.
+	Snapshot(id string, ttl time.Duration) error
+
+	// Rotate moves the leaderboard to the snapshot id, which expires after ttl unless ttl is 0, and starts an empty one.
+	// It reports whether the leaderboard was rotated, false if it was empty or the snapshot already exists.
+	Rotate(id string, ttl time.Duration) (bool, error)
+
+	// RotateEvery rotates the leaderboard at the end of every period until ctx is done, into a snapshot
+	// identified by the Unix time of the start of the period. Every instance may run it, each period is rotated once.
+	RotateEvery(ctx context.Context, period time.Duration, ttl time.Duration)
+
+	// GetSnapshot returns the leaderboard saved as the snapshot id.
+	GetSnapshot(id string) RLeaderboard
+}
+
+var (
+	_ RLeaderboard = (*RedissonLeaderboard)(nil)
+)
+
+// RedissonLeaderboard is the implementation of RLeaderboard
+// the scores are stored in the name sorted set and the snapshots in the {name}:snapshot:id sorted sets
+type RedissonLeaderboard struct {
+	*RedissonExpirable
+}
+
+// newRedissonLeaderboard creates a new RedissonLeaderboard
+func newRedissonLeaderboard(name string, redisson *Redisson, options *objectOptions) *RedissonLeaderboard {
+	m := &RedissonLeaderboard{
+		RedissonExpirable: newRedissonExpirable(name, redisson),
+	}
+	m.ttl = options.ttl
+	return m
+}
+
+// getSnapshotName returns the name of the sorted set of the snapshot id
+func (m *RedissonLeaderboard) getSnapshotName(id string) string {
+	return m.suffixName(m.getRawName(), "snapshot:"+id)
+}
+
+// IncrementScore adds delta to the score of member and returns the new score.
+func (m *RedissonLeaderboard) IncrementScore(member string, delta float64) (float64, error) {
+	ctx, cancel := m.newContext()
+	defer cancel()
+	score, err := m.client.ZIncrBy(ctx, m.getRawName(), delta, member).Result()
+	if err != nil {
+		return 0, err
+	}
+	return score, m.applyTTL(ctx, m.getRawName())
+}
+
+// GetScore returns the score of member and whether it is ranked.
+func (m *RedissonLeaderboard) GetScore(member string) (float64, bool, error) {
+	ctx, cancel := m.newContext()
+	defer cancel()
+	score, err := m.client.ZScore(ctx, m.getRawName(), member).Result()
+	if err == redis.Nil {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return score, true, nil
+}
+
+// GetRank returns the 0-based rank of member by ascending score, -1 if it is not ranked.
+func (m *RedissonLeaderboard) GetRank(member string) (int64, error) {
+	ctx, cancel := m.newContext()
+	defer cancel()
+	return rankOrMissing(m.client.ZRank(ctx, m.getRawName(), member).Result())
+}
+
+// GetRevRank returns the 0-based rank of member by descending score, -1 if it is not ranked.
+func (m *RedissonLeaderboard) GetRevRank(member string) (int64, error) {
+	ctx, cancel := m.newContext()
+	defer cancel()
+	return rankOrMissing(m.client.ZRevRank(ctx, m.getRawName(), member).Result())
+}
+
+// rankOrMissing returns -1 for the rank of a member which is not ranked
+func rankOrMissing(rank int64, err error) (int64, error) {
+	if err == redis.Nil {
+		return -1, nil
+	}
+	if err != nil {
+		return -1, err
+	}
+	return rank, nil
+}
+
+// Around returns member and up to n members ranked right above and below it, highest score first.
+func (m *RedissonLeaderboard) Around(member string, n int64) ([]LeaderboardEntry, error) {
+	ctx, cancel := m.newContext()
+	defer cancel()
+	rank, err := rankOrMissing(m.client.ZRevRank(ctx, m.getRawName(), member).Result())
+	if err != nil || rank < 0 {
+		return nil, err
+	}
+	start := rank - n
+	if start < 0 {
+		start = 0
+	}
+	return m.rangeEntries(ctx, start, rank+n)
+}
+
+// Top returns the 0-based page of n members, highest score first.
+func (m *RedissonLeaderboard) Top(n int64, page int64) ([]LeaderboardEntry, error) {
+	if n <= 0 || page < 0 {
+		return nil, nil
+	}
+	ctx, cancel := m.newContext()
+	defer cancel()
+	return m.rangeEntries(ctx, page*n, (page+1)*n-1)
+}
+
+// rangeEntries returns the members ranked from start to stop inclusive, highest score first
+func (m *RedissonLeaderboard) rangeEntries(ctx context.Context, start, stop int64) ([]LeaderboardEntry, error) {
+	members, err := m.client.ZRevRangeWithScores(ctx, m.getRawName(), start, stop).Result()
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]LeaderboardEntry, len(members))
+	for i, z := range members {
+		entries[i] = LeaderboardEntry{
+			Member: z.Member.(string),
+			Score:  z.Score,
+			Rank:   start + int64(i),
+		}
+	}
+	return entries, nil
+}
+
+// Size returns the number of ranked members.
+func (m *RedissonLeaderboard) Size() (int64, error) {
+	ctx, cancel := m.newContext()
+	defer cancel()
+	return m.client.ZCard(ctx, m.getRawName()).Result()
+}
+
+// Snapshot copies the leaderboard to the snapshot id, which expires after ttl unless ttl is 0.
+func (m *RedissonLeaderboard) Snapshot(id string, ttl time.Duration) error {
+	ctx, cancel := m.newContext()
+	defer cancel()
+	_, err := m.eval(ctx, "leaderboard.snapshot", `
+redis.call('del', KEYS[2]);
+if redis.call('exists', KEYS[1]) == 0 then
+    return 0;
+end ;
+redis.call('zunionstore', KEYS[2], 1, KEYS[1]);
+if tonumber(ARGV[1]) > 0 then
+    redis.call('pexpire', KEYS[2], ARGV[1]);
+end ;
+return 1;
+`, []string{m.getRawName(), m.getSnapshotName(id)}, ttl.Milliseconds()).Result()
+	return err
+}
+
+// Rotate moves the leaderboard to the snapshot id, which expires after ttl unless ttl is 0, and starts an empty one.
+func (m *RedissonLeaderboard) Rotate(id string, ttl time.Duration) (bool, error) {
+	ctx, cancel := m.newContext()
+	defer cancel()
+	return m.rotate(ctx, id, ttl)
+}
+
+// rotate renames the leaderboard to the snapshot id unless the snapshot exists
+func (m *RedissonLeaderboard) rotate(ctx context.Context, id string, ttl time.Duration) (bool, error) {
+	rotated, err := m.eval(ctx, "leaderboard.rotate", `
+if redis.call('exists', KEYS[1]) == 0 or redis.call('exists', KEYS[2]) == 1 then
+    return 0;
+end ;
+redis.call('rename', KEYS[1], KEYS[2]);
+if tonumber(ARGV[1]) > 0 then
+    redis.call('pexpire', KEYS[2], ARGV[1]);
+else
+    redis.call('persist', KEYS[2]);
+end ;
+return 1;
+`, []string{m.getRawName(), m.getSnapshotName(id)}, ttl.Milliseconds()).Int64()
+	if err != nil {
+		return false, err
+	}
+	return rotated == 1, nil
+}
+
+// RotateEvery rotates the leaderboard at the end of every period until ctx is done.
+func (m *RedissonLeaderboard) RotateEvery(ctx context.Context, period time.Duration, ttl time.Duration) {
+	go func() {
+		for {
+			now := m.clock.Now()
+			end := now.Truncate(period).Add(period)
+			select {
+			case <-ctx.Done():
+				return
+			case <-m.clock.After(end.Sub(now)):
+			}
+			// the snapshot of a period is named after its start, so only the first instance rotates it
+			id := strconv.FormatInt(end.Add(-period).Unix(), 10)
+			rotateCtx, cancel := m.withCommandTimeout(ctx)
+			_, err := m.rotate(rotateCtx, id, ttl)
+			cancel()
+			if err != nil && ctx.Err() == nil {
+				log.Printf("leaderboard %s: rotation failed: %v", m.getRawName(), err)
+			}
+		}
+	}()
+}
+
+// GetSnapshot returns the leaderboard saved as the snapshot id.
+func (m *RedissonLeaderboard) GetSnapshot(id string) RLeaderboard {
+	// the snapshot keeps the TTL it was saved with
+	return newRedissonLeaderboard(m.getSnapshotName(id), m.Redisson, &objectOptions{})
+}
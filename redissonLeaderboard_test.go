@@ -0,0 +1,101 @@
+package redisson
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestLeaderboard(t *testing.T) {
+	g := GetRedisson()
+	board := g.GetLeaderboard("testLeaderboard")
+	if err := g.client.Del(context.Background(), "testLeaderboard", "{testLeaderboard}:snapshot:s1").Err(); err != nil {
+		t.Fatal(err)
+	}
+	for i, member := range []string{"a", "b", "c", "d", "e"} {
+		if _, err := board.IncrementScore(member, float64(i+1)*10); err != nil {
+			t.Fatal(err)
+		}
+	}
+	score, err := board.IncrementScore("a", 5)
+	if err != nil || score != 15 {
+		t.Fatalf("score=%v err=%v", score, err)
+	}
+
+	if rank, err := board.GetRevRank("e"); err != nil || rank != 0 {
+		t.Fatalf("rank=%v err=%v", rank, err)
+	}
+	if rank, err := board.GetRank("e"); err != nil || rank != 4 {
+		t.Fatalf("rank=%v err=%v", rank, err)
+	}
+	if rank, err := board.GetRank("missing"); err != nil || rank != -1 {
+		t.Fatalf("rank=%v err=%v", rank, err)
+	}
+
+	around, err := board.Around("c", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(around) != 3 || around[0].Member != "d" || around[1].Member != "c" || around[1].Rank != 2 || around[2].Member != "b" {
+		t.Fatalf("around=%+v", around)
+	}
+
+	page, err := board.Top(2, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page) != 2 || page[0].Member != "c" || page[0].Rank != 2 || page[1].Member != "b" {
+		t.Fatalf("page=%+v", page)
+	}
+
+	rotated, err := board.Rotate("s1", time.Minute)
+	if err != nil || !rotated {
+		t.Fatalf("rotated=%v err=%v", rotated, err)
+	}
+	if size, err := board.Size(); err != nil || size != 0 {
+		t.Fatalf("size=%v err=%v", size, err)
+	}
+	snapshot := board.GetSnapshot("s1")
+	if size, err := snapshot.Size(); err != nil || size != 5 {
+		t.Fatalf("size=%v err=%v", size, err)
+	}
+	if _, err = board.IncrementScore("a", 1); err != nil {
+		t.Fatal(err)
+	}
+	// the snapshot of a period is only rotated once
+	if rotated, err = board.Rotate("s1", time.Minute); err != nil || rotated {
+		t.Fatalf("rotated=%v err=%v", rotated, err)
+	}
+}
+
+// TestLeaderboardTTL test the object options apply to the leaderboard and the permit semaphore
+func TestLeaderboardTTL(t *testing.T) {
+	g := GetRedisson()
+	if err := g.client.Del(context.Background(), "testLeaderboardTTL", "testLeaderboardTTLPermits",
+		"{testLeaderboardTTLPermits}:timeout", "{testLeaderboardTTLPermits}:metadata").Err(); err != nil {
+		t.Fatal(err)
+	}
+	board := g.GetLeaderboard("testLeaderboardTTL", WithTTL(time.Minute))
+	defer g.client.Del(context.Background(), "testLeaderboardTTL")
+	if _, err := board.IncrementScore("player", 1); err != nil {
+		t.Fatal(err)
+	}
+	if ttl, err := g.client.PTTL(context.Background(), "testLeaderboardTTL").Result(); err != nil || ttl <= 0 || ttl > time.Minute {
+		t.Fatalf("ttl=%v err=%v", ttl, err)
+	}
+
+	sem := g.GetPermitExpirableSemaphore("testLeaderboardTTLPermits", WithTTL(time.Minute))
+	defer g.client.Del(context.Background(), "testLeaderboardTTLPermits", "{testLeaderboardTTLPermits}:timeout",
+		"{testLeaderboardTTLPermits}:metadata")
+	if ok, err := sem.TrySetPermits(1); err != nil || !ok {
+		t.Fatalf("ok=%v err=%v", ok, err)
+	}
+	if _, err := sem.TryAcquire(time.Minute, "worker"); err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"testLeaderboardTTLPermits", "{testLeaderboardTTLPermits}:timeout", "{testLeaderboardTTLPermits}:metadata"} {
+		if ttl, err := g.client.PTTL(context.Background(), key).Result(); err != nil || ttl <= 0 || ttl > time.Minute {
+			t.Fatalf("%s ttl=%v err=%v", key, ttl, err)
+		}
+	}
+}
@@ -0,0 +1,380 @@
+package redisson
+
+import (
+	"context"
+	"errors"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrIndexOutOfBounds is returned by the methods of RList given an index which is not in the list.
+var ErrIndexOutOfBounds = errors.New("index out of bounds")
+
+// RList is a list of values of type T stored in a redis list, like the RList of Java Redisson.
+// The indexes start at 0, and a negative index or one which is not in the list returns ErrIndexOutOfBounds.
+type RList[T any] interface {
+	RExpirable
+
+	// Get returns the value at index.
+	Get(index int64) (T, error)
+
+	// Add appends value to the list.
+	Add(value T) error
+
+	// AddAll appends values to the list in order.
+	AddAll(values ...T) error
+
+	// Insert inserts value at index, moving the values from index on. index may be the size of the list.
+	Insert(index int64, value T) error
+
+	// Set replaces the value at index and returns the previous one.
+	Set(index int64, value T) (T, error)
+
+	// FastSet replaces the value at index without returning the previous one.
+	FastSet(index int64, value T) error
+
+	// Remove removes the value at index and returns it.
+	Remove(index int64) (T, error)
+
+	// RemoveValue removes the first value equal to value and reports whether there was one.
+	// Values are equal when encoded to the same bytes.
+	RemoveValue(value T) (bool, error)
+
+	// IndexOf returns the index of the first value equal to value, or -1.
+	IndexOf(value T) (int64, error)
+
+	// LastIndexOf returns the index of the last value equal to value, or -1.
+	LastIndexOf(value T) (int64, error)
+
+	// SubList returns the values from fromIndex, inclusive, to toIndex, exclusive.
+	SubList(fromIndex, toIndex int64) ([]T, error)
+
+	// Size returns the number of values in the list.
+	Size() (int64, error)
+
+	// ReadAll returns all the values of the list.
+	ReadAll() ([]T, error)
+
+	// ForEach calls fn for the values of the list in order, read count at a time, until fn returns an error.
+	// A value added or removed during the iteration may shift the values which are not passed yet.
+	ForEach(ctx context.Context, count int64, fn func(index int64, value T) error) error
+
+	// Delete deletes the list and reports whether it existed.
+	Delete() (bool, error)
+}
+
+var (
+	_ RList[string] = (*RedissonList[string])(nil)
+)
+
+// RedissonList is the implementation of RList
+type RedissonList[T any] struct {
+	*RedissonExpirable
+	codec Codec
+}
+
+// newRedissonList creates a new RedissonList
+func newRedissonList[T any](name string, redisson *Redisson, options *objectOptions) *RedissonList[T] {
+	m := &RedissonList[T]{
+		RedissonExpirable: newRedissonExpirable(name, redisson),
+		codec:             options.codec,
+	}
+	m.ttl = options.ttl
+	return m
+}
+
+// Get returns the value at index.
+func (m *RedissonList[T]) Get(index int64) (T, error) {
+	var v T
+	if index < 0 {
+		return v, ErrIndexOutOfBounds
+	}
+	ctx, cancel := m.newContext()
+	defer cancel()
+	return m.decodeReply(m.client.LIndex(ctx, m.getRawName(), index).Result())
+}
+
+// Add appends value to the list.
+func (m *RedissonList[T]) Add(value T) error {
+	return m.AddAll(value)
+}
+
+// AddAll appends values to the list in order.
+func (m *RedissonList[T]) AddAll(values ...T) error {
+	if len(values) == 0 {
+		return nil
+	}
+	items, err := m.encodeAll(values)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := m.newContext()
+	defer cancel()
+	if err = m.client.RPush(ctx, m.getRawName(), items...).Err(); err != nil {
+		return err
+	}
+	return m.applyTTL(ctx, m.getRawName())
+}
+
+// Insert inserts value at index, moving the values from index on. index may be the size of the list.
+func (m *RedissonList[T]) Insert(index int64, value T) error {
+	if index < 0 {
+		return ErrIndexOutOfBounds
+	}
+	data, err := m.codec.Encode(value)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := m.newContext()
+	defer cancel()
+	// LINSERT puts the value before the first one equal to the pivot, which is not the one at index
+	// when the list holds duplicates, so the tail is moved instead
+	inserted, err := m.eval(ctx, "list.insert", `
+local index = tonumber(ARGV[1]);
+local size = redis.call('llen', KEYS[1]);
+if index > size then
+    return 0;
+end;
+if index == 0 then
+    redis.call('lpush', KEYS[1], ARGV[2]);
+    return 1;
+end;
+local tail = redis.call('lrange', KEYS[1], index, -1);
+redis.call('ltrim', KEYS[1], 0, index - 1);
+redis.call('rpush', KEYS[1], ARGV[2]);
+for i = 1, #tail, 5000 do
+    redis.call('rpush', KEYS[1], unpack(tail, i, math.min(i + 4999, #tail)));
+end;
+return 1;
+`, []string{m.getRawName()}, index, data).Bool()
+	if err != nil {
+		return err
+	}
+	if !inserted {
+		return ErrIndexOutOfBounds
+	}
+	return m.applyTTL(ctx, m.getRawName())
+}
+
+// Set replaces the value at index and returns the previous one.
+func (m *RedissonList[T]) Set(index int64, value T) (T, error) {
+	var v T
+	if index < 0 {
+		return v, ErrIndexOutOfBounds
+	}
+	data, err := m.codec.Encode(value)
+	if err != nil {
+		return v, err
+	}
+	ctx, cancel := m.newContext()
+	defer cancel()
+	return m.decodeReply(m.eval(ctx, "list.set", `
+local v = redis.call('lindex', KEYS[1], ARGV[1]);
+if v == false then
+    return nil;
+end;
+redis.call('lset', KEYS[1], ARGV[1], ARGV[2]);
+return v;
+`, []string{m.getRawName()}, index, data).Text())
+}
+
+// FastSet replaces the value at index without returning the previous one.
+func (m *RedissonList[T]) FastSet(index int64, value T) error {
+	if index < 0 {
+		return ErrIndexOutOfBounds
+	}
+	data, err := m.codec.Encode(value)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := m.newContext()
+	defer cancel()
+	err = m.client.LSet(ctx, m.getRawName(), index, data).Err()
+	if err != nil && (err.Error() == "ERR index out of range" || err.Error() == "ERR no such key") {
+		return ErrIndexOutOfBounds
+	}
+	return err
+}
+
+// Remove removes the value at index and returns it.
+func (m *RedissonList[T]) Remove(index int64) (T, error) {
+	var v T
+	if index < 0 {
+		return v, ErrIndexOutOfBounds
+	}
+	ctx, cancel := m.newContext()
+	defer cancel()
+	// the value at index is replaced with a marker which is then removed, as values may be duplicated
+	return m.decodeReply(m.eval(ctx, "list.remove", `
+local v = redis.call('lindex', KEYS[1], ARGV[1]);
+if v == false then
+    return nil;
+end;
+redis.call('lset', KEYS[1], ARGV[1], 'DELETED_BY_REDISSON');
+redis.call('lrem', KEYS[1], 1, 'DELETED_BY_REDISSON');
+return v;
+`, []string{m.getRawName()}, index).Text())
+}
+
+// RemoveValue removes the first value equal to value and reports whether there was one.
+func (m *RedissonList[T]) RemoveValue(value T) (bool, error) {
+	data, err := m.codec.Encode(value)
+	if err != nil {
+		return false, err
+	}
+	ctx, cancel := m.newContext()
+	defer cancel()
+	n, err := m.client.LRem(ctx, m.getRawName(), 1, data).Result()
+	return n == 1, err
+}
+
+// IndexOf returns the index of the first value equal to value, or -1.
+func (m *RedissonList[T]) IndexOf(value T) (int64, error) {
+	return m.indexOf("list.indexOf", `
+local items = redis.call('lrange', KEYS[1], 0, -1);
+for i = 1, #items do
+    if items[i] == ARGV[1] then
+        return i - 1;
+    end;
+end;
+return -1;
+`, value)
+}
+
+// LastIndexOf returns the index of the last value equal to value, or -1.
+func (m *RedissonList[T]) LastIndexOf(value T) (int64, error) {
+	return m.indexOf("list.lastIndexOf", `
+local items = redis.call('lrange', KEYS[1], 0, -1);
+for i = #items, 1, -1 do
+    if items[i] == ARGV[1] then
+        return i - 1;
+    end;
+end;
+return -1;
+`, value)
+}
+
+// indexOf runs one of the index scripts for value
+func (m *RedissonList[T]) indexOf(name, script string, value T) (int64, error) {
+	data, err := m.codec.Encode(value)
+	if err != nil {
+		return 0, err
+	}
+	ctx, cancel := m.newContext()
+	defer cancel()
+	return m.eval(ctx, name, script, []string{m.getRawName()}, data).Int64()
+}
+
+// SubList returns the values from fromIndex, inclusive, to toIndex, exclusive.
+func (m *RedissonList[T]) SubList(fromIndex, toIndex int64) ([]T, error) {
+	if fromIndex < 0 || fromIndex > toIndex {
+		return nil, ErrIndexOutOfBounds
+	}
+	ctx, cancel := m.newContext()
+	defer cancel()
+	size, err := m.client.LLen(ctx, m.getRawName()).Result()
+	if err != nil {
+		return nil, err
+	}
+	if toIndex > size {
+		return nil, ErrIndexOutOfBounds
+	}
+	if fromIndex == toIndex {
+		return []T{}, nil
+	}
+	items, err := m.client.LRange(ctx, m.getRawName(), fromIndex, toIndex-1).Result()
+	if err != nil {
+		return nil, err
+	}
+	return m.decodeAll(items)
+}
+
+// Size returns the number of values in the list.
+func (m *RedissonList[T]) Size() (int64, error) {
+	ctx, cancel := m.newContext()
+	defer cancel()
+	return m.client.LLen(ctx, m.getRawName()).Result()
+}
+
+// ReadAll returns all the values of the list.
+func (m *RedissonList[T]) ReadAll() ([]T, error) {
+	ctx, cancel := m.newContext()
+	defer cancel()
+	items, err := m.client.LRange(ctx, m.getRawName(), 0, -1).Result()
+	if err != nil {
+		return nil, err
+	}
+	return m.decodeAll(items)
+}
+
+// ForEach calls fn for the values of the list in order, read count at a time, until fn returns an error.
+func (m *RedissonList[T]) ForEach(ctx context.Context, count int64, fn func(index int64, value T) error) error {
+	if count <= 0 {
+		return errors.New("count must be greater than 0")
+	}
+	for start := int64(0); ; start += count {
+		rangeCtx, cancel := m.withCommandTimeout(ctx)
+		items, err := m.client.LRange(rangeCtx, m.getRawName(), start, start+count-1).Result()
+		cancel()
+		if err != nil {
+			return err
+		}
+		values, err := m.decodeAll(items)
+		if err != nil {
+			return err
+		}
+		for i, v := range values {
+			if err = fn(start+int64(i), v); err != nil {
+				return err
+			}
+		}
+		if int64(len(items)) < count {
+			return nil
+		}
+	}
+}
+
+// Delete deletes the list and reports whether it existed.
+func (m *RedissonList[T]) Delete() (bool, error) {
+	ctx, cancel := m.newContext()
+	defer cancel()
+	n, err := m.client.Del(ctx, m.getRawName()).Result()
+	return n == 1, err
+}
+
+// encodeAll encodes values with the codec of the list
+func (m *RedissonList[T]) encodeAll(values []T) ([]interface{}, error) {
+	items := make([]interface{}, len(values))
+	for i, v := range values {
+		data, err := m.codec.Encode(v)
+		if err != nil {
+			return nil, err
+		}
+		items[i] = data
+	}
+	return items, nil
+}
+
+// decodeAll decodes items with the codec of the list
+func (m *RedissonList[T]) decodeAll(items []string) ([]T, error) {
+	values := make([]T, len(items))
+	for i, item := range items {
+		if err := m.codec.Decode([]byte(item), &values[i]); err != nil {
+			return nil, err
+		}
+	}
+	return values, nil
+}
+
+// decodeReply decodes the value replied, a nil reply meaning the index is not in the list
+func (m *RedissonList[T]) decodeReply(item string, err error) (T, error) {
+	var v T
+	if err == redis.Nil {
+		return v, ErrIndexOutOfBounds
+	}
+	if err != nil {
+		return v, err
+	}
+	err = m.codec.Decode([]byte(item), &v)
+	return v, err
+}
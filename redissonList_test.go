@@ -0,0 +1,93 @@
+package redisson
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestList(t *testing.T) {
+	l := GetList[string](GetRedisson(), "testList")
+	if _, err := l.Delete(); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.AddAll("a", "b", "a", "c"); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Add("d"); err != nil {
+		t.Fatal(err)
+	}
+	if v, err := l.Get(2); err != nil || v != "a" {
+		t.Fatalf("v=%v err=%v", v, err)
+	}
+	if _, err := l.Get(5); !errors.Is(err, ErrIndexOutOfBounds) {
+		t.Fatalf("err=%v", err)
+	}
+	// inserts at index 2 although "a" is at index 0 too
+	if err := l.Insert(2, "x"); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Insert(0, "first"); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Insert(7, "last"); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Insert(9, "y"); !errors.Is(err, ErrIndexOutOfBounds) {
+		t.Fatalf("err=%v", err)
+	}
+	if values, err := l.ReadAll(); err != nil || !reflect.DeepEqual(values, []string{"first", "a", "b", "x", "a", "c", "d", "last"}) {
+		t.Fatalf("values=%v err=%v", values, err)
+	}
+	if i, err := l.IndexOf("a"); err != nil || i != 1 {
+		t.Fatalf("i=%v err=%v", i, err)
+	}
+	if i, err := l.LastIndexOf("a"); err != nil || i != 4 {
+		t.Fatalf("i=%v err=%v", i, err)
+	}
+	if i, err := l.IndexOf("z"); err != nil || i != -1 {
+		t.Fatalf("i=%v err=%v", i, err)
+	}
+	// removes the "a" at index 4, not the one at index 1
+	if v, err := l.Remove(4); err != nil || v != "a" {
+		t.Fatalf("v=%v err=%v", v, err)
+	}
+	if v, err := l.Set(1, "A"); err != nil || v != "a" {
+		t.Fatalf("v=%v err=%v", v, err)
+	}
+	if _, err := l.Set(7, "A"); !errors.Is(err, ErrIndexOutOfBounds) {
+		t.Fatalf("err=%v", err)
+	}
+	if err := l.FastSet(0, "F"); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.FastSet(7, "F"); !errors.Is(err, ErrIndexOutOfBounds) {
+		t.Fatalf("err=%v", err)
+	}
+	if ok, err := l.RemoveValue("x"); err != nil || !ok {
+		t.Fatalf("ok=%v err=%v", ok, err)
+	}
+	if values, err := l.SubList(1, 4); err != nil || !reflect.DeepEqual(values, []string{"A", "b", "c"}) {
+		t.Fatalf("values=%v err=%v", values, err)
+	}
+	if _, err := l.SubList(1, 7); !errors.Is(err, ErrIndexOutOfBounds) {
+		t.Fatalf("err=%v", err)
+	}
+	var values []string
+	if err := l.ForEach(context.Background(), 2, func(index int64, v string) error {
+		if index != int64(len(values)) {
+			t.Fatalf("index=%v", index)
+		}
+		values = append(values, v)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(values, []string{"F", "A", "b", "c", "d", "last"}) {
+		t.Fatalf("values=%v", values)
+	}
+	if n, err := l.Size(); err != nil || n != 6 {
+		t.Fatalf("n=%v err=%v", n, err)
+	}
+}
@@ -0,0 +1,202 @@
+package redisson
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"strings"
+)
+
+// LiveConfigValue is a version of the value of a RLiveConfig.
+type LiveConfigValue[T any] struct {
+	// Value is the configuration
+	Value T
+	// Version is incremented by every update, 0 while the configuration was never set.
+	// It starts again from 1 once the configuration expired or was deleted.
+	Version int64
+	//epoch time of the server in microseconds when the configuration was first set after it was missing,
+	//so that the versions set again after an expiry or a deletion are newer than the previous ones
+	epoch int64
+}
+
+// newerThan reports whether v is a later version than other
+func (v LiveConfigValue[T]) newerThan(other LiveConfigValue[T]) bool {
+	if v.epoch != other.epoch {
+		return v.epoch > other.epoch
+	}
+	return v.Version > other.Version
+}
+
+// RLiveConfig is a configuration value shared by all instances. Updates are versioned so concurrent writers
+// do not overwrite each other, and are pushed to the watchers over Pub/Sub.
+type RLiveConfig[T any] interface {
+	RExpirable
+
+	// Get returns the current value and its version, the zero value of T and version 0 if it was never set.
+	Get() (LiveConfigValue[T], error)
+
+	// CompareAndUpdate stores value if the current version is still version and reports whether it was stored.
+	CompareAndUpdate(version int64, value T) (bool, error)
+
+	// Watch sends the current value on the returned channel, then every update until ctx is done.
+	// A slow watcher may skip intermediate versions but always receives the newest one.
+	Watch(ctx context.Context) (<-chan LiveConfigValue[T], error)
+}
+
+var (
+	_ RLiveConfig[string] = (*RedissonLiveConfig[string])(nil)
+)
+
+// RedissonLiveConfig is the implementation of RLiveConfig
+// the version, the encoded value and the epoch are stored in the v, d and e fields of the name hash,
+// updates are published as epoch:version:data on the redisson_config:{name} channel
+type RedissonLiveConfig[T any] struct {
+	*RedissonExpirable
+	codec Codec
+}
+
+// newRedissonLiveConfig creates a new RedissonLiveConfig
+func newRedissonLiveConfig[T any](name string, redisson *Redisson, options *objectOptions) *RedissonLiveConfig[T] {
+	return &RedissonLiveConfig[T]{
+		RedissonExpirable: newRedissonExpirable(name, redisson),
+		codec:             options.codec,
+	}
+}
+
+// getChannelName returns the channel the updates are published on
+func (m *RedissonLiveConfig[T]) getChannelName() string {
+	return m.prefixName("redisson_config", m.getRawName())
+}
+
+// Get returns the current value and its version.
+func (m *RedissonLiveConfig[T]) Get() (LiveConfigValue[T], error) {
+	ctx, cancel := m.newContext()
+	defer cancel()
+	return m.get(ctx)
+}
+
+// get reads the current value and its version
+func (m *RedissonLiveConfig[T]) get(ctx context.Context) (LiveConfigValue[T], error) {
+	var current LiveConfigValue[T]
+	fields, err := m.client.HMGet(ctx, m.getRawName(), "v", "d", "e").Result()
+	if err != nil {
+		return current, err
+	}
+	version, ok := fields[0].(string)
+	if !ok {
+		return current, nil
+	}
+	if current.Version, err = strconv.ParseInt(version, 10, 64); err != nil {
+		return current, err
+	}
+	// the configurations set before the epoch was stored have epoch 0
+	if epoch, ok := fields[2].(string); ok {
+		if current.epoch, err = strconv.ParseInt(epoch, 10, 64); err != nil {
+			return current, err
+		}
+	}
+	data, _ := fields[1].(string)
+	err = m.codec.Decode([]byte(data), &current.Value)
+	return current, err
+}
+
+// CompareAndUpdate stores value if the current version is still version and reports whether it was stored.
+func (m *RedissonLiveConfig[T]) CompareAndUpdate(version int64, value T) (bool, error) {
+	ctx, cancel := m.newContext()
+	defer cancel()
+	data, err := m.codec.Encode(value)
+	if err != nil {
+		return false, err
+	}
+	updated, err := m.eval(ctx, "liveConfig.compareAndUpdate", `
+local version = tonumber(redis.call('hget', KEYS[1], 'v') or '0');
+if version ~= tonumber(ARGV[1]) then
+    return 0;
+end ;
+-- a configuration set again after an expiry or a deletion starts a new epoch, later than the previous one
+local epoch = redis.call('hget', KEYS[1], 'e');
+if version == 0 then
+    redis.replicate_commands();
+    local time = redis.call('time');
+    epoch = time[1] .. string.format('%06d', tonumber(time[2]));
+elseif epoch == false then
+    epoch = '0';
+end ;
+version = version + 1;
+redis.call('hset', KEYS[1], 'v', version, 'd', ARGV[2], 'e', epoch);
+redis.call('publish', KEYS[2], epoch .. ':' .. version .. ':' .. ARGV[2]);
+return 1;
+`, []string{m.getRawName(), m.getChannelName()}, version, data).Int64()
+	if err != nil {
+		return false, err
+	}
+	return updated == 1, nil
+}
+
+// Watch sends the current value on the returned channel, then every update until ctx is done.
+func (m *RedissonLiveConfig[T]) Watch(ctx context.Context) (<-chan LiveConfigValue[T], error) {
+	sub := m.client.Subscribe(ctx, m.getChannelName())
+	// subscribe before reading the current value so no update is missed in between
+	if _, err := sub.Receive(ctx); err != nil {
+		sub.Close()
+		return nil, err
+	}
+	getCtx, cancel := m.withCommandTimeout(ctx)
+	current, err := m.get(getCtx)
+	cancel()
+	if err != nil {
+		sub.Close()
+		return nil, err
+	}
+	// the channel holds the newest value not received yet
+	values := make(chan LiveConfigValue[T], 1)
+	values <- current
+	go func() {
+		defer close(values)
+		defer sub.Close()
+		ch := sub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-ch:
+				if !ok {
+					return
+				}
+				update, err := m.decodeUpdate(msg.Payload)
+				if err != nil {
+					log.Printf("live config %s: failed to decode update: %v", m.getRawName(), err)
+					continue
+				}
+				// the updates published before the current value was read are older
+				if !update.newerThan(current) {
+					continue
+				}
+				current = update
+				// replace the value the watcher did not receive yet
+				select {
+				case <-values:
+				default:
+				}
+				values <- current
+			}
+		}
+	}()
+	return values, nil
+}
+
+// decodeUpdate decodes an epoch:version:data update message
+func (m *RedissonLiveConfig[T]) decodeUpdate(payload string) (LiveConfigValue[T], error) {
+	var update LiveConfigValue[T]
+	epoch, rest, _ := strings.Cut(payload, ":")
+	version, data, _ := strings.Cut(rest, ":")
+	var err error
+	if update.epoch, err = strconv.ParseInt(epoch, 10, 64); err != nil {
+		return update, err
+	}
+	if update.Version, err = strconv.ParseInt(version, 10, 64); err != nil {
+		return update, err
+	}
+	err = m.codec.Decode([]byte(data), &update.Value)
+	return update, err
+}
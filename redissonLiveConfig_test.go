@@ -0,0 +1,72 @@
+package redisson
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+type liveConfigTest struct {
+	MaxConns int
+	Feature  bool
+}
+
+func TestLiveConfig(t *testing.T) {
+	g := GetRedisson()
+	config := GetLiveConfig[liveConfigTest](g, "testLiveConfig")
+	if err := g.client.Del(context.Background(), "testLiveConfig").Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	values, err := config.Watch(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := <-values; v.Version != 0 {
+		t.Fatalf("value=%+v", v)
+	}
+
+	ok, err := config.CompareAndUpdate(0, liveConfigTest{MaxConns: 10})
+	if err != nil || !ok {
+		t.Fatalf("ok=%v err=%v", ok, err)
+	}
+	// a writer holding a stale version does not overwrite the update
+	ok, err = config.CompareAndUpdate(0, liveConfigTest{MaxConns: 20})
+	if err != nil || ok {
+		t.Fatalf("ok=%v err=%v", ok, err)
+	}
+	current, err := config.Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if current.Version != 1 || current.Value.MaxConns != 10 {
+		t.Fatalf("current=%+v", current)
+	}
+
+	select {
+	case v := <-values:
+		if v.Version != 1 || v.Value.MaxConns != 10 {
+			t.Fatalf("value=%+v", v)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no update")
+	}
+
+	// the versions start again from 1 after a deletion, the watcher still receives them
+	if err = g.client.Del(context.Background(), "testLiveConfig").Err(); err != nil {
+		t.Fatal(err)
+	}
+	if ok, err = config.CompareAndUpdate(0, liveConfigTest{MaxConns: 30}); err != nil || !ok {
+		t.Fatalf("ok=%v err=%v", ok, err)
+	}
+	select {
+	case v := <-values:
+		if v.Version != 1 || v.Value.MaxConns != 30 {
+			t.Fatalf("value=%+v", v)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no update after the deletion")
+	}
+}
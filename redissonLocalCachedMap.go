@@ -0,0 +1,589 @@
+package redisson
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/satori/go.uuid"
+)
+
+// RLocalCachedMap is a map of values of type T stored in a redis hash, whose entries read by an instance are
+// also cached in its memory so that hot reads do not need a round trip. The writes are published on a topic
+// and the other instances drop or update their cached entries according to the LocalCacheSyncStrategy.
+type RLocalCachedMap[T any] interface {
+	RExpirable
+
+	// Get returns the value of key and whether the map holds it, from the local cache if it is cached.
+	Get(ctx context.Context, key string) (T, bool, error)
+
+	// Put stores the value of key.
+	Put(ctx context.Context, key string, value T) error
+
+	// Remove removes key and reports whether the map held it.
+	Remove(ctx context.Context, key string) (bool, error)
+
+	// Clear removes all the entries.
+	Clear(ctx context.Context) error
+
+	// ClearLocalCache drops the entries cached by this instance.
+	ClearLocalCache()
+
+	// CachedSize returns the number of entries cached by this instance.
+	CachedSize() int
+
+	// Close stops listening to the writes of the other instances and drops the local cache,
+	// the map reads redis afterwards.
+	Close() error
+}
+
+var (
+	_ RLocalCachedMap[string] = (*RedissonLocalCachedMap[string])(nil)
+)
+
+// LocalCacheEvictionPolicy selects the cached entry evicted when the local cache of a RLocalCachedMap is full.
+type LocalCacheEvictionPolicy int
+
+const (
+	// LocalCacheEvictionNone never evicts, the size of the local cache is not limited.
+	LocalCacheEvictionNone LocalCacheEvictionPolicy = iota
+	// LocalCacheEvictionLRU evicts the least recently used entry.
+	LocalCacheEvictionLRU
+	// LocalCacheEvictionFIFO evicts the entry cached first.
+	LocalCacheEvictionFIFO
+)
+
+// LocalCacheSyncStrategy is how the instances of a RLocalCachedMap keep their local caches in sync with the writes.
+type LocalCacheSyncStrategy int
+
+const (
+	// LocalCacheSyncInvalidate drops the written entries from the local caches of the other instances.
+	LocalCacheSyncInvalidate LocalCacheSyncStrategy = iota
+	// LocalCacheSyncUpdate publishes the written values, which replace the entries of the other instances.
+	LocalCacheSyncUpdate
+	// LocalCacheSyncNone publishes nothing, the cached entries stay until they expire or are evicted.
+	LocalCacheSyncNone
+)
+
+// localCachedMapOptions holds the settings a local cached map is created with
+type localCachedMapOptions struct {
+	//size maximum number of cached entries, 0 for no limit
+	size int
+	//eviction entry evicted when size is reached
+	eviction LocalCacheEvictionPolicy
+	//ttl time an entry stays cached, 0 for no limit
+	ttl time.Duration
+	//sync how the local caches follow the writes
+	sync LocalCacheSyncStrategy
+}
+
+// WithLocalCacheSize limits the local cache of a RLocalCachedMap to size entries, evicted with policy.
+func WithLocalCacheSize(size int, policy LocalCacheEvictionPolicy) ObjectOption {
+	return func(o *objectOptions) {
+		o.localCache.size = size
+		o.localCache.eviction = policy
+	}
+}
+
+// WithLocalCacheTTL drops the cached entries ttl after they were cached, bounding how stale they can be
+// when a write is missed, e.g. with LocalCacheSyncNone.
+func WithLocalCacheTTL(ttl time.Duration) ObjectOption {
+	return func(o *objectOptions) {
+		o.localCache.ttl = ttl
+	}
+}
+
+// WithLocalCacheSync sets how the local caches of a RLocalCachedMap follow the writes, LocalCacheSyncInvalidate
+// by default.
+func WithLocalCacheSync(strategy LocalCacheSyncStrategy) ObjectOption {
+	return func(o *objectOptions) {
+		o.localCache.sync = strategy
+	}
+}
+
+// localCacheMessage is published on the topic of a RLocalCachedMap for each write
+type localCacheMessage struct {
+	//Instance id of the writing instance, which ignores its own messages
+	Instance string `json:"i"`
+	//Keys written keys
+	Keys []string `json:"k,omitempty"`
+	//Values encoded values of Keys with LocalCacheSyncUpdate, nil for a removed key
+	Values [][]byte `json:"v,omitempty"`
+	//Clear whether all the entries were removed, or the expiry of the map changed
+	Clear bool `json:"c,omitempty"`
+	//TTL TTL in milliseconds of the hash after a write with LocalCacheSyncUpdate, -1 if it does not expire,
+	//set by the script of the write
+	TTL int64 `json:"t,omitempty"`
+}
+
+// RedissonLocalCachedMap is the implementation of RLocalCachedMap
+// the entries are stored in the name hash and the writes published on the {name}:topic channel,
+// the cached entries are dropped once the hash expires
+type RedissonLocalCachedMap[T any] struct {
+	*RedissonExpirable
+	codec   Codec
+	options localCachedMapOptions
+	//instance id of this instance in the messages
+	instance string
+	cache    *localCache[T]
+
+	mu sync.Mutex
+	//sub subscription to the topic, nil until the first read
+	sub    *redis.PubSub
+	closed bool
+}
+
+// newRedissonLocalCachedMap creates a new RedissonLocalCachedMap
+func newRedissonLocalCachedMap[T any](name string, redisson *Redisson, options *objectOptions) *RedissonLocalCachedMap[T] {
+	m := &RedissonLocalCachedMap[T]{
+		RedissonExpirable: newRedissonExpirable(name, redisson),
+		codec:             options.codec,
+		options:           options.localCache,
+		instance:          uuid.NewV4().String(),
+	}
+	m.ttl = options.ttl
+	m.cache = newLocalCache[T](m.options, redisson.clock)
+	return m
+}
+
+// getChannelName returns the channel the writes are published on
+func (m *RedissonLocalCachedMap[T]) getChannelName() string {
+	return m.suffixName(m.getRawName(), "topic")
+}
+
+// Get returns the value of key and whether the map holds it, from the local cache if it is cached.
+func (m *RedissonLocalCachedMap[T]) Get(ctx context.Context, key string) (T, bool, error) {
+	var zero T
+	listening, err := m.listen(ctx)
+	if err != nil {
+		return zero, false, err
+	}
+	if listening {
+		if v, ok := m.cache.get(key); ok {
+			return v, true, nil
+		}
+	}
+	// a write received while reading redis makes the value read stale, it is not cached then
+	generation := m.cache.generation()
+	ctx, cancel := m.withCommandTimeout(ctx)
+	defer cancel()
+	// the TTL of the hash bounds how long the value can be cached
+	reply, err := m.eval(ctx, "localCachedMap.get", `
+return {redis.call('hget', KEYS[1], ARGV[1]), redis.call('pttl', KEYS[1])};
+`, []string{m.getRawName()}, key).Slice()
+	if err != nil {
+		return zero, false, err
+	}
+	data, _ := reply[0].(string)
+	pttl, _ := reply[1].(int64)
+	if reply[0] == nil {
+		return zero, false, nil
+	}
+	var v T
+	if err = m.codec.Decode([]byte(data), &v); err != nil {
+		return zero, false, err
+	}
+	if listening {
+		m.cache.putIfGeneration(key, v, generation, pttl)
+	}
+	return v, true, nil
+}
+
+// Put stores the value of key.
+func (m *RedissonLocalCachedMap[T]) Put(ctx context.Context, key string, value T) error {
+	data, err := m.codec.Encode(value)
+	if err != nil {
+		return err
+	}
+	msg := localCacheMessage{Instance: m.instance, Keys: []string{key}}
+	if m.options.sync == LocalCacheSyncUpdate {
+		msg.Values = [][]byte{data}
+	}
+	message, err := m.encodeMessage(msg)
+	if err != nil {
+		return err
+	}
+	listening, err := m.listen(ctx)
+	if err != nil {
+		return err
+	}
+	// a write of another instance received before the reply makes value stale, it is not cached then
+	generation := m.cache.generation()
+	ctx, cancel := m.withCommandTimeout(ctx)
+	defer cancel()
+	pttl, err := m.eval(ctx, "localCachedMap.put", `
+redis.call('hset', KEYS[1], ARGV[1], ARGV[2]);
+if tonumber(ARGV[4]) > 0 and redis.call('pttl', KEYS[1]) == -1 then
+    redis.call('pexpire', KEYS[1], ARGV[4]);
+end;
+local pttl = redis.call('pttl', KEYS[1]);
+if ARGV[5] == '1' then
+    -- the other instances cache the value until the hash expires
+    local message = cjson.decode(ARGV[3]);
+    message['t'] = pttl;
+    redis.call('publish', KEYS[2], cjson.encode(message));
+elseif ARGV[3] ~= '' then
+    redis.call('publish', KEYS[2], ARGV[3]);
+end;
+return pttl;
+`, []string{m.getRawName(), m.getChannelName()}, key, data, message, m.ttl.Milliseconds(), len(msg.Values)).Int64()
+	if err != nil {
+		m.cache.remove(key)
+		return err
+	}
+	m.cache.written(key, value, generation, pttl, listening)
+	return nil
+}
+
+// Remove removes key and reports whether the map held it.
+func (m *RedissonLocalCachedMap[T]) Remove(ctx context.Context, key string) (bool, error) {
+	message, err := m.encodeMessage(localCacheMessage{Instance: m.instance, Keys: []string{key}})
+	if err != nil {
+		return false, err
+	}
+	m.cache.remove(key)
+	ctx, cancel := m.withCommandTimeout(ctx)
+	defer cancel()
+	removed, err := m.eval(ctx, "localCachedMap.remove", `
+local removed = redis.call('hdel', KEYS[1], ARGV[1]);
+if removed == 1 and ARGV[2] ~= '' then
+    redis.call('publish', KEYS[2], ARGV[2]);
+end;
+return removed;
+`, []string{m.getRawName(), m.getChannelName()}, key, message).Int()
+	// a Get which read the value before the reply does not cache it
+	m.cache.remove(key)
+	return removed == 1, err
+}
+
+// Clear removes all the entries.
+func (m *RedissonLocalCachedMap[T]) Clear(ctx context.Context) error {
+	message, err := m.encodeMessage(localCacheMessage{Instance: m.instance, Clear: true})
+	if err != nil {
+		return err
+	}
+	m.cache.clear()
+	ctx, cancel := m.withCommandTimeout(ctx)
+	defer cancel()
+	err = m.eval(ctx, "localCachedMap.clear", `
+redis.call('del', KEYS[1]);
+if ARGV[1] ~= '' then
+    redis.call('publish', KEYS[2], ARGV[1]);
+end;
+`, []string{m.getRawName(), m.getChannelName()}, message).Err()
+	// a Get which read a value before the reply does not cache it
+	m.cache.clear()
+	if err == redis.Nil {
+		return nil
+	}
+	return err
+}
+
+// Expire sets an expiration duration for the map, the local caches drop their entries to follow it.
+func (m *RedissonLocalCachedMap[T]) Expire(d time.Duration) (bool, error) {
+	ok, err := m.RedissonExpirable.Expire(d)
+	if err != nil {
+		return ok, err
+	}
+	return ok, m.expiryChanged()
+}
+
+// ExpireAt sets an expiration date for the map, the local caches drop their entries to follow it.
+func (m *RedissonLocalCachedMap[T]) ExpireAt(t time.Time) (bool, error) {
+	ok, err := m.RedissonExpirable.ExpireAt(t)
+	if err != nil {
+		return ok, err
+	}
+	return ok, m.expiryChanged()
+}
+
+// ClearExpire clears the expiration of the map, the local caches drop their entries to follow it.
+func (m *RedissonLocalCachedMap[T]) ClearExpire() (bool, error) {
+	ok, err := m.RedissonExpirable.ClearExpire()
+	if err != nil {
+		return ok, err
+	}
+	return ok, m.expiryChanged()
+}
+
+// expiryChanged drops the local caches, which read the new expiry of the hash with the entries
+func (m *RedissonLocalCachedMap[T]) expiryChanged() error {
+	m.cache.clear()
+	message, err := m.encodeMessage(localCacheMessage{Instance: m.instance, Clear: true})
+	if err != nil || message == "" {
+		return err
+	}
+	ctx, cancel := m.newContext()
+	defer cancel()
+	return m.client.Publish(ctx, m.getChannelName(), message).Err()
+}
+
+// ClearLocalCache drops the entries cached by this instance.
+func (m *RedissonLocalCachedMap[T]) ClearLocalCache() {
+	m.cache.clear()
+}
+
+// CachedSize returns the number of entries cached by this instance.
+func (m *RedissonLocalCachedMap[T]) CachedSize() int {
+	return m.cache.len()
+}
+
+// Close stops listening to the writes of the other instances and drops the local cache.
+func (m *RedissonLocalCachedMap[T]) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.closed = true
+	m.cache.clear()
+	if m.sub == nil {
+		return nil
+	}
+	sub := m.sub
+	m.sub = nil
+	return sub.Close()
+}
+
+// encodeMessage returns the message published for a write, empty with LocalCacheSyncNone
+func (m *RedissonLocalCachedMap[T]) encodeMessage(msg localCacheMessage) (string, error) {
+	if m.options.sync == LocalCacheSyncNone {
+		return "", nil
+	}
+	data, err := json.Marshal(msg)
+	return string(data), err
+}
+
+// listen subscribes to the topic on first use and reports whether the local cache can be used,
+// which it cannot once the map is closed
+func (m *RedissonLocalCachedMap[T]) listen(ctx context.Context) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return false, nil
+	}
+	if m.sub != nil || m.options.sync == LocalCacheSyncNone {
+		return true, nil
+	}
+	sub := m.client.Subscribe(m.baseContext(), m.getChannelName())
+	// wait for the subscription to be confirmed so no write published afterwards is missed
+	receiveCtx, cancel := m.withCommandTimeout(ctx)
+	defer cancel()
+	if _, err := sub.Receive(receiveCtx); err != nil {
+		sub.Close()
+		return false, err
+	}
+	m.sub = sub
+	go m.receive(sub)
+	return true, nil
+}
+
+// receive applies the messages of the other instances to the local cache until sub is closed
+func (m *RedissonLocalCachedMap[T]) receive(sub *redis.PubSub) {
+	for msg := range sub.ChannelWithSubscriptions() {
+		switch msg := msg.(type) {
+		case *redis.Subscription:
+			// the connection was lost and subscribed again, the writes in between were missed
+			m.cache.clear()
+		case *redis.Message:
+			var message localCacheMessage
+			if err := json.Unmarshal([]byte(msg.Payload), &message); err != nil {
+				log.Printf("local cached map %s: failed to decode message: %v", m.getRawName(), err)
+				continue
+			}
+			if message.Instance == m.instance {
+				continue
+			}
+			m.apply(message)
+		}
+	}
+	m.cache.clear()
+}
+
+// apply updates the local cache with a message of another instance
+func (m *RedissonLocalCachedMap[T]) apply(message localCacheMessage) {
+	if message.Clear {
+		m.cache.clear()
+		return
+	}
+	for i, key := range message.Keys {
+		if i >= len(message.Values) || message.Values[i] == nil {
+			m.cache.remove(key)
+			continue
+		}
+		var v T
+		if err := m.codec.Decode(message.Values[i], &v); err != nil {
+			log.Printf("local cached map %s: failed to decode value of %s: %v", m.getRawName(), key, err)
+			m.cache.remove(key)
+			continue
+		}
+		m.cache.put(key, v, message.TTL)
+	}
+}
+
+// localCache is the cache of the entries of a RLocalCachedMap in the memory of an instance
+type localCache[T any] struct {
+	sync.Mutex
+	options localCachedMapOptions
+	clock   Clock
+	entries map[string]*list.Element
+	//order entries from the most recently used or cached to the least
+	order *list.List
+	//gen is incremented by every removal and write, see putIfGeneration
+	gen uint64
+	//expires time the hash expires, zero if it does not expire
+	expires time.Time
+}
+
+// localCacheEntry is an entry of a localCache
+type localCacheEntry[T any] struct {
+	key     string
+	value   T
+	expires time.Time
+}
+
+// newLocalCache creates a new localCache
+func newLocalCache[T any](options localCachedMapOptions, clock Clock) *localCache[T] {
+	return &localCache[T]{
+		options: options,
+		clock:   clock,
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+	}
+}
+
+// get returns the cached value of key
+func (c *localCache[T]) get(key string) (T, bool) {
+	c.Lock()
+	defer c.Unlock()
+	var zero T
+	if !c.expires.IsZero() && !c.clock.Now().Before(c.expires) {
+		// the hash expired with all its entries
+		c.clearLocked()
+		return zero, false
+	}
+	e, ok := c.entries[key]
+	if !ok {
+		return zero, false
+	}
+	entry := e.Value.(*localCacheEntry[T])
+	if !entry.expires.IsZero() && !c.clock.Now().Before(entry.expires) {
+		c.order.Remove(e)
+		delete(c.entries, key)
+		return zero, false
+	}
+	if c.options.eviction == LocalCacheEvictionLRU {
+		c.order.MoveToFront(e)
+	}
+	return entry.value, true
+}
+
+// generation returns the number of removals and writes so far
+func (c *localCache[T]) generation() uint64 {
+	c.Lock()
+	defer c.Unlock()
+	return c.gen
+}
+
+// put caches value for key written by another instance, pttl being the TTL of the hash after the write
+func (c *localCache[T]) put(key string, value T, pttl int64) {
+	c.Lock()
+	defer c.Unlock()
+	c.gen++
+	c.setExpiryLocked(pttl)
+	c.putLocked(key, value)
+}
+
+// putIfGeneration caches value for key read with the TTL pttl of the hash unless an entry was removed or written
+// since generation returned gen
+func (c *localCache[T]) putIfGeneration(key string, value T, gen uint64, pttl int64) {
+	c.Lock()
+	defer c.Unlock()
+	if c.gen == gen {
+		c.setExpiryLocked(pttl)
+		c.putLocked(key, value)
+	}
+}
+
+// written caches value for key written by this instance, with the TTL pttl of the hash after the write, if cache
+// is true and no other write was received since generation returned gen. The generation is incremented in any case,
+// so that a Get which read the previous value before the reply does not cache it
+func (c *localCache[T]) written(key string, value T, gen uint64, pttl int64, cache bool) {
+	c.Lock()
+	defer c.Unlock()
+	if cache && c.gen == gen {
+		c.setExpiryLocked(pttl)
+		c.putLocked(key, value)
+	} else {
+		c.removeLocked(key)
+	}
+	c.gen++
+}
+
+// setExpiryLocked records the TTL pttl of the hash read now, -1 if it does not expire
+func (c *localCache[T]) setExpiryLocked(pttl int64) {
+	c.expires = time.Time{}
+	if pttl >= 0 {
+		c.expires = c.clock.Now().Add(time.Duration(pttl) * time.Millisecond)
+	}
+}
+
+// putLocked caches value for key, evicting an entry if the cache is full
+func (c *localCache[T]) putLocked(key string, value T) {
+	entry := &localCacheEntry[T]{key: key, value: value}
+	if c.options.ttl > 0 {
+		entry.expires = c.clock.Now().Add(c.options.ttl)
+	}
+	if e, ok := c.entries[key]; ok {
+		e.Value = entry
+		if c.options.eviction == LocalCacheEvictionLRU {
+			c.order.MoveToFront(e)
+		}
+		return
+	}
+	c.entries[key] = c.order.PushFront(entry)
+	if c.options.eviction != LocalCacheEvictionNone && c.options.size > 0 && c.order.Len() > c.options.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*localCacheEntry[T]).key)
+	}
+}
+
+// remove drops the cached value of key
+func (c *localCache[T]) remove(key string) {
+	c.Lock()
+	defer c.Unlock()
+	c.gen++
+	c.removeLocked(key)
+}
+
+// removeLocked drops the cached value of key
+func (c *localCache[T]) removeLocked(key string) {
+	if e, ok := c.entries[key]; ok {
+		c.order.Remove(e)
+		delete(c.entries, key)
+	}
+}
+
+// clear drops all the cached values and the expiry of the hash
+func (c *localCache[T]) clear() {
+	c.Lock()
+	defer c.Unlock()
+	c.clearLocked()
+}
+
+// clearLocked drops all the cached values and the expiry of the hash
+func (c *localCache[T]) clearLocked() {
+	c.gen++
+	c.entries = make(map[string]*list.Element)
+	c.order.Init()
+	c.expires = time.Time{}
+}
+
+// len returns the number of cached values
+func (c *localCache[T]) len() int {
+	c.Lock()
+	defer c.Unlock()
+	return c.order.Len()
+}
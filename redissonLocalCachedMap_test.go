@@ -0,0 +1,208 @@
+package redisson
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// waitFor polls cond until it holds or a second passed
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	for deadline := time.Now().Add(time.Second); !cond(); time.Sleep(5 * time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met")
+		}
+	}
+}
+
+func TestLocalCachedMap(t *testing.T) {
+	ctx := context.Background()
+	r := GetRedisson()
+	a := GetLocalCachedMap[User](r, "testLocalCachedMap")
+	b := GetLocalCachedMap[User](r, "testLocalCachedMap")
+	defer a.Close()
+	defer b.Close()
+	if err := a.Clear(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := a.Put(ctx, "1", User{ID: 1, Name: "Alice"}); err != nil {
+		t.Fatal(err)
+	}
+	if v, ok, err := b.Get(ctx, "1"); err != nil || !ok || v.Name != "Alice" {
+		t.Fatalf("v=%v ok=%v err=%v", v, ok, err)
+	}
+	if b.CachedSize() != 1 {
+		t.Fatalf("size=%v", b.CachedSize())
+	}
+
+	// the write of a invalidates the entry cached by b
+	if err := a.Put(ctx, "1", User{ID: 1, Name: "Bob"}); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, func() bool { return b.CachedSize() == 0 })
+	if v, ok, err := b.Get(ctx, "1"); err != nil || !ok || v.Name != "Bob" {
+		t.Fatalf("v=%v ok=%v err=%v", v, ok, err)
+	}
+
+	if removed, err := a.Remove(ctx, "1"); err != nil || !removed {
+		t.Fatalf("removed=%v err=%v", removed, err)
+	}
+	waitFor(t, func() bool { return b.CachedSize() == 0 })
+	if _, ok, err := b.Get(ctx, "1"); err != nil || ok {
+		t.Fatalf("ok=%v err=%v", ok, err)
+	}
+}
+
+func TestLocalCachedMapSyncUpdate(t *testing.T) {
+	ctx := context.Background()
+	r := GetRedisson()
+	a := GetLocalCachedMap[string](r, "testLocalCachedMapUpdate", WithLocalCacheSync(LocalCacheSyncUpdate))
+	b := GetLocalCachedMap[string](r, "testLocalCachedMapUpdate", WithLocalCacheSync(LocalCacheSyncUpdate))
+	defer a.Close()
+	defer b.Close()
+	if err := a.Clear(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := b.Get(ctx, "k"); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Put(ctx, "k", "v1"); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, func() bool { return b.CachedSize() == 1 })
+	// served from the local cache of b, updated by the message of a
+	if err := r.client.HSet(ctx, "testLocalCachedMapUpdate", "k", `"changed"`).Err(); err != nil {
+		t.Fatal(err)
+	}
+	if v, ok, err := b.Get(ctx, "k"); err != nil || !ok || v != "v1" {
+		t.Fatalf("v=%v ok=%v err=%v", v, ok, err)
+	}
+
+	if err := a.Clear(ctx); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, func() bool { return b.CachedSize() == 0 })
+}
+
+func TestLocalCachedMapEviction(t *testing.T) {
+	ctx := context.Background()
+	clock := NewManualClock(time.Now())
+	r := NewRedisson(redis.NewClient(&redis.Options{Addr: redisAddr}), WithClock(clock))
+	m := GetLocalCachedMap[int](r, "testLocalCachedMapEviction",
+		WithLocalCacheSize(2, LocalCacheEvictionLRU), WithLocalCacheTTL(time.Minute))
+	defer m.Close()
+	for i, key := range []string{"a", "b", "c"} {
+		if err := m.Put(ctx, key, i); err != nil {
+			t.Fatal(err)
+		}
+		if i == 1 {
+			// a becomes the most recently used
+			if _, _, err := m.Get(ctx, "a"); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	c := m.(*RedissonLocalCachedMap[int]).cache
+	if _, ok := c.get("b"); ok || c.len() != 2 {
+		t.Fatalf("b cached=%v len=%v", ok, c.len())
+	}
+	clock.Advance(time.Minute)
+	if _, ok := c.get("a"); ok {
+		t.Fatal("expired entry cached")
+	}
+	// read from redis again
+	if v, ok, err := m.Get(ctx, "a"); err != nil || !ok || v != 0 {
+		t.Fatalf("v=%v ok=%v err=%v", v, ok, err)
+	}
+}
+
+func TestLocalCachedMapWriteRace(t *testing.T) {
+	ctx := context.Background()
+	m := GetLocalCachedMap[int](GetRedisson(), "testLocalCachedMapWriteRace")
+	defer m.Close()
+	if err := m.Clear(ctx); err != nil {
+		t.Fatal(err)
+	}
+	// a Get reading the previous value concurrently with a write must not cache it after the write
+	for i := 0; i < 100; i++ {
+		var wg sync.WaitGroup
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			if _, _, err := m.Get(ctx, "k"); err != nil {
+				t.Error(err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			if i%2 == 0 {
+				if err := m.Put(ctx, "k", i); err != nil {
+					t.Error(err)
+				}
+			} else if _, err := m.Remove(ctx, "k"); err != nil {
+				t.Error(err)
+			}
+		}()
+		wg.Wait()
+		v, ok, err := m.Get(ctx, "k")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if i%2 == 0 && (!ok || v != i) || i%2 == 1 && ok {
+			t.Fatalf("write %d: v=%v ok=%v", i, v, ok)
+		}
+	}
+}
+
+func TestLocalCachedMapExpire(t *testing.T) {
+	ctx := context.Background()
+	r := GetRedisson()
+	a := GetLocalCachedMap[string](r, "testLocalCachedMapExpire", WithCodec(StringCodec{}))
+	b := GetLocalCachedMap[string](r, "testLocalCachedMapExpire", WithCodec(StringCodec{}))
+	defer a.Close()
+	defer b.Close()
+	if err := a.Clear(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Put(ctx, "k", "v"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok, err := b.Get(ctx, "k"); err != nil || !ok {
+		t.Fatalf("ok=%v err=%v", ok, err)
+	}
+	if r.client.HGet(ctx, "testLocalCachedMapExpire", "k").Val() != "v" {
+		t.Fatal("the codec of the option is not used")
+	}
+
+	// the expiry set by a reaches the local cache of b
+	if ok, err := a.Expire(200 * time.Millisecond); err != nil || !ok {
+		t.Fatalf("ok=%v err=%v", ok, err)
+	}
+	waitFor(t, func() bool { return b.CachedSize() == 0 })
+	if _, ok, err := b.Get(ctx, "k"); err != nil || !ok {
+		t.Fatalf("ok=%v err=%v", ok, err)
+	}
+	time.Sleep(300 * time.Millisecond)
+	if _, ok, err := b.Get(ctx, "k"); err != nil || ok {
+		t.Fatalf("expired entry read, ok=%v err=%v", ok, err)
+	}
+
+	// WithTTL expires the hash, and the entries cached by the writer
+	c := GetLocalCachedMap[string](r, "testLocalCachedMapExpireTTL", WithTTL(200*time.Millisecond))
+	defer c.Close()
+	if err := c.Put(ctx, "k", "v"); err != nil {
+		t.Fatal(err)
+	}
+	if c.CachedSize() != 1 {
+		t.Fatalf("size=%v", c.CachedSize())
+	}
+	time.Sleep(300 * time.Millisecond)
+	if _, ok, err := c.Get(ctx, "k"); err != nil || ok {
+		t.Fatalf("expired entry read, ok=%v err=%v", ok, err)
+	}
+}
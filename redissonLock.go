@@ -16,6 +16,13 @@ var (
 var (
 	// ErrObtainLockTimeout indicates that Lock cannot be acquired within waitTime
 	ErrObtainLockTimeout = errors.New("obtained lock timeout")
+	// ErrLockUpgrade indicates that a goroutine holding the read lock tried to acquire the write lock.
+	// Upgrading would wait for the goroutine itself to release the read lock, release it first instead.
+	// Downgrading is supported: the holder of the write lock can acquire the read lock, then release the write lock.
+	ErrLockUpgrade = errors.New("cannot upgrade a read lock to a write lock")
+	// ErrLockLost indicates that the watchdog found the lock no longer held when renewing it,
+	// because it expired or was deleted.
+	ErrLockLost = errors.New("lock was lost before it could be renewed")
 )
 
 // RedissonLock is a distributed lock implementation
@@ -29,15 +36,15 @@ func (m *RedissonLock) getChannelName() string {
 }
 
 // newRedisLock creates a new RedissonLock
-func newRedisLock(name string, Redisson *Redisson) Lock {
+func newRedisLock(name string, Redisson *Redisson, opts ...ObjectOption) Lock {
 	redisLock := &RedissonLock{}
-	redisLock.RedissonBaseLock = *newBaseLock(Redisson.id, name, Redisson, redisLock)
+	redisLock.RedissonBaseLock = *newBaseLock(Redisson.id, name, Redisson, redisLock, opts...)
 	return redisLock
 }
 
 // tryLockInner tries to acquire the lock
 func (m *RedissonLock) tryLockInner(ctx context.Context, leaseTime time.Duration, goroutineId uint64) (*int64, error) {
-	result, err := m.client.Eval(ctx, `
+	result, err := m.eval(ctx, "lock.tryLock", `
 if (redis.call('exists', KEYS[1]) == 0) then
     redis.call('hincrby', KEYS[1], ARGV[2], 1);
     redis.call('pexpire', KEYS[1], ARGV[1]);
@@ -62,7 +69,7 @@ return redis.call('pttl', KEYS[1]);
 // unlockInner releases the lock
 func (m *RedissonLock) unlockInner(ctx context.Context, goroutineId uint64) (*int64, error) {
 	defer m.cancelExpirationRenewal(goroutineId)
-	result, err := m.client.Eval(ctx, `
+	result, err := m.eval(ctx, "lock.unlock", unlockPublishLua+`
 if (redis.call('hexists', KEYS[1], ARGV[3]) == 0) then
     return nil;
 end ;
@@ -72,11 +79,11 @@ if (counter > 0) then
     return 0;
 else
     redis.call('del', KEYS[1]);
-    redis.call('publish', KEYS[2], ARGV[1]);
+    publishUnlock(KEYS[2], ARGV[1], ARGV[4], KEYS[3]);
     return 1;
 end ;
 return nil;
-`, []string{m.getRawName(), m.getChannelName()}, unlockMessage, m.internalLockLeaseTime.Milliseconds(), m.getLockName(goroutineId)).Int64()
+`, []string{m.getRawName(), m.getChannelName(), m.getWaitersName()}, unlockMessage, m.getLockLeaseTime().Milliseconds(), m.getLockName(goroutineId), m.lockChannelShards).Int64()
 	if err != nil {
 		if err == redis.Nil {
 			return nil, nil
@@ -88,11 +95,39 @@ return nil;
 
 // renewExpirationInner renews the lock expiration
 func (m *RedissonLock) renewExpirationInner(ctx context.Context, goroutineId uint64) (int64, error) {
-	return m.client.Eval(ctx, `
+	return m.eval(ctx, "lock.renew", `
 if (redis.call('hexists', KEYS[1], ARGV[2]) == 1) then
     redis.call('pexpire', KEYS[1], ARGV[1]);
     return 1;
 end ;
 return 0;
-`, []string{m.getRawName()}, m.internalLockLeaseTime.Milliseconds(), m.getLockName(goroutineId)).Int64()
+`, []string{m.getRawName()}, m.getLockLeaseTime().Milliseconds(), m.getLockName(goroutineId)).Int64()
 }
+
+// isLockedInner reports whether the lock is held
+func (m *RedissonLock) isLockedInner(ctx context.Context) (bool, error) {
+	exists, err := m.client.Exists(ctx, m.getRawName()).Result()
+	return exists == 1, err
+}
+
+// holdCountInner returns how many times the goroutine holds the lock
+func (m *RedissonLock) holdCountInner(ctx context.Context, goroutineId uint64) (int64, error) {
+	count, err := m.client.HGet(ctx, m.getRawName(), m.getLockName(goroutineId)).Int64()
+	if err == redis.Nil {
+		return 0, nil
+	}
+	return count, err
+}
+
+// renewSpec returns how the renewalBatcher renews the lock held by the goroutine
+func (m *RedissonLock) renewSpec(goroutineId uint64) (int, string) {
+	return renewHashField, m.getLockName(goroutineId)
+}
+
+// transferInner moves the holds of the lock from a goroutine to another
+func (m *RedissonLock) transferInner(ctx context.Context, from, to uint64) (int64, error) {
+	return m.transferHashHold(ctx, m.getLockName(from), m.getLockName(to))
+}
+
+// reentersLocally makes a goroutine holding the lock enter it again without a round trip
+func (m *RedissonLock) reentersLocally() {}
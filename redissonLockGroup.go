@@ -0,0 +1,204 @@
+package redisson
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// LockGroup acquires several named locks at once.
+// The locks of a group are the same as the ones returned by GetLock, so a resource can be locked
+// either alone or as part of a group.
+type LockGroup interface {
+	// TryLockAll tries to acquire every lock of the group and reports whether it succeeded.
+	// Locks sharing a hash slot are acquired in a single script, slots are acquired in a fixed order
+	// and all the locks already acquired are released if one of them is held by another goroutine.
+	TryLockAll(ctx context.Context) (bool, error)
+
+	// UnlockAll releases every lock of the group held by the current goroutine.
+	UnlockAll(ctx context.Context) error
+}
+
+var (
+	// check RedissonLockGroup implements LockGroup
+	_ LockGroup = (*RedissonLockGroup)(nil)
+)
+
+// RedissonLockGroup is the implementation of LockGroup
+type RedissonLockGroup struct {
+	*Redisson
+	//chunks locks grouped by hash slot, in ascending slot order
+	chunks [][]*RedissonLock
+}
+
+// newRedissonLockGroup creates a new RedissonLockGroup
+func newRedissonLockGroup(redisson *Redisson, names []string) *RedissonLockGroup {
+	names = append([]string(nil), names...)
+	sort.Slice(names, func(i, j int) bool {
+		si, sj := keySlot(names[i]), keySlot(names[j])
+		if si != sj {
+			return si < sj
+		}
+		return names[i] < names[j]
+	})
+	g := &RedissonLockGroup{
+		Redisson: redisson,
+	}
+	for i, name := range names {
+		if i > 0 && name == names[i-1] {
+			continue
+		}
+		lock := newRedisLock(name, redisson).(*RedissonLock)
+		n := len(g.chunks)
+		if n > 0 && keySlot(g.chunks[n-1][0].getRawName()) == keySlot(name) {
+			g.chunks[n-1] = append(g.chunks[n-1], lock)
+		} else {
+			g.chunks = append(g.chunks, []*RedissonLock{lock})
+		}
+	}
+	return g
+}
+
+// TryLockAll tries to acquire every lock of the group and reports whether it succeeded.
+func (m *RedissonLockGroup) TryLockAll(ctx context.Context) (bool, error) {
+	goroutineId, err := getId()
+	if err != nil {
+		return false, err
+	}
+	ctx, cancel := m.withCommandTimeout(ctx)
+	defer cancel()
+	for i, chunk := range m.chunks {
+		acquired, err := m.tryLockChunk(ctx, chunk, goroutineId)
+		if err == nil && acquired {
+			continue
+		}
+		// the script of a chunk which failed with an error may still have run, so the chunk is released too
+		held := m.chunks[:i]
+		if err != nil {
+			held = m.chunks[:i+1]
+		}
+		// the rollback has its own timeout since ctx may be the reason the chunk failed
+		rollbackCtx, cancel := m.newContext()
+		for _, c := range held {
+			if unlockErr := m.unlockChunk(rollbackCtx, c, goroutineId); unlockErr != nil && err == nil {
+				err = unlockErr
+			}
+		}
+		cancel()
+		return false, err
+	}
+	for _, chunk := range m.chunks {
+		for _, lock := range chunk {
+			lock.scheduleExpirationRenewal(goroutineId)
+		}
+	}
+	return true, nil
+}
+
+// UnlockAll releases every lock of the group held by the current goroutine.
+func (m *RedissonLockGroup) UnlockAll(ctx context.Context) error {
+	goroutineId, err := getId()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := m.withCommandTimeout(ctx)
+	defer cancel()
+	for _, chunk := range m.chunks {
+		if err := m.unlockChunk(ctx, chunk, goroutineId); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// tryLockChunk acquires all the locks of a chunk or none of them
+func (m *RedissonLockGroup) tryLockChunk(ctx context.Context, chunk []*RedissonLock, goroutineId uint64) (bool, error) {
+	keys := make([]string, len(chunk))
+	for i, lock := range chunk {
+		keys[i] = lock.getRawName()
+	}
+	_, err := m.eval(ctx, "lockGroup.tryLock", `
+for i = 1, #KEYS, 1 do
+    if (redis.call('exists', KEYS[i]) == 1 and redis.call('hexists', KEYS[i], ARGV[2]) == 0) then
+        return redis.call('pttl', KEYS[i]);
+    end ;
+end ;
+for i = 1, #KEYS, 1 do
+    redis.call('hincrby', KEYS[i], ARGV[2], 1);
+    redis.call('pexpire', KEYS[i], ARGV[1]);
+end ;
+return nil;
+`, keys, m.watchDogTimeout.Milliseconds(), chunk[0].getLockName(goroutineId)).Int64()
+	if err == redis.Nil {
+		return true, nil
+	}
+	return false, err
+}
+
+// unlockChunk releases the locks of a chunk held by the goroutine
+func (m *RedissonLockGroup) unlockChunk(ctx context.Context, chunk []*RedissonLock, goroutineId uint64) error {
+	keys := make([]string, 0, 3*len(chunk))
+	for _, lock := range chunk {
+		keys = append(keys, lock.getRawName())
+	}
+	for _, lock := range chunk {
+		keys = append(keys, lock.getChannelName())
+		defer lock.cancelExpirationRenewal(goroutineId)
+	}
+	for _, lock := range chunk {
+		keys = append(keys, lock.getWaitersName())
+	}
+	released, err := m.eval(ctx, "lockGroup.unlock", unlockPublishLua+`
+local n = #KEYS / 3;
+local released = 0;
+for i = 1, n, 1 do
+    if (redis.call('hexists', KEYS[i], ARGV[3]) == 1) then
+        local counter = redis.call('hincrby', KEYS[i], ARGV[3], -1);
+        if (counter > 0) then
+            redis.call('pexpire', KEYS[i], ARGV[2]);
+        else
+            redis.call('del', KEYS[i]);
+            publishUnlock(KEYS[n + i], ARGV[1], ARGV[4], KEYS[2 * n + i]);
+        end ;
+        released = released + 1;
+    end ;
+end ;
+return released;
+`, keys, unlockMessage, m.watchDogTimeout.Milliseconds(), chunk[0].getLockName(goroutineId), m.lockChannelShards).Int64()
+	if err != nil {
+		return err
+	}
+	if released != int64(len(chunk)) {
+		return fmt.Errorf("attempt to unlock lock group, %d of %d locks not locked by current goroutine by node id: %s goroutine-id: %d", int64(len(chunk))-released, len(chunk), m.id, goroutineId)
+	}
+	return nil
+}
+
+// keySlot returns the redis cluster hash slot of key
+func keySlot(key string) int {
+	if s := strings.IndexByte(key, '{'); s >= 0 {
+		if e := strings.IndexByte(key[s+1:], '}'); e > 0 {
+			key = key[s+1 : s+1+e]
+		}
+	}
+	return int(crc16([]byte(key)) % 16384)
+}
+
+// crc16 computes the CRC16/XMODEM checksum used by redis cluster
+func crc16(data []byte) uint16 {
+	var crc uint16
+	for _, b := range data {
+		crc ^= uint16(b) << 8
+		for i := 0; i < 8; i++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
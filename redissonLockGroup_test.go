@@ -0,0 +1,114 @@
+package redisson
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+)
+
+func TestKeySlot(t *testing.T) {
+	if s := keySlot("foo"); s != 12182 {
+		t.Fatalf("keySlot(foo)=%v", s)
+	}
+	if keySlot("{user1000}.following") != keySlot("{user1000}.followers") {
+		t.FailNow()
+	}
+}
+
+func TestLockGroup(t *testing.T) {
+	g := GetRedisson()
+	group := g.GetLockGroup("TestLockGroupA", "TestLockGroupB", "{TestLockGroup}C", "{TestLockGroup}D")
+	if ok, err := group.TryLockAll(context.Background()); err != nil {
+		t.Fatal(err)
+	} else if !ok {
+		t.Fatal("group should be acquired")
+	}
+
+	// another goroutine cannot take an overlapping group, and must not keep any of its locks
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		other := g.GetLockGroup("TestLockGroupE", "TestLockGroupB")
+		ok, err := other.TryLockAll(context.Background())
+		if err != nil {
+			panic(err)
+		}
+		if ok {
+			panic("overlapping group should not be acquired")
+		}
+		if ok, err = g.GetLockGroup("TestLockGroupE").TryLockAll(context.Background()); err != nil || !ok {
+			panic("TestLockGroupE should have been released")
+		}
+		if err = g.GetLockGroup("TestLockGroupE").UnlockAll(context.Background()); err != nil {
+			panic(err)
+		}
+	}()
+	<-done
+
+	if err := group.UnlockAll(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := group.UnlockAll(context.Background()); err == nil {
+		t.Fatal("unlocking a released group should fail")
+	}
+}
+
+// lostReplyHook runs the first script with key and replaces its reply with an error, as if the connection was lost
+// after redis ran it
+type lostReplyHook struct {
+	key  string
+	lost *atomic.Bool
+}
+
+func (h lostReplyHook) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+func (h lostReplyHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		err := next(ctx, cmd)
+		if (err != nil && err != redis.Nil) || h.lost.Load() {
+			return err
+		}
+		for _, arg := range cmd.Args() {
+			if arg == h.key && h.lost.CompareAndSwap(false, true) {
+				err = errors.New("connection lost")
+				cmd.SetErr(err)
+				return err
+			}
+		}
+		return err
+	}
+}
+
+func (h lostReplyHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return next
+}
+
+// TestLockGroupLostReply test the chunk whose script failed with an error is released with the chunks before it
+func TestLockGroupLostReply(t *testing.T) {
+	names := []string{"TestLockGroupLostA", "TestLockGroupLostB", "TestLockGroupLostC"}
+	// the script of the last chunk runs but its reply is lost
+	last := newRedissonLockGroup(GetRedisson(), names).chunks[2][0].getRawName()
+	client := redis.NewClient(&redis.Options{Addr: redisAddr})
+	var lost atomic.Bool
+	client.AddHook(lostReplyHook{key: last, lost: &lost})
+	g := NewRedisson(client)
+	if err := GetRedisson().client.Del(context.Background(), names...).Err(); err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := g.GetLockGroup(names...).TryLockAll(context.Background()); err == nil || ok {
+		t.Fatalf("ok=%v err=%v", ok, err)
+	}
+	if !lost.Load() {
+		t.Fatal("the reply was not lost")
+	}
+	for _, name := range names {
+		if locked, err := g.GetLock(name).IsLocked(); err != nil || locked {
+			t.Fatalf("%s should be released, got %v %v", name, locked, err)
+		}
+	}
+}
@@ -420,3 +420,655 @@ func TestLockBackground(t *testing.T) {
 		panic(a)
 	}
 }
+
+func TestLockChannelShards(t *testing.T) {
+	redisDB := redis.NewClient(&redis.Options{
+		Addr: redisAddr,
+	})
+	g := NewRedisson(redisDB, WithLockChannelShards(4))
+	l := g.GetLock("TestLockChannelShards")
+	if err := l.Lock(); err != nil {
+		t.Fatal(err)
+	}
+	acquired := make(chan time.Time, 1)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := l.LockContext(ctx); err != nil {
+			panic(err)
+		}
+		acquired <- time.Now()
+		if err := l.Unlock(); err != nil {
+			panic(err)
+		}
+	}()
+	time.Sleep(200 * time.Millisecond)
+	// the waiter is registered with its shard, after a waiter which left without unregistering
+	waiters := "redisson_lock__channel:{TestLockChannelShards}:waiters"
+	if members, err := redisDB.ZRange(context.Background(), waiters, 0, -1).Result(); err != nil || len(members) != 1 {
+		t.Fatalf("members=%v err=%v", members, err)
+	}
+	if err := redisDB.ZAdd(context.Background(), waiters, redis.Z{Score: 0, Member: "3:gone"}).Err(); err != nil {
+		t.Fatal(err)
+	}
+	unlockedAt := time.Now()
+	if err := l.Unlock(); err != nil {
+		t.Fatal(err)
+	}
+	// the waiter must be woken by its shard notification rather than by lease expiry
+	if at := <-acquired; at.Sub(unlockedAt) > time.Second {
+		t.Fatalf("waiter acquired after %v", at.Sub(unlockedAt))
+	}
+	time.Sleep(100 * time.Millisecond)
+	if n, err := redisDB.Exists(context.Background(), waiters).Result(); err != nil || n != 0 {
+		t.Fatalf("n=%v err=%v", n, err)
+	}
+}
+
+func TestWakeGateDelay(t *testing.T) {
+	gate := &wakeGate{limit: 2, stagger: 50 * time.Millisecond}
+	now := time.Now()
+	want := []time.Duration{0, 0, 50 * time.Millisecond, 50 * time.Millisecond, 100 * time.Millisecond}
+	for i, w := range want {
+		if d := gate.delay(now); d != w {
+			t.Fatalf("waiter %d: delay=%v, want %v", i, d, w)
+		}
+	}
+	// a notification after the stagger window starts a new release
+	if d := gate.delay(now.Add(60 * time.Millisecond)); d != 0 {
+		t.Fatalf("delay=%v after new release", d)
+	}
+}
+
+func TestLockUnlockWakeLimit(t *testing.T) {
+	redisDB := redis.NewClient(&redis.Options{
+		Addr: redisAddr,
+	})
+	g := NewRedisson(redisDB, WithUnlockWakeLimit(2, 5*time.Millisecond))
+	l := g.GetLock("TestLockUnlockWakeLimit")
+	a := 0
+	wg := sync.WaitGroup{}
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := l.Lock(); err != nil {
+				panic(err)
+			}
+			a++
+			if err := l.Unlock(); err != nil {
+				panic(err)
+			}
+		}()
+	}
+	wg.Wait()
+	if a != 50 {
+		t.Fatalf("a=%v", a)
+	}
+	// the gate is removed with its last waiter
+	if _, ok := g.wakeGates.Load("TestLockUnlockWakeLimit"); ok {
+		t.Fatal("the wake gate is not removed")
+	}
+}
+
+func TestLockWithLease(t *testing.T) {
+	g := GetRedisson()
+	l := g.GetLock("TestLockWithLease", WithLease(500*time.Millisecond))
+	if err := l.Lock(); err != nil {
+		t.Fatal(err)
+	}
+	ttl, err := l.RemainTimeToLive()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ttl <= 0 || ttl > 500 {
+		t.Fatalf("ttl=%v", ttl)
+	}
+	// the lease is not renewed, another goroutine gets the lock once it expires
+	done := make(chan error)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		done <- g.GetLock("TestLockWithLease").LockContext(ctx)
+	}()
+	if err = <-done; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAdaptiveLease(t *testing.T) {
+	a := newAdaptiveLease(time.Second, 30*time.Second)
+	if lease := a.get(); lease != 30*time.Second {
+		t.Fatalf("lease=%v", lease)
+	}
+	now := time.Now()
+	for i := 0; i < 20; i++ {
+		a.acquired(1, now)
+		// reentrant acquisitions keep the first acquire time
+		a.acquired(1, now.Add(50*time.Millisecond))
+		a.released(1, now.Add(100*time.Millisecond))
+	}
+	if lease := a.get(); lease != time.Second {
+		t.Fatalf("lease=%v", lease)
+	}
+	for i := 0; i < 20; i++ {
+		a.acquired(1, now)
+		a.released(1, now.Add(10*time.Second))
+	}
+	if lease := a.get(); lease < 15*time.Second || lease > 20*time.Second {
+		t.Fatalf("lease=%v", lease)
+	}
+	for i := 0; i < 20; i++ {
+		a.acquired(1, now)
+		a.released(1, now.Add(time.Minute))
+	}
+	if lease := a.get(); lease != 30*time.Second {
+		t.Fatalf("lease=%v", lease)
+	}
+}
+
+func TestLockWithAdaptiveLease(t *testing.T) {
+	g := GetRedisson()
+	l := g.GetLock("TestLockWithAdaptiveLease", WithAdaptiveLease(time.Second, 10*time.Second))
+	for i := 0; i < 10; i++ {
+		if err := l.Lock(); err != nil {
+			t.Fatal(err)
+		}
+		if err := l.Unlock(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := l.Lock(); err != nil {
+		t.Fatal(err)
+	}
+	defer l.Unlock()
+	// short holds shrink the lease to its lower bound
+	ttl, err := l.RemainTimeToLive()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ttl <= 0 || ttl > 1000 {
+		t.Fatalf("ttl=%v", ttl)
+	}
+}
+
+func TestLockLockWithLease(t *testing.T) {
+	g := GetRedisson()
+	l := g.GetLock("TestLockLockWithLease")
+	if err := l.LockWithLease(300 * time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	ttl, err := l.RemainTimeToLive()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ttl <= 0 || ttl > 300 {
+		t.Fatalf("ttl=%v", ttl)
+	}
+	// the watchdog does not renew the lease
+	time.Sleep(500 * time.Millisecond)
+	if ttl, err = l.RemainTimeToLive(); err != nil || ttl > 0 {
+		t.Fatalf("ttl=%v err=%v", ttl, err)
+	}
+}
+
+func TestLockIntrospection(t *testing.T) {
+	g := GetRedisson()
+	l := g.GetLock("TestLockIntrospection")
+	if locked, err := l.IsLocked(); err != nil || locked {
+		t.Fatalf("locked=%v err=%v", locked, err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := l.Lock(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if locked, err := l.IsLocked(); err != nil || !locked {
+		t.Fatalf("locked=%v err=%v", locked, err)
+	}
+	if count, err := l.GetHoldCount(); err != nil || count != 2 {
+		t.Fatalf("count=%v err=%v", count, err)
+	}
+	done := make(chan bool)
+	go func() {
+		held, err := l.IsHeldByCurrentGoroutine()
+		if err != nil {
+			panic(err)
+		}
+		done <- held
+	}()
+	if <-done {
+		t.Fatal("held by another goroutine")
+	}
+	if held, err := l.IsHeldByCurrentGoroutine(); err != nil || !held {
+		t.Fatalf("held=%v err=%v", held, err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := l.Unlock(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if count, err := l.GetHoldCount(); err != nil || count != 0 {
+		t.Fatalf("count=%v err=%v", count, err)
+	}
+}
+
+// TestLockBatchedRenewal test the locks of an instance renewed together by the watchdog
+func TestLockBatchedRenewal(t *testing.T) {
+	redisDB := redis.NewClient(&redis.Options{
+		Addr: redisAddr,
+	})
+	clock := NewManualClock(time.Now())
+	g := NewRedisson(redisDB, WithBatchedRenewal(), WithClock(clock))
+	lock := g.GetLock("TestLockBatchedRenewal")
+	mutex := g.GetMutex("TestLockBatchedRenewalMutex")
+	lost := g.GetLock("TestLockBatchedRenewalLost")
+	for _, l := range []Lock{lock, mutex, lost} {
+		if err := l.Lock(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ctx := context.Background()
+	for _, key := range []string{"TestLockBatchedRenewal", "TestLockBatchedRenewalMutex"} {
+		if err := redisDB.PExpire(ctx, key, time.Minute).Err(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := redisDB.Del(ctx, "TestLockBatchedRenewalLost").Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	clock.Advance(g.watchDogTimeout / 3)
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		lockTTL := redisDB.PTTL(ctx, "TestLockBatchedRenewal").Val()
+		mutexTTL := redisDB.PTTL(ctx, "TestLockBatchedRenewalMutex").Val()
+		_, tracked := lost.(*RedissonLock).ExpirationRenewalMap.Load(lost.(*RedissonLock).getEntryName())
+		if lockTTL <= g.watchDogTimeout && mutexTTL <= g.watchDogTimeout && !tracked {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("locks not renewed together, ttls %v %v, lost lock still renewed %v", lockTTL, mutexTTL, tracked)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	for _, l := range []Lock{lock, mutex} {
+		if err := l.Unlock(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	g.renewalBatcher.Lock()
+	defer g.renewalBatcher.Unlock()
+	if len(g.renewalBatcher.tasks) != 0 {
+		t.Fatalf("%d locks still renewed after unlocking", len(g.renewalBatcher.tasks))
+	}
+}
+
+// TestLockWithOwner test a lock held on behalf of an owner token released by another goroutine
+func TestLockWithOwner(t *testing.T) {
+	g := GetRedisson()
+	ctx := context.Background()
+	for _, lock := range []Lock{g.GetLock("TestLockWithOwner"), g.GetMutex("TestLockWithOwnerMutex"), g.GetSpinLock("TestLockWithOwnerSpin")} {
+		done := make(chan error)
+		go func() {
+			done <- lock.LockContextWithOwner(ctx, "request-1")
+		}()
+		if err := <-done; err != nil {
+			t.Fatal(err)
+		}
+		held, err := lock.IsHeldByCurrentGoroutine()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if held {
+			t.Fatal("lock held on behalf of an owner should not be held by the goroutine")
+		}
+		if err = lock.UnlockContextWithOwner(ctx, "request-2"); err == nil {
+			t.Fatal("unlock by another owner should fail")
+		}
+		if err = lock.UnlockContextWithOwner(ctx, "request-1"); err != nil {
+			t.Fatal(err)
+		}
+		locked, err := lock.IsLocked()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if locked {
+			t.Fatal("lock should be released by its owner")
+		}
+	}
+}
+
+// lockEvents is a LockObserver counting the notifications
+type lockEvents struct {
+	acquired, released, renewed atomic.Int32
+}
+
+func (e *lockEvents) OnAcquire(string, time.Duration, int) { e.acquired.Add(1) }
+func (e *lockEvents) OnRelease(string, time.Duration)      { e.released.Add(1) }
+func (e *lockEvents) OnRenew(string, error)                { e.renewed.Add(1) }
+
+// TestLockStats test the metrics of the locks of a name
+func TestLockStats(t *testing.T) {
+	redisDB := redis.NewClient(&redis.Options{
+		Addr: redisAddr,
+	})
+	clock := NewManualClock(time.Now())
+	events := &lockEvents{}
+	g := NewRedisson(redisDB, WithClock(clock), WithLockObserver(events))
+	lock := g.GetLock("TestLockStats", WithLockStats())
+	if err := lock.Lock(); err != nil {
+		t.Fatal(err)
+	}
+
+	clock.Advance(g.watchDogTimeout / 3)
+	deadline := time.Now().Add(5 * time.Second)
+	for lock.LockStats().Renewals == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("renewal of the lock should be recorded")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	acquired := make(chan error, 1)
+	go func() {
+		if err := g.GetLock("TestLockStats", WithLockStats()).Lock(); err != nil {
+			acquired <- err
+			return
+		}
+		acquired <- g.GetLock("TestLockStats", WithLockStats()).Unlock()
+	}()
+	time.Sleep(100 * time.Millisecond)
+	clock.Advance(time.Second)
+	if err := lock.Unlock(); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-acquired; err != nil {
+		t.Fatal(err)
+	}
+
+	stats := lock.LockStats()
+	if stats.Acquisitions != 2 || stats.Contended != 1 || stats.Retries < 1 || stats.Releases != 2 {
+		t.Fatalf("unexpected lock stats %+v", stats)
+	}
+	if stats.MaxHoldTime < g.watchDogTimeout/3 || stats.MaxAcquireTime < time.Second {
+		t.Fatalf("hold and acquire times should follow the clock, got %+v", stats)
+	}
+	if events.acquired.Load() != 2 || events.released.Load() != 2 || events.renewed.Load() == 0 {
+		t.Fatalf("observer should be notified, got %d acquired %d released %d renewed", events.acquired.Load(), events.released.Load(), events.renewed.Load())
+	}
+
+	// a lock without WithLockStats records nothing in the instance
+	other := g.GetLock("TestLockStatsOff")
+	if err := other.Lock(); err != nil {
+		t.Fatal(err)
+	}
+	if err := other.Unlock(); err != nil {
+		t.Fatal(err)
+	}
+	if stats := other.LockStats(); stats != (LockStats{}) {
+		t.Fatalf("unexpected lock stats %+v", stats)
+	}
+	if _, ok := g.lockStats.Load("TestLockStatsOff"); ok {
+		t.Fatal("the stats of a lock without WithLockStats are kept")
+	}
+}
+
+// TestLockTryLockContext test TryLockContext reports a done context as not locked and cleans up its wait
+func TestLockTryLockContext(t *testing.T) {
+	g := GetRedisson()
+	for _, name := range []string{"TestLockTryLockContext", "TestLockTryLockContextSpin"} {
+		holder := g.GetLock(name)
+		if err := holder.Lock(); err != nil {
+			t.Fatal(err)
+		}
+		waiter := g.GetLock(name)
+		if name == "TestLockTryLockContextSpin" {
+			waiter = g.GetSpinLock(name)
+		}
+		result := make(chan error, 1)
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+			defer cancel()
+			locked, err := waiter.TryLockContext(ctx)
+			if err == nil && locked {
+				err = fmt.Errorf("%s should not be locked while held", name)
+			}
+			result <- err
+		}()
+		if err := <-result; err != nil {
+			t.Fatal(err)
+		}
+		g.subscriptions.Lock()
+		subscribed := len(g.subscriptions.channels)
+		g.subscriptions.Unlock()
+		if subscribed != 0 {
+			t.Fatalf("%d channels still subscribed after TryLockContext", subscribed)
+		}
+		if err := holder.Unlock(); err != nil {
+			t.Fatal(err)
+		}
+
+		locked, err := waiter.TryLockContext(context.Background())
+		if err != nil || !locked {
+			t.Fatalf("%s should be locked once free, got %v %v", name, locked, err)
+		}
+		if err = waiter.Unlock(); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// TestLockDetachAttach test a lock handed over from the goroutine which acquired it to another one
+func TestLockDetachAttach(t *testing.T) {
+	redisDB := redis.NewClient(&redis.Options{
+		Addr: redisAddr,
+	})
+	clock := NewManualClock(time.Now())
+	g := NewRedisson(redisDB, WithClock(clock))
+	ctx := context.Background()
+
+	tokens := make(chan string, 1)
+	go func() {
+		lock := g.GetLock("TestLockDetachAttach")
+		if err := lock.Lock(); err != nil {
+			panic(err)
+		}
+		token, err := lock.Detach()
+		if err != nil {
+			panic(err)
+		}
+		tokens <- token
+	}()
+	token := <-tokens
+	lock := g.GetLock("TestLockDetachAttach")
+	if _, err := lock.Detach(); err != ErrNotLockOwner {
+		t.Fatalf("a goroutine not holding the lock should not detach it, got %v", err)
+	}
+	if err := lock.Attach(token); err != nil {
+		t.Fatal(err)
+	}
+	if count, err := lock.GetHoldCount(); err != nil || count != 1 {
+		t.Fatalf("attached lock should be held once, got %d %v", count, err)
+	}
+
+	// the watchdog keeps renewing the lock for its new holder
+	if err := redisDB.PExpire(ctx, "TestLockDetachAttach", time.Minute).Err(); err != nil {
+		t.Fatal(err)
+	}
+	clock.Advance(g.watchDogTimeout / 3)
+	deadline := time.Now().Add(5 * time.Second)
+	for redisDB.PTTL(ctx, "TestLockDetachAttach").Val() > g.watchDogTimeout {
+		if time.Now().After(deadline) {
+			t.Fatal("attached lock should be renewed")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if err := lock.Unlock(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, l := range []Lock{g.GetMutex("TestLockDetachAttachMutex"), g.GetReadWriteLock("TestLockDetachAttachRW").ReadLock()} {
+		if err := l.Lock(); err != nil {
+			t.Fatal(err)
+		}
+		token, err := l.Detach()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if held, err := l.IsHeldByCurrentGoroutine(); err != nil || held {
+			t.Fatalf("detached lock should not be held by the goroutine, got %v %v", held, err)
+		}
+		if err = l.UnlockContextWithOwner(ctx, token); err != nil {
+			t.Fatal(err)
+		}
+		if locked, err := l.IsLocked(); err != nil || locked {
+			t.Fatalf("lock should be released with its token, got %v %v", locked, err)
+		}
+	}
+}
+
+// TestLockOnRenewalFailure test the handler is notified when the watchdog finds the lock lost
+func TestLockOnRenewalFailure(t *testing.T) {
+	for _, batched := range []bool{false, true} {
+		redisDB := redis.NewClient(&redis.Options{
+			Addr: redisAddr,
+		})
+		clock := NewManualClock(time.Now())
+		opts := []OptionFunc{WithClock(clock)}
+		if batched {
+			opts = append(opts, WithBatchedRenewal())
+		}
+		g := NewRedisson(redisDB, opts...)
+		failures := make(chan error, 1)
+		g.OnRenewalFailure(func(lockName string, err error) {
+			if lockName == "TestLockOnRenewalFailure" {
+				failures <- err
+			}
+		})
+		lock := g.GetLock("TestLockOnRenewalFailure")
+		if err := lock.Lock(); err != nil {
+			t.Fatal(err)
+		}
+		if err := redisDB.Del(context.Background(), "TestLockOnRenewalFailure").Err(); err != nil {
+			t.Fatal(err)
+		}
+		clock.Advance(g.watchDogTimeout / 3)
+		select {
+		case err := <-failures:
+			if err != ErrLockLost {
+				t.Fatalf("lost lock should be reported with ErrLockLost, got %v", err)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("renewal failure should be reported, batched %v", batched)
+		}
+	}
+}
+
+// TestLockAsync test a lock acquired in the background is held by the calling goroutine
+func TestLockAsync(t *testing.T) {
+	g := GetRedisson()
+	for _, lock := range []Lock{g.GetLock("TestLockAsync"), g.GetSpinLock("TestLockAsyncSpin")} {
+		holder := make(chan struct{})
+		release := make(chan struct{})
+		go func() {
+			if err := lock.Lock(); err != nil {
+				panic(err)
+			}
+			close(holder)
+			<-release
+			if err := lock.Unlock(); err != nil {
+				panic(err)
+			}
+		}()
+		<-holder
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		acquired := lock.LockAsync(ctx)
+		select {
+		case err := <-acquired:
+			t.Fatalf("lock should not be acquired while held, got %v", err)
+		case <-time.After(100 * time.Millisecond):
+		}
+		close(release)
+		if err := <-acquired; err != nil {
+			t.Fatal(err)
+		}
+		cancel()
+		if held, err := lock.IsHeldByCurrentGoroutine(); err != nil || !held {
+			t.Fatalf("lock should be held by the goroutine which called LockAsync, got %v %v", held, err)
+		}
+		if err := <-lock.UnlockAsync(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		if locked, err := lock.IsLocked(); err != nil || locked {
+			t.Fatalf("lock should be released, got %v %v", locked, err)
+		}
+
+		// a caller which stops waiting cancels ctx, and the lock is not left held
+		holder = make(chan struct{})
+		release = make(chan struct{})
+		released := make(chan struct{})
+		go func() {
+			defer close(released)
+			if err := lock.Lock(); err != nil {
+				panic(err)
+			}
+			close(holder)
+			<-release
+			if err := lock.Unlock(); err != nil {
+				panic(err)
+			}
+		}()
+		<-holder
+		ctx, cancel = context.WithCancel(context.Background())
+		acquired = lock.LockAsync(ctx)
+		cancel()
+		close(release)
+		if err := <-acquired; err != ErrObtainLockTimeout {
+			t.Fatalf("err=%v", err)
+		}
+		<-released
+		if locked, err := lock.IsLocked(); err != nil || locked {
+			t.Fatalf("lock should be released, got %v %v", locked, err)
+		}
+	}
+}
+
+// TestLockReenterLocally test a goroutine entering again a lock it holds without a round trip
+func TestLockReenterLocally(t *testing.T) {
+	g := GetRedisson()
+	lock := g.GetLock("TestLockReenterLocally")
+	for i := 0; i < 3; i++ {
+		if err := lock.Lock(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	fields, err := g.client.HGetAll(context.Background(), "TestLockReenterLocally").Result()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, count := range fields {
+		if count != "1" {
+			t.Fatalf("reentrant holds should not reach redis, got %s", count)
+		}
+	}
+	if count, err := lock.GetHoldCount(); err != nil || count != 3 {
+		t.Fatalf("lock should be held 3 times, got %d %v", count, err)
+	}
+	for i := 0; i < 3; i++ {
+		if locked, err := lock.IsLocked(); err != nil || !locked {
+			t.Fatalf("lock should be held until its last unlock, got %v %v", locked, err)
+		}
+		if err := lock.Unlock(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if locked, err := lock.IsLocked(); err != nil || locked {
+		t.Fatalf("lock should be released, got %v %v", locked, err)
+	}
+	if err := lock.Unlock(); err == nil {
+		t.Fatal("unlocking a released lock should fail")
+	}
+}
@@ -0,0 +1,167 @@
+package redisson
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// JavaStructure is a structure of Java Redisson whose on-Redis layout can be checked by VerifyJavaLayout.
+type JavaStructure int
+
+const (
+	// JavaLock is RLock, stored as a hash of "<uuid>:<threadId>" holders to reentrancy counters
+	JavaLock JavaStructure = iota
+	// JavaRateLimiter is RRateLimiter, stored as a config hash with {name}:value and {name}:permits companions
+	JavaRateLimiter
+	// JavaAtomicLong is RAtomicLong, stored as an integer string
+	JavaAtomicLong
+	// JavaBloomFilter is RBloomFilter, stored as a bitmap with a {name}:config hash
+	JavaBloomFilter
+	// JavaSemaphore is RSemaphore
+	JavaSemaphore
+	// JavaMap is RMap
+	JavaMap
+	// JavaMapCache is RMapCache
+	JavaMapCache
+)
+
+// String returns the name of the Java Redisson interface
+func (s JavaStructure) String() string {
+	switch s {
+	case JavaLock:
+		return "RLock"
+	case JavaRateLimiter:
+		return "RRateLimiter"
+	case JavaAtomicLong:
+		return "RAtomicLong"
+	case JavaBloomFilter:
+		return "RBloomFilter"
+	case JavaSemaphore:
+		return "RSemaphore"
+	case JavaMap:
+		return "RMap"
+	case JavaMapCache:
+		return "RMapCache"
+	}
+	return "JavaStructure(" + strconv.Itoa(int(s)) + ")"
+}
+
+// JavaLayoutResult reports whether an object written by Java Redisson can be used by this package in place.
+type JavaLayoutResult struct {
+	Name      string
+	Structure JavaStructure
+	// Compatible reports whether the object can be shared by Java and Go services as is
+	Compatible bool
+	// Detail explains why the object is not compatible
+	Detail string
+}
+
+// VerifyJavaLayout checks that the objects named "names", written by Java Redisson as structure,
+// have the layout this package expects, to support porting services gradually while both run side by side.
+// Locks, rate limiters, atomic longs and Bloom filters share the Java layout, a Bloom filter must be created
+// WithBloomHasher(HighwayBloomHasher{}) and a codec encoding the elements like the Java one, such as StringCodec.
+// Semaphores, maps and map caches have no counterpart in this package.
+//
+// It is a read-only check: it neither converts nor migrates anything, the objects reported as incompatible
+// must be rewritten by the application, e.g. by reading them with Java Redisson and writing them with this package.
+func (g *Redisson) VerifyJavaLayout(ctx context.Context, structure JavaStructure, names ...string) ([]JavaLayoutResult, error) {
+	results := make([]JavaLayoutResult, 0, len(names))
+	for _, name := range names {
+		detail, err := g.verifyJavaLayout(ctx, structure, name)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, JavaLayoutResult{
+			Name:       name,
+			Structure:  structure,
+			Compatible: detail == "",
+			Detail:     detail,
+		})
+	}
+	return results, nil
+}
+
+// verifyJavaLayout returns why the object is not compatible, or "" if it is
+func (g *Redisson) verifyJavaLayout(ctx context.Context, structure JavaStructure, name string) (string, error) {
+	switch structure {
+	case JavaSemaphore, JavaMap, JavaMapCache:
+		return structure.String() + " has no counterpart in this package", nil
+	}
+
+	keyType, err := g.client.Type(ctx, name).Result()
+	if err != nil {
+		return "", err
+	}
+	// a missing object is created with the shared layout by whichever side writes it first
+	if keyType == "none" {
+		return "", nil
+	}
+	switch structure {
+	case JavaLock:
+		if keyType != "hash" {
+			return fmt.Sprintf("expected a hash, found a %s", keyType), nil
+		}
+		holders, err := g.client.HGetAll(ctx, name).Result()
+		if err != nil {
+			return "", err
+		}
+		for holder, counter := range holders {
+			sep := strings.LastIndexByte(holder, ':')
+			if sep <= 0 {
+				return fmt.Sprintf("holder %q is not <id>:<threadId>", holder), nil
+			}
+			if _, err := strconv.ParseUint(holder[sep+1:], 10, 64); err != nil {
+				return fmt.Sprintf("holder %q is not <id>:<threadId>", holder), nil
+			}
+			if _, err := strconv.ParseInt(counter, 10, 64); err != nil {
+				return fmt.Sprintf("counter of holder %q is not an integer", holder), nil
+			}
+		}
+	case JavaRateLimiter:
+		if keyType != "hash" {
+			return fmt.Sprintf("expected a hash, found a %s", keyType), nil
+		}
+		config, err := g.client.HGetAll(ctx, name).Result()
+		if err != nil {
+			return "", err
+		}
+		for _, field := range []string{"rate", "interval", "type"} {
+			if _, ok := config[field]; !ok {
+				return fmt.Sprintf("config field %q is missing", field), nil
+			}
+		}
+	case JavaAtomicLong:
+		if keyType != "string" {
+			return fmt.Sprintf("expected a string, found a %s", keyType), nil
+		}
+		v, err := g.client.Get(ctx, name).Result()
+		if err != nil {
+			return "", err
+		}
+		if _, err := strconv.ParseInt(v, 10, 64); err != nil {
+			return fmt.Sprintf("value %q is not an integer", v), nil
+		}
+	case JavaBloomFilter:
+		if keyType != "string" {
+			return fmt.Sprintf("expected a bitmap, found a %s", keyType), nil
+		}
+		configName := "{" + name + "}:config"
+		if strings.Contains(name, "{") {
+			configName = name + ":config"
+		}
+		config, err := g.client.HGetAll(ctx, configName).Result()
+		if err != nil {
+			return "", err
+		}
+		for _, field := range []string{"size", "hashIterations", "expectedInsertions", "falseProbability"} {
+			if _, ok := config[field]; !ok {
+				return fmt.Sprintf("config field %q is missing", field), nil
+			}
+		}
+	default:
+		return "", fmt.Errorf("unknown structure %v", structure)
+	}
+	return "", nil
+}
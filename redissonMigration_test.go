@@ -0,0 +1,45 @@
+package redisson
+
+import (
+	"context"
+	"testing"
+)
+
+func TestVerifyJavaLayout(t *testing.T) {
+	g := GetRedisson()
+	ctx := context.Background()
+	// layouts as written by Java Redisson
+	if err := g.client.HSet(ctx, "testJavaLock", "6f1c2a5e-2b0e-4a55-9f0e-3c1d2e4f5a6b:57", 1).Err(); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.client.Set(ctx, "testJavaAtomicLong", "42", 0).Err(); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.client.Set(ctx, "testJavaBrokenLock", "x", 0).Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := g.VerifyJavaLayout(ctx, JavaLock, "testJavaLock", "testJavaBrokenLock")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !results[0].Compatible || results[1].Compatible {
+		t.Fatalf("results=%+v", results)
+	}
+	if results, err = g.VerifyJavaLayout(ctx, JavaAtomicLong, "testJavaAtomicLong"); err != nil || !results[0].Compatible {
+		t.Fatalf("results=%+v err=%v", results, err)
+	}
+	if err = g.client.SetBit(ctx, "testJavaBloomFilter", 7, 1).Err(); err != nil {
+		t.Fatal(err)
+	}
+	if err = g.client.HSet(ctx, "{testJavaBloomFilter}:config", "size", "9585", "hashIterations", "7",
+		"expectedInsertions", "1000", "falseProbability", "0.01").Err(); err != nil {
+		t.Fatal(err)
+	}
+	if results, err = g.VerifyJavaLayout(ctx, JavaBloomFilter, "testJavaBloomFilter"); err != nil || !results[0].Compatible {
+		t.Fatalf("results=%+v err=%v", results, err)
+	}
+	if results, err = g.VerifyJavaLayout(ctx, JavaMapCache, "testJavaMapCache"); err != nil || results[0].Compatible {
+		t.Fatalf("results=%+v err=%v", results, err)
+	}
+}
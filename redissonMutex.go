@@ -23,15 +23,15 @@ func (m *RedissonMutex) getChannelName() string {
 }
 
 // newGoRedissonMutex creates a new RedissonMutex
-func newRedissonMutex(name string, redisson *Redisson) *RedissonMutex {
+func newRedissonMutex(name string, redisson *Redisson, opts ...ObjectOption) *RedissonMutex {
 	redisLock := &RedissonMutex{}
-	redisLock.RedissonBaseLock = *newBaseLock(redisson.id, name, redisson, redisLock)
+	redisLock.RedissonBaseLock = *newBaseLock(redisson.id, name, redisson, redisLock, opts...)
 	return redisLock
 }
 
 // tryLockInner tries to acquire the mutex
 func (m *RedissonMutex) tryLockInner(ctx context.Context, leaseTime time.Duration, goroutineId uint64) (*int64, error) {
-	result, err := m.client.Eval(ctx, `
+	result, err := m.eval(ctx, "mutex.tryLock", `
 if (redis.call('setnx', KEYS[1], ARGV[2]) == 1) then
     redis.call('pexpire', KEYS[1], ARGV[1]);
     return nil;
@@ -50,17 +50,17 @@ return redis.call('pttl', KEYS[1]);
 // unlockInner releases the mutex
 func (m *RedissonMutex) unlockInner(ctx context.Context, goroutineId uint64) (*int64, error) {
 	defer m.cancelExpirationRenewal(goroutineId)
-	result, err := m.client.Eval(ctx, `
+	result, err := m.eval(ctx, "mutex.unlock", unlockPublishLua+`
 local val = redis.call('get', KEYS[1]);
 if (val ~= ARGV[3]) then
     return nil;
 else
     redis.call('del', KEYS[1]);
-    redis.call('publish', KEYS[2], ARGV[1]);
+    publishUnlock(KEYS[2], ARGV[1], ARGV[4], KEYS[3]);
     return 1;
 end ;
 return nil;
-`, []string{m.getRawName(), m.getChannelName()}, unlockMessage, m.internalLockLeaseTime.Milliseconds(), m.getLockName(goroutineId)).Int64()
+`, []string{m.getRawName(), m.getChannelName(), m.getWaitersName()}, unlockMessage, m.getLockLeaseTime().Milliseconds(), m.getLockName(goroutineId), m.lockChannelShards).Int64()
 	if err != nil {
 		if err == redis.Nil {
 			return nil, nil
@@ -72,11 +72,50 @@ return nil;
 
 // renewExpirationInner renews the mutex expiration
 func (m *RedissonMutex) renewExpirationInner(ctx context.Context, goroutineId uint64) (int64, error) {
-	return m.client.Eval(ctx, `
+	return m.eval(ctx, "mutex.renew", `
 if (redis.call('exists', KEYS[1]) == 1) then
     redis.call('pexpire', KEYS[1], ARGV[1]);
     return 1;
 end ;
 return 0;
-`, []string{m.getRawName()}, m.internalLockLeaseTime.Milliseconds(), m.getLockName(goroutineId)).Int64()
+`, []string{m.getRawName()}, m.getLockLeaseTime().Milliseconds(), m.getLockName(goroutineId)).Int64()
+}
+
+// isLockedInner reports whether the mutex is held
+func (m *RedissonMutex) isLockedInner(ctx context.Context) (bool, error) {
+	exists, err := m.client.Exists(ctx, m.getRawName()).Result()
+	return exists == 1, err
+}
+
+// holdCountInner returns 1 if the goroutine holds the mutex, which is not reentrant, else 0
+func (m *RedissonMutex) holdCountInner(ctx context.Context, goroutineId uint64) (int64, error) {
+	holder, err := m.client.Get(ctx, m.getRawName()).Result()
+	if err == redis.Nil {
+		return 0, nil
+	}
+	if err != nil || holder != m.getLockName(goroutineId) {
+		return 0, err
+	}
+	return 1, nil
+}
+
+// renewSpec returns how the renewalBatcher renews the mutex held by the goroutine
+func (m *RedissonMutex) renewSpec(goroutineId uint64) (int, string) {
+	return renewStringValue, m.getLockName(goroutineId)
+}
+
+// transferInner moves the mutex from a goroutine to another
+func (m *RedissonMutex) transferInner(ctx context.Context, from, to uint64) (int64, error) {
+	return m.eval(ctx, "mutex.transfer", `
+if (redis.call('get', KEYS[1]) ~= ARGV[1]) then
+    return 0;
+end ;
+local ttl = redis.call('pttl', KEYS[1]);
+if (ttl > 0) then
+    redis.call('set', KEYS[1], ARGV[2], 'px', ttl);
+else
+    redis.call('set', KEYS[1], ARGV[2]);
+end ;
+return 1;
+`, []string{m.getRawName()}, m.getLockName(from), m.getLockName(to)).Int64()
 }
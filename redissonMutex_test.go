@@ -356,3 +356,48 @@ func TestMutexUnlockFailed(t *testing.T) {
 	}()
 	time.Sleep(2 * time.Second)
 }
+
+// TestMutexTryLock test TryLockOnce on a taken mutex returns at once without subscribing to its channel
+func TestMutexTryLock(t *testing.T) {
+	g := GetRedisson()
+	mutex := g.GetMutex("TestMutexTryLock")
+	if err := mutex.Lock(); err != nil {
+		t.Fatal(err)
+	}
+	defer mutex.Unlock()
+
+	result := make(chan bool, 1)
+	go func() {
+		locked, err := g.GetMutex("TestMutexTryLock").TryLockOnce(context.Background())
+		if err != nil {
+			panic(err)
+		}
+		result <- locked
+	}()
+	select {
+	case locked := <-result:
+		if locked {
+			t.Fatal("TryLockOnce should fail on a taken mutex")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("TryLockOnce should not wait for the mutex")
+	}
+	g.subscriptions.Lock()
+	defer g.subscriptions.Unlock()
+	if len(g.subscriptions.channels) != 0 {
+		t.Fatalf("TryLockOnce should not subscribe, %d channels subscribed", len(g.subscriptions.channels))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if locked, err := g.GetMutex("TestMutexTryLockFree").TryLockOnce(ctx); locked || err != context.Canceled {
+		t.Fatalf("locked=%v err=%v", locked, err)
+	}
+	other := g.GetMutex("TestMutexTryLockFree")
+	if locked, err := other.TryLockOnce(context.Background()); err != nil || !locked {
+		t.Fatalf("locked=%v err=%v", locked, err)
+	}
+	if err := other.Unlock(); err != nil {
+		t.Fatal(err)
+	}
+}
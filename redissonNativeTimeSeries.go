@@ -0,0 +1,308 @@
+package redisson
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrTimeSeriesModuleUnavailable is returned by RNativeTimeSeries when the RedisTimeSeries module is not loaded
+var ErrTimeSeriesModuleUnavailable = errors.New("time series module is not loaded")
+
+// TimeSeriesAggregator is how RNativeTimeSeries aggregates the samples of a bucket
+type TimeSeriesAggregator int
+
+const (
+	// TimeSeriesAvg is the average of the samples
+	TimeSeriesAvg TimeSeriesAggregator = iota + 1
+	// TimeSeriesSum is the sum of the samples
+	TimeSeriesSum
+	// TimeSeriesCount is the number of samples
+	TimeSeriesCount
+)
+
+// redisAggregator returns the aggregator of go-redis
+func (a TimeSeriesAggregator) redisAggregator() redis.Aggregator {
+	switch a {
+	case TimeSeriesAvg:
+		return redis.Avg
+	case TimeSeriesSum:
+		return redis.Sum
+	case TimeSeriesCount:
+		return redis.Count
+	default:
+		return redis.Invalid
+	}
+}
+
+// TimeSeriesAggregation aggregates the samples of a range of RNativeTimeSeries into buckets, each sample of the
+// reply being the aggregate of a bucket at the start time of the bucket
+type TimeSeriesAggregation struct {
+	// Aggregator is how the samples of a bucket are aggregated
+	Aggregator TimeSeriesAggregator
+	// Bucket is the duration of a bucket, with a millisecond precision
+	Bucket time.Duration
+}
+
+// args returns the arguments of TS.RANGE and TS.MRANGE for the aggregation
+func (a *TimeSeriesAggregation) args() (redis.Aggregator, int, error) {
+	aggregator := a.Aggregator.redisAggregator()
+	if aggregator == redis.Invalid {
+		return 0, 0, fmt.Errorf("invalid time series aggregator %d", a.Aggregator)
+	}
+	bucket := int(a.Bucket.Milliseconds())
+	if bucket <= 0 {
+		return 0, 0, fmt.Errorf("time series bucket %v is shorter than a millisecond", a.Bucket)
+	}
+	return aggregator, bucket, nil
+}
+
+// RNativeTimeSeries is a series of float64 samples stored with the TS.* commands of the RedisTimeSeries module,
+// lighter than RTimeSeries for metrics. Every method returns ErrTimeSeriesModuleUnavailable when the module is not loaded.
+type RNativeTimeSeries interface {
+	RExpirable
+
+	// Create creates the series, which keeps the samples for retention, 0 for ever, and is labeled with labels for
+	// MRange. It reports false if the series already exists.
+	Create(retention time.Duration, labels map[string]string) (bool, error)
+
+	// Add adds the sample of value at timestamp, creating the series without retention nor labels if it does not
+	// exist. It fails if there is a sample at timestamp already.
+	Add(timestamp time.Time, value float64) error
+
+	// MAdd adds the samples at once, see Add.
+	MAdd(samples []TimeSeriesEntry[float64]) error
+
+	// Range returns the samples from from to to, inclusive, oldest first, aggregated by aggregation if not nil.
+	Range(from, to time.Time, aggregation *TimeSeriesAggregation) ([]TimeSeriesEntry[float64], error)
+
+	// MRange is Range over all the series whose labels match filters, such as "sensor=1" or "region!=eu",
+	// by name of series.
+	MRange(from, to time.Time, filters []string, aggregation *TimeSeriesAggregation) (map[string][]TimeSeriesEntry[float64], error)
+
+	// Delete deletes the series and reports whether it existed.
+	Delete() (bool, error)
+}
+
+var (
+	_ RNativeTimeSeries = (*RedissonNativeTimeSeries)(nil)
+)
+
+// RedissonNativeTimeSeries is the implementation of RNativeTimeSeries
+// the samples are stored in the name key of the module
+type RedissonNativeTimeSeries struct {
+	*RedissonExpirable
+}
+
+// newRedissonNativeTimeSeries creates a new RedissonNativeTimeSeries
+func newRedissonNativeTimeSeries(name string, redisson *Redisson, options *objectOptions) *RedissonNativeTimeSeries {
+	m := &RedissonNativeTimeSeries{
+		RedissonExpirable: newRedissonExpirable(name, redisson),
+	}
+	m.ttl = options.ttl
+	return m
+}
+
+// checkModule returns ErrTimeSeriesModuleUnavailable if the module is not loaded
+func (m *RedissonNativeTimeSeries) checkModule(ctx context.Context) error {
+	ok, err := m.hasCommand(ctx, "TS.ADD")
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrTimeSeriesModuleUnavailable
+	}
+	return nil
+}
+
+// Create creates the series, which keeps the samples for retention and is labeled with labels.
+func (m *RedissonNativeTimeSeries) Create(retention time.Duration, labels map[string]string) (bool, error) {
+	ctx, cancel := m.newContext()
+	defer cancel()
+	if err := m.checkModule(ctx); err != nil {
+		return false, err
+	}
+	err := m.client.TSCreateWithArgs(ctx, m.getRawName(), &redis.TSOptions{
+		Retention: int(retention.Milliseconds()),
+		Labels:    labels,
+	}).Err()
+	if err != nil {
+		if strings.Contains(err.Error(), "already exists") {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, m.applyTTL(ctx, m.getRawName())
+}
+
+// Add adds the sample of value at timestamp.
+func (m *RedissonNativeTimeSeries) Add(timestamp time.Time, value float64) error {
+	return m.MAdd([]TimeSeriesEntry[float64]{{Timestamp: timestamp, Value: value}})
+}
+
+// MAdd adds the samples at once.
+func (m *RedissonNativeTimeSeries) MAdd(samples []TimeSeriesEntry[float64]) error {
+	if len(samples) == 0 {
+		return nil
+	}
+	ctx, cancel := m.newContext()
+	defer cancel()
+	if err := m.checkModule(ctx); err != nil {
+		return err
+	}
+	// TS.MADD does not create the series, TS.ADD of the first sample does
+	if err := m.client.TSAdd(ctx, m.getRawName(), samples[0].Timestamp.UnixMilli(), samples[0].Value).Err(); err != nil {
+		return err
+	}
+	if len(samples) > 1 {
+		ktv := make([][]interface{}, len(samples)-1)
+		for i, sample := range samples[1:] {
+			ktv[i] = []interface{}{m.getRawName(), sample.Timestamp.UnixMilli(), sample.Value}
+		}
+		if err := m.client.TSMAdd(ctx, ktv).Err(); err != nil {
+			return err
+		}
+	}
+	return m.applyTTL(ctx, m.getRawName())
+}
+
+// Range returns the samples from from to to, inclusive, oldest first, aggregated by aggregation if not nil.
+func (m *RedissonNativeTimeSeries) Range(from, to time.Time, aggregation *TimeSeriesAggregation) ([]TimeSeriesEntry[float64], error) {
+	options := &redis.TSRangeOptions{}
+	if aggregation != nil {
+		var err error
+		if options.Aggregator, options.BucketDuration, err = aggregation.args(); err != nil {
+			return nil, err
+		}
+	}
+	ctx, cancel := m.newContext()
+	defer cancel()
+	if err := m.checkModule(ctx); err != nil {
+		return nil, err
+	}
+	values, err := m.client.TSRangeWithArgs(ctx, m.getRawName(), int(from.UnixMilli()), int(to.UnixMilli()), options).Result()
+	if err != nil {
+		// the series does not exist
+		if strings.Contains(err.Error(), "key does not exist") {
+			return nil, nil
+		}
+		return nil, err
+	}
+	samples := make([]TimeSeriesEntry[float64], len(values))
+	for i, v := range values {
+		samples[i] = TimeSeriesEntry[float64]{Timestamp: time.UnixMilli(v.Timestamp), Value: v.Value}
+	}
+	return samples, nil
+}
+
+// MRange is Range over all the series whose labels match filters, by name of series.
+func (m *RedissonNativeTimeSeries) MRange(from, to time.Time, filters []string, aggregation *TimeSeriesAggregation) (map[string][]TimeSeriesEntry[float64], error) {
+	if len(filters) == 0 {
+		return nil, errors.New("time series MRange needs a filter")
+	}
+	args := []interface{}{"TS.MRANGE", from.UnixMilli(), to.UnixMilli()}
+	if aggregation != nil {
+		aggregator, bucket, err := aggregation.args()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, "AGGREGATION", aggregator.String(), bucket)
+	}
+	args = append(args, "FILTER")
+	for _, filter := range filters {
+		args = append(args, filter)
+	}
+	ctx, cancel := m.newContext()
+	defer cancel()
+	if err := m.checkModule(ctx); err != nil {
+		return nil, err
+	}
+	// the reply is read raw since it is an array of [name, labels, samples] with RESP2 and a map of name to
+	// [labels, metadata..., samples] with RESP3
+	reply, err := m.client.Do(ctx, args...).Result()
+	if err != nil {
+		return nil, err
+	}
+	series := make(map[string][]TimeSeriesEntry[float64])
+	add := func(name interface{}, fields []interface{}) error {
+		key, ok := name.(string)
+		if !ok || len(fields) == 0 {
+			return fmt.Errorf("unexpected TS.MRANGE reply %v", reply)
+		}
+		samples, err := parseNativeTimeSeriesSamples(fields[len(fields)-1])
+		if err != nil {
+			return err
+		}
+		series[key] = samples
+		return nil
+	}
+	switch reply := reply.(type) {
+	case []interface{}:
+		for _, item := range reply {
+			fields, ok := item.([]interface{})
+			if !ok || len(fields) < 2 {
+				return nil, fmt.Errorf("unexpected TS.MRANGE reply %v", reply)
+			}
+			if err = add(fields[0], fields[1:]); err != nil {
+				return nil, err
+			}
+		}
+	case map[interface{}]interface{}:
+		for name, item := range reply {
+			fields, _ := item.([]interface{})
+			if err = add(name, fields); err != nil {
+				return nil, err
+			}
+		}
+	default:
+		return nil, fmt.Errorf("unexpected TS.MRANGE reply %v", reply)
+	}
+	return series, nil
+}
+
+// Delete deletes the series and reports whether it existed.
+func (m *RedissonNativeTimeSeries) Delete() (bool, error) {
+	ctx, cancel := m.newContext()
+	defer cancel()
+	n, err := m.client.Del(ctx, m.getRawName()).Result()
+	return n == 1, err
+}
+
+// parseNativeTimeSeriesSamples parses the [timestamp, value] samples of a raw reply, whose values are strings
+// with RESP2 and doubles with RESP3
+func parseNativeTimeSeriesSamples(reply interface{}) ([]TimeSeriesEntry[float64], error) {
+	items, ok := reply.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected time series samples %v", reply)
+	}
+	samples := make([]TimeSeriesEntry[float64], len(items))
+	for i, item := range items {
+		sample, ok := item.([]interface{})
+		if !ok || len(sample) != 2 {
+			return nil, fmt.Errorf("unexpected time series sample %v", item)
+		}
+		ms, ok := sample[0].(int64)
+		if !ok {
+			return nil, fmt.Errorf("unexpected time series timestamp %v", sample[0])
+		}
+		samples[i].Timestamp = time.UnixMilli(ms)
+		switch v := sample[1].(type) {
+		case float64:
+			samples[i].Value = v
+		case string:
+			f, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return nil, err
+			}
+			samples[i].Value = f
+		default:
+			return nil, fmt.Errorf("unexpected time series value %v", sample[1])
+		}
+	}
+	return samples, nil
+}
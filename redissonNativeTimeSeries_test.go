@@ -0,0 +1,107 @@
+package redisson
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNativeTimeSeries(t *testing.T) {
+	g := GetRedisson()
+	if ok, err := g.hasCommand(context.Background(), "TS.ADD"); err != nil {
+		t.Fatal(err)
+	} else if !ok {
+		t.Skip("the RedisTimeSeries module is not loaded")
+	}
+	ts := g.GetNativeTimeSeries("testNativeTimeSeries")
+	other := g.GetNativeTimeSeries("testNativeTimeSeriesOther")
+	defer ts.Delete()
+	defer other.Delete()
+	ts.Delete()
+	other.Delete()
+
+	if ok, err := ts.Create(time.Hour, map[string]string{"test": "nativeTimeSeries", "sensor": "1"}); err != nil || !ok {
+		t.Fatalf("ok=%v err=%v", ok, err)
+	}
+	if ok, err := ts.Create(time.Hour, nil); err != nil || ok {
+		t.Fatalf("ok=%v err=%v", ok, err)
+	}
+	if _, err := other.Create(time.Hour, map[string]string{"test": "nativeTimeSeries", "sensor": "2"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// buckets start at multiples of their duration
+	start := time.Now().Truncate(time.Hour)
+	if err := ts.Add(start, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := ts.MAdd([]TimeSeriesEntry[float64]{
+		{Timestamp: start.Add(time.Second), Value: 2},
+		{Timestamp: start.Add(10 * time.Second), Value: 3},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := other.Add(start, 5); err != nil {
+		t.Fatal(err)
+	}
+
+	samples, err := ts.Range(start, start.Add(time.Minute), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(samples) != 3 || !samples[0].Timestamp.Equal(start) || samples[2].Value != 3 {
+		t.Fatalf("samples=%v", samples)
+	}
+
+	sums, err := ts.Range(start, start.Add(time.Minute), &TimeSeriesAggregation{Aggregator: TimeSeriesSum, Bucket: time.Hour})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sums) != 1 || sums[0].Value != 6 {
+		t.Fatalf("sums=%v", sums)
+	}
+	counts, err := ts.Range(start, start.Add(time.Minute), &TimeSeriesAggregation{Aggregator: TimeSeriesCount, Bucket: 5 * time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(counts) != 2 || counts[0].Value != 2 || !counts[1].Timestamp.Equal(start.Add(10*time.Second)) || counts[1].Value != 1 {
+		t.Fatalf("counts=%v", counts)
+	}
+
+	series, err := ts.MRange(start, start.Add(time.Minute), []string{"test=nativeTimeSeries"}, &TimeSeriesAggregation{Aggregator: TimeSeriesAvg, Bucket: time.Hour})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(series) != 2 || len(series["testNativeTimeSeriesOther"]) != 1 || series["testNativeTimeSeriesOther"][0].Value != 5 ||
+		series["testNativeTimeSeries"][0].Value != 2 {
+		t.Fatalf("series=%v", series)
+	}
+	series, err = ts.MRange(start, start.Add(time.Minute), []string{"test=nativeTimeSeries", "sensor=1"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(series) != 1 || len(series["testNativeTimeSeries"]) != 3 {
+		t.Fatalf("series=%v", series)
+	}
+
+	if _, err = ts.Range(start, start, &TimeSeriesAggregation{Aggregator: TimeSeriesSum}); err == nil {
+		t.Fatal("a bucket of 0 is accepted")
+	}
+}
+
+func TestNativeTimeSeriesModuleUnavailable(t *testing.T) {
+	g := GetRedisson()
+	if ok, err := g.hasCommand(context.Background(), "TS.ADD"); err != nil {
+		t.Fatal(err)
+	} else if ok {
+		t.Skip("the RedisTimeSeries module is loaded")
+	}
+	ts := g.GetNativeTimeSeries("testNativeTimeSeriesUnavailable")
+	if err := ts.Add(time.Now(), 1); !errors.Is(err, ErrTimeSeriesModuleUnavailable) {
+		t.Fatalf("err=%v", err)
+	}
+	if _, err := ts.Range(time.Now().Add(-time.Hour), time.Now(), nil); !errors.Is(err, ErrTimeSeriesModuleUnavailable) {
+		t.Fatalf("err=%v", err)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"github.com/redis/go-redis/v9"
 	"strings"
 	"sync"
+	"time"
 )
 
 // RedissonObject is the base struct for all objects
@@ -12,6 +13,8 @@ type RedissonObject struct {
 	name string
 	*Redisson
 	mutex sync.Mutex
+	// ttl is set on the keys of the object when they are first written, 0 means no TTL
+	ttl time.Duration
 }
 
 // prefixName prefixes the name with the given prefix
@@ -46,9 +49,30 @@ func newRedissonObject(name string, redisson *Redisson) *RedissonObject {
 	return &RedissonObject{
 		name:     name,
 		Redisson: redisson,
+		ttl:      redisson.defaultObjectTTL,
 	}
 }
 
+// applyTTL sets the ttl of the object on the given keys which do not have a TTL yet, bounded by its own command timeout
+func (o *RedissonObject) applyTTL(ctx context.Context, keys ...string) error {
+	if o.ttl <= 0 {
+		return nil
+	}
+	ctx, cancel := o.withCommandTimeout(ctx)
+	defer cancel()
+	return o.eval(ctx, "object.applyTTL", applyTTLScript, keys, o.ttl.Milliseconds()).Err()
+}
+
+// applyTTLScript sets the ttl ARGV[1] on the KEYS which have no expiry
+const applyTTLScript = `
+for j = 1, #KEYS, 1 do
+    if redis.call('pttl', KEYS[j]) == -1 then
+        redis.call('pexpire', KEYS[j], ARGV[1]);
+    end;
+end;
+return 1;
+`
+
 // sizeInMemoryAsync calculates the total memory usage for the given keys asynchronously
 func (r *Redisson) sizeInMemoryAsync(keys []string) (*int64, error) {
 	luaScript := `
@@ -63,8 +87,9 @@ func (r *Redisson) sizeInMemoryAsync(keys []string) (*int64, error) {
 
 	// Execute the Lua script
 	//创建一个 ctx
-	ctx := context.Background()
-	res, err := r.client.Eval(ctx, luaScript, keys).Int64()
+	ctx, cancel := r.newContext()
+	defer cancel()
+	res, err := r.eval(ctx, "object.sizeInMemory", luaScript, keys).Int64()
 	if err != nil {
 		if err == redis.Nil {
 			return nil, nil
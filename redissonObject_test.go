@@ -1,6 +1,11 @@
 package redisson
 
-import "testing"
+import (
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
 
 func TestPrefixName(t *testing.T) {
 	o := newRedissonObjectNULL("test")
@@ -25,3 +30,86 @@ func TestSuffixName(t *testing.T) {
 		t.Fatal(name)
 	}
 }
+
+func TestDefaultObjectTTL(t *testing.T) {
+	redisDB := redis.NewClient(&redis.Options{
+		Addr: redisAddr,
+	})
+	g := NewRedisson(redisDB, WithDefaultObjectTTL(time.Minute))
+
+	al := g.GetAtomicLong("testDefaultObjectTTL")
+	defer al.GetAndDelete()
+	if err := al.Set(1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := al.IncrementAndGet(); err != nil {
+		t.Fatal(err)
+	}
+	if ttl, err := al.RemainTimeToLive(); err != nil {
+		t.Fatal(err)
+	} else if ttl <= 0 || ttl > time.Minute.Milliseconds() {
+		t.Fatalf("ttl=%v", ttl)
+	}
+
+	// WithTTL(0) overrides the default
+	al = g.GetAtomicLong("testDefaultObjectTTLOverride", WithTTL(0))
+	defer al.GetAndDelete()
+	if err := al.Set(1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := al.IncrementAndGet(); err != nil {
+		t.Fatal(err)
+	}
+	if ttl, err := al.RemainTimeToLive(); err != nil {
+		t.Fatal(err)
+	} else if ttl > 0 {
+		t.Fatalf("ttl=%v", ttl)
+	}
+
+	// Set, GetAndSet and CompareAndSet keep the TTL of the counter instead of clearing or restarting it
+	if _, err := al.Expire(time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if err := al.Set(2); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := al.GetAndSet(3); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := al.CompareAndSet(3, 4); err != nil {
+		t.Fatal(err)
+	}
+	if ttl, err := al.RemainTimeToLive(); err != nil {
+		t.Fatal(err)
+	} else if ttl <= time.Minute.Milliseconds() {
+		t.Fatalf("ttl=%v", ttl)
+	}
+	ad := g.GetAtomicDouble("testDefaultObjectTTLDouble")
+	defer ad.GetAndDelete()
+	if err := ad.Set(1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ad.Expire(time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if err := ad.Set(2); err != nil {
+		t.Fatal(err)
+	}
+	if ttl, err := ad.RemainTimeToLive(); err != nil {
+		t.Fatal(err)
+	} else if ttl <= time.Minute.Milliseconds() {
+		t.Fatalf("ttl=%v", ttl)
+	}
+
+	// the counters created by the batch helper get the default TTL too
+	batched := g.GetAtomicLong("testDefaultObjectTTLBatch")
+	defer batched.GetAndDelete()
+	if _, err := g.AddAndGetAll(map[string]int64{"testDefaultObjectTTLBatch": 1}); err != nil {
+		t.Fatal(err)
+	}
+	if ttl, err := batched.RemainTimeToLive(); err != nil {
+		t.Fatal(err)
+	} else if ttl <= 0 || ttl > time.Minute.Milliseconds() {
+		t.Fatalf("ttl=%v", ttl)
+	}
+}
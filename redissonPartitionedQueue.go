@@ -0,0 +1,317 @@
+package redisson
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"hash/fnv"
+	"log"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RPartitionedQueue is a work queue split into partitions by the hash of the key of each task. Every partition is
+// consumed by a single consumer at a time, so the tasks of a key are handled in order while the partitions are
+// handled in parallel. Partitions are rebalanced when consumers join, leave or die.
+type RPartitionedQueue[T any] interface {
+	// Offer appends the task to the partition of key.
+	Offer(key string, task T) error
+
+	// Consume registers a consumer calling handler for the tasks of the partitions assigned to it until ctx is done.
+	// A task whose handler fails is put back at the head of its partition and retried.
+	Consume(ctx context.Context, handler func(ctx context.Context, task T) error) error
+
+	// Size returns the number of tasks in all the partitions.
+	Size() (int64, error)
+}
+
+var (
+	_ RPartitionedQueue[string] = (*RedissonPartitionedQueue[string])(nil)
+)
+
+// partitionRetryDelay is how long a partition waits before retrying a task whose handler failed
+const partitionRetryDelay = time.Second
+
+// RedissonPartitionedQueue is the implementation of RPartitionedQueue
+// the tasks of partition i are stored in the {name}:p:i list, the live consumers in the {name}:consumers zset
+// scored by the expiry of their heartbeat in the time of the server, and the consumer owning partition i in the
+// {name}:owner:i lease key.
+// Partition i is assigned to the consumer at index i % n of the n live consumers sorted by id.
+type RedissonPartitionedQueue[T any] struct {
+	*RedissonExpirable
+	codec      Codec
+	partitions int
+}
+
+// newRedissonPartitionedQueue creates a new RedissonPartitionedQueue
+func newRedissonPartitionedQueue[T any](name string, redisson *Redisson, partitions int, options *objectOptions) *RedissonPartitionedQueue[T] {
+	if partitions < 1 {
+		partitions = 1
+	}
+	return &RedissonPartitionedQueue[T]{
+		RedissonExpirable: newRedissonExpirable(name, redisson),
+		codec:             options.codec,
+		partitions:        partitions,
+	}
+}
+
+// getPartitionName returns the name of the list of the tasks of partition
+func (m *RedissonPartitionedQueue[T]) getPartitionName(partition int) string {
+	return m.suffixName(m.getRawName(), "p:"+strconv.Itoa(partition))
+}
+
+// getOwnerName returns the name of the lease key of partition
+func (m *RedissonPartitionedQueue[T]) getOwnerName(partition int) string {
+	return m.suffixName(m.getRawName(), "owner:"+strconv.Itoa(partition))
+}
+
+// getConsumersName returns the name of the zset of the live consumers
+func (m *RedissonPartitionedQueue[T]) getConsumersName() string {
+	return m.suffixName(m.getRawName(), "consumers")
+}
+
+// partitionOf returns the partition of key
+func (m *RedissonPartitionedQueue[T]) partitionOf(key string) int {
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return int(h.Sum32() % uint32(m.partitions))
+}
+
+// Offer appends the task to the partition of key.
+func (m *RedissonPartitionedQueue[T]) Offer(key string, task T) error {
+	ctx, cancel := m.newContext()
+	defer cancel()
+	data, err := m.codec.Encode(task)
+	if err != nil {
+		return err
+	}
+	return m.client.RPush(ctx, m.getPartitionName(m.partitionOf(key)), data).Err()
+}
+
+// Size returns the number of tasks in all the partitions.
+func (m *RedissonPartitionedQueue[T]) Size() (int64, error) {
+	ctx, cancel := m.newContext()
+	defer cancel()
+	pipe := m.client.Pipeline()
+	lens := make([]*redis.IntCmd, m.partitions)
+	for i := range lens {
+		lens[i] = pipe.LLen(ctx, m.getPartitionName(i))
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, err
+	}
+	var size int64
+	for _, l := range lens {
+		size += l.Val()
+	}
+	return size, nil
+}
+
+// partitionWorker is a partition consumed by this consumer
+type partitionWorker struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+	//leaseEnd time the lease of the partition expires unless it is renewed
+	leaseEnd time.Time
+}
+
+// Consume registers a consumer calling handler for the tasks of the partitions assigned to it until ctx is done.
+func (m *RedissonPartitionedQueue[T]) Consume(ctx context.Context, handler func(ctx context.Context, task T) error) error {
+	idBytes := make([]byte, 16)
+	if _, err := rand.Read(idBytes); err != nil {
+		return err
+	}
+	id := hex.EncodeToString(idBytes)
+	// the heartbeat and the partition leases last as long as a lock held by the watchdog
+	lease := m.watchDogTimeout
+	workers := make(map[int]*partitionWorker)
+	stop := func(partition int) {
+		w := workers[partition]
+		w.cancel()
+		<-w.done
+		delete(workers, partition)
+	}
+	defer func() {
+		for _, w := range workers {
+			w.cancel()
+		}
+		for partition := range workers {
+			stop(partition)
+		}
+		// leave without waiting for the heartbeat and the leases to expire, so the others take over at once
+		leaveCtx, cancel := m.withCommandTimeout(context.WithoutCancel(ctx))
+		defer cancel()
+		if err := m.leave(leaveCtx, id); err != nil {
+			log.Printf("partitioned queue %s: consumer %s failed to leave: %v", m.getRawName(), id, err)
+		}
+	}()
+
+	for {
+		renewed := m.clock.Now()
+		rebalanceCtx, cancel := m.withCommandTimeout(ctx)
+		owned, err := m.rebalance(rebalanceCtx, id, lease)
+		cancel()
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			log.Printf("partitioned queue %s: rebalancing failed: %v", m.getRawName(), err)
+			// a partition whose lease expires before the next renewal may be taken by another consumer,
+			// its worker stops first so that two consumers never handle the partition at once
+			next := m.clock.Now().Add(lease / 3)
+			for _, w := range workers {
+				if !w.leaseEnd.After(next) {
+					w.cancel()
+				}
+			}
+			for partition, w := range workers {
+				if !w.leaseEnd.After(next) {
+					log.Printf("partitioned queue %s: stopping partition %d whose lease could not be renewed", m.getRawName(), partition)
+					stop(partition)
+				}
+			}
+		} else {
+			for partition, w := range workers {
+				if !owned[partition] {
+					w.cancel()
+				}
+			}
+			for partition := range workers {
+				if owned[partition] {
+					continue
+				}
+				// the next owner takes the partition only once its worker stopped, keeping the order of the tasks
+				stop(partition)
+				releaseCtx, cancel := m.withCommandTimeout(ctx)
+				err = m.releasePartition(releaseCtx, partition, id)
+				cancel()
+				if err != nil && ctx.Err() == nil {
+					log.Printf("partitioned queue %s: releasing partition %d failed: %v", m.getRawName(), partition, err)
+				}
+			}
+			for partition := range owned {
+				if w, ok := workers[partition]; ok {
+					w.leaseEnd = renewed.Add(lease)
+					continue
+				}
+				workerCtx, cancel := context.WithCancel(ctx)
+				w := &partitionWorker{cancel: cancel, done: make(chan struct{}), leaseEnd: renewed.Add(lease)}
+				workers[partition] = w
+				go func(partition int) {
+					defer close(w.done)
+					m.consumePartition(workerCtx, partition, handler)
+				}(partition)
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-m.clock.After(lease / 3):
+		}
+	}
+}
+
+// rebalance renews the heartbeat of the consumer, takes or renews the leases of the partitions assigned to it,
+// and returns the partitions it owns
+func (m *RedissonPartitionedQueue[T]) rebalance(ctx context.Context, id string, lease time.Duration) (map[int]bool, error) {
+	// the heartbeats are scored with the time of the server, so the skew of the clocks of the consumers
+	// does not evict live consumers
+	consumers, err := m.eval(ctx, "partitionedQueue.heartbeat", `
+redis.replicate_commands();
+local time = redis.call('time');
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000);
+redis.call('zremrangebyscore', KEYS[1], '-inf', '(' .. string.format('%d', now));
+redis.call('zadd', KEYS[1], string.format('%d', now + tonumber(ARGV[2])), ARGV[1]);
+return redis.call('zrange', KEYS[1], 0, -1);
+`, []string{m.getConsumersName()}, id, lease.Milliseconds()).StringSlice()
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(consumers)
+	index := sort.SearchStrings(consumers, id)
+
+	owned := make(map[int]bool)
+	for partition := 0; partition < m.partitions; partition++ {
+		if partition%len(consumers) != index {
+			continue
+		}
+		// the lease of the previous owner is released once it notices the new assignment, or expires if it died
+		acquired, err := m.eval(ctx, "partitionedQueue.acquire", `
+local owner = redis.call('get', KEYS[1]);
+if owner == false or owner == ARGV[1] then
+    redis.call('set', KEYS[1], ARGV[1], 'px', ARGV[2]);
+    return 1;
+end ;
+return 0;
+`, []string{m.getOwnerName(partition)}, id, lease.Milliseconds()).Int64()
+		if err != nil {
+			return nil, err
+		}
+		if acquired == 1 {
+			owned[partition] = true
+		}
+	}
+	return owned, nil
+}
+
+// releasePartition releases the lease of partition if the consumer holds it
+func (m *RedissonPartitionedQueue[T]) releasePartition(ctx context.Context, partition int, id string) error {
+	_, err := m.eval(ctx, "partitionedQueue.release", `
+if redis.call('get', KEYS[1]) == ARGV[1] then
+    redis.call('del', KEYS[1]);
+end ;
+return 1;
+`, []string{m.getOwnerName(partition)}, id).Result()
+	return err
+}
+
+// leave releases the leases of the consumer and removes it from the live consumers
+func (m *RedissonPartitionedQueue[T]) leave(ctx context.Context, id string) error {
+	for partition := 0; partition < m.partitions; partition++ {
+		if err := m.releasePartition(ctx, partition, id); err != nil {
+			return err
+		}
+	}
+	return m.client.ZRem(ctx, m.getConsumersName(), id).Err()
+}
+
+// consumePartition calls handler for the tasks of partition in order until ctx is done
+func (m *RedissonPartitionedQueue[T]) consumePartition(ctx context.Context, partition int, handler func(ctx context.Context, task T) error) {
+	name := m.getPartitionName(partition)
+	// a task popped while ctx is cancelled would be lost, so the pop itself is not cancelled
+	popCtx := context.WithoutCancel(ctx)
+	for ctx.Err() == nil {
+		res, err := m.client.BLPop(popCtx, time.Second, name).Result()
+		if err == redis.Nil {
+			continue
+		}
+		if err != nil {
+			log.Printf("partitioned queue %s: consuming partition %d failed: %v", m.getRawName(), partition, err)
+			select {
+			case <-ctx.Done():
+			case <-m.clock.After(partitionRetryDelay):
+			}
+			continue
+		}
+		data := res[1]
+		var task T
+		if err = m.codec.Decode([]byte(data), &task); err != nil {
+			log.Printf("partitioned queue %s: failed to decode task: %v", m.getRawName(), err)
+			continue
+		}
+		if err = handler(ctx, task); err != nil {
+			log.Printf("partitioned queue %s: handling task of partition %d failed: %v", m.getRawName(), partition, err)
+			// keep the order of the partition by retrying the task before the next ones
+			if err = m.client.LPush(popCtx, name, data).Err(); err != nil {
+				log.Printf("partitioned queue %s: failed to put back task: %v", m.getRawName(), err)
+			}
+			select {
+			case <-ctx.Done():
+			case <-m.clock.After(partitionRetryDelay):
+			}
+		}
+	}
+}
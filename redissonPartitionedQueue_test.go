@@ -0,0 +1,180 @@
+package redisson
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type partitionedTask struct {
+	Key string
+	Seq int
+}
+
+func TestPartitionedQueue(t *testing.T) {
+	clock := NewManualClock(time.Now())
+	newConsumer := func() RPartitionedQueue[partitionedTask] {
+		g := NewRedisson(redis.NewClient(&redis.Options{Addr: redisAddr}), WithClock(clock))
+		return GetPartitionedQueue[partitionedTask](g, "testPartitionedQueue", 4)
+	}
+	g := GetRedisson()
+	keys, err := g.client.Keys(context.Background(), "{testPartitionedQueue}:*").Result()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) > 0 {
+		if err = g.client.Del(context.Background(), keys...).Err(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	mu := sync.Mutex{}
+	// handled sequence numbers by key and handled task count by consumer
+	handled := make(map[string][]int)
+	counts := make([]int, 2)
+	consume := func(consumer int) func(ctx context.Context, task partitionedTask) error {
+		return func(ctx context.Context, task partitionedTask) error {
+			mu.Lock()
+			defer mu.Unlock()
+			handled[task.Key] = append(handled[task.Key], task.Seq)
+			counts[consumer]++
+			return nil
+		}
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan struct{}, 2)
+	for i := 0; i < 2; i++ {
+		go func(i int) {
+			newConsumer().Consume(ctx, consume(i))
+			done <- struct{}{}
+		}(i)
+	}
+	// let the consumers see each other and hand over the partitions
+	// a worker stops once its pending pop returns, within a second
+	for i := 0; i < 3; i++ {
+		time.Sleep(1200 * time.Millisecond)
+		clock.Advance(10 * time.Second)
+	}
+	time.Sleep(100 * time.Millisecond)
+
+	queue := newConsumer()
+	for seq := 0; seq < 10; seq++ {
+		for k := 0; k < 8; k++ {
+			if err = queue.Offer(fmt.Sprintf("key-%d", k), partitionedTask{Key: fmt.Sprintf("key-%d", k), Seq: seq}); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	deadline := time.Now().Add(3 * time.Second)
+	for {
+		size, err := queue.Size()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if size == 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("%d tasks left", size)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	time.Sleep(100 * time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+	for key, seqs := range handled {
+		for i, seq := range seqs {
+			if seq != i {
+				t.Fatalf("%s handled out of order: %v", key, seqs)
+			}
+		}
+		if len(seqs) != 10 {
+			t.Fatalf("%s handled %v", key, seqs)
+		}
+	}
+	if counts[0] == 0 || counts[1] == 0 {
+		t.Fatalf("partitions not balanced: %v", counts)
+	}
+	cancel()
+	<-done
+	<-done
+}
+
+// failingHook fails the commands of a client while fail is set, as if redis was unreachable
+type failingHook struct {
+	fail *atomic.Bool
+}
+
+func (h failingHook) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+func (h failingHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		if h.fail.Load() {
+			err := errors.New("redis is unreachable")
+			cmd.SetErr(err)
+			return err
+		}
+		return next(ctx, cmd)
+	}
+}
+
+func (h failingHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return next
+}
+
+func TestPartitionedQueueLeaseLost(t *testing.T) {
+	clock := NewManualClock(time.Now())
+	client := redis.NewClient(&redis.Options{Addr: redisAddr})
+	var fail atomic.Bool
+	client.AddHook(failingHook{fail: &fail})
+	queue := GetPartitionedQueue[int](NewRedisson(client, WithClock(clock)), "testPartitionedQueueLeaseLost", 1)
+	g := GetRedisson()
+	if err := g.client.Del(context.Background(), "{testPartitionedQueueLeaseLost}:p:0", "{testPartitionedQueueLeaseLost}:owner:0",
+		"{testPartitionedQueueLeaseLost}:consumers").Err(); err != nil {
+		t.Fatal(err)
+	}
+	if err := queue.Offer("key", 1); err != nil {
+		t.Fatal(err)
+	}
+
+	handling := make(chan struct{})
+	cancelled := make(chan struct{})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go queue.Consume(ctx, func(ctx context.Context, task int) error {
+		close(handling)
+		<-ctx.Done()
+		close(cancelled)
+		return nil
+	})
+	<-handling
+	// let the consumer wait for its next renewal
+	time.Sleep(100 * time.Millisecond)
+
+	// the lease of 30s is not renewed: the worker keeps the partition while the lease lasts
+	// and stops before the next renewal attempt would come after its expiry
+	fail.Store(true)
+	clock.Advance(10 * time.Second)
+	select {
+	case <-cancelled:
+		t.Fatal("the worker stopped while its lease lasts")
+	case <-time.After(200 * time.Millisecond):
+	}
+	clock.Advance(10 * time.Second)
+	select {
+	case <-cancelled:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the worker was not stopped before its lease expired")
+	}
+	fail.Store(false)
+}
@@ -0,0 +1,256 @@
+package redisson
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrPermitNotFound indicates that a permit was already released or expired
+var ErrPermitNotFound = errors.New("permit not found")
+
+// permitSemaphoreRetryInterval is how long Acquire waits for a release before trying again when no held permit
+// expires, e.g. when the semaphore has no permit
+const permitSemaphoreRetryInterval = time.Second
+
+// PermitInfo describes a permit held on a RPermitExpirableSemaphore.
+type PermitInfo struct {
+	// ID identifies the permit, it is used to release it
+	ID string
+	// Metadata is the payload attached when the permit was acquired, e.g. the worker or job holding it
+	Metadata string
+	// ExpiresAt is the time the permit is released automatically
+	ExpiresAt time.Time
+}
+
+// RPermitExpirableSemaphore is a semaphore whose permits are identified, carry metadata and expire
+// after their lease, so permits held by crashed workers are reclaimed automatically.
+type RPermitExpirableSemaphore interface {
+	RExpirable
+
+	// TrySetPermits sets the number of permits if it was not set yet and reports whether it was set.
+	TrySetPermits(permits int64) (bool, error)
+
+	// TryAcquire acquires a permit leased for leaseTime with the given metadata if one is available.
+	// It returns the id of the permit, or "" if none is available.
+	TryAcquire(leaseTime time.Duration, metadata string) (string, error)
+
+	// Acquire acquires a permit leased for leaseTime with the given metadata,
+	// waiting until one is available or ctx is done.
+	Acquire(ctx context.Context, leaseTime time.Duration, metadata string) (string, error)
+
+	// Release releases the permit, which may be held by another worker.
+	// It returns ErrPermitNotFound if the permit was already released or expired.
+	Release(permitId string) error
+
+	// ListPermits returns the permits currently held.
+	ListPermits() ([]PermitInfo, error)
+
+	// AvailablePermits returns the number of permits which can be acquired.
+	AvailablePermits() (int64, error)
+}
+
+var (
+	_ RPermitExpirableSemaphore = (*RedissonPermitExpirableSemaphore)(nil)
+)
+
+// RedissonPermitExpirableSemaphore is the implementation of RPermitExpirableSemaphore
+// the number of permits is stored in the name key, held permits in the {name}:timeout zset scored by expiry
+// and their metadata in the {name}:metadata hash
+type RedissonPermitExpirableSemaphore struct {
+	*RedissonExpirable
+}
+
+// newRedissonPermitExpirableSemaphore creates a new RedissonPermitExpirableSemaphore
+func newRedissonPermitExpirableSemaphore(name string, redisson *Redisson, options *objectOptions) *RedissonPermitExpirableSemaphore {
+	m := &RedissonPermitExpirableSemaphore{
+		RedissonExpirable: newRedissonExpirable(name, redisson),
+	}
+	m.ttl = options.ttl
+	m.componentKeys = func() []string {
+		return []string{m.getRawName(), m.getTimeoutName(), m.getMetadataName()}
+	}
+	return m
+}
+
+// getTimeoutName returns the name of the zset of the held permits
+func (m *RedissonPermitExpirableSemaphore) getTimeoutName() string {
+	return m.suffixName(m.getRawName(), "timeout")
+}
+
+// getMetadataName returns the name of the hash of the metadata of the held permits
+func (m *RedissonPermitExpirableSemaphore) getMetadataName() string {
+	return m.suffixName(m.getRawName(), "metadata")
+}
+
+// getChannelName returns the channel notified when a permit is released
+func (m *RedissonPermitExpirableSemaphore) getChannelName() string {
+	return m.prefixName("redisson_sc", m.getRawName())
+}
+
+// TrySetPermits sets the number of permits if it was not set yet and reports whether it was set.
+func (m *RedissonPermitExpirableSemaphore) TrySetPermits(permits int64) (bool, error) {
+	ctx, cancel := m.newContext()
+	defer cancel()
+	ok, err := m.client.SetNX(ctx, m.getRawName(), permits, 0).Result()
+	if err != nil || !ok {
+		return ok, err
+	}
+	return true, m.applyTTL(ctx, m.getRawName())
+}
+
+// TryAcquire acquires a permit leased for leaseTime with the given metadata if one is available.
+func (m *RedissonPermitExpirableSemaphore) TryAcquire(leaseTime time.Duration, metadata string) (string, error) {
+	ctx, cancel := m.newContext()
+	defer cancel()
+	id, _, err := m.tryAcquire(ctx, leaseTime, metadata)
+	return id, err
+}
+
+// Acquire acquires a permit leased for leaseTime with the given metadata, waiting until one is available or ctx is done.
+func (m *RedissonPermitExpirableSemaphore) Acquire(ctx context.Context, leaseTime time.Duration, metadata string) (string, error) {
+	sub, err := m.subscriptions.subscribe(ctx, m.getChannelName())
+	if err != nil {
+		return "", err
+	}
+	defer m.subscriptions.unsubscribe(context.WithoutCancel(ctx), sub)
+	for {
+		id, wait, err := m.tryAcquire(ctx, leaseTime, metadata)
+		if err != nil || id != "" {
+			return id, err
+		}
+		if wait < 0 {
+			// no held permit expires, only a release or new permits make one available
+			wait = permitSemaphoreRetryInterval
+		}
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		// a permit has been released
+		case <-sub.c:
+		// the first permit to expire has expired
+		case <-m.clock.After(max(wait, time.Millisecond)):
+		}
+	}
+}
+
+// tryAcquire acquires a permit, or returns how long to wait until the first held permit expires, negative if
+// no permit is held
+func (m *RedissonPermitExpirableSemaphore) tryAcquire(ctx context.Context, leaseTime time.Duration, metadata string) (string, time.Duration, error) {
+	idBytes := make([]byte, 16)
+	if _, err := rand.Read(idBytes); err != nil {
+		return "", 0, err
+	}
+	id := hex.EncodeToString(idBytes)
+	now := m.clock.Now().UnixMilli()
+	wait, err := m.eval(ctx, "permitSemaphore.tryAcquire", `
+local expired = redis.call('zrangebyscore', KEYS[2], '-inf', ARGV[1]);
+if #expired > 0 then
+    redis.call('zremrangebyscore', KEYS[2], '-inf', ARGV[1]);
+    redis.call('hdel', KEYS[3], unpack(expired));
+end ;
+local permits = tonumber(redis.call('get', KEYS[1]));
+if permits == nil then
+    return redis.error_reply('semaphore permits are not set');
+end ;
+if redis.call('zcard', KEYS[2]) < permits then
+    redis.call('zadd', KEYS[2], ARGV[2], ARGV[3]);
+    redis.call('hset', KEYS[3], ARGV[3], ARGV[4]);
+    return nil;
+end ;
+local first = redis.call('zrange', KEYS[2], 0, 0, 'WITHSCORES');
+if #first == 0 then
+    return -1;
+end ;
+return tonumber(first[2]) - tonumber(ARGV[1]);
+`, []string{m.getRawName(), m.getTimeoutName(), m.getMetadataName()}, now, now+leaseTime.Milliseconds(), id, metadata).Int64()
+	if err == redis.Nil {
+		return id, 0, m.applyTTL(ctx, m.getTimeoutName(), m.getMetadataName())
+	}
+	if err != nil {
+		return "", 0, err
+	}
+	return "", time.Duration(wait) * time.Millisecond, nil
+}
+
+// Release releases the permit, which may be held by another worker.
+func (m *RedissonPermitExpirableSemaphore) Release(permitId string) error {
+	ctx, cancel := m.newContext()
+	defer cancel()
+	released, err := m.eval(ctx, "permitSemaphore.release", `
+local released = redis.call('zrem', KEYS[1], ARGV[1]);
+redis.call('hdel', KEYS[2], ARGV[1]);
+if released == 1 then
+    redis.call('publish', KEYS[3], ARGV[1]);
+end ;
+return released;
+`, []string{m.getTimeoutName(), m.getMetadataName(), m.getChannelName()}, permitId).Int64()
+	if err != nil {
+		return err
+	}
+	if released == 0 {
+		return ErrPermitNotFound
+	}
+	return nil
+}
+
+// ListPermits returns the permits currently held.
+func (m *RedissonPermitExpirableSemaphore) ListPermits() ([]PermitInfo, error) {
+	ctx, cancel := m.newContext()
+	defer cancel()
+	held, err := m.client.ZRangeByScoreWithScores(ctx, m.getTimeoutName(), &redis.ZRangeBy{
+		Min: "(" + strconv.FormatInt(m.clock.Now().UnixMilli(), 10),
+		Max: "+inf",
+	}).Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(held) == 0 {
+		return nil, nil
+	}
+	ids := make([]string, len(held))
+	for i, z := range held {
+		ids[i] = z.Member.(string)
+	}
+	metadata, err := m.client.HMGet(ctx, m.getMetadataName(), ids...).Result()
+	if err != nil {
+		return nil, err
+	}
+	permits := make([]PermitInfo, len(held))
+	for i, z := range held {
+		permits[i] = PermitInfo{
+			ID:        ids[i],
+			ExpiresAt: time.UnixMilli(int64(z.Score)),
+		}
+		if s, ok := metadata[i].(string); ok {
+			permits[i].Metadata = s
+		}
+	}
+	return permits, nil
+}
+
+// AvailablePermits returns the number of permits which can be acquired.
+func (m *RedissonPermitExpirableSemaphore) AvailablePermits() (int64, error) {
+	ctx, cancel := m.newContext()
+	defer cancel()
+	permits, err := m.client.Get(ctx, m.getRawName()).Int64()
+	if err == redis.Nil {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	held, err := m.client.ZCount(ctx, m.getTimeoutName(), "("+strconv.FormatInt(m.clock.Now().UnixMilli(), 10), "+inf").Result()
+	if err != nil {
+		return 0, err
+	}
+	if held > permits {
+		return 0, nil
+	}
+	return permits - held, nil
+}
@@ -0,0 +1,109 @@
+package redisson
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+func TestPermitExpirableSemaphore(t *testing.T) {
+	g := GetRedisson()
+	s := g.GetPermitExpirableSemaphore("testPermitSemaphore")
+	if err := g.client.Del(context.Background(), "testPermitSemaphore", "{testPermitSemaphore}:timeout", "{testPermitSemaphore}:metadata").Err(); err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := s.TrySetPermits(2); err != nil || !ok {
+		t.Fatalf("ok=%v err=%v", ok, err)
+	}
+
+	first, err := s.TryAcquire(time.Minute, "worker-1/job-42")
+	if err != nil || first == "" {
+		t.Fatalf("first=%v err=%v", first, err)
+	}
+	second, err := s.TryAcquire(200*time.Millisecond, "worker-2/job-43")
+	if err != nil || second == "" {
+		t.Fatalf("second=%v err=%v", second, err)
+	}
+	if id, err := s.TryAcquire(time.Minute, "worker-3"); err != nil || id != "" {
+		t.Fatalf("id=%v err=%v", id, err)
+	}
+
+	permits, err := s.ListPermits()
+	if err != nil {
+		t.Fatal(err)
+	}
+	metadata := map[string]string{}
+	for _, p := range permits {
+		metadata[p.ID] = p.Metadata
+	}
+	if metadata[first] != "worker-1/job-42" || metadata[second] != "worker-2/job-43" {
+		t.Fatalf("permits=%+v", permits)
+	}
+
+	// the second permit expires, a waiter gets it
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	third, err := s.Acquire(ctx, time.Minute, "worker-3")
+	if err != nil || third == "" {
+		t.Fatalf("third=%v err=%v", third, err)
+	}
+
+	// an operator reclaims a specific permit
+	if err = s.Release(first); err != nil {
+		t.Fatal(err)
+	}
+	if err = s.Release(first); err != ErrPermitNotFound {
+		t.Fatalf("err=%v", err)
+	}
+	if n, err := s.AvailablePermits(); err != nil || n != 1 {
+		t.Fatalf("n=%v err=%v", n, err)
+	}
+}
+
+// countingHook counts the commands of a client
+type countingHook struct {
+	count *atomic.Int64
+}
+
+func (h countingHook) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+func (h countingHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		h.count.Add(1)
+		return next(ctx, cmd)
+	}
+}
+
+func (h countingHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return next
+}
+
+// TestPermitExpirableSemaphoreNoPermits test Acquire waits for a release instead of retrying when no permit is held
+func TestPermitExpirableSemaphoreNoPermits(t *testing.T) {
+	client := redis.NewClient(&redis.Options{Addr: redisAddr})
+	var commands atomic.Int64
+	client.AddHook(countingHook{count: &commands})
+	g := NewRedisson(client)
+	s := g.GetPermitExpirableSemaphore("testPermitSemaphoreNoPermits")
+	if err := g.client.Del(context.Background(), "testPermitSemaphoreNoPermits", "{testPermitSemaphoreNoPermits}:timeout",
+		"{testPermitSemaphoreNoPermits}:metadata").Err(); err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := s.TrySetPermits(0); err != nil || !ok {
+		t.Fatalf("ok=%v err=%v", ok, err)
+	}
+	commands.Store(0)
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	if id, err := s.Acquire(ctx, time.Minute, ""); err != context.DeadlineExceeded {
+		t.Fatalf("id=%v err=%v", id, err)
+	}
+	if n := commands.Load(); n > 5 {
+		t.Fatalf("%d commands while waiting", n)
+	}
+}
@@ -0,0 +1,252 @@
+package redisson
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log"
+	"strconv"
+	"strings"
+)
+
+// PresenceInstance is a live instance of a RPresenceRegistry.
+type PresenceInstance struct {
+	// ID identifies the registration
+	ID string
+	// Metadata is the payload the instance registered with, e.g. its address
+	Metadata string
+}
+
+// PresenceEventType is the type of a PresenceEvent.
+type PresenceEventType int
+
+const (
+	// PresenceJoin is sent when an instance registers
+	PresenceJoin PresenceEventType = iota
+	// PresenceLeave is sent when an instance deregisters or its heartbeat expires
+	PresenceLeave
+)
+
+// PresenceEvent notifies that an instance joined or left a RPresenceRegistry.
+type PresenceEvent struct {
+	Type PresenceEventType
+	// ID identifies the registration
+	ID string
+	// Metadata is the payload of the instance, empty when it left because its heartbeat expired
+	Metadata string
+}
+
+// RPresenceRegistry tracks the live instances of a service. Each registration is a key whose TTL is renewed
+// by a heartbeat for as long as the instance lives, so crashed instances disappear once the TTL elapses.
+type RPresenceRegistry interface {
+	// Register registers an instance with the given metadata and returns the id of the registration.
+	// The heartbeat keeps it alive until ctx is done, then the instance deregisters.
+	Register(ctx context.Context, metadata string) (string, error)
+
+	// List returns the live instances.
+	List() ([]PresenceInstance, error)
+
+	// Subscribe sends on the returned channel the instances joining and leaving until ctx is done.
+	// Leaves caused by an expired heartbeat are only seen when the server sends expired keyevent
+	// notifications, i.e. notify-keyspace-events contains "Ex".
+	Subscribe(ctx context.Context) (<-chan PresenceEvent, error)
+}
+
+var (
+	_ RPresenceRegistry = (*RedissonPresenceRegistry)(nil)
+)
+
+const (
+	// presenceJoinMessage presenceLeaveMessage prefix the messages published on the channel of a registry
+	presenceJoinMessage  = "j"
+	presenceLeaveMessage = "l"
+)
+
+// RedissonPresenceRegistry is the implementation of RPresenceRegistry
+// the ids of the instances are stored in the name set and the metadata of each in the {name}:instance:id key,
+// which expires unless renewed by the heartbeat
+type RedissonPresenceRegistry struct {
+	*RedissonExpirable
+}
+
+// newRedissonPresenceRegistry creates a new RedissonPresenceRegistry
+func newRedissonPresenceRegistry(name string, redisson *Redisson, options *objectOptions) *RedissonPresenceRegistry {
+	m := &RedissonPresenceRegistry{
+		RedissonExpirable: newRedissonExpirable(name, redisson),
+	}
+	m.ttl = options.ttl
+	return m
+}
+
+// getInstancePrefix returns the prefix of the keys of the instances
+func (m *RedissonPresenceRegistry) getInstancePrefix() string {
+	return m.suffixName(m.getRawName(), "instance:")
+}
+
+// getChannelName returns the channel the joins and leaves are published on
+func (m *RedissonPresenceRegistry) getChannelName() string {
+	return m.prefixName("redisson_presence", m.getRawName())
+}
+
+// Register registers an instance with the given metadata and returns the id of the registration.
+func (m *RedissonPresenceRegistry) Register(ctx context.Context, metadata string) (string, error) {
+	idBytes := make([]byte, 16)
+	if _, err := rand.Read(idBytes); err != nil {
+		return "", err
+	}
+	id := hex.EncodeToString(idBytes)
+	cmdCtx, cancel := m.withCommandTimeout(ctx)
+	defer cancel()
+	if err := m.heartbeat(cmdCtx, id, metadata, true); err != nil {
+		return "", err
+	}
+	go m.keepAlive(ctx, id, metadata)
+	return id, nil
+}
+
+// heartbeat writes the key of the instance with the watchdog timeout as TTL, announcing the instance when it joins
+func (m *RedissonPresenceRegistry) heartbeat(ctx context.Context, id, metadata string, join bool) error {
+	announce := 0
+	if join {
+		announce = 1
+	}
+	_, err := m.eval(ctx, "presence.heartbeat", `
+redis.call('sadd', KEYS[1], ARGV[1]);
+redis.call('set', KEYS[2], ARGV[2], 'px', ARGV[3]);
+if ARGV[4] == '1' then
+    redis.call('publish', KEYS[3], ARGV[5] .. ARGV[1] .. ':' .. ARGV[2]);
+end ;
+return 1;
+`, []string{m.getRawName(), m.getInstancePrefix() + id, m.getChannelName()},
+		id, metadata, m.watchDogTimeout.Milliseconds(), announce, presenceJoinMessage).Result()
+	if err != nil {
+		return err
+	}
+	// the set is recreated by the next heartbeat of every instance once it expired
+	return m.applyTTL(ctx, m.getRawName())
+}
+
+// keepAlive renews the key of the instance like the lock watchdog renews a held lock, then deregisters it once ctx is done
+func (m *RedissonPresenceRegistry) keepAlive(ctx context.Context, id, metadata string) {
+	for {
+		select {
+		case <-ctx.Done():
+			m.deregister(context.WithoutCancel(ctx), id, metadata)
+			return
+		case <-m.clock.After(m.watchDogTimeout / 3):
+		}
+		hbCtx, cancel := m.withCommandTimeout(ctx)
+		err := m.heartbeat(hbCtx, id, metadata, false)
+		cancel()
+		if err != nil && ctx.Err() == nil {
+			log.Printf("presence %s: heartbeat of %s failed: %v", m.getRawName(), id, err)
+		}
+	}
+}
+
+// deregister removes the instance and announces that it left
+func (m *RedissonPresenceRegistry) deregister(ctx context.Context, id, metadata string) {
+	ctx, cancel := m.withCommandTimeout(ctx)
+	defer cancel()
+	_, err := m.eval(ctx, "presence.deregister", `
+redis.call('srem', KEYS[1], ARGV[1]);
+if redis.call('del', KEYS[2]) == 1 then
+    redis.call('publish', KEYS[3], ARGV[3] .. ARGV[1] .. ':' .. ARGV[2]);
+end ;
+return 1;
+`, []string{m.getRawName(), m.getInstancePrefix() + id, m.getChannelName()}, id, metadata, presenceLeaveMessage).Result()
+	if err != nil {
+		log.Printf("presence %s: deregistering %s failed: %v", m.getRawName(), id, err)
+	}
+}
+
+// List returns the live instances.
+func (m *RedissonPresenceRegistry) List() ([]PresenceInstance, error) {
+	ctx, cancel := m.newContext()
+	defer cancel()
+	ids, err := m.client.SMembers(ctx, m.getRawName()).Result()
+	if err != nil || len(ids) == 0 {
+		return nil, err
+	}
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = m.getInstancePrefix() + id
+	}
+	values, err := m.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, err
+	}
+	instances := make([]PresenceInstance, 0, len(ids))
+	var expired []interface{}
+	for i, v := range values {
+		metadata, ok := v.(string)
+		if !ok {
+			expired = append(expired, ids[i])
+			continue
+		}
+		instances = append(instances, PresenceInstance{ID: ids[i], Metadata: metadata})
+	}
+	// the heartbeats of these instances expired, they no longer need to be listed
+	if len(expired) > 0 {
+		if err = m.client.SRem(ctx, m.getRawName(), expired...).Err(); err != nil {
+			return nil, err
+		}
+	}
+	return instances, nil
+}
+
+// Subscribe sends on the returned channel the instances joining and leaving until ctx is done.
+func (m *RedissonPresenceRegistry) Subscribe(ctx context.Context) (<-chan PresenceEvent, error) {
+	expiredChannel := "__keyevent@" + strconv.Itoa(m.client.Options().DB) + "__:expired"
+	sub := m.client.Subscribe(ctx, m.getChannelName(), expiredChannel)
+	// wait for the subscriptions to be confirmed so no event published afterwards is missed
+	for i := 0; i < 2; i++ {
+		if _, err := sub.Receive(ctx); err != nil {
+			sub.Close()
+			return nil, err
+		}
+	}
+	events := make(chan PresenceEvent, 16)
+	go func() {
+		defer close(events)
+		defer sub.Close()
+		ch := sub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-ch:
+				if !ok {
+					return
+				}
+				event, ok := m.parseEvent(msg.Channel, msg.Payload)
+				if !ok {
+					continue
+				}
+				select {
+				case events <- event:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return events, nil
+}
+
+// parseEvent returns the event carried by a message of the registry channel or an expired keyevent notification
+func (m *RedissonPresenceRegistry) parseEvent(channel, payload string) (PresenceEvent, bool) {
+	if channel != m.getChannelName() {
+		id, ok := strings.CutPrefix(payload, m.getInstancePrefix())
+		return PresenceEvent{Type: PresenceLeave, ID: id}, ok
+	}
+	if len(payload) == 0 {
+		return PresenceEvent{}, false
+	}
+	eventType := PresenceJoin
+	if payload[:1] == presenceLeaveMessage {
+		eventType = PresenceLeave
+	}
+	id, metadata, ok := strings.Cut(payload[1:], ":")
+	return PresenceEvent{Type: eventType, ID: id, Metadata: metadata}, ok
+}
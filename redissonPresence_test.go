@@ -0,0 +1,59 @@
+package redisson
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestPresenceRegistry(t *testing.T) {
+	g := GetRedisson()
+	registry := g.GetPresenceRegistry("testPresenceRegistry")
+
+	subCtx, subCancel := context.WithCancel(context.Background())
+	defer subCancel()
+	events, err := registry.Subscribe(subCtx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	id, err := registry.Register(ctx, "10.0.0.1:8080")
+	if err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case e := <-events:
+		if e.Type != PresenceJoin || e.ID != id || e.Metadata != "10.0.0.1:8080" {
+			t.Fatalf("event=%+v", e)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no join event")
+	}
+
+	instances, err := registry.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(instances) != 1 || instances[0].ID != id || instances[0].Metadata != "10.0.0.1:8080" {
+		t.Fatalf("instances=%+v", instances)
+	}
+
+	// cancelling the registration deregisters the instance
+	cancel()
+	select {
+	case e := <-events:
+		if e.Type != PresenceLeave || e.ID != id {
+			t.Fatalf("event=%+v", e)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no leave event")
+	}
+	instances, err = registry.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(instances) != 0 {
+		t.Fatalf("instances=%+v", instances)
+	}
+}
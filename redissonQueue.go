@@ -0,0 +1,61 @@
+package redisson
+
+// RQueue is a FIFO queue of values of type T stored in a redis list, like the RQueue of Java Redisson.
+// It is stored as RBlockingQueue, so the consumers of a queue may wait for its values with the RBlockingQueue
+// of the same name.
+type RQueue[T any] interface {
+	RExpirable
+
+	// Offer appends value to the tail of the queue.
+	Offer(value T) error
+
+	// OfferAll appends values to the tail of the queue in order.
+	OfferAll(values ...T) error
+
+	// Poll removes and returns the value at the head of the queue, and whether the queue had one.
+	Poll() (T, bool, error)
+
+	// Peek returns the value at the head of the queue without removing it.
+	Peek() (T, bool, error)
+
+	// Size returns the number of values in the queue.
+	Size() (int64, error)
+
+	// ReadAll returns all the values of the queue from head to tail.
+	ReadAll() ([]T, error)
+
+	// Delete deletes the queue and reports whether it existed.
+	Delete() (bool, error)
+
+	getRawName() string
+	getCodec() Codec
+}
+
+var (
+	_ RQueue[string] = (*RedissonQueue[string])(nil)
+)
+
+// RedissonQueue is the implementation of RQueue
+// it shares the implementation of RedissonBlockingQueue but never waits
+type RedissonQueue[T any] struct {
+	*RedissonBlockingQueue[T]
+}
+
+// newRedissonQueue creates a new RedissonQueue
+func newRedissonQueue[T any](name string, redisson *Redisson, options *objectOptions) *RedissonQueue[T] {
+	return &RedissonQueue[T]{
+		RedissonBlockingQueue: newRedissonBlockingQueue[T](name, redisson, options),
+	}
+}
+
+// Poll removes and returns the value at the head of the queue, and whether the queue had one.
+func (m *RedissonQueue[T]) Poll() (T, bool, error) {
+	ctx, cancel := m.newContext()
+	defer cancel()
+	return m.decodeReply(m.client.LPop(ctx, m.getRawName()).Result())
+}
+
+// getCodec returns the codec of the values
+func (m *RedissonQueue[T]) getCodec() Codec {
+	return m.codec
+}
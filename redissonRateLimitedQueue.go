@@ -0,0 +1,158 @@
+package redisson
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RRateLimitedQueue is a distributed task queue whose consumers, across all instances,
+// collectively take at most rate tasks per interval. Tasks are handed out in the order consumers
+// started waiting for them, so no consumer starves the others.
+type RRateLimitedQueue[T any] interface {
+	// Offer appends the task to the queue.
+	Offer(task T) error
+
+	// OfferWithDelay appends the task to the queue once delay elapsed.
+	OfferWithDelay(task T, delay time.Duration) error
+
+	// Take removes and returns the task at the head of the queue, waiting until a task is available
+	// and the rate allows it, or until ctx is done.
+	Take(ctx context.Context) (T, error)
+
+	// Size returns the number of tasks ready to be taken.
+	Size() (int64, error)
+}
+
+var (
+	_ RRateLimitedQueue[string] = (*RedissonRateLimitedQueue[string])(nil)
+)
+
+// delayedTaskIdLen is the length of the random id prefixing delayed tasks, which keeps equal tasks distinct in the zset
+const delayedTaskIdLen = 32
+
+// RedissonRateLimitedQueue is the implementation of RRateLimitedQueue
+// ready tasks are stored in the name list, delayed tasks in the {name}:delayed zset scored by the time they are due,
+// and the rate is enforced by the {name}:limiter rate limiter
+type RedissonRateLimitedQueue[T any] struct {
+	*RedissonExpirable
+	codec   Codec
+	limiter RRateLimiter
+}
+
+// newRedissonRateLimitedQueue creates a new RedissonRateLimitedQueue
+func newRedissonRateLimitedQueue[T any](name string, redisson *Redisson, rate int64, interval time.Duration, options *objectOptions) *RedissonRateLimitedQueue[T] {
+	m := &RedissonRateLimitedQueue[T]{
+		RedissonExpirable: newRedissonExpirable(name, redisson),
+		codec:             options.codec,
+	}
+	m.limiter = newRedissonRateLimiter(m.suffixName(name, "limiter"), redisson, WithRate(rate, interval))
+	return m
+}
+
+// getDelayedName returns the name of the zset of the delayed tasks
+func (m *RedissonRateLimitedQueue[T]) getDelayedName() string {
+	return m.suffixName(m.getRawName(), "delayed")
+}
+
+// Offer appends the task to the queue.
+func (m *RedissonRateLimitedQueue[T]) Offer(task T) error {
+	ctx, cancel := m.newContext()
+	defer cancel()
+	data, err := m.codec.Encode(task)
+	if err != nil {
+		return err
+	}
+	return m.client.RPush(ctx, m.getRawName(), data).Err()
+}
+
+// OfferWithDelay appends the task to the queue once delay elapsed.
+func (m *RedissonRateLimitedQueue[T]) OfferWithDelay(task T, delay time.Duration) error {
+	if delay <= 0 {
+		return m.Offer(task)
+	}
+	ctx, cancel := m.newContext()
+	defer cancel()
+	data, err := m.codec.Encode(task)
+	if err != nil {
+		return err
+	}
+	id := make([]byte, delayedTaskIdLen/2)
+	if _, err = rand.Read(id); err != nil {
+		return err
+	}
+	return m.client.ZAdd(ctx, m.getDelayedName(), redis.Z{
+		Score:  float64(m.clock.Now().Add(delay).UnixMilli()),
+		Member: hex.EncodeToString(id) + string(data),
+	}).Err()
+}
+
+// Take removes and returns the task at the head of the queue, waiting until a task is available
+// and the rate allows it, or until ctx is done.
+// The permit is acquired before the task is popped, so a consumer waiting for the rate holds no task which a crash
+// would lose or which could be given back out of order. A permit left unused because ctx is done before a task
+// is available only lowers the rate.
+func (m *RedissonRateLimitedQueue[T]) Take(ctx context.Context) (T, error) {
+	var zero T
+	if err := m.limiter.AcquireContext(ctx); err != nil {
+		return zero, err
+	}
+	for {
+		wait, err := m.moveDueTasks(ctx)
+		if err != nil {
+			return zero, err
+		}
+		// blocked consumers are served by redis in the order they started waiting
+		res, err := m.client.BLPop(ctx, wait, m.getRawName()).Result()
+		if err == redis.Nil {
+			continue
+		}
+		if err != nil {
+			return zero, err
+		}
+		var task T
+		err = m.codec.Decode([]byte(res[1]), &task)
+		return task, err
+	}
+}
+
+// moveDueTasks appends the delayed tasks which are due to the queue,
+// and returns how long to wait for a task before checking the delayed tasks again
+func (m *RedissonRateLimitedQueue[T]) moveDueTasks(ctx context.Context) (time.Duration, error) {
+	next, err := m.eval(ctx, "rateLimitedQueue.moveDueTasks", `
+local due = redis.call('zrangebyscore', KEYS[2], '-inf', ARGV[1], 'limit', 0, 100);
+for i = 1, #due, 1 do
+    redis.call('rpush', KEYS[1], string.sub(due[i], tonumber(ARGV[2]) + 1));
+end ;
+if #due > 0 then
+    redis.call('zrem', KEYS[2], unpack(due));
+end ;
+local first = redis.call('zrange', KEYS[2], 0, 0, 'WITHSCORES');
+if #first == 0 then
+    return -1;
+end ;
+return tonumber(first[2]) - tonumber(ARGV[1]);
+`, []string{m.getRawName(), m.getDelayedName()}, m.clock.Now().UnixMilli(), delayedTaskIdLen).Int64()
+	if err != nil {
+		return 0, err
+	}
+	wait := time.Second
+	if next >= 0 && time.Duration(next)*time.Millisecond < wait {
+		wait = time.Duration(next) * time.Millisecond
+	}
+	// BLPOP cannot wait less than a millisecond, 0 would block forever
+	if wait < time.Millisecond {
+		wait = time.Millisecond
+	}
+	return wait, nil
+}
+
+// Size returns the number of tasks ready to be taken.
+func (m *RedissonRateLimitedQueue[T]) Size() (int64, error) {
+	ctx, cancel := m.newContext()
+	defer cancel()
+	return m.client.LLen(ctx, m.getRawName()).Result()
+}
@@ -0,0 +1,66 @@
+package redisson
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRateLimitedQueue(t *testing.T) {
+	g := GetRedisson()
+	if err := g.client.Del(context.Background(), "testRateLimitedQueue", "{testRateLimitedQueue}:delayed",
+		"{testRateLimitedQueue}:limiter", "{testRateLimitedQueue}:limiter:value", "{testRateLimitedQueue}:limiter:permits").Err(); err != nil {
+		t.Fatal(err)
+	}
+	q := GetRateLimitedQueue[int](g, "testRateLimitedQueue", 2, time.Second)
+	for i := 0; i < 3; i++ {
+		if err := q.Offer(i); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := q.OfferWithDelay(3, 100*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		task, err := q.Take(ctx)
+		cancel()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if task != i {
+			t.Fatalf("task=%v, want %v", task, i)
+		}
+	}
+	// 4 tasks at 2 per second need at least a second
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Fatalf("4 tasks taken in %v", elapsed)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if _, err := q.Take(ctx); err == nil {
+		t.Fatal("Take on an empty queue should time out")
+	}
+
+	// a consumer waiting for the rate holds no task
+	if err := q.Offer(4); err != nil {
+		t.Fatal(err)
+	}
+	for {
+		ctx, cancel = context.WithTimeout(context.Background(), 50*time.Millisecond)
+		_, err := q.Take(ctx)
+		cancel()
+		if err != nil {
+			break
+		}
+		if err = q.Offer(4); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n, err := q.Size(); err != nil || n != 1 {
+		t.Fatalf("n=%v err=%v", n, err)
+	}
+}
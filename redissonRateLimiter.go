@@ -8,6 +8,8 @@ import (
 	"fmt"
 	"github.com/redis/go-redis/v9"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 )
 
@@ -68,49 +70,180 @@ type RateLimiterConfig struct {
 	RateType     RateType
 	RateInterval int64 // 毫秒
 	Rate         int64 // 速率(令牌桶容量)
+	// Tiers SetRateTiers 设置的第一个层级之外的其他层级，第一个层级即 Rate 和 RateInterval
+	Tiers []RateTier
+}
+
+// RateTier 多层级限流的一个层级：每 Interval 最多 Rate 个许可
+type RateTier struct {
+	Rate     int64
+	Interval time.Duration
+}
+
+// RateLimiterInit 限流器首次使用时如何写入 RateLimiterOptions 中的速率
+type RateLimiterInit int
+
+const (
+	// RateLimiterInitIfAbsent 没有配置时写入，已有配置时不做修改，同 TrySetRate
+	RateLimiterInitIfAbsent RateLimiterInit = iota
+	// RateLimiterInitOverwrite 覆盖已有的速率和时间间隔，同 UpdateRate，已发放的许可继续计入
+	RateLimiterInitOverwrite
+	// RateLimiterInitNone 不写入，由调用方调用 SetRate 等方法配置
+	RateLimiterInitNone
+)
+
+// RateLimiterOptions GetRateLimiterWithOptions 创建限流器的选项，零值字段使用默认值
+type RateLimiterOptions struct {
+	// Algorithm 限流算法，默认 RateLimiterTokenBucket
+	Algorithm RateLimiterAlgorithm
+	// RateType 限流模式，默认 RateTypeOVERALL
+	RateType RateType
+	// Rate Interval 每 Interval 最多 Rate 个许可，Rate 为 0 时不自动初始化
+	Rate     int64
+	Interval time.Duration
+	// Init 首次使用时如何写入 Rate，默认 RateLimiterInitIfAbsent
+	Init RateLimiterInit
+	// KeepAlive 限流器闲置多久后过期：配置写入时设置过期时间，每次使用时续期，过期后下次使用时按 Rate 重新初始化，
+	// 0 使用实例的 WithDefaultObjectTTL
+	KeepAlive time.Duration
+	// WarmUp 冷启动的预热时长，见 WithWarmUp
+	WarmUp time.Duration
+}
+
+// objectOptions 将 RateLimiterOptions 转换为对应的 ObjectOption
+func (o RateLimiterOptions) objectOptions() []ObjectOption {
+	opts := []ObjectOption{
+		WithRateLimiterAlgorithm(o.Algorithm),
+		WithRateType(o.RateType),
+		WithWarmUp(o.WarmUp),
+		withRateInit(o.Init),
+	}
+	if o.Rate > 0 && o.Init != RateLimiterInitNone {
+		opts = append(opts, WithRate(o.Rate, o.Interval))
+	}
+	if o.KeepAlive > 0 {
+		opts = append(opts, WithTTL(o.KeepAlive))
+	}
+	return opts
 }
 
 // RRateLimiter 接口
+// 每个方法都有接收 context 的 ...Context 版本，用于传递调用方的截止时间和取消信号；
+// 不带 context 的版本使用实例的默认 context。
 type RRateLimiter interface {
 	RExpirable
 
 	// TrySetRate 初始化限流器的配置，并将配置存储到 Redis 服务器。
 	// 如果设置成功，返回 true，否则返回 false。
 	TrySetRate(mode RateType, rate, rateInterval int64, unit RateIntervalUnit) (bool, error)
+	TrySetRateContext(ctx context.Context, mode RateType, rate, rateInterval int64, unit RateIntervalUnit) (bool, error)
 
 	// SetRate 更新限流器的配置，并将配置存储到 Redis 服务器。
+	// 之前 SetRateWithTTL 设置的过期时间被清除，配置不再过期（WithTTL 设置的过期时间仍然生效）。
 	SetRate(mode RateType, rate, rateInterval int64, unit RateIntervalUnit) error
+	SetRateContext(ctx context.Context, mode RateType, rate, rateInterval int64, unit RateIntervalUnit) error
+
+	// SetRateTiers 同 SetRate，但同时设置多个层级（如每秒 100 个且每分钟 2000 个），在一个脚本中同时检查，
+	// 任一层级的许可不足时获取失败，返回各层级中最长的等待时间。第一个层级即 GetConfig 返回的 Rate 和 RateInterval，
+	// 只有 RateLimiterSlidingWindow 算法支持，其他算法返回 ErrTiersUnsupported。
+	SetRateTiers(mode RateType, tiers ...RateTier) error
+	SetRateTiersContext(ctx context.Context, mode RateType, tiers ...RateTier) error
+
+	// UpdateRate 修改速率和时间间隔，与 SetRate 不同，已发放的许可继续计入，不会因修改配置而立即恢复全部许可。
+	// RateTypePER_CLIENT 模式下其他客户端的令牌余量在它们的许可过期后按新速率恢复。
+	UpdateRate(rate, rateInterval int64, unit RateIntervalUnit) error
+	UpdateRateContext(ctx context.Context, rate, rateInterval int64, unit RateIntervalUnit) error
+
+	// SetRateWithTTL 同 SetRate，并在同一个脚本中为配置、令牌余量和许可记录设置 keepAlive 的过期时间，
+	// 限流器在 keepAlive 内未被重新配置时自动清理。
+	SetRateWithTTL(mode RateType, rate, rateInterval int64, unit RateIntervalUnit, keepAlive time.Duration) error
+	SetRateWithTTLContext(ctx context.Context, mode RateType, rate, rateInterval int64, unit RateIntervalUnit, keepAlive time.Duration) error
+
+	// Delete 在一个脚本中删除限流器的配置、令牌余量、许可记录和预热键，如果存在任一键则返回 true。
+	Delete() (bool, error)
+	DeleteContext(ctx context.Context) (bool, error)
 
 	// TryAcquire 尝试获取一个许可，如果成功则返回 true，否则返回 false。
 	TryAcquire() (bool, error)
+	TryAcquireContext(ctx context.Context) (bool, error)
 
 	// TryAcquirePermits 尝试获取指定数量的许可，如果成功则返回 true，否则返回 false。
 	TryAcquirePermits(permits int64) (bool, error)
+	TryAcquirePermitsContext(ctx context.Context, permits int64) (bool, error)
+
+	// TryAcquireWithDelay 尝试获取指定数量的许可，失败时同时返回许可可用前需要等待的时间，
+	// 便于 HTTP 服务返回准确的 Retry-After 而无需再次查询。
+	TryAcquireWithDelay(permits int64) (ok bool, retryAfter time.Duration, err error)
+	TryAcquireWithDelayContext(ctx context.Context, permits int64) (ok bool, retryAfter time.Duration, err error)
+
+	// TryAcquirePermitsWithBurst 同 TryAcquirePermits，本次获取允许超出速率 burst 个许可，超出的许可照常计入，
+	// 之后的请求需要等待它们恢复。
+	TryAcquirePermitsWithBurst(permits, burst int64) (bool, error)
+	TryAcquirePermitsWithBurstContext(ctx context.Context, permits, burst int64) (bool, error)
 
 	// Acquire 获取一个许可，阻塞直到成功。
 	Acquire() error
+	// AcquireContext 获取一个许可，阻塞直到成功或 ctx 结束，ctx 结束时返回 ctx.Err()。
+	AcquireContext(ctx context.Context) error
 
 	// AcquirePermits 获取指定数量的许可，阻塞直到成功。
 	AcquirePermits(permits int64) error
+	// AcquirePermitsContext 获取指定数量的许可，阻塞直到成功或 ctx 结束，ctx 结束时返回 ctx.Err()。
+	AcquirePermitsContext(ctx context.Context, permits int64) error
+
+	// AcquireAsync 在后台获取指定数量的许可，返回的 channel 恰好收到一个结果，
+	// 便于与请求取消等其他事件一起 select。ctx 结束时收到 ctx.Err()。
+	// 等待期间不占用 goroutine，每次重试由定时器或配置变更通知触发。
+	AcquireAsync(ctx context.Context, permits int64) <-chan error
 
 	// TryAcquireWithTimeout 尝试在指定时间内获取一个许可，如果成功则返回 true，否则返回 false。
 	TryAcquireWithTimeout(timeout time.Duration) (bool, error)
+	TryAcquireWithTimeoutContext(ctx context.Context, timeout time.Duration) (bool, error)
 
 	// TryAcquirePermitsWithTimeout 尝试在指定时间内获取指定数量的许可，如果成功则返回 true，否则返回 false。
 	TryAcquirePermitsWithTimeout(permits int64, timeout time.Duration) (bool, error)
+	// TryAcquirePermitsWithTimeoutContext 同 TryAcquirePermitsWithTimeout，ctx 先结束时返回 false 和 ctx.Err()。
+	TryAcquirePermitsWithTimeoutContext(ctx context.Context, permits int64, timeout time.Duration) (bool, error)
+
+	// Reserve 预约指定数量的许可并返回使用前需要等待的时间，许可立即可用时返回 0。
+	// 预约的许可即使调用方放弃也会被计入，RateLimiterCell 算法不支持预约，返回 ErrReserveUnsupported。
+	Reserve(permits int64) (time.Duration, error)
+	ReserveContext(ctx context.Context, permits int64) (time.Duration, error)
+
+	// Stats 返回本实例上同名限流器获取、拒绝的许可数和累计等待时间。
+	Stats() RateLimiterStats
 
 	// GetConfig 返回当前限流器的配置。
 	GetConfig() (*RateLimiterConfig, error)
+	GetConfigContext(ctx context.Context) (*RateLimiterConfig, error)
 
 	// AvailablePermits 返回当前可用的许可数量。
 	AvailablePermits() (int64, error)
+	AvailablePermitsContext(ctx context.Context) (int64, error)
 }
 
+var (
+	// ErrReserveUnsupported 限流算法不支持预约许可
+	ErrReserveUnsupported = errors.New("rate limiter algorithm does not support reservations")
+	// ErrTiersUnsupported 限流算法不支持多层级限流
+	ErrTiersUnsupported = errors.New("rate limiter algorithm does not support rate tiers")
+)
+
 // =============== 具体的限流器实现 ===============
 
 type RedissonRateLimiter struct {
 	*RedissonExpirable
 	name string
+	// WithRate 设置的初始速率，首次使用时以 TrySetRate 的语义写入，rate 为 0 表示不自动初始化
+	initRate         int64
+	initRateInterval time.Duration
+	initRateType     RateType
+	initMode         RateLimiterInit
+	rateInitialized  bool
+	// algorithm 限流算法，见 WithRateLimiterAlgorithm
+	algorithm RateLimiterAlgorithm
+	// warmUp 冷启动的预热时长，见 WithWarmUp
+	warmUp time.Duration
 }
 
 // getPermitsName 返回全局许可键名。
@@ -135,11 +268,33 @@ func (rl *RedissonRateLimiter) getClientValueName() string {
 }
 
 // 构造函数
-func newRedissonRateLimiter(name string, redisson *Redisson) RRateLimiter {
-	return &RedissonRateLimiter{
+func newRedissonRateLimiter(name string, redisson *Redisson, opts ...ObjectOption) RRateLimiter {
+	rl := &RedissonRateLimiter{
 		RedissonExpirable: newRedissonExpirable(name, redisson),
 		name:              name,
 	}
+	// 配置的 TTL 设置在 config hash 上并在每次使用时续期，tryAcquireScript 会让 value 与 permits 跟随它过期
+	options := redisson.newObjectOptions(opts)
+	rl.ttl = options.ttl
+	rl.initRate = options.rate
+	rl.initRateInterval = options.rateInterval
+	rl.initRateType = options.rateType
+	rl.initMode = options.rateInit
+	rl.algorithm = options.rateAlgorithm
+	rl.warmUp = options.rateWarmUp
+	// Expire、ExpireAt 和 ClearExpire 在一个脚本中作用于所有组成键，固定窗口的计数 hash 在 value 键中
+	rl.componentKeys = func() []string {
+		return []string{
+			rl.configHashKey(),
+			rl.valueKey(),
+			rl.clientValueKey(),
+			rl.permitsKey(),
+			rl.clientPermitsKey(),
+			rl.warmUpKey(),
+			rl.clientWarmUpKey(),
+		}
+	}
+	return rl
 }
 
 // 一些在 Lua 中会用到的 key 约定
@@ -168,12 +323,84 @@ func (rl *RedissonRateLimiter) clientPermitsKey() string {
 	return rl.suffixName(rl.permitsKey(), rl.id)
 }
 
+// 预热开始时间，见 WithWarmUp
+func (rl *RedissonRateLimiter) warmUpKey() string {
+	return rl.suffixName(rl.getRawName(), "warmup")
+}
+
+func (rl *RedissonRateLimiter) clientWarmUpKey() string {
+	return rl.suffixName(rl.warmUpKey(), rl.id)
+}
+
+// getChannelName 返回配置变更通知的频道名，SetRate 修改配置后在此频道发布消息，唤醒等待中的 Acquire
+func (rl *RedissonRateLimiter) getChannelName() string {
+	return rl.prefixName("redisson_rate_limiter__channel", rl.getRawName())
+}
+
 // =============== 接口方法实现 ===============
 
+// rateLimiterNotInitialized 配置不存在时脚本的错误信息
+const rateLimiterNotInitialized = "RateLimiter is not initialized"
+
+// ensureRate 在首次使用时写入 WithRate 设置的速率，已有配置时不做修改
+func (rl *RedissonRateLimiter) ensureRate(ctx context.Context) error {
+	if rl.initRate <= 0 {
+		return nil
+	}
+	rl.mutex.Lock()
+	defer rl.mutex.Unlock()
+	if rl.rateInitialized {
+		return nil
+	}
+	res, err := rl.trySetRateLua(ctx, rl.initRateType, rl.initRate, rl.initRateInterval.Milliseconds(), Milliseconds)
+	if err != nil {
+		return err
+	}
+	// 已有配置时按 RateLimiterInitOverwrite 覆盖速率
+	if rl.initMode == RateLimiterInitOverwrite && res != nil && *res == 0 {
+		if err = rl.updateRateLua(ctx, rl.initRate, rl.initRateInterval.Milliseconds()); err != nil {
+			return err
+		}
+	}
+	rl.rateInitialized = true
+	return nil
+}
+
+// reinitRate 配置因过期或被删除而不存在时按 WithRate 重新写入速率，没有 WithRate 时返回 false
+func (rl *RedissonRateLimiter) reinitRate(ctx context.Context) (bool, error) {
+	if rl.initRate <= 0 {
+		return false, nil
+	}
+	rl.mutex.Lock()
+	rl.rateInitialized = false
+	rl.mutex.Unlock()
+	return true, rl.ensureRate(ctx)
+}
+
+// evalInitialized 执行读取配置的脚本，配置不存在时按 WithRate 重新初始化并重试一次
+func (rl *RedissonRateLimiter) evalInitialized(ctx context.Context, name, script string, keys []string, args ...interface{}) *redis.Cmd {
+	cmd := rl.eval(ctx, name, script, keys, args...)
+	if err := cmd.Err(); err == nil || !strings.Contains(err.Error(), rateLimiterNotInitialized) {
+		return cmd
+	}
+	if ok, err := rl.reinitRate(ctx); err != nil {
+		cmd = redis.NewCmd(ctx)
+		cmd.SetErr(err)
+		return cmd
+	} else if !ok {
+		return cmd
+	}
+	return rl.eval(ctx, name, script, keys, args...)
+}
+
 // TrySetRate
 func (rl *RedissonRateLimiter) TrySetRate(mode RateType, rate, rateInterval int64, unit RateIntervalUnit) (bool, error) {
+	return rl.TrySetRateContext(rl.baseContext(), mode, rate, rateInterval, unit)
+}
 
-	res, err := rl.trySetRateLua(mode, rate, rateInterval, unit)
+// TrySetRateContext
+func (rl *RedissonRateLimiter) TrySetRateContext(ctx context.Context, mode RateType, rate, rateInterval int64, unit RateIntervalUnit) (bool, error) {
+	res, err := rl.trySetRateLua(ctx, mode, rate, rateInterval, unit)
 	if err != nil {
 		return false, err
 	}
@@ -182,15 +409,16 @@ func (rl *RedissonRateLimiter) TrySetRate(mode RateType, rate, rateInterval int6
 
 }
 
-func (rl *RedissonRateLimiter) trySetRateLua(mode RateType, rate, rateInterval int64, unit RateIntervalUnit) (*int64, error) {
-	ctx := context.Background()
+func (rl *RedissonRateLimiter) trySetRateLua(ctx context.Context, mode RateType, rate, rateInterval int64, unit RateIntervalUnit) (*int64, error) {
+	ctx, cancel := rl.withCommandTimeout(ctx)
+	defer cancel()
 	keys := []string{rl.configHashKey()}
 	args := []interface{}{
 		rate,
 		unit.ToMillis(rateInterval),
 		mode, // 0 或 1
 	}
-	res, err := rl.client.Eval(ctx, trySetRateScript, keys, args...).Int64()
+	res, err := rl.eval(ctx, "rateLimiter.trySetRate", trySetRateScript, keys, args...).Int64()
 
 	if err != nil {
 		if err == redis.Nil {
@@ -198,36 +426,114 @@ func (rl *RedissonRateLimiter) trySetRateLua(mode RateType, rate, rateInterval i
 		}
 		return nil, err
 	}
+	if err = rl.applyTTL(ctx, rl.configHashKey()); err != nil {
+		return nil, err
+	}
 	return &res, err
 }
 
 // SetRate
 func (rl *RedissonRateLimiter) SetRate(mode RateType, rate, rateInterval int64, unit RateIntervalUnit) error {
-	_, err := rl.setRateLua(mode, rate, rateInterval, unit)
+	return rl.SetRateContext(rl.baseContext(), mode, rate, rateInterval, unit)
+}
 
-	return err
+// SetRateContext
+func (rl *RedissonRateLimiter) SetRateContext(ctx context.Context, mode RateType, rate, rateInterval int64, unit RateIntervalUnit) error {
+	return rl.setRateLua(ctx, mode, rate, rateInterval, unit, 0, nil)
+}
+
+// SetRateWithTTL
+func (rl *RedissonRateLimiter) SetRateWithTTL(mode RateType, rate, rateInterval int64, unit RateIntervalUnit, keepAlive time.Duration) error {
+	return rl.SetRateWithTTLContext(rl.baseContext(), mode, rate, rateInterval, unit, keepAlive)
+}
+
+// SetRateWithTTLContext
+func (rl *RedissonRateLimiter) SetRateWithTTLContext(ctx context.Context, mode RateType, rate, rateInterval int64, unit RateIntervalUnit, keepAlive time.Duration) error {
+	if keepAlive <= 0 {
+		return errors.New("keepAlive must be positive")
+	}
+	return rl.setRateLua(ctx, mode, rate, rateInterval, unit, keepAlive, nil)
+}
+
+// SetRateTiers
+func (rl *RedissonRateLimiter) SetRateTiers(mode RateType, tiers ...RateTier) error {
+	return rl.SetRateTiersContext(rl.baseContext(), mode, tiers...)
+}
+
+// SetRateTiersContext
+func (rl *RedissonRateLimiter) SetRateTiersContext(ctx context.Context, mode RateType, tiers ...RateTier) error {
+	if rl.algorithm != RateLimiterSlidingWindow {
+		return ErrTiersUnsupported
+	}
+	if len(tiers) == 0 {
+		return errors.New("at least one rate tier is required")
+	}
+	for _, tier := range tiers {
+		if tier.Rate <= 0 || tier.Interval.Milliseconds() <= 0 {
+			return fmt.Errorf("invalid rate tier %d per %v", tier.Rate, tier.Interval)
+		}
+	}
+	return rl.setRateLua(ctx, mode, tiers[0].Rate, tiers[0].Interval.Milliseconds(), Milliseconds, 0, tiers[1:])
 }
 
-func (rl *RedissonRateLimiter) setRateLua(mode RateType, rate, rateInterval int64, unit RateIntervalUnit) (*int64, error) {
-	ctx := context.Background()
+// setRateLua 写入配置并清空令牌余量和许可记录，keepAlive 大于 0 时为它们设置过期时间，否则清除配置的过期时间，
+// tiers 为第一个层级之外的其他层级，为空时删除原有的其他层级
+func (rl *RedissonRateLimiter) setRateLua(ctx context.Context, mode RateType, rate, rateInterval int64, unit RateIntervalUnit, keepAlive time.Duration, tiers []RateTier) error {
+	ctx, cancel := rl.withCommandTimeout(ctx)
+	defer cancel()
 	keys := []string{
 		rl.configHashKey(),
 		rl.valueKey(),
 		rl.permitsKey(),
+		rl.getChannelName(),
 	}
 	args := []interface{}{
 		rate,
 		unit.ToMillis(rateInterval),
 		mode,
+		keepAlive.Milliseconds(),
+		formatRateTiers(tiers),
 	}
-	res, err := rl.client.Eval(ctx, setRateScript, keys, args...).Int64()
-	if err != nil {
-		if err == redis.Nil {
-			return nil, nil
-		}
-		return nil, err
+	err := rl.eval(ctx, "rateLimiter.setRate", setRateScript, keys, args...).Err()
+	if err != nil && err != redis.Nil {
+		return err
 	}
-	return &res, err
+	return rl.applyTTL(ctx, rl.configHashKey())
+}
+
+// UpdateRate
+func (rl *RedissonRateLimiter) UpdateRate(rate, rateInterval int64, unit RateIntervalUnit) error {
+	return rl.UpdateRateContext(rl.baseContext(), rate, rateInterval, unit)
+}
+
+// UpdateRateContext 令牌桶的余量按速率的变化调整，其他算法记录的是已用许可，无需调整
+func (rl *RedissonRateLimiter) UpdateRateContext(ctx context.Context, rate, rateInterval int64, unit RateIntervalUnit) error {
+	if err := rl.ensureRate(ctx); err != nil {
+		return err
+	}
+	return rl.updateRateLua(ctx, rate, unit.ToMillis(rateInterval))
+}
+
+// updateRateLua 修改速率和时间间隔（毫秒）
+func (rl *RedissonRateLimiter) updateRateLua(ctx context.Context, rate, rateInterval int64) error {
+	ctx, cancel := rl.withCommandTimeout(ctx)
+	defer cancel()
+	keys := []string{
+		rl.configHashKey(),
+		rl.valueKey(),
+		rl.clientValueKey(),
+		rl.getChannelName(),
+	}
+	args := []interface{}{
+		rate,
+		rateInterval,
+		rl.algorithm == RateLimiterTokenBucket,
+	}
+	err := rl.eval(ctx, "rateLimiter.updateRate", updateRateScript, keys, args...).Err()
+	if err != nil && err != redis.Nil {
+		return err
+	}
+	return nil
 }
 
 // TryAcquire
@@ -236,6 +542,11 @@ func (rl *RedissonRateLimiter) TryAcquire() (bool, error) {
 	return rl.TryAcquirePermits(1)
 }
 
+// TryAcquireContext
+func (rl *RedissonRateLimiter) TryAcquireContext(ctx context.Context) (bool, error) {
+	return rl.TryAcquirePermitsContext(ctx, 1)
+}
+
 //	func (rl *RedissonRateLimiter) TryAcquirePermits(permits int64) (bool, error) {
 //		waitTime, err := rl.tryAcquireLua(permits)
 //		if err != nil {
@@ -249,12 +560,18 @@ func (rl *RedissonRateLimiter) TryAcquire() (bool, error) {
 //		}
 //	}
 func (rl *RedissonRateLimiter) TryAcquirePermits(permits int64) (bool, error) {
+	return rl.TryAcquirePermitsContext(rl.baseContext(), permits)
+}
+
+// TryAcquirePermitsContext
+func (rl *RedissonRateLimiter) TryAcquirePermitsContext(ctx context.Context, permits int64) (bool, error) {
 	fmt.Printf("Attempting to acquire %d permits...\n", permits)
-	waitTime, err := rl.tryAcquireLua(permits)
+	waitTime, err := rl.tryAcquireLua(ctx, permits)
 	if err != nil {
 		fmt.Printf("Error in TryAcquirePermits: %v\n", err)
 		return false, err
 	}
+	rl.recordAttempt(permits, waitTime, false)
 
 	if waitTime == nil {
 		fmt.Println("Permits acquired successfully.")
@@ -265,89 +582,347 @@ func (rl *RedissonRateLimiter) TryAcquirePermits(permits int64) (bool, error) {
 	}
 }
 
+// TryAcquireWithDelay
+func (rl *RedissonRateLimiter) TryAcquireWithDelay(permits int64) (bool, time.Duration, error) {
+	return rl.TryAcquireWithDelayContext(rl.baseContext(), permits)
+}
+
+// TryAcquireWithDelayContext
+func (rl *RedissonRateLimiter) TryAcquireWithDelayContext(ctx context.Context, permits int64) (bool, time.Duration, error) {
+	waitTime, err := rl.tryAcquireLua(ctx, permits)
+	if err != nil {
+		return false, 0, err
+	}
+	rl.recordAttempt(permits, waitTime, false)
+	if waitTime == nil {
+		return true, 0, nil
+	}
+	return false, time.Duration(*waitTime) * time.Millisecond, nil
+}
+
+// TryAcquirePermitsWithBurst
+func (rl *RedissonRateLimiter) TryAcquirePermitsWithBurst(permits, burst int64) (bool, error) {
+	return rl.TryAcquirePermitsWithBurstContext(rl.baseContext(), permits, burst)
+}
+
+// TryAcquirePermitsWithBurstContext
+func (rl *RedissonRateLimiter) TryAcquirePermitsWithBurstContext(ctx context.Context, permits, burst int64) (bool, error) {
+	waitTime, err := rl.acquireLua(ctx, permits, burst, false)
+	if err != nil {
+		return false, err
+	}
+	rl.recordAttempt(permits, waitTime, false)
+	return waitTime == nil, nil
+}
+
 // Acquire
 // 简化实现：循环调用 TryAcquire，如果不成功就阻塞等待
 func (rl *RedissonRateLimiter) Acquire() error {
 	return rl.AcquirePermits(1)
 }
 
+// AcquireContext
+func (rl *RedissonRateLimiter) AcquireContext(ctx context.Context) error {
+	return rl.AcquirePermitsContext(ctx, 1)
+}
+
 // AcquirePermits
 func (rl *RedissonRateLimiter) AcquirePermits(permits int64) error {
-	_, err := rl.TryAcquirePermitsWithTimeout(permits, -1)
+	return rl.AcquirePermitsContext(rl.baseContext(), permits)
+}
+
+// AcquirePermitsContext
+func (rl *RedissonRateLimiter) AcquirePermitsContext(ctx context.Context, permits int64) error {
+	_, err := rl.TryAcquirePermitsWithTimeoutContext(ctx, permits, -1)
 	return err
 }
 
+// AcquireAsync 不占用等待的 goroutine：每次尝试由时钟定时器、配置变更通知或 ctx 结束触发
+func (rl *RedissonRateLimiter) AcquireAsync(ctx context.Context, permits int64) <-chan error {
+	a := &asyncAcquire{
+		rl:      rl,
+		ctx:     ctx,
+		permits: permits,
+		result:  make(chan error, 1),
+		start:   rl.clock.Now(),
+	}
+	a.stopCtx = context.AfterFunc(ctx, a.cancel)
+	go a.attempt()
+	return a.result
+}
+
+// asyncAcquire 是一次进行中的 AcquireAsync，同一时刻最多只有一次尝试在执行
+type asyncAcquire struct {
+	sync.Mutex
+	rl      *RedissonRateLimiter
+	ctx     context.Context
+	permits int64
+	result  chan error
+	start   time.Time
+	// sub 第一次需要等待时订阅的配置变更通知
+	sub       *subscription
+	stopTimer func() bool
+	stopCtx   func() bool
+	// attempting 有尝试在执行，again 为执行期间又被触发，结束后立即再试一次
+	attempting bool
+	again      bool
+	done       bool
+	// throttled 已有一次尝试许可不足，整个调用只计入一次统计
+	throttled bool
+}
+
+// attempt 执行一次获取，需要等待时注册下一次尝试的定时器
+func (a *asyncAcquire) attempt() {
+	a.Lock()
+	if a.done {
+		a.Unlock()
+		return
+	}
+	if a.attempting {
+		a.again = true
+		a.Unlock()
+		return
+	}
+	a.attempting = true
+	if a.stopTimer != nil {
+		a.stopTimer()
+		a.stopTimer = nil
+	}
+	a.Unlock()
+
+	timeWait, err := a.rl.tryAcquireLua(a.ctx, a.permits)
+	if err == nil && timeWait != nil && !a.throttled {
+		a.throttled = true
+		a.rl.notifyThrottled(a.permits, time.Duration(*timeWait)*time.Millisecond)
+	}
+	if err == nil && timeWait != nil {
+		err = a.ctx.Err()
+		if err == nil && a.sub == nil {
+			a.sub, err = a.rl.subscriptions.subscribeFunc(a.ctx, a.rl.getChannelName(), a.wake)
+		}
+	}
+
+	a.Lock()
+	defer a.Unlock()
+	a.attempting = false
+	if err != nil || timeWait == nil {
+		a.finish(err)
+		return
+	}
+	if a.again {
+		a.again = false
+		go a.attempt()
+		return
+	}
+	a.stopTimer = clockAfterFunc(a.rl.clock, time.Duration(*timeWait)*time.Millisecond, a.attempt)
+}
+
+// wake 在配置变更时按新配置立即重试，由订阅的分发调用，不能阻塞
+func (a *asyncAcquire) wake() {
+	go a.attempt()
+}
+
+// cancel 在 ctx 结束时返回 ctx.Err()，有尝试在执行时由该尝试返回
+func (a *asyncAcquire) cancel() {
+	a.Lock()
+	defer a.Unlock()
+	if a.done || a.attempting {
+		return
+	}
+	a.finish(a.ctx.Err())
+}
+
+// finish 发送结果并释放定时器、ctx 回调和订阅，需持有锁
+func (a *asyncAcquire) finish(err error) {
+	a.done = true
+	a.result <- err
+	if a.stopTimer != nil {
+		a.stopTimer()
+	}
+	a.stopCtx()
+	if a.throttled {
+		a.rl.recordWait(a.permits, err == nil, a.rl.clock.Now().Sub(a.start))
+	} else if err == nil {
+		a.rl.recordAttempt(a.permits, nil, false)
+	}
+	if a.sub != nil {
+		go a.rl.subscriptions.unsubscribe(context.WithoutCancel(a.ctx), a.sub)
+	}
+}
+
 // TryAcquireWithTimeout
 func (rl *RedissonRateLimiter) TryAcquireWithTimeout(timeout time.Duration) (bool, error) {
 	return rl.TryAcquirePermitsWithTimeout(1, timeout)
 }
 
+// TryAcquireWithTimeoutContext
+func (rl *RedissonRateLimiter) TryAcquireWithTimeoutContext(ctx context.Context, timeout time.Duration) (bool, error) {
+	return rl.TryAcquirePermitsWithTimeoutContext(ctx, 1, timeout)
+}
+
 // TryAcquirePermitsWithTimeout 参考 Java 中的逻辑：
 // 1. 先尝试获取令牌；
 // 2. 若立即可获取 (delay == nil), 返回 true；
 // 3. 若返回 delay，需要判断 timeout；
 //   - 若 timeout < 0，表示无限等待，则等待 delay 毫秒后再次尝试；
 //   - 若有超时时间，则看是否还有剩余等待时间；
-//   - 若剩余等待时间 < 0，直接返回 false；
-//   - 若剩余等待时间 < delay，等待到期后返回 false；
-//   - 否则等待 delay 后再次递归尝试，直到超时或成功。
+//   - 若剩余等待时间 <= 0，直接返回 false；
+//   - 若剩余等待时间 < delay，只等待剩余时间，再尝试最后一次；
+//   - 否则等待 delay 后再次尝试，直到超时或成功。
+//
+// 等待期间若限流器配置被 SetRate 修改，会提前唤醒并按新配置重新尝试。
 func (rl *RedissonRateLimiter) TryAcquirePermitsWithTimeout(permits int64, timeout time.Duration) (bool, error) {
-	start := time.Now()
-	timeWait, err := rl.tryAcquireLua(permits)
-	if err != nil {
-		return false, err
-	}
+	return rl.TryAcquirePermitsWithTimeoutContext(rl.baseContext(), permits, timeout)
+}
 
-	if timeWait == nil { // 先检查是否为 nil
-		return true, nil // 可以立即获取许可
-	}
+// TryAcquirePermitsWithTimeoutContext 同 TryAcquirePermitsWithTimeout，ctx 先结束时返回 false 和 ctx.Err()。
+func (rl *RedissonRateLimiter) TryAcquirePermitsWithTimeoutContext(ctx context.Context, permits int64, timeout time.Duration) (bool, error) {
+	start := rl.clock.Now()
+	// 第一次需要等待时才订阅配置变更通知，整个调用只计入一次统计
+	var sub *subscription
+	throttled := false
+	acquired := false
+	defer func() {
+		if throttled {
+			rl.recordWait(permits, acquired, rl.clock.Now().Sub(start))
+		}
+	}()
+	for {
+		timeWait, err := rl.tryAcquireLua(ctx, permits)
+		if err != nil {
+			return false, err
+		}
+		if timeWait == nil { // 可以立即获取许可
+			if !throttled {
+				rl.recordAttempt(permits, nil, false)
+			}
+			acquired = true
+			return true, nil
+		}
+		if !throttled {
+			throttled = true
+			rl.notifyThrottled(permits, time.Duration(*timeWait)*time.Millisecond)
+		}
 
-	delayMs := *timeWait // 确保 timeWait 不为 nil 后再解引用
+		// 脚本返回了 delay，有超时时间时最多等待剩余时间
+		wait := time.Duration(*timeWait) * time.Millisecond
+		if timeout >= 0 {
+			remains := timeout - rl.clock.Now().Sub(start)
+			if remains <= 0 {
+				return false, nil
+			}
+			if remains < wait {
+				wait = remains
+			}
+		}
 
-	// 脚本返回了 delay，需要根据 timeout 判断是否再次调度
-	if timeout < 0 {
-		// 等待 delay 后再无限重试
-		time.Sleep(time.Duration(delayMs) * time.Millisecond)
-		return rl.TryAcquirePermitsWithTimeout(permits, timeout)
+		if sub == nil {
+			if sub, err = rl.subscriptions.subscribe(ctx, rl.getChannelName()); err != nil {
+				return false, err
+			}
+			defer rl.subscriptions.unsubscribe(context.WithoutCancel(ctx), sub)
+		}
+		select {
+		case <-ctx.Done():
+			return false, ctx.Err()
+		// 等待 delay 后再次尝试
+		case <-rl.clock.After(wait):
+		// 配置已变更，按新配置立即重试
+		case <-sub.c:
+		}
 	}
+}
 
-	// 有超时时间，计算剩余时间
-	elapsed := time.Since(start)
-	remains := timeout - elapsed
-	if remains <= 0 {
-		// 超时
-		return false, nil
-	}
+// Reserve
+func (rl *RedissonRateLimiter) Reserve(permits int64) (time.Duration, error) {
+	return rl.ReserveContext(rl.baseContext(), permits)
+}
 
-	// 如果剩余时间小于本次返回的 delay，则等待到期后返回 false
-	delayDuration := time.Duration(delayMs) * time.Millisecond
-	if remains < delayDuration {
-		time.Sleep(remains)
-		return false, nil
+// ReserveContext
+func (rl *RedissonRateLimiter) ReserveContext(ctx context.Context, permits int64) (time.Duration, error) {
+	scripts, err := rl.getScripts(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if !scripts.reserves {
+		return 0, ErrReserveUnsupported
+	}
+	waitMs, err := rl.acquireLua(ctx, permits, 0, true)
+	if err != nil {
+		return 0, err
 	}
+	rl.recordAttempt(permits, waitMs, true)
+	if waitMs == nil {
+		return 0, nil
+	}
+	return time.Duration(*waitMs) * time.Millisecond, nil
+}
 
-	// 否则可等待 delay，再次尝试
-	time.Sleep(delayDuration)
+// Delete
+func (rl *RedissonRateLimiter) Delete() (bool, error) {
+	return rl.DeleteContext(rl.baseContext())
+}
 
-	// 等待完 delay 后可能又经过了一小段时间，需再次计算剩余
-	newElapsed := time.Since(start)
-	newRemains := timeout - newElapsed
-	if newRemains <= 0 {
-		return false, nil
+// DeleteContext 本实例 PerClient 模式下的令牌余量、许可记录和预热键一并删除
+func (rl *RedissonRateLimiter) DeleteContext(ctx context.Context) (bool, error) {
+	ctx, cancel := rl.withCommandTimeout(ctx)
+	defer cancel()
+	keys := []string{
+		rl.configHashKey(),
+		rl.valueKey(),
+		rl.permitsKey(),
+		rl.clientValueKey(),
+		rl.clientPermitsKey(),
+		rl.warmUpKey(),
+		rl.clientWarmUpKey(),
 	}
+	res, err := rl.eval(ctx, "rateLimiter.delete", deleteScript, keys).Int64()
+	if err != nil {
+		return false, err
+	}
+	rl.mutex.Lock()
+	rl.rateInitialized = false
+	rl.mutex.Unlock()
+	rl.rateLimiterStats.Delete(rl.getRawName())
+	return res > 0, nil
+}
 
-	return rl.TryAcquirePermitsWithTimeout(permits, newRemains)
+// Stats
+func (rl *RedissonRateLimiter) Stats() RateLimiterStats {
+	stats, ok := rl.rateLimiterStats.Load(rl.getRawName())
+	if !ok {
+		return RateLimiterStats{}
+	}
+	return stats.(*rateLimiterStats).snapshot()
 }
 
 // GetConfig
 func (rl *RedissonRateLimiter) GetConfig() (*RateLimiterConfig, error) {
-	ctx := context.Background()
+	return rl.GetConfigContext(rl.baseContext())
+}
+
+// GetConfigContext
+func (rl *RedissonRateLimiter) GetConfigContext(ctx context.Context) (*RateLimiterConfig, error) {
+	if err := rl.ensureRate(ctx); err != nil {
+		return nil, err
+	}
+	ctx, cancel := rl.withCommandTimeout(ctx)
+	defer cancel()
 	h, err := rl.client.HGetAll(ctx, rl.configHashKey()).Result()
 	if err != nil {
 		return nil, err
 	}
 	if len(h) == 0 {
-		return nil, errors.New("RateLimiter is not initialized")
+		if ok, err := rl.reinitRate(ctx); err != nil {
+			return nil, err
+		} else if ok {
+			if h, err = rl.client.HGetAll(ctx, rl.configHashKey()).Result(); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if len(h) == 0 {
+		return nil, errors.New(rateLimiterNotInitialized)
 	}
 	rate, _ := strconv.ParseInt(h["rate"], 10, 64)
 	interval, _ := strconv.ParseInt(h["interval"], 10, 64)
@@ -356,13 +931,43 @@ func (rl *RedissonRateLimiter) GetConfig() (*RateLimiterConfig, error) {
 		RateType:     RateType(typ),
 		RateInterval: interval,
 		Rate:         rate,
+		Tiers:        parseRateTiers(h["tiers"]),
 	}, nil
 }
 
+// formatRateTiers 将层级编码为配置中的 "rate:interval毫秒,..."
+func formatRateTiers(tiers []RateTier) string {
+	specs := make([]string, len(tiers))
+	for i, tier := range tiers {
+		specs[i] = strconv.FormatInt(tier.Rate, 10) + ":" + strconv.FormatInt(tier.Interval.Milliseconds(), 10)
+	}
+	return strings.Join(specs, ",")
+}
+
+// parseRateTiers 解析 formatRateTiers 编码的层级
+func parseRateTiers(spec string) []RateTier {
+	var tiers []RateTier
+	for _, s := range strings.Split(spec, ",") {
+		rate, interval, ok := strings.Cut(s, ":")
+		if !ok {
+			continue
+		}
+		r, _ := strconv.ParseInt(rate, 10, 64)
+		i, _ := strconv.ParseInt(interval, 10, 64)
+		tiers = append(tiers, RateTier{Rate: r, Interval: time.Duration(i) * time.Millisecond})
+	}
+	return tiers
+}
+
 // AvailablePermits
 func (rl *RedissonRateLimiter) AvailablePermits() (int64, error) {
+	return rl.AvailablePermitsContext(rl.baseContext())
+}
+
+// AvailablePermitsContext
+func (rl *RedissonRateLimiter) AvailablePermitsContext(ctx context.Context) (int64, error) {
 	fmt.Println("Fetching available permits...")
-	res, err := rl.availablePermitsLua()
+	res, err := rl.availablePermitsLua(ctx)
 	if err != nil {
 		//fmt.Printf("Error fetching available permits: %v\n", err)
 		//return 0, err
@@ -375,19 +980,31 @@ func (rl *RedissonRateLimiter) AvailablePermits() (int64, error) {
 	return *res, nil
 }
 
-func (rl *RedissonRateLimiter) availablePermitsLua() (*int64, error) {
-	ctx := context.Background()
+func (rl *RedissonRateLimiter) availablePermitsLua(ctx context.Context) (*int64, error) {
+	if err := rl.ensureRate(ctx); err != nil {
+		return nil, err
+	}
+	ctx, cancel := rl.withCommandTimeout(ctx)
+	defer cancel()
 	keys := []string{
 		rl.configHashKey(),
 		rl.valueKey(),
 		rl.clientValueKey(),
 		rl.permitsKey(),
 		rl.clientPermitsKey(),
+		rl.warmUpKey(),
+		rl.clientWarmUpKey(),
 	}
 	args := []interface{}{
-		time.Now().UnixMilli(),
+		rl.clock.Now().UnixMilli(),
+		rl.warmUp.Milliseconds(),
+		rl.ttl.Milliseconds(),
+	}
+	scripts, err := rl.getScripts(ctx)
+	if err != nil {
+		return nil, err
 	}
-	res, err := rl.client.Eval(ctx, availablePermitsScript, keys, args...).Int64()
+	res, err := rl.evalInitialized(ctx, scripts.name+".availablePermits", scripts.availablePermits, keys, args...).Int64()
 	if err != nil {
 		if err == redis.Nil {
 			return nil, nil
@@ -398,8 +1015,16 @@ func (rl *RedissonRateLimiter) availablePermitsLua() (*int64, error) {
 
 }
 
-func (rl *RedissonRateLimiter) tryAcquireLua(permits int64) (*int64, error) {
-	// 加锁保护并发访问
+func (rl *RedissonRateLimiter) tryAcquireLua(ctx context.Context, permits int64) (*int64, error) {
+	return rl.acquireLua(ctx, permits, 0, false)
+}
+
+// acquireLua 获取许可，本次允许超出速率 burst 个，许可不足时返回需要等待的毫秒数；
+// reserve 为 true 时许可不足也预约许可，等待后即可使用
+func (rl *RedissonRateLimiter) acquireLua(ctx context.Context, permits, burst int64, reserve bool) (*int64, error) {
+	if err := rl.ensureRate(ctx); err != nil {
+		return nil, err
+	}
 
 	keys := []string{
 		rl.getRawName(),
@@ -407,11 +1032,13 @@ func (rl *RedissonRateLimiter) tryAcquireLua(permits int64) (*int64, error) {
 		rl.getClientValueName(),
 		rl.getPermitsName(),
 		rl.getClientPermitsName(),
+		rl.warmUpKey(),
+		rl.clientWarmUpKey(),
 	}
 
 	//nowMillis := time.Now().UnixNano() / int64(time.Millisecond)
 
-	nowMillis := time.Now().UnixMilli()
+	nowMillis := rl.clock.Now().UnixMilli()
 	// 使用更安全的随机数生成
 	randomBytes := make([]byte, 16)
 	if _, err := rand.Read(randomBytes); err != nil {
@@ -422,22 +1049,66 @@ func (rl *RedissonRateLimiter) tryAcquireLua(permits int64) (*int64, error) {
 		permits,
 		nowMillis,
 		hex.EncodeToString(randomBytes), // 使用 hex 编码确保安全传输
+		rl.warmUp.Milliseconds(),
+		reserve,
+		burst,
+		rl.ttl.Milliseconds(),
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := rl.withCommandTimeout(ctx)
 	defer cancel()
 
-	res, err := rl.client.Eval(ctx, tryAcquireScript, keys, args...).Int64()
+	scripts, err := rl.getScripts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	res, err := rl.evalInitialized(ctx, scripts.name+".tryAcquire", scripts.tryAcquire, keys, args...).Int64()
 	if err != nil {
 		if err == redis.Nil {
 			return nil, nil
 		}
-		return nil, fmt.Errorf("failed to execute rate limit script: %v", err)
+		return nil, fmt.Errorf("failed to execute rate limit script: %w", err)
 	}
 
 	return &res, nil
 }
 
+// recordAttempt 记录一次不等待的获取，waitMs 为脚本返回的等待毫秒数，reserved 为 true 时许可已预约
+func (rl *RedissonRateLimiter) recordAttempt(permits int64, waitMs *int64, reserved bool) {
+	stats := rl.getRateLimiterStats(rl.getRawName())
+	if waitMs == nil {
+		stats.acquired(permits)
+		return
+	}
+	wait := time.Duration(*waitMs) * time.Millisecond
+	rl.notifyThrottled(permits, wait)
+	if reserved {
+		stats.acquired(permits)
+		stats.waited(wait)
+		return
+	}
+	stats.rejected(permits)
+}
+
+// recordWait 记录一次许可不足后等待的阻塞获取的结果
+func (rl *RedissonRateLimiter) recordWait(permits int64, acquired bool, wait time.Duration) {
+	stats := rl.getRateLimiterStats(rl.getRawName())
+	if acquired {
+		stats.acquired(permits)
+	} else {
+		stats.rejected(permits)
+	}
+	stats.waited(wait)
+}
+
+// notifyThrottled 记录一次许可不足并通知 OnThrottled 设置的处理函数
+func (rl *RedissonRateLimiter) notifyThrottled(permits int64, wait time.Duration) {
+	rl.getRateLimiterStats(rl.getRawName()).throttled()
+	if handler := rl.throttledHandler.Load(); handler != nil {
+		(*handler)(rl.getRawName(), permits, wait)
+	}
+}
+
 // =============== Lua 脚本（示例） ===============
 
 const tryAcquireScript = `
@@ -445,7 +1116,8 @@ local rate = redis.call('hget', KEYS[1], 'rate');
 local interval = redis.call('hget', KEYS[1], 'interval');
 local type = redis.call('hget', KEYS[1], 'type');
 assert(rate ~= false and interval ~= false and type ~= false, 'RateLimiter is not initialized')
-
+local keepAlive = tonumber(ARGV[7]);
+` + rateLimiterKeepAliveScript + `
 local valueName = KEYS[2];
 local permitsName = KEYS[4];
 if type == '1' then 
@@ -453,7 +1125,8 @@ valueName = KEYS[3];
 permitsName = KEYS[5];
 end;
 
-assert(tonumber(rate) >= tonumber(ARGV[1]), 'Requested permits amount could not exceed defined rate'); 
+local burst = tonumber(ARGV[6] or '0');
+assert(tonumber(rate) + burst >= tonumber(ARGV[1]), 'Requested permits amount could not exceed defined rate'); 
 
 local currentValue = redis.call('get', valueName); 
 local res;
@@ -475,7 +1148,23 @@ end;
 redis.call('set', valueName, currentValue);
 end;
 
-if tonumber(currentValue) < tonumber(ARGV[1]) then 
+if tonumber(currentValue) + burst < tonumber(ARGV[1]) and ARGV[5] == '1' then
+-- 预约：按获取时间依次释放的许可腾出足够余量时即可使用，预约的许可从那时起占用 interval，余量可以为负
+local need = tonumber(ARGV[1]) - burst - tonumber(currentValue);
+local entries = redis.call('zrange', permitsName, 0, -1, 'withscores');
+local usableAt = tonumber(ARGV[2]);
+for i = 1, #entries, 2 do
+local random, permits = struct.unpack('Bc0I', entries[i]);
+usableAt = tonumber(entries[i + 1]) + interval;
+need = need - permits;
+if need <= 0 then
+break;
+end;
+end;
+res = math.max(1, usableAt - tonumber(ARGV[2]));
+redis.call('zadd', permitsName, usableAt, struct.pack('Bc0I', string.len(ARGV[3]), ARGV[3], ARGV[1]));
+redis.call('decrby', valueName, ARGV[1]);
+elseif tonumber(currentValue) + burst < tonumber(ARGV[1]) then 
 local firstValue = redis.call('zrange', permitsName, 0, 0, 'withscores'); 
 res = 3 + interval - (tonumber(ARGV[2]) - tonumber(firstValue[2]));
 else 
@@ -495,6 +1184,9 @@ if ttl > 0 then
 redis.call('pexpire', valueName, ttl); 
 redis.call('pexpire', permitsName, ttl); 
 end; 
+-- 所有许可都在 interval 后释放，之后状态与新建时无异
+local idle = tonumber(interval);
+` + rateLimiterClientIdleScript + `
 return res;
 `
 
@@ -503,7 +1195,44 @@ const setRateScript = `
 redis.call('hset', KEYS[1], 'rate', ARGV[1]);
 redis.call('hset', KEYS[1], 'interval', ARGV[2]);
 redis.call('hset', KEYS[1], 'type', ARGV[3]);
+if ARGV[5] ~= '' then
+    redis.call('hset', KEYS[1], 'tiers', ARGV[5]);
+else
+    redis.call('hdel', KEYS[1], 'tiers');
+end;
+redis.call('hdel', KEYS[1], 'maxBurst');
 redis.call('del', KEYS[2], KEYS[3]);
+-- 令牌余量和许可记录已删除，重新创建时由 tryAcquireScript 跟随配置的过期时间；
+-- 不带 keepAlive 时清除之前 SetRateWithTTL 设置的过期时间
+if tonumber(ARGV[4]) > 0 then
+    redis.call('pexpire', KEYS[1], ARGV[4]);
+else
+    redis.call('persist', KEYS[1]);
+end;
+redis.call('publish', KEYS[4], 1);
+`
+
+// updateRateScript：修改速率和时间间隔，保留已发放的许可；ARGV[3] 为 1 时按速率的变化调整令牌余量
+const updateRateScript = `
+local rate = redis.call('hget', KEYS[1], 'rate');
+assert(rate ~= false, 'RateLimiter is not initialized');
+redis.call('hset', KEYS[1], 'rate', ARGV[1], 'interval', ARGV[2]);
+redis.call('hdel', KEYS[1], 'maxBurst');
+if ARGV[3] == '1' then
+    local delta = tonumber(ARGV[1]) - tonumber(rate);
+    for i = 2, 3 do
+        local value = redis.call('get', KEYS[i]);
+        if value ~= false then
+            redis.call('set', KEYS[i], math.max(0, tonumber(value) + delta), 'keepttl');
+        end;
+    end;
+end;
+redis.call('publish', KEYS[4], 1);
+`
+
+// deleteScript：删除配置、令牌余量、许可记录和预热键
+const deleteScript = `
+return redis.call('del', unpack(KEYS));
 `
 
 // trySetRateScript：只有当还没设置过的时候才写入
@@ -520,17 +1249,20 @@ local rate = redis.call('hget', KEYS[1], 'rate');
 local interval = redis.call('hget', KEYS[1], 'interval');
 local type = redis.call('hget', KEYS[1], 'type');
 assert(rate ~= false and interval ~= false and type ~= false, 'RateLimiter is not initialized');
-
+local keepAlive = tonumber(ARGV[3]);
+` + rateLimiterKeepAliveScript + `
 local valueName = KEYS[2];
 local permitsName = KEYS[4];
 if type == '1' then
    valueName = KEYS[3];
    permitsName = KEYS[5];
 end;
-
+` + rateLimiterFollowTTLScript + `
+local idle = tonumber(interval);
 local currentValue = redis.call('get', valueName);
 if currentValue == false then
    redis.call('set', valueName, rate);
+` + rateLimiterFollowTTLScript + rateLimiterClientIdleScript + `
    return rate;
 else
    -- 移除过期
@@ -545,8 +1277,10 @@ else
        redis.call('zremrangebyscore', permitsName, 0, tonumber(ARGV[1]) - interval);
        currentValue = tonumber(currentValue) + released;
        redis.call('set', valueName, currentValue);
+` + rateLimiterFollowTTLScript + rateLimiterClientIdleScript + `
    end;
 
-   return currentValue;
+   -- 预约的许可使余量为负时没有可用的许可
+   return math.max(0, tonumber(currentValue));
 end;
 `
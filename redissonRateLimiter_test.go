@@ -2,6 +2,7 @@ package redisson
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/redis/go-redis/v9"
 	"log"
@@ -621,3 +622,182 @@ func TestRateLimiter_TokenReplenishment1(t *testing.T) {
 		t.Errorf("Expected 5 permits after replenishment, got %d", finalAvailable)
 	}
 }
+
+func TestRateLimiterWithRate(t *testing.T) {
+	g := GetRedisson()
+	rl := g.GetRateLimiter("testRateLimiterWithRate", WithRate(2, time.Minute), WithTTL(time.Hour))
+	if err := g.client.Del(context.Background(), "testRateLimiterWithRate", "{testRateLimiterWithRate}:value", "{testRateLimiterWithRate}:permits").Err(); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if ok, err := rl.TryAcquire(); err != nil {
+			t.Fatal(err)
+		} else if !ok {
+			t.Fatalf("permit %d should be acquired", i)
+		}
+	}
+	if ok, err := rl.TryAcquire(); err != nil {
+		t.Fatal(err)
+	} else if ok {
+		t.Fatal("rate should be limited to 2 permits")
+	}
+
+	// WithRate does not change an existing rate
+	other := g.GetRateLimiter("testRateLimiterWithRate", WithRate(100, time.Second))
+	config, err := other.GetConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.Rate != 2 || config.RateInterval != time.Minute.Milliseconds() {
+		t.Fatalf("config=%+v", config)
+	}
+}
+
+func TestRateLimiterWithRateExpired(t *testing.T) {
+	g := GetRedisson()
+	ctx := context.Background()
+	if err := g.client.Del(ctx, "testRateLimiterWithRateExpired", "{testRateLimiterWithRateExpired}:value", "{testRateLimiterWithRateExpired}:permits").Err(); err != nil {
+		t.Fatal(err)
+	}
+	rl := g.GetRateLimiter("testRateLimiterWithRateExpired", WithRate(2, time.Minute), WithTTL(300*time.Millisecond))
+	if err := rl.Acquire(); err != nil {
+		t.Fatal(err)
+	}
+
+	// every use renews the TTL, so the limiter outlives it while in use and keeps its issued permits
+	for i := 0; i < 4; i++ {
+		time.Sleep(150 * time.Millisecond)
+		if _, err := rl.AvailablePermits(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if ok, err := rl.TryAcquirePermits(2); err != nil {
+		t.Fatal(err)
+	} else if ok {
+		t.Fatal("the issued permit should still count")
+	}
+
+	// once idle for the TTL the config expires, and the next use initializes it again
+	time.Sleep(500 * time.Millisecond)
+	if n := g.client.Exists(ctx, "testRateLimiterWithRateExpired").Val(); n != 0 {
+		t.Fatalf("the config should have expired")
+	}
+	if err := rl.AcquirePermits(2); err != nil {
+		t.Fatal(err)
+	}
+	if ttl := g.client.PTTL(ctx, "testRateLimiterWithRateExpired").Val(); ttl <= 0 || ttl > 300*time.Millisecond {
+		t.Fatalf("ttl=%v", ttl)
+	}
+	if err := g.client.Del(ctx, "testRateLimiterWithRateExpired").Err(); err != nil {
+		t.Fatal(err)
+	}
+	if config, err := rl.GetConfig(); err != nil {
+		t.Fatal(err)
+	} else if config.Rate != 2 {
+		t.Fatalf("config=%+v", config)
+	}
+}
+
+func TestRateLimiterAcquireContext(t *testing.T) {
+	g := GetRedisson()
+	rl := g.GetRateLimiter("testRateLimiterAcquireContext")
+	if err := g.client.Del(context.Background(), "testRateLimiterAcquireContext", "{testRateLimiterAcquireContext}:value", "{testRateLimiterAcquireContext}:permits").Err(); err != nil {
+		t.Fatal(err)
+	}
+	if err := rl.SetRateContext(context.Background(), RateTypeOVERALL, 1, 1, Minutes); err != nil {
+		t.Fatal(err)
+	}
+	if err := rl.AcquireContext(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	// the next permit is a minute away, the wait ends with the context
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if err := rl.AcquireContext(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err=%v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("acquire waited %v after the context ended", elapsed)
+	}
+}
+
+func TestRateLimiterWakeOnSetRate(t *testing.T) {
+	g := GetRedisson()
+	rl := g.GetRateLimiter("testRateLimiterWakeOnSetRate")
+	if err := g.client.Del(context.Background(), "testRateLimiterWakeOnSetRate", "{testRateLimiterWakeOnSetRate}:value", "{testRateLimiterWakeOnSetRate}:permits").Err(); err != nil {
+		t.Fatal(err)
+	}
+	if err := rl.SetRate(RateTypeOVERALL, 1, 1, Minutes); err != nil {
+		t.Fatal(err)
+	}
+	if err := rl.Acquire(); err != nil {
+		t.Fatal(err)
+	}
+
+	// the next permit is a minute away until the rate is raised
+	done := make(chan bool, 1)
+	go func() {
+		ok, err := rl.TryAcquireWithTimeout(10 * time.Second)
+		if err != nil {
+			t.Error(err)
+		}
+		done <- ok
+	}()
+	time.Sleep(200 * time.Millisecond)
+	if err := rl.SetRate(RateTypeOVERALL, 10, 1, Seconds); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case ok := <-done:
+		if !ok {
+			t.Fatal("permit should be acquired with the new rate")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("the waiter was not woken by the new rate")
+	}
+}
+
+func TestRateLimiterSetRateWithTTLAndDelete(t *testing.T) {
+	g := GetRedisson()
+	rl := g.GetRateLimiter("testRateLimiterSetRateWithTTL")
+	ctx := context.Background()
+	if err := g.client.Del(ctx, "testRateLimiterSetRateWithTTL", "{testRateLimiterSetRateWithTTL}:value", "{testRateLimiterSetRateWithTTL}:permits").Err(); err != nil {
+		t.Fatal(err)
+	}
+	if err := rl.SetRateWithTTL(RateTypeOVERALL, 10, 1, Seconds, 0); err == nil {
+		t.Fatal("keepAlive should be positive")
+	}
+	if err := rl.SetRateWithTTL(RateTypeOVERALL, 10, 1, Seconds, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if ttl := g.client.PTTL(ctx, "testRateLimiterSetRateWithTTL").Val(); ttl <= 0 || ttl > time.Minute {
+		t.Fatalf("ttl=%v", ttl)
+	}
+	// SetRate makes the limiter permanent again
+	if err := rl.SetRate(RateTypeOVERALL, 10, 1, Seconds); err != nil {
+		t.Fatal(err)
+	}
+	if ttl := g.client.PTTL(ctx, "testRateLimiterSetRateWithTTL").Val(); ttl != -time.Nanosecond {
+		t.Fatalf("ttl=%v", ttl)
+	}
+	// a permit record left by an earlier acquisition
+	if err := g.client.Set(ctx, "{testRateLimiterSetRateWithTTL}:value", 9, 0).Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	if ok, err := rl.Delete(); err != nil {
+		t.Fatal(err)
+	} else if !ok {
+		t.Fatal("the limiter should be deleted")
+	}
+	if n := g.client.Exists(ctx, "testRateLimiterSetRateWithTTL", "{testRateLimiterSetRateWithTTL}:value", "{testRateLimiterSetRateWithTTL}:permits").Val(); n != 0 {
+		t.Fatalf("%d keys left", n)
+	}
+	if ok, err := rl.Delete(); err != nil {
+		t.Fatal(err)
+	} else if ok {
+		t.Fatal("a deleted limiter should not be deleted again")
+	}
+}
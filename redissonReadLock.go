@@ -8,15 +8,43 @@ import (
 	"github.com/redis/go-redis/v9"
 )
 
+// purgeStaleReadersLua defines purgeStaleReaders(lockKey, keyPrefix) used by the read write lock scripts.
+// Every hold of a reader has a timeout key renewed by the watchdog of its goroutine only, so the reads held by a
+// crashed process are detected once all their timeout keys expired. It removes these readers from the lock and
+// returns the longest remaining time of the timeout keys of the live readers, -3 if there is none.
+const purgeStaleReadersLua = `
+local function purgeStaleReaders(lockKey, keyPrefix)
+    local maxRemainTime = -3;
+    local fields = redis.call('hkeys', lockKey);
+    for n, field in ipairs(fields) do
+        local counter = tonumber(redis.call('hget', lockKey, field));
+        if type(counter) == 'number' and string.sub(field, -6) ~= ':write' then
+            local alive = false;
+            for i = counter, 1, -1 do
+                local remainTime = redis.call('pttl', keyPrefix .. ':' .. field .. ':rwlock_timeout:' .. i);
+                if remainTime ~= -2 then
+                    alive = true;
+                    maxRemainTime = math.max(remainTime, maxRemainTime);
+                end ;
+            end ;
+            if not alive then
+                redis.call('hdel', lockKey, field);
+            end ;
+        end ;
+    end ;
+    return maxRemainTime;
+end ;
+`
+
 // RedissonReadLock implements Lock
 type RedissonReadLock struct {
 	RedissonBaseLock
 }
 
 // newReadLock creates a new RedissonReadLock
-func newReadLock(name string, redisson *Redisson) Lock {
+func newReadLock(name string, redisson *Redisson, opts ...ObjectOption) Lock {
 	RedissonReadLock := &RedissonReadLock{}
-	RedissonReadLock.RedissonBaseLock = *newBaseLock(redisson.id, name, redisson, RedissonReadLock)
+	RedissonReadLock.RedissonBaseLock = *newBaseLock(redisson.id, name, redisson, RedissonReadLock, opts...)
 	return RedissonReadLock
 }
 
@@ -37,7 +65,7 @@ func (m *RedissonReadLock) getReadWriteTimeoutNamePrefix(goroutineId uint64) str
 
 // tryLockInner tries to acquire the lock
 func (m *RedissonReadLock) tryLockInner(ctx context.Context, leaseTime time.Duration, goroutineId uint64) (*int64, error) {
-	result, err := m.client.Eval(ctx, `
+	result, err := m.eval(ctx, "readLock.tryLock", `
 local mode = redis.call('hget', KEYS[1], 'mode');
 if (mode == false) then
     redis.call('hset', KEYS[1], 'mode', 'read');
@@ -80,10 +108,10 @@ func (m *RedissonReadLock) unlockInner(ctx context.Context, goroutineId uint64)
 	timeoutPrefix := m.getReadWriteTimeoutNamePrefix(goroutineId)
 	keyPrefix := m.getKeyPrefix(goroutineId, timeoutPrefix)
 
-	result, err := m.client.Eval(ctx, `
+	result, err := m.eval(ctx, "readLock.unlock", unlockPublishLua+purgeStaleReadersLua+`
 local mode = redis.call('hget', KEYS[1], 'mode');
 if (mode == false) then
-    redis.call('publish', KEYS[2], ARGV[1]);
+    publishUnlock(KEYS[2], ARGV[1], ARGV[3], KEYS[5]);
     return 1;
 end ;
 local lockExists = redis.call('hexists', KEYS[1], ARGV[2]);
@@ -98,17 +126,7 @@ end ;
 redis.call('del', KEYS[3] .. ':' .. (counter + 1));
 
 if (redis.call('hlen', KEYS[1]) > 1) then
-    local maxRemainTime = -3;
-    local keys = redis.call('hkeys', KEYS[1]);
-    for n, key in ipairs(keys) do
-        counter = tonumber(redis.call('hget', KEYS[1], key));
-        if type(counter) == 'number' then
-            for i = counter, 1, -1 do
-                local remainTime = redis.call('pttl', KEYS[4] .. ':' .. key .. ':rwlock_timeout:' .. i);
-                maxRemainTime = math.max(remainTime, maxRemainTime);
-            end ;
-        end ;
-    end ;
+    local maxRemainTime = purgeStaleReaders(KEYS[1], KEYS[4]);
 
     if maxRemainTime > 0 then
         redis.call('pexpire', KEYS[1], maxRemainTime);
@@ -121,9 +139,9 @@ if (redis.call('hlen', KEYS[1]) > 1) then
 end ;
 
 redis.call('del', KEYS[1]);
-redis.call('publish', KEYS[2], ARGV[1]);
+publishUnlock(KEYS[2], ARGV[1], ARGV[3], KEYS[5]);
 return 1;
-`, []string{m.getRawName(), m.getChannelName(), timeoutPrefix, keyPrefix}, unlockMessage, m.getLockName(goroutineId)).Int64()
+`, []string{m.getRawName(), m.getChannelName(), timeoutPrefix, keyPrefix, m.getWaitersName()}, unlockMessage, m.getLockName(goroutineId), m.lockChannelShards).Int64()
 	if err != nil {
 		if err == redis.Nil {
 			return nil, nil
@@ -138,25 +156,56 @@ func (m *RedissonReadLock) renewExpirationInner(ctx context.Context, goroutineId
 	timeoutPrefix := m.getReadWriteTimeoutNamePrefix(goroutineId)
 	keyPrefix := m.getKeyPrefix(goroutineId, timeoutPrefix)
 
-	return m.client.Eval(ctx, `
-local counter = redis.call('hget', KEYS[1], ARGV[2]);
-if (counter ~= false) then
-    redis.call('pexpire', KEYS[1], ARGV[1]);
-
-    if (redis.call('hlen', KEYS[1]) > 1) then
-        local keys = redis.call('hkeys', KEYS[1]);
-        for n, key in ipairs(keys) do
-            counter = tonumber(redis.call('hget', KEYS[1], key));
-            if type(counter) == 'number' then
-                for i = counter, 1, -1 do
-                    redis.call('pexpire', KEYS[2] .. ':' .. key .. ':rwlock_timeout:' .. i, ARGV[1]);
-                end ;
-            end ;
-        end ;
+	// only the timeout keys of the goroutine are renewed, those of the readers which stopped renewing expire
+	return m.eval(ctx, "readLock.renew", purgeStaleReadersLua+`
+local counter = tonumber(redis.call('hget', KEYS[1], ARGV[2]));
+if (counter ~= nil) then
+    for i = counter, 1, -1 do
+        redis.call('pexpire', KEYS[2] .. ':' .. ARGV[2] .. ':rwlock_timeout:' .. i, ARGV[1]);
     end ;
-
+    purgeStaleReaders(KEYS[1], KEYS[2]);
+    redis.call('pexpire', KEYS[1], ARGV[1]);
     return 1;
 end ;
 return 0;
-`, []string{m.getRawName(), keyPrefix}, m.internalLockLeaseTime.Milliseconds(), m.getLockName(goroutineId)).Int64()
+`, []string{m.getRawName(), keyPrefix}, m.getLockLeaseTime().Milliseconds(), m.getLockName(goroutineId)).Int64()
+}
+
+// isLockedInner reports whether the lock is held for reading
+func (m *RedissonReadLock) isLockedInner(ctx context.Context) (bool, error) {
+	mode, err := m.client.HGet(ctx, m.getRawName(), "mode").Result()
+	if err == redis.Nil {
+		return false, nil
+	}
+	return mode == "read", err
+}
+
+// holdCountInner returns how many times the goroutine holds the lock for reading
+func (m *RedissonReadLock) holdCountInner(ctx context.Context, goroutineId uint64) (int64, error) {
+	count, err := m.client.HGet(ctx, m.getRawName(), m.getLockName(goroutineId)).Int64()
+	if err == redis.Nil {
+		return 0, nil
+	}
+	return count, err
+}
+
+// transferInner moves the holds of the lock for reading from a goroutine to another, with their timeout keys
+func (m *RedissonReadLock) transferInner(ctx context.Context, from, to uint64) (int64, error) {
+	keyPrefix := m.getKeyPrefix(from, m.getReadWriteTimeoutNamePrefix(from))
+	return m.eval(ctx, "readLock.transfer", `
+local holds = tonumber(redis.call('hget', KEYS[1], ARGV[1]));
+if (holds == nil) then
+    return 0;
+end ;
+local base = tonumber(redis.call('hget', KEYS[1], ARGV[2])) or 0;
+for i = 1, holds, 1 do
+    local key = KEYS[2] .. ':' .. ARGV[1] .. ':rwlock_timeout:' .. i;
+    if (redis.call('exists', key) == 1) then
+        redis.call('rename', key, KEYS[2] .. ':' .. ARGV[2] .. ':rwlock_timeout:' .. (base + i));
+    end ;
+end ;
+redis.call('hdel', KEYS[1], ARGV[1]);
+redis.call('hset', KEYS[1], ARGV[2], base + holds);
+return holds;
+`, []string{m.getRawName(), keyPrefix}, m.getLockName(from), m.getLockName(to)).Int64()
 }
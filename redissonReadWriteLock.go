@@ -30,11 +30,11 @@ func (m *RedissonReadWriteLock) WriteLock() Lock {
 }
 
 // newRedisReadWriteLock creates a new RedissonReadWriteLock
-func newRedisReadWriteLock(name string, redisson *Redisson) ReadWriteLock {
+func newRedisReadWriteLock(name string, redisson *Redisson, opts ...ObjectOption) ReadWriteLock {
 	return &RedissonReadWriteLock{
 		RedissonExpirable: newRedissonExpirable(name, redisson),
 		redisson:          redisson,
-		rLock:             newReadLock(name, redisson),
-		wLock:             newRedisWriteLock(name, redisson),
+		rLock:             newReadLock(name, redisson, opts...),
+		wLock:             newRedisWriteLock(name, redisson, opts...),
 	}
 }
@@ -191,3 +191,162 @@ func HammerRWMutex(gomaxprocs, numReaders, num_iterations int) {
 		<-cdone
 	}
 }
+
+func TestReadWriteLockIntrospection(t *testing.T) {
+	g := GetRedisson()
+	rw := g.GetReadWriteLock("TestReadWriteLockIntrospection")
+	if err := rw.WriteLock().Lock(); err != nil {
+		t.Fatal(err)
+	}
+	if locked, err := rw.WriteLock().IsLocked(); err != nil || !locked {
+		t.Fatalf("locked=%v err=%v", locked, err)
+	}
+	if locked, err := rw.ReadLock().IsLocked(); err != nil || locked {
+		t.Fatalf("locked=%v err=%v", locked, err)
+	}
+	if count, err := rw.WriteLock().GetHoldCount(); err != nil || count != 1 {
+		t.Fatalf("count=%v err=%v", count, err)
+	}
+	if err := rw.WriteLock().Unlock(); err != nil {
+		t.Fatal(err)
+	}
+	if locked, err := rw.WriteLock().IsLocked(); err != nil || locked {
+		t.Fatalf("locked=%v err=%v", locked, err)
+	}
+}
+
+func TestReadWriteLockTryLock(t *testing.T) {
+	g := GetRedisson()
+	rw := g.GetReadWriteLock("TestReadWriteLockTryLock")
+	ok, err := rw.WriteLock().TryLockWithTimeout(time.Second, 300*time.Millisecond)
+	if err != nil || !ok {
+		t.Fatalf("ok=%v err=%v", ok, err)
+	}
+	done := make(chan bool)
+	go func() {
+		ok, err := rw.ReadLock().TryLock()
+		if err != nil {
+			panic(err)
+		}
+		done <- ok
+	}()
+	if <-done {
+		t.Fatal("read lock acquired while write locked")
+	}
+	// the write lease expires while the reader waits
+	go func() {
+		ok, err := rw.ReadLock().TryLockWithTimeout(time.Second, 300*time.Millisecond)
+		if err != nil {
+			panic(err)
+		}
+		held, err := rw.ReadLock().IsHeldByCurrentGoroutine()
+		if err != nil {
+			panic(err)
+		}
+		done <- ok && held
+	}()
+	if !<-done {
+		t.Fatal("read lock not acquired after the write lease expired")
+	}
+	// the read lease and its timeout key expire without the watchdog
+	time.Sleep(500 * time.Millisecond)
+	if locked, err := rw.ReadLock().IsLocked(); err != nil || locked {
+		t.Fatalf("locked=%v err=%v", locked, err)
+	}
+	go func() {
+		ok, err := rw.ReadLock().TryLockWithTimeout(50*time.Millisecond, 0)
+		if err != nil {
+			panic(err)
+		}
+		done <- ok
+		rw.ReadLock().Unlock()
+	}()
+	if !<-done {
+		t.Fatal("read lock not acquired")
+	}
+}
+
+func TestReadWriteLockDowngrade(t *testing.T) {
+	g := GetRedisson()
+	rw := g.GetReadWriteLock("TestReadWriteLockDowngrade")
+	if err := rw.WriteLock().Lock(); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := rw.ReadLock().LockContext(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := rw.WriteLock().Unlock(); err != nil {
+		t.Fatal(err)
+	}
+	// other readers share the downgraded lock
+	done := make(chan error)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		if err := rw.ReadLock().LockContext(ctx); err != nil {
+			done <- err
+			return
+		}
+		done <- rw.ReadLock().Unlock()
+	}()
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	// upgrading fails instead of waiting forever for the goroutine itself
+	if err := rw.WriteLock().LockContext(ctx); err != ErrLockUpgrade {
+		t.Fatalf("err=%v", err)
+	}
+	if err := rw.ReadLock().Unlock(); err != nil {
+		t.Fatal(err)
+	}
+	if locked, err := rw.ReadLock().IsLocked(); err != nil || locked {
+		t.Fatalf("locked=%v err=%v", locked, err)
+	}
+}
+
+// addStaleReader holds the read lock name for a reader of a crashed process, whose timeout key expires after ttl
+func addStaleReader(t *testing.T, g *Redisson, name string, ttl time.Duration) {
+	ctx := context.Background()
+	pipe := g.client.TxPipeline()
+	pipe.HSetNX(ctx, name, "mode", "read")
+	pipe.HSet(ctx, name, "crashed:1", 1)
+	pipe.Set(ctx, "{"+name+"}:crashed:1:rwlock_timeout:1", 1, ttl)
+	pipe.PExpire(ctx, name, 30*time.Second)
+	if _, err := pipe.Exec(ctx); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestReadWriteLockStaleReader test the reads of a crashed process do not keep the writers waiting
+func TestReadWriteLockStaleReader(t *testing.T) {
+	g := GetRedisson()
+	rw := g.GetReadWriteLock("TestReadWriteLockStaleReader")
+	defer g.client.Del(context.Background(), "TestReadWriteLockStaleReader")
+
+	addStaleReader(t, g, "TestReadWriteLockStaleReader", 100*time.Millisecond)
+	if locked, err := rw.WriteLock().TryLock(); err != nil || locked {
+		t.Fatalf("write lock should wait for a reader before its timeout, got %v %v", locked, err)
+	}
+	time.Sleep(200 * time.Millisecond)
+	if locked, err := rw.WriteLock().TryLock(); err != nil || !locked {
+		t.Fatalf("write lock should be taken once the reader timed out, got %v %v", locked, err)
+	}
+	if err := rw.WriteLock().Unlock(); err != nil {
+		t.Fatal(err)
+	}
+
+	// a live reader releasing the lock also releases it from the stale readers
+	if err := rw.ReadLock().Lock(); err != nil {
+		t.Fatal(err)
+	}
+	addStaleReader(t, g, "TestReadWriteLockStaleReader", 100*time.Millisecond)
+	time.Sleep(200 * time.Millisecond)
+	if err := rw.ReadLock().Unlock(); err != nil {
+		t.Fatal(err)
+	}
+	if exists := g.client.Exists(context.Background(), "TestReadWriteLockStaleReader").Val(); exists != 0 {
+		t.Fatal("lock should be released with its last live reader")
+	}
+}
@@ -0,0 +1,280 @@
+package redisson
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// DefaultReliableTopicSubscriberTimeout is how long a subscriber of RReliableTopic stays registered
+	// without reading, see WithSubscriberTimeout
+	DefaultReliableTopicSubscriberTimeout = 10 * time.Minute
+
+	// reliableTopicReadCount is the maximum number of messages read by a subscriber at once
+	reliableTopicReadCount = 100
+)
+
+// RReliableTopic publishes and receives messages of type T over a redis stream, like the RReliableTopic of Java Redisson.
+// Unlike RTopic, every subscriber reads the messages from its own position in the stream, so the messages published
+// while it is slow or disconnected are received once it reads again. A message is kept until all the registered
+// subscribers read it, and a subscriber which did not read for the subscriber timeout is unregistered.
+type RReliableTopic[T any] interface {
+	RExpirable
+
+	// Publish publishes the message and returns the number of subscribers registered, which will receive it.
+	// A message published while no subscriber is registered is dropped.
+	Publish(message T) (int64, error)
+
+	// Subscribe registers a new subscriber calling handler for every message published from now on, in order,
+	// until ctx is done or unsubscribe is called.
+	Subscribe(ctx context.Context, handler func(message T)) (unsubscribe func() error, err error)
+
+	// SubscribeAs is Subscribe with the id of the subscriber. If the subscriber is still registered, e.g. its
+	// previous ctx is done for less than the subscriber timeout, it resumes from the messages it did not receive.
+	// unsubscribe unregisters it, while ctx being done leaves it registered.
+	SubscribeAs(ctx context.Context, subscriberID string, handler func(message T)) (unsubscribe func() error, err error)
+
+	// CountSubscribers returns the number of subscribers registered.
+	CountSubscribers() (int64, error)
+
+	// Size returns the number of messages kept for the subscribers.
+	Size() (int64, error)
+
+	// Delete deletes the messages and the subscribers, and reports whether there were any.
+	Delete() (bool, error)
+}
+
+var (
+	_ RReliableTopic[string] = (*RedissonReliableTopic[string])(nil)
+)
+
+// RedissonReliableTopic is the implementation of RReliableTopic
+// the messages are stored in the name stream, the subscribers in the {name}:subscribers zset scored by the time they
+// expire, and the id of the last message each subscriber read in the {name}:positions hash
+type RedissonReliableTopic[T any] struct {
+	*RedissonExpirable
+	codec             Codec
+	subscriberTimeout time.Duration
+}
+
+// newRedissonReliableTopic creates a new RedissonReliableTopic
+func newRedissonReliableTopic[T any](name string, redisson *Redisson, options *objectOptions) *RedissonReliableTopic[T] {
+	m := &RedissonReliableTopic[T]{
+		RedissonExpirable: newRedissonExpirable(name, redisson),
+		codec:             options.codec,
+		subscriberTimeout: options.subscriberTimeout,
+	}
+	if m.subscriberTimeout <= 0 {
+		m.subscriberTimeout = DefaultReliableTopicSubscriberTimeout
+	}
+	m.ttl = options.ttl
+	m.componentKeys = func() []string {
+		return []string{m.getRawName(), m.getSubscribersName(), m.getPositionsName()}
+	}
+	return m
+}
+
+// getSubscribersName returns the name of the zset of the subscribers
+func (m *RedissonReliableTopic[T]) getSubscribersName() string {
+	return m.suffixName(m.getRawName(), "subscribers")
+}
+
+// getPositionsName returns the name of the hash of the positions of the subscribers
+func (m *RedissonReliableTopic[T]) getPositionsName() string {
+	return m.suffixName(m.getRawName(), "positions")
+}
+
+// Publish publishes the message and returns the number of subscribers registered, which will receive it.
+func (m *RedissonReliableTopic[T]) Publish(message T) (int64, error) {
+	data, err := m.codec.Encode(message)
+	if err != nil {
+		return 0, err
+	}
+	ctx, cancel := m.newContext()
+	defer cancel()
+	n, err := m.eval(ctx, "reliableTopic.publish", `
+local subscribers = redis.call('zcount', KEYS[2], ARGV[2], '+inf');
+if subscribers == 0 then
+    return 0;
+end;
+redis.call('xadd', KEYS[1], '*', ARGV[3], ARGV[1]);
+return subscribers;
+`, []string{m.getRawName(), m.getSubscribersName()}, data, m.clock.Now().UnixMilli(), streamValueField).Int64()
+	if err != nil || n == 0 {
+		return n, err
+	}
+	return n, m.applyTTL(ctx, m.getRawName())
+}
+
+// Subscribe registers a new subscriber calling handler for every message published from now on.
+func (m *RedissonReliableTopic[T]) Subscribe(ctx context.Context, handler func(message T)) (func() error, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+	return m.SubscribeAs(ctx, hex.EncodeToString(b), handler)
+}
+
+// SubscribeAs is Subscribe with the id of the subscriber.
+func (m *RedissonReliableTopic[T]) SubscribeAs(ctx context.Context, subscriberID string, handler func(message T)) (func() error, error) {
+	registerCtx, cancel := m.withCommandTimeout(ctx)
+	lastID, err := m.eval(registerCtx, "reliableTopic.register", `
+local score = redis.call('zscore', KEYS[2], ARGV[1]);
+local position = redis.call('hget', KEYS[3], ARGV[1]);
+if score ~= false and tonumber(score) >= tonumber(ARGV[3]) and position ~= false then
+    redis.call('zadd', KEYS[2], ARGV[2], ARGV[1]);
+    return position;
+end;
+local last = redis.call('xrevrange', KEYS[1], '+', '-', 'COUNT', 1);
+position = '0-0';
+if #last > 0 then
+    position = last[1][1];
+end;
+redis.call('zadd', KEYS[2], ARGV[2], ARGV[1]);
+redis.call('hset', KEYS[3], ARGV[1], position);
+return position;
+`, []string{m.getRawName(), m.getSubscribersName(), m.getPositionsName()},
+		subscriberID, m.clock.Now().Add(m.subscriberTimeout).UnixMilli(), m.clock.Now().UnixMilli()).Text()
+	if err == nil {
+		err = m.applyTTL(registerCtx, m.getSubscribersName(), m.getPositionsName())
+	}
+	cancel()
+	if err != nil {
+		return nil, err
+	}
+
+	readCtx, stop := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		m.read(readCtx, subscriberID, lastID, handler)
+	}()
+	return func() error {
+		stop()
+		<-done
+		unregisterCtx, cancel := m.withCommandTimeout(context.WithoutCancel(ctx))
+		defer cancel()
+		pipe := m.client.TxPipeline()
+		pipe.ZRem(unregisterCtx, m.getSubscribersName(), subscriberID)
+		pipe.HDel(unregisterCtx, m.getPositionsName(), subscriberID)
+		_, err := pipe.Exec(unregisterCtx)
+		return err
+	}, nil
+}
+
+// read calls handler for the messages after lastID until ctx is done, saving the position of the subscriber
+// after every read which delivered messages, and renewing its registration every half of the subscriber timeout
+// while there are none
+func (m *RedissonReliableTopic[T]) read(ctx context.Context, subscriberID, lastID string, handler func(message T)) {
+	saved := m.clock.Now()
+	for ctx.Err() == nil {
+		// the wait is bounded so that the subscriber stays registered while there are no messages, and ctx being
+		// done is seen even if the redis client is not created with ContextTimeoutEnabled
+		streams, err := m.client.XRead(ctx, &redis.XReadArgs{
+			Streams: []string{m.getRawName(), lastID},
+			Count:   reliableTopicReadCount,
+			Block:   blockingQueueWaitStep,
+		}).Result()
+		if err != nil && err != redis.Nil {
+			if ctx.Err() != nil {
+				return
+			}
+			log.Printf("reliable topic %s: failed to read messages: %v", m.getRawName(), err)
+			select {
+			case <-ctx.Done():
+				return
+			case <-m.clock.After(blockingQueueWaitStep):
+			}
+			continue
+		}
+		// the messages read as ctx was done are left for the next subscription of the subscriber
+		if ctx.Err() != nil {
+			return
+		}
+		read := false
+		for _, stream := range streams {
+			for _, msg := range stream.Messages {
+				lastID = msg.ID
+				read = true
+				data, ok := msg.Values[streamValueField].(string)
+				if !ok {
+					continue
+				}
+				var v T
+				if err = m.codec.Decode([]byte(data), &v); err != nil {
+					log.Printf("reliable topic %s: failed to decode message: %v", m.getRawName(), err)
+					continue
+				}
+				handler(v)
+			}
+		}
+		if !read && m.clock.Now().Sub(saved) < m.subscriberTimeout/2 {
+			continue
+		}
+		if err = m.saveProgress(context.WithoutCancel(ctx), subscriberID, lastID); err != nil {
+			log.Printf("reliable topic %s: failed to save the position of %s: %v", m.getRawName(), subscriberID, err)
+			continue
+		}
+		saved = m.clock.Now()
+	}
+}
+
+// saveProgress saves lastID as the position of the subscriber and renews its registration, unregisters the
+// subscribers which expired, and trims the messages read by all the subscribers
+func (m *RedissonReliableTopic[T]) saveProgress(ctx context.Context, subscriberID, lastID string) error {
+	ctx, cancel := m.withCommandTimeout(ctx)
+	defer cancel()
+	return m.eval(ctx, "reliableTopic.saveProgress", `
+redis.call('zadd', KEYS[2], ARGV[3], ARGV[1]);
+redis.call('hset', KEYS[3], ARGV[1], ARGV[2]);
+local expired = redis.call('zrangebyscore', KEYS[2], '-inf', '(' .. ARGV[4]);
+for i = 1, #expired do
+    redis.call('zrem', KEYS[2], expired[i]);
+    redis.call('hdel', KEYS[3], expired[i]);
+end;
+local minID;
+local minMs;
+local minSeq;
+for _, position in ipairs(redis.call('hvals', KEYS[3])) do
+    local dash = string.find(position, '-', 1, true);
+    local ms = tonumber(string.sub(position, 1, dash - 1));
+    local seq = tonumber(string.sub(position, dash + 1));
+    if minID == nil or ms < minMs or (ms == minMs and seq < minSeq) then
+        minID, minMs, minSeq = position, ms, seq;
+    end;
+end;
+if minID ~= nil then
+    redis.call('xtrim', KEYS[1], 'MINID', minID);
+end;
+return 1;
+`, []string{m.getRawName(), m.getSubscribersName(), m.getPositionsName()},
+		subscriberID, lastID, m.clock.Now().Add(m.subscriberTimeout).UnixMilli(), m.clock.Now().UnixMilli()).Err()
+}
+
+// CountSubscribers returns the number of subscribers registered.
+func (m *RedissonReliableTopic[T]) CountSubscribers() (int64, error) {
+	ctx, cancel := m.newContext()
+	defer cancel()
+	return m.client.ZCount(ctx, m.getSubscribersName(), strconv.FormatInt(m.clock.Now().UnixMilli(), 10), "+inf").Result()
+}
+
+// Size returns the number of messages kept for the subscribers.
+func (m *RedissonReliableTopic[T]) Size() (int64, error) {
+	ctx, cancel := m.newContext()
+	defer cancel()
+	return m.client.XLen(ctx, m.getRawName()).Result()
+}
+
+// Delete deletes the messages and the subscribers, and reports whether there were any.
+func (m *RedissonReliableTopic[T]) Delete() (bool, error) {
+	ctx, cancel := m.newContext()
+	defer cancel()
+	n, err := m.client.Del(ctx, m.getRawName(), m.getSubscribersName(), m.getPositionsName()).Result()
+	return n > 0, err
+}
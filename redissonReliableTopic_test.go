@@ -0,0 +1,124 @@
+package redisson
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+func TestReliableTopic(t *testing.T) {
+	topic := GetReliableTopic[string](GetRedisson(), "testReliableTopic")
+	if _, err := topic.Delete(); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := topic.Publish("dropped"); err != nil || n != 0 {
+		t.Fatalf("n=%v err=%v", n, err)
+	}
+
+	var mu sync.Mutex
+	var first, second []string
+	unsubscribe, err := topic.Subscribe(context.Background(), func(message string) {
+		mu.Lock()
+		defer mu.Unlock()
+		first = append(first, message)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	if _, err = topic.SubscribeAs(ctx, "second", func(message string) {
+		mu.Lock()
+		defer mu.Unlock()
+		second = append(second, message)
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := topic.Publish("a"); err != nil || n != 2 {
+		t.Fatalf("n=%v err=%v", n, err)
+	}
+	waitFor(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(first) == 1 && len(second) == 1
+	})
+
+	// the messages published while the second subscriber is down are kept for it
+	cancel()
+	time.Sleep(100 * time.Millisecond)
+	for _, message := range []string{"b", "c"} {
+		if _, err := topic.Publish(message); err != nil {
+			t.Fatal(err)
+		}
+	}
+	unsubscribeSecond, err := topic.SubscribeAs(context.Background(), "second", func(message string) {
+		mu.Lock()
+		defer mu.Unlock()
+		second = append(second, message)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(first) == 3 && len(second) == 3
+	})
+	mu.Lock()
+	if first[2] != "c" || second[1] != "b" || second[2] != "c" {
+		t.Fatalf("first=%v second=%v", first, second)
+	}
+	mu.Unlock()
+
+	// the messages read by all the subscribers are trimmed
+	waitFor(t, func() bool {
+		n, err := topic.Size()
+		return err == nil && n <= 1
+	})
+	if err = unsubscribe(); err != nil {
+		t.Fatal(err)
+	}
+	if err = unsubscribeSecond(); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := topic.CountSubscribers(); err != nil || n != 0 {
+		t.Fatalf("n=%v err=%v", n, err)
+	}
+}
+
+// TestReliableTopicIdle test an idle subscriber only reads, and renews its registration before it expires
+func TestReliableTopicIdle(t *testing.T) {
+	client := redis.NewClient(&redis.Options{Addr: redisAddr})
+	var commands atomic.Int64
+	client.AddHook(countingHook{count: &commands})
+	g := NewRedisson(client)
+	idle := GetReliableTopic[string](g, "testReliableTopicIdle")
+	renewed := GetReliableTopic[string](GetRedisson(), "testReliableTopicRenewed", WithSubscriberTimeout(1500*time.Millisecond))
+	for _, topic := range []RReliableTopic[string]{idle, renewed} {
+		if _, err := topic.Delete(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	unsubscribeIdle, err := idle.Subscribe(context.Background(), func(string) {})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer unsubscribeIdle()
+	unsubscribeRenewed, err := renewed.Subscribe(context.Background(), func(string) {})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer unsubscribeRenewed()
+	commands.Store(0)
+	time.Sleep(2500 * time.Millisecond)
+	// the reads of the first subscriber, which saves nothing
+	if n := commands.Load(); n > 3 {
+		t.Fatalf("%d commands while idle", n)
+	}
+	if n, err := renewed.CountSubscribers(); err != nil || n != 1 {
+		t.Fatalf("n=%v err=%v", n, err)
+	}
+}
@@ -0,0 +1,215 @@
+package redisson
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RRotatingBloomFilter is a Bloom filter over a sliding time window.
+// It is composed of several time-bucketed sub-filters, one per period,
+// each of which expires automatically once it falls out of the window.
+type RRotatingBloomFilter[T any] interface {
+	// TryInit initializes the parameters used by every sub-filter
+	// Returns true if the filter was initialized
+	// Returns false if the filter was already initialized
+	// Returns an error if expectedInsertions is not positive or falseProbability is not between 0 and 1
+	TryInit(expectedInsertions int64, falseProbability float64) (bool, error)
+
+	// Add adds an element to the sub-filter of the current period
+	// Returns true if element was added successfully
+	// Returns false if element is already present in the current period
+	Add(object T) (bool, error)
+
+	// Contains checks if an element was added within the window
+	// Returns true if any of the recent sub-filters contains the element
+	Contains(object T) (bool, error)
+
+	// GetPeriod returns the time span covered by one sub-filter
+	GetPeriod() time.Duration
+
+	// GetBuckets returns the number of sub-filters checked by Contains
+	GetBuckets() int
+
+	// Embedded interface for expiration functionality of the shared config,
+	// sub-filters expire on their own
+	RExpirable
+}
+
+var (
+	_ RRotatingBloomFilter[string] = (*RedissonRotatingBloomFilter[string])(nil)
+)
+
+// RedissonRotatingBloomFilter 实现 RRotatingBloomFilter 接口
+// 共享配置存放在 {name}:config 哈希中，子过滤器存放在 {name}:bucket:<编号> 键中
+type RedissonRotatingBloomFilter[T any] struct {
+	*RedissonExpirable
+	period         time.Duration // 每个子过滤器覆盖的时间段
+	buckets        int           // 窗口内子过滤器的个数
+	size           int64
+	hashIterations int
+	expiredBucket  int64 // 最近一次设置过期时间的桶编号
+}
+
+// NewRedissonRotatingBloomFilter 构造函数
+func NewRedissonRotatingBloomFilter[T any](redisson *Redisson, key string, period time.Duration, buckets int) *RedissonRotatingBloomFilter[T] {
+	if buckets < 1 {
+		buckets = 1
+	}
+	if period < time.Millisecond {
+		period = time.Millisecond
+	}
+	rbf := &RedissonRotatingBloomFilter[T]{
+		RedissonExpirable: newRedissonExpirable(key, redisson),
+		period:            period,
+		buckets:           buckets,
+		expiredBucket:     -1,
+	}
+	// Expire、ExpireAt 和 ClearExpire 同时作用于配置哈希和旧版本的 JSON 配置
+	rbf.componentKeys = rbf.configKeys
+	return rbf
+}
+
+// GetPeriod 返回单个子过滤器覆盖的时间段
+func (rbf *RedissonRotatingBloomFilter[T]) GetPeriod() time.Duration {
+	return rbf.period
+}
+
+// GetBuckets 返回窗口内子过滤器的个数
+func (rbf *RedissonRotatingBloomFilter[T]) GetBuckets() int {
+	return rbf.buckets
+}
+
+// TryInit 初始化子过滤器共享的参数，参数的校验和 {name}:config 哈希的布局与 RedissonBloomFilter 相同
+func (rbf *RedissonRotatingBloomFilter[T]) TryInit(expectedInsertions int64, falseProbability float64) (bool, error) {
+	ctx, cancel := rbf.newContext()
+	defer cancel()
+	rbf.mutex.Lock()
+	defer rbf.mutex.Unlock()
+
+	size, hashIterations := optimalBloomParameters(expectedInsertions, falseProbability)
+	config := BloomConfig{
+		ExpectedInsertions: expectedInsertions,
+		FalseProbability:   falseProbability,
+		Size:               size,
+		HashIterations:     hashIterations,
+	}
+	// 参数无效时脚本返回错误，已经初始化时返回生效的配置
+	res, err := rbf.eval(ctx, "rotatingBloomFilter.tryInit", bloomFilterTryInitScript, rbf.configKeys(),
+		append(config.fields(), bloomFilterMaxSize)...).Slice()
+	if err != nil {
+		return false, err
+	}
+	if len(res) > 1 {
+		winner, err := decodeBloomConfig(res[1])
+		if err != nil {
+			return false, err
+		}
+		rbf.size = winner.Size
+		rbf.hashIterations = winner.HashIterations
+		return false, nil
+	}
+	rbf.size = size
+	rbf.hashIterations = hashIterations
+	return true, nil
+}
+
+// Add 添加元素到当前时间段的子过滤器
+func (rbf *RedissonRotatingBloomFilter[T]) Add(object T) (bool, error) {
+	ctx, cancel := rbf.newContext()
+	defer cancel()
+	rbf.mutex.Lock()
+	defer rbf.mutex.Unlock()
+
+	if err := rbf.ensureConfig(); err != nil {
+		return false, err
+	}
+
+	current := rbf.currentBucket()
+	bf := rbf.bucketFilter(current)
+	added, err := bf.AddAll([]T{object})
+	if err != nil {
+		return false, err
+	}
+
+	// 每个桶只需设置一次过期时间：桶在滑出窗口时过期
+	if rbf.expiredBucket != current {
+		expireAt := time.UnixMilli((current + int64(rbf.buckets)) * rbf.period.Milliseconds())
+		if err = rbf.client.ExpireAt(ctx, bf.getRawName(), expireAt).Err(); err != nil {
+			return added > 0, fmt.Errorf("failed to set the expiration of bucket %d: %w", current, err)
+		}
+		rbf.expiredBucket = current
+	}
+	return added > 0, nil
+}
+
+// Contains 检查元素是否出现在窗口内任意子过滤器中
+func (rbf *RedissonRotatingBloomFilter[T]) Contains(object T) (bool, error) {
+	rbf.mutex.Lock()
+	defer rbf.mutex.Unlock()
+
+	if err := rbf.ensureConfig(); err != nil {
+		return false, err
+	}
+
+	current := rbf.currentBucket()
+	for i := 0; i < rbf.buckets; i++ {
+		found, err := rbf.bucketFilter(current - int64(i)).ContainsAny([]T{object})
+		if err != nil || found {
+			return found, err
+		}
+	}
+	return false, nil
+}
+
+// currentBucket 返回当前时间所在的桶编号
+func (rbf *RedissonRotatingBloomFilter[T]) currentBucket() int64 {
+	return rbf.clock.Now().UnixMilli() / rbf.period.Milliseconds()
+}
+
+// bucketName 返回指定桶的键名
+func (rbf *RedissonRotatingBloomFilter[T]) bucketName(bucket int64) string {
+	return rbf.suffixName(rbf.getRawName(), "bucket:"+strconv.FormatInt(bucket, 10))
+}
+
+// bucketFilter 返回指定桶的子过滤器，参数直接使用共享配置
+func (rbf *RedissonRotatingBloomFilter[T]) bucketFilter(bucket int64) *RedissonBloomFilter[T] {
+	bf := NewRedissonBloomFilter[T](rbf.Redisson, rbf.bucketName(bucket))
+	bf.size = rbf.size
+	bf.hashIterations = rbf.hashIterations
+	bf.verifyConfig = false
+	// 子过滤器按窗口自行过期，不使用默认 TTL
+	bf.ttl = 0
+	return bf
+}
+
+// configKeys 返回 {name}:config 哈希和旧版本存放在 name 键中的 JSON 配置
+func (rbf *RedissonRotatingBloomFilter[T]) configKeys() []string {
+	return []string{rbf.suffixName(rbf.getRawName(), "config"), rbf.getRawName()}
+}
+
+// ensureConfig 在本地参数缺失时从 Redis 读取共享配置
+func (rbf *RedissonRotatingBloomFilter[T]) ensureConfig() error {
+	ctx, cancel := rbf.newContext()
+	defer cancel()
+	if rbf.size != 0 && rbf.hashIterations != 0 {
+		return nil
+	}
+	res, err := rbf.eval(ctx, "rotatingBloomFilter.getConfig", bloomFilterGetConfigScript, rbf.configKeys()).Result()
+	if err != nil {
+		if err == redis.Nil {
+			err = errors.New("not initialized")
+		}
+		return fmt.Errorf("failed to get rotating Bloom filter config: %w", err)
+	}
+	config, err := decodeBloomConfig(res)
+	if err != nil {
+		return err
+	}
+	rbf.size = config.Size
+	rbf.hashIterations = config.HashIterations
+	return nil
+}
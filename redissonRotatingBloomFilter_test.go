@@ -0,0 +1,61 @@
+package redisson
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRotatingBloomFilter(t *testing.T) {
+	red := GetRedisson()
+	name := "rotating_bloom_filter" + time.Now().String()
+	bf := GetRotatingBloomFilter[string](red, name, time.Second, 2)
+	defer red.client.Del(context.Background(), "{"+name+"}:config")
+	if ok, err := bf.TryInit(1000, 0.01); err != nil || !ok {
+		t.Fatalf("rotating bloom filter should be initialized, got %v %v", ok, err)
+	}
+	if ok, err := bf.TryInit(1000, 0.01); err != nil || ok {
+		t.Fatalf("rotating bloom filter should already be initialized, got %v %v", ok, err)
+	}
+	// the config is stored in the same hash as the one of the Bloom filter
+	if typ, err := red.client.Type(context.Background(), "{"+name+"}:config").Result(); err != nil || typ != "hash" {
+		t.Fatalf("type=%v err=%v", typ, err)
+	}
+	if added, err := bf.Add("hello"); err != nil || !added {
+		t.Fatalf("hello should be added, got %v %v", added, err)
+	}
+	if found, err := bf.Contains("hello"); err != nil || !found {
+		t.Fatalf("hello should be contained, got %v %v", found, err)
+	}
+	if found, err := bf.Contains("world"); err != nil || found {
+		t.Fatalf("world should not be contained, got %v %v", found, err)
+	}
+
+	// the element leaves the window after buckets*period
+	time.Sleep(3 * time.Second)
+	if found, err := bf.Contains("hello"); err != nil || found {
+		t.Fatalf("hello should have left the window, got %v %v", found, err)
+	}
+
+	// the errors are returned instead of being reported as absent
+	uninitialized := GetRotatingBloomFilter[string](red, "rotating_bloom_filter_uninitialized"+time.Now().String(), time.Second, 2)
+	if _, err := uninitialized.Add("hello"); err == nil {
+		t.Fatal("an uninitialized filter should return an error")
+	}
+	if _, err := uninitialized.Contains("hello"); err == nil {
+		t.Fatal("an uninitialized filter should return an error")
+	}
+
+	// invalid parameters are rejected instead of making the filter divide by zero
+	for _, params := range []struct {
+		expectedInsertions int64
+		falseProbability   float64
+	}{{0, 0.01}, {-1, 0.01}, {1000, 0}, {1000, 1}, {1000, 1.5}} {
+		if ok, err := uninitialized.TryInit(params.expectedInsertions, params.falseProbability); err == nil || ok {
+			t.Fatalf("TryInit(%v, %v) should fail, got %v %v", params.expectedInsertions, params.falseProbability, ok, err)
+		}
+	}
+	if _, err := uninitialized.Add("hello"); err == nil {
+		t.Fatal("a filter whose TryInit failed should return an error")
+	}
+}
@@ -0,0 +1,236 @@
+package redisson
+
+import (
+	"context"
+	"errors"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RSet is a set of values of type T stored in a redis set. The values are members encoded with the codec
+// of the object, so the codec must encode equal values to the same bytes, as JSONCodec does.
+type RSet[T any] interface {
+	RExpirable
+
+	// Add adds value and reports whether it was not in the set.
+	Add(value T) (bool, error)
+
+	// AddAll adds values and returns the number of them which were not in the set.
+	AddAll(values ...T) (int64, error)
+
+	// Remove removes value and reports whether it was in the set.
+	Remove(value T) (bool, error)
+
+	// Contains reports whether value is in the set.
+	Contains(value T) (bool, error)
+
+	// Size returns the number of values in the set.
+	Size() (int64, error)
+
+	// RandomMember returns a random value of the set and whether the set has one, without removing it.
+	RandomMember() (T, bool, error)
+
+	// Pop removes and returns a random value of the set and whether the set had one.
+	Pop() (T, bool, error)
+
+	// Move moves value to the set named destination and reports whether it was in this set.
+	Move(destination string, value T) (bool, error)
+
+	// ReadAll returns all the values of the set.
+	ReadAll() ([]T, error)
+
+	// ForEach calls fn for the values of the set, scanned count at a time with SSCAN, until fn returns an error.
+	// A value added or removed during the iteration may be missed, and a value may be passed more than once.
+	ForEach(ctx context.Context, count int64, fn func(value T) error) error
+
+	// Delete deletes the set and reports whether it existed.
+	Delete() (bool, error)
+}
+
+var (
+	_ RSet[string] = (*RedissonSet[string])(nil)
+)
+
+// RedissonSet is the implementation of RSet
+type RedissonSet[T any] struct {
+	*RedissonExpirable
+	codec Codec
+}
+
+// newRedissonSet creates a new RedissonSet
+func newRedissonSet[T any](name string, redisson *Redisson, options *objectOptions) *RedissonSet[T] {
+	m := &RedissonSet[T]{
+		RedissonExpirable: newRedissonExpirable(name, redisson),
+		codec:             options.codec,
+	}
+	m.ttl = options.ttl
+	return m
+}
+
+// Add adds value and reports whether it was not in the set.
+func (m *RedissonSet[T]) Add(value T) (bool, error) {
+	n, err := m.AddAll(value)
+	return n == 1, err
+}
+
+// AddAll adds values and returns the number of them which were not in the set.
+func (m *RedissonSet[T]) AddAll(values ...T) (int64, error) {
+	if len(values) == 0 {
+		return 0, nil
+	}
+	members, err := m.encodeAll(values)
+	if err != nil {
+		return 0, err
+	}
+	ctx, cancel := m.newContext()
+	defer cancel()
+	n, err := m.client.SAdd(ctx, m.getRawName(), members...).Result()
+	if err != nil {
+		return 0, err
+	}
+	if err = m.applyTTL(ctx, m.getRawName()); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+// Remove removes value and reports whether it was in the set.
+func (m *RedissonSet[T]) Remove(value T) (bool, error) {
+	member, err := m.codec.Encode(value)
+	if err != nil {
+		return false, err
+	}
+	ctx, cancel := m.newContext()
+	defer cancel()
+	n, err := m.client.SRem(ctx, m.getRawName(), member).Result()
+	return n == 1, err
+}
+
+// Contains reports whether value is in the set.
+func (m *RedissonSet[T]) Contains(value T) (bool, error) {
+	member, err := m.codec.Encode(value)
+	if err != nil {
+		return false, err
+	}
+	ctx, cancel := m.newContext()
+	defer cancel()
+	return m.client.SIsMember(ctx, m.getRawName(), member).Result()
+}
+
+// Size returns the number of values in the set.
+func (m *RedissonSet[T]) Size() (int64, error) {
+	ctx, cancel := m.newContext()
+	defer cancel()
+	return m.client.SCard(ctx, m.getRawName()).Result()
+}
+
+// RandomMember returns a random value of the set and whether the set has one, without removing it.
+func (m *RedissonSet[T]) RandomMember() (T, bool, error) {
+	ctx, cancel := m.newContext()
+	defer cancel()
+	return m.decodeReply(m.client.SRandMember(ctx, m.getRawName()).Bytes())
+}
+
+// Pop removes and returns a random value of the set and whether the set had one.
+func (m *RedissonSet[T]) Pop() (T, bool, error) {
+	ctx, cancel := m.newContext()
+	defer cancel()
+	return m.decodeReply(m.client.SPop(ctx, m.getRawName()).Bytes())
+}
+
+// Move moves value to the set named destination and reports whether it was in this set.
+// The two sets must be in the same slot in a cluster, e.g. named with the same hash tag.
+func (m *RedissonSet[T]) Move(destination string, value T) (bool, error) {
+	member, err := m.codec.Encode(value)
+	if err != nil {
+		return false, err
+	}
+	ctx, cancel := m.newContext()
+	defer cancel()
+	return m.client.SMove(ctx, m.getRawName(), destination, member).Result()
+}
+
+// ReadAll returns all the values of the set.
+func (m *RedissonSet[T]) ReadAll() ([]T, error) {
+	ctx, cancel := m.newContext()
+	defer cancel()
+	members, err := m.client.SMembers(ctx, m.getRawName()).Result()
+	if err != nil {
+		return nil, err
+	}
+	return m.decodeAll(members)
+}
+
+// ForEach calls fn for the values of the set, scanned count at a time with SSCAN, until fn returns an error.
+func (m *RedissonSet[T]) ForEach(ctx context.Context, count int64, fn func(value T) error) error {
+	if count <= 0 {
+		return errors.New("count must be greater than 0")
+	}
+	var cursor uint64
+	for {
+		scanCtx, cancel := m.withCommandTimeout(ctx)
+		members, next, err := m.client.SScan(scanCtx, m.getRawName(), cursor, "", count).Result()
+		cancel()
+		if err != nil {
+			return err
+		}
+		values, err := m.decodeAll(members)
+		if err != nil {
+			return err
+		}
+		for _, v := range values {
+			if err = fn(v); err != nil {
+				return err
+			}
+		}
+		if next == 0 {
+			return nil
+		}
+		cursor = next
+	}
+}
+
+// Delete deletes the set and reports whether it existed.
+func (m *RedissonSet[T]) Delete() (bool, error) {
+	ctx, cancel := m.newContext()
+	defer cancel()
+	n, err := m.client.Del(ctx, m.getRawName()).Result()
+	return n == 1, err
+}
+
+// encodeAll encodes values with the codec of the set
+func (m *RedissonSet[T]) encodeAll(values []T) ([]interface{}, error) {
+	members := make([]interface{}, len(values))
+	for i, v := range values {
+		data, err := m.codec.Encode(v)
+		if err != nil {
+			return nil, err
+		}
+		members[i] = data
+	}
+	return members, nil
+}
+
+// decodeAll decodes members with the codec of the set
+func (m *RedissonSet[T]) decodeAll(members []string) ([]T, error) {
+	values := make([]T, len(members))
+	for i, member := range members {
+		if err := m.codec.Decode([]byte(member), &values[i]); err != nil {
+			return nil, err
+		}
+	}
+	return values, nil
+}
+
+// decodeReply decodes the member replied, if any
+func (m *RedissonSet[T]) decodeReply(member []byte, err error) (T, bool, error) {
+	var v T
+	if err == redis.Nil {
+		return v, false, nil
+	}
+	if err != nil {
+		return v, false, err
+	}
+	err = m.codec.Decode(member, &v)
+	return v, err == nil, err
+}
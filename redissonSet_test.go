@@ -0,0 +1,85 @@
+package redisson
+
+import (
+	"context"
+	"sort"
+	"testing"
+)
+
+func TestSet(t *testing.T) {
+	s := GetSet[User](GetRedisson(), "{testSet}:a")
+	if _, err := s.Delete(); err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := s.Add(User{ID: 1, Name: "Alice"}); err != nil || !ok {
+		t.Fatalf("ok=%v err=%v", ok, err)
+	}
+	if ok, err := s.Add(User{ID: 1, Name: "Alice"}); err != nil || ok {
+		t.Fatalf("ok=%v err=%v", ok, err)
+	}
+	if n, err := s.AddAll(User{ID: 2, Name: "Bob"}, User{ID: 3, Name: "Carol"}, User{ID: 1, Name: "Alice"}); err != nil || n != 2 {
+		t.Fatalf("n=%v err=%v", n, err)
+	}
+	if ok, err := s.Contains(User{ID: 2, Name: "Bob"}); err != nil || !ok {
+		t.Fatalf("ok=%v err=%v", ok, err)
+	}
+	if n, err := s.Size(); err != nil || n != 3 {
+		t.Fatalf("n=%v err=%v", n, err)
+	}
+	if v, ok, err := s.RandomMember(); err != nil || !ok || v.ID == 0 {
+		t.Fatalf("v=%v ok=%v err=%v", v, ok, err)
+	}
+	if ok, err := s.Remove(User{ID: 3, Name: "Carol"}); err != nil || !ok {
+		t.Fatalf("ok=%v err=%v", ok, err)
+	}
+
+	other := GetSet[User](GetRedisson(), "{testSet}:b")
+	if _, err := other.Delete(); err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := s.Move("{testSet}:b", User{ID: 2, Name: "Bob"}); err != nil || !ok {
+		t.Fatalf("ok=%v err=%v", ok, err)
+	}
+	if values, err := other.ReadAll(); err != nil || len(values) != 1 || values[0].Name != "Bob" {
+		t.Fatalf("values=%v err=%v", values, err)
+	}
+
+	if v, ok, err := s.Pop(); err != nil || !ok || v.Name != "Alice" {
+		t.Fatalf("v=%v ok=%v err=%v", v, ok, err)
+	}
+	if _, ok, err := s.Pop(); err != nil || ok {
+		t.Fatalf("ok=%v err=%v", ok, err)
+	}
+}
+
+func TestSetForEach(t *testing.T) {
+	s := GetSet[int](GetRedisson(), "testSetForEach")
+	if _, err := s.Delete(); err != nil {
+		t.Fatal(err)
+	}
+	values := make([]int, 500)
+	for i := range values {
+		values[i] = i
+	}
+	if _, err := s.AddAll(values...); err != nil {
+		t.Fatal(err)
+	}
+	seen := make(map[int]bool)
+	if err := s.ForEach(context.Background(), 50, func(v int) error {
+		seen[v] = true
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if len(seen) != 500 {
+		t.Fatalf("len=%v", len(seen))
+	}
+	all, err := s.ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Ints(all)
+	if len(all) != 500 || all[0] != 0 || all[499] != 499 {
+		t.Fatalf("all=%v", all)
+	}
+}
@@ -0,0 +1,78 @@
+package redisson
+
+import (
+	"context"
+	"log"
+)
+
+// RShardedTopic publishes and receives messages of type T over the sharded Pub/Sub of redis 7, like the
+// RShardedTopic of Java Redisson. In a cluster, a message is only propagated within the shard owning the slot
+// of the topic name instead of across the whole cluster bus, so the client must be connected to that shard.
+type RShardedTopic[T any] interface {
+	// Publish publishes the message and returns the number of clients of the shard that received it.
+	Publish(message T) (int64, error)
+
+	// Subscribe calls handler for every message received until ctx is done or unsubscribe is called.
+	Subscribe(ctx context.Context, handler func(message T)) (unsubscribe func() error, err error)
+}
+
+var (
+	_ RShardedTopic[string] = (*RedissonShardedTopic[string])(nil)
+)
+
+// RedissonShardedTopic is the implementation of RShardedTopic
+type RedissonShardedTopic[T any] struct {
+	*RedissonObject
+	codec Codec
+}
+
+// newRedissonShardedTopic creates a new RedissonShardedTopic
+func newRedissonShardedTopic[T any](name string, redisson *Redisson, options *objectOptions) *RedissonShardedTopic[T] {
+	return &RedissonShardedTopic[T]{
+		RedissonObject: newRedissonObject(name, redisson),
+		codec:          options.codec,
+	}
+}
+
+// Publish publishes the message and returns the number of clients of the shard that received it.
+func (m *RedissonShardedTopic[T]) Publish(message T) (int64, error) {
+	ctx, cancel := m.newContext()
+	defer cancel()
+	data, err := m.codec.Encode(message)
+	if err != nil {
+		return 0, err
+	}
+	return m.client.SPublish(ctx, m.getRawName(), data).Result()
+}
+
+// Subscribe calls handler for every message received until ctx is done or unsubscribe is called.
+// Messages which cannot be decoded are logged and dropped.
+func (m *RedissonShardedTopic[T]) Subscribe(ctx context.Context, handler func(message T)) (func() error, error) {
+	sub := m.client.SSubscribe(ctx, m.getRawName())
+	// wait for the subscription to be confirmed so no message published afterwards is missed
+	if _, err := sub.Receive(ctx); err != nil {
+		sub.Close()
+		return nil, err
+	}
+	go func() {
+		ch := sub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				sub.Close()
+				return
+			case msg, ok := <-ch:
+				if !ok {
+					return
+				}
+				var v T
+				if err := m.codec.Decode([]byte(msg.Payload), &v); err != nil {
+					log.Printf("sharded topic %s: failed to decode message: %v", m.getRawName(), err)
+					continue
+				}
+				handler(v)
+			}
+		}
+	}()
+	return sub.Close, nil
+}
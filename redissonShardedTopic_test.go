@@ -0,0 +1,39 @@
+package redisson
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestShardedTopic(t *testing.T) {
+	g := GetRedisson()
+	if ok, err := g.hasCommand(context.Background(), "SPUBLISH"); err != nil {
+		t.Fatal(err)
+	} else if !ok {
+		t.Skip("sharded Pub/Sub requires redis 7")
+	}
+	topic := GetShardedTopic[User](g, "testShardedTopic")
+	received := make(chan User, 1)
+	unsubscribe, err := topic.Subscribe(context.Background(), func(u User) {
+		received <- u
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer unsubscribe()
+
+	if n, err := topic.Publish(User{ID: 1, Name: "Alice"}); err != nil {
+		t.Fatal(err)
+	} else if n != 1 {
+		t.Fatalf("n=%v", n)
+	}
+	select {
+	case u := <-received:
+		if u.Name != "Alice" {
+			t.Fatalf("u=%v", u)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("message not received")
+	}
+}
@@ -0,0 +1,264 @@
+package redisson
+
+import (
+	"context"
+	"errors"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RSortedSet is a set of values of type T kept in the order of a comparator defined by the client, like the
+// RSortedSet of Java Redisson. The values are stored encoded in a redis list in order, the writers insert and
+// remove them under a lock, and two values the comparator finds equal are the same value of the set.
+// All the instances must use the same comparator, and the list must not be written otherwise.
+type RSortedSet[T any] interface {
+	RExpirable
+
+	// Add inserts value at its position and reports whether it was not in the set.
+	Add(value T) (bool, error)
+
+	// Remove removes value and reports whether it was in the set.
+	Remove(value T) (bool, error)
+
+	// Contains reports whether value is in the set. It searches under the lock of the writers, since a write
+	// between the probes of the search shifts the values.
+	Contains(value T) (bool, error)
+
+	// Size returns the number of values in the set.
+	Size() (int64, error)
+
+	// First returns the first value of the set and whether the set has one.
+	First() (T, bool, error)
+
+	// Last returns the last value of the set and whether the set has one.
+	Last() (T, bool, error)
+
+	// ReadAll returns all the values of the set in order.
+	ReadAll() ([]T, error)
+
+	// ForEach calls fn for the values of the set in order, read count at a time, until fn returns an error.
+	// A value added or removed during the iteration may be missed or passed twice.
+	ForEach(ctx context.Context, count int64, fn func(value T) error) error
+
+	// Delete deletes the set and reports whether it existed.
+	Delete() (bool, error)
+}
+
+var (
+	_ RSortedSet[string] = (*RedissonSortedSet[string])(nil)
+)
+
+// RedissonSortedSet is the implementation of RSortedSet
+// the values are stored in the name list, and the writers hold the redisson_sortedset_lock:{name} lock
+type RedissonSortedSet[T any] struct {
+	*RedissonExpirable
+	codec Codec
+	//compare returns a negative number, 0 or a positive number when a is less than, equal to or greater than b
+	compare func(a, b T) int
+}
+
+// newRedissonSortedSet creates a new RedissonSortedSet
+func newRedissonSortedSet[T any](name string, redisson *Redisson, compare func(a, b T) int, options *objectOptions) *RedissonSortedSet[T] {
+	m := &RedissonSortedSet[T]{
+		RedissonExpirable: newRedissonExpirable(name, redisson),
+		codec:             options.codec,
+		compare:           compare,
+	}
+	m.ttl = options.ttl
+	return m
+}
+
+// getLockName returns the name of the lock of the writers
+func (m *RedissonSortedSet[T]) getLockName() string {
+	return m.prefixName("redisson_sortedset_lock", m.getRawName())
+}
+
+// Add inserts value at its position and reports whether it was not in the set.
+func (m *RedissonSortedSet[T]) Add(value T) (bool, error) {
+	data, err := m.codec.Encode(value)
+	if err != nil {
+		return false, err
+	}
+	lock := m.GetLock(m.getLockName())
+	if err = lock.Lock(); err != nil {
+		return false, err
+	}
+	defer lock.Unlock()
+
+	ctx, cancel := m.newContext()
+	defer cancel()
+	index, _, found, err := m.binarySearch(ctx, value)
+	if err != nil || found {
+		return false, err
+	}
+	err = m.eval(ctx, "sortedSet.add", `
+local pivot = redis.call('lindex', KEYS[1], ARGV[1]);
+if pivot == false then
+    redis.call('rpush', KEYS[1], ARGV[2]);
+else
+    redis.call('linsert', KEYS[1], 'before', pivot, ARGV[2]);
+end;
+`, []string{m.getRawName()}, index, data).Err()
+	if err != nil && err != redis.Nil {
+		return false, err
+	}
+	if err = m.applyTTL(ctx, m.getRawName()); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Remove removes value and reports whether it was in the set.
+func (m *RedissonSortedSet[T]) Remove(value T) (bool, error) {
+	lock := m.GetLock(m.getLockName())
+	if err := lock.Lock(); err != nil {
+		return false, err
+	}
+	defer lock.Unlock()
+
+	ctx, cancel := m.newContext()
+	defer cancel()
+	_, stored, found, err := m.binarySearch(ctx, value)
+	if err != nil || !found {
+		return false, err
+	}
+	n, err := m.client.LRem(ctx, m.getRawName(), 1, stored).Result()
+	if err != nil || n == 0 {
+		return false, err
+	}
+	return true, m.applyTTL(ctx, m.getRawName())
+}
+
+// Contains reports whether value is in the set.
+func (m *RedissonSortedSet[T]) Contains(value T) (bool, error) {
+	lock := m.GetLock(m.getLockName())
+	if err := lock.Lock(); err != nil {
+		return false, err
+	}
+	defer lock.Unlock()
+
+	ctx, cancel := m.newContext()
+	defer cancel()
+	_, _, found, err := m.binarySearch(ctx, value)
+	return found, err
+}
+
+// Size returns the number of values in the set.
+func (m *RedissonSortedSet[T]) Size() (int64, error) {
+	ctx, cancel := m.newContext()
+	defer cancel()
+	return m.client.LLen(ctx, m.getRawName()).Result()
+}
+
+// First returns the first value of the set and whether the set has one.
+func (m *RedissonSortedSet[T]) First() (T, bool, error) {
+	return m.index(0)
+}
+
+// Last returns the last value of the set and whether the set has one.
+func (m *RedissonSortedSet[T]) Last() (T, bool, error) {
+	return m.index(-1)
+}
+
+// index returns the value at index and whether there is one
+func (m *RedissonSortedSet[T]) index(index int64) (T, bool, error) {
+	ctx, cancel := m.newContext()
+	defer cancel()
+	var v T
+	data, err := m.client.LIndex(ctx, m.getRawName(), index).Bytes()
+	if err == redis.Nil {
+		return v, false, nil
+	}
+	if err != nil {
+		return v, false, err
+	}
+	err = m.codec.Decode(data, &v)
+	return v, err == nil, err
+}
+
+// ReadAll returns all the values of the set in order.
+func (m *RedissonSortedSet[T]) ReadAll() ([]T, error) {
+	ctx, cancel := m.newContext()
+	defer cancel()
+	items, err := m.client.LRange(ctx, m.getRawName(), 0, -1).Result()
+	if err != nil {
+		return nil, err
+	}
+	return m.decodeAll(items)
+}
+
+// ForEach calls fn for the values of the set in order, read count at a time, until fn returns an error.
+func (m *RedissonSortedSet[T]) ForEach(ctx context.Context, count int64, fn func(value T) error) error {
+	if count <= 0 {
+		return errors.New("count must be greater than 0")
+	}
+	for start := int64(0); ; start += count {
+		rangeCtx, cancel := m.withCommandTimeout(ctx)
+		items, err := m.client.LRange(rangeCtx, m.getRawName(), start, start+count-1).Result()
+		cancel()
+		if err != nil {
+			return err
+		}
+		values, err := m.decodeAll(items)
+		if err != nil {
+			return err
+		}
+		for _, v := range values {
+			if err = fn(v); err != nil {
+				return err
+			}
+		}
+		if int64(len(items)) < count {
+			return nil
+		}
+	}
+}
+
+// Delete deletes the set and reports whether it existed.
+func (m *RedissonSortedSet[T]) Delete() (bool, error) {
+	ctx, cancel := m.newContext()
+	defer cancel()
+	n, err := m.client.Del(ctx, m.getRawName()).Result()
+	return n == 1, err
+}
+
+// binarySearch looks value up with LINDEX. It returns the index of the value and its stored bytes if found,
+// or else the index it is inserted at.
+func (m *RedissonSortedSet[T]) binarySearch(ctx context.Context, value T) (int64, []byte, bool, error) {
+	size, err := m.client.LLen(ctx, m.getRawName()).Result()
+	if err != nil {
+		return 0, nil, false, err
+	}
+	low, high := int64(0), size-1
+	for low <= high {
+		mid := low + (high-low)/2
+		data, err := m.client.LIndex(ctx, m.getRawName(), mid).Bytes()
+		if err != nil {
+			return 0, nil, false, err
+		}
+		var v T
+		if err = m.codec.Decode(data, &v); err != nil {
+			return 0, nil, false, err
+		}
+		switch c := m.compare(v, value); {
+		case c < 0:
+			low = mid + 1
+		case c > 0:
+			high = mid - 1
+		default:
+			return mid, data, true, nil
+		}
+	}
+	return low, nil, false, nil
+}
+
+// decodeAll decodes items with the codec of the set
+func (m *RedissonSortedSet[T]) decodeAll(items []string) ([]T, error) {
+	values := make([]T, len(items))
+	for i, item := range items {
+		if err := m.codec.Decode([]byte(item), &values[i]); err != nil {
+			return nil, err
+		}
+	}
+	return values, nil
+}
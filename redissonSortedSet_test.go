@@ -0,0 +1,124 @@
+package redisson
+
+import (
+	"cmp"
+	"context"
+	"math/rand"
+	"sync"
+	"testing"
+)
+
+func TestSortedSet(t *testing.T) {
+	byName := func(a, b User) int {
+		return cmp.Compare(a.Name, b.Name)
+	}
+	s := GetSortedSet[User](GetRedisson(), "testSortedSet", byName)
+	if _, err := s.Delete(); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"Dave", "Alice", "Carol", "Bob"} {
+		if ok, err := s.Add(User{Name: name}); err != nil || !ok {
+			t.Fatalf("ok=%v err=%v", ok, err)
+		}
+	}
+	// equal for the comparator
+	if ok, err := s.Add(User{ID: 1, Name: "Bob"}); err != nil || ok {
+		t.Fatalf("ok=%v err=%v", ok, err)
+	}
+	if values, err := s.ReadAll(); err != nil || len(values) != 4 || values[0].Name != "Alice" || values[3].Name != "Dave" {
+		t.Fatalf("values=%v err=%v", values, err)
+	}
+	if ok, err := s.Contains(User{Name: "Carol"}); err != nil || !ok {
+		t.Fatalf("ok=%v err=%v", ok, err)
+	}
+	if ok, err := s.Remove(User{Name: "Alice"}); err != nil || !ok {
+		t.Fatalf("ok=%v err=%v", ok, err)
+	}
+	if ok, err := s.Remove(User{Name: "Alice"}); err != nil || ok {
+		t.Fatalf("ok=%v err=%v", ok, err)
+	}
+	if v, ok, err := s.First(); err != nil || !ok || v.Name != "Bob" {
+		t.Fatalf("v=%v ok=%v err=%v", v, ok, err)
+	}
+	if v, ok, err := s.Last(); err != nil || !ok || v.Name != "Dave" {
+		t.Fatalf("v=%v ok=%v err=%v", v, ok, err)
+	}
+}
+
+func TestSortedSetConcurrentAdd(t *testing.T) {
+	s := GetSortedSet[int](GetRedisson(), "testSortedSetConcurrent", cmp.Compare[int])
+	if _, err := s.Delete(); err != nil {
+		t.Fatal(err)
+	}
+	values := rand.Perm(100)
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(part []int) {
+			defer wg.Done()
+			for _, v := range part {
+				if _, err := s.Add(v); err != nil {
+					t.Error(err)
+				}
+			}
+		}(values[i*25 : (i+1)*25])
+	}
+	wg.Wait()
+	expected := 0
+	if err := s.ForEach(context.Background(), 30, func(v int) error {
+		if v != expected {
+			t.Fatalf("v=%v expected %v", v, expected)
+		}
+		expected++
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if expected != 100 {
+		t.Fatalf("expected=%v", expected)
+	}
+}
+
+// TestSortedSetContainsConcurrentWrites test Contains finds a value while other values are added and removed
+func TestSortedSetContainsConcurrentWrites(t *testing.T) {
+	s := GetSortedSet[int](GetRedisson(), "testSortedSetContains", cmp.Compare[int])
+	if _, err := s.Delete(); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 20; i++ {
+		if _, err := s.Add(i * 10); err != nil {
+			t.Fatal(err)
+		}
+	}
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; ; i++ {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			// the values before 150 shift its index
+			if _, err := s.Add(i%15*10 + 5); err != nil {
+				t.Error(err)
+				return
+			}
+			if _, err := s.Remove(i%15*10 + 5); err != nil {
+				t.Error(err)
+				return
+			}
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		if found, err := s.Contains(150); err != nil || !found {
+			close(done)
+			wg.Wait()
+			t.Fatalf("found=%v err=%v", found, err)
+		}
+	}
+	close(done)
+	wg.Wait()
+}
@@ -0,0 +1,140 @@
+package redisson
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var (
+	// check RedissonSpinLock implements Lock
+	_ Lock = (*RedissonSpinLock)(nil)
+)
+
+const (
+	// spinLockInitialDelay is the delay before the first retry of a spin lock
+	spinLockInitialDelay = time.Millisecond
+	// spinLockMaxDelay is the maximum delay between the retries of a spin lock
+	spinLockMaxDelay = 128 * time.Millisecond
+)
+
+// RedissonSpinLock is a reentrant distributed lock whose waiters retry with exponential backoff
+// instead of waiting for unlock notifications over Pub/Sub. It avoids the cost of Pub/Sub for short
+// critical sections and works behind proxies which do not support Pub/Sub.
+type RedissonSpinLock struct {
+	RedissonLock
+}
+
+// newRedisSpinLock creates a new RedissonSpinLock
+func newRedisSpinLock(name string, Redisson *Redisson, opts ...ObjectOption) Lock {
+	spinLock := &RedissonSpinLock{}
+	spinLock.RedissonBaseLock = *newBaseLock(Redisson.id, name, Redisson, spinLock, opts...)
+	return spinLock
+}
+
+// unlockInner releases the lock, nobody waits for a notification
+func (m *RedissonSpinLock) unlockInner(ctx context.Context, goroutineId uint64) (*int64, error) {
+	defer m.cancelExpirationRenewal(goroutineId)
+	result, err := m.eval(ctx, "spinLock.unlock", `
+if (redis.call('hexists', KEYS[1], ARGV[2]) == 0) then
+    return nil;
+end ;
+local counter = redis.call('hincrby', KEYS[1], ARGV[2], -1);
+if (counter > 0) then
+    redis.call('pexpire', KEYS[1], ARGV[1]);
+    return 0;
+else
+    redis.call('del', KEYS[1]);
+    return 1;
+end ;
+return nil;
+`, []string{m.getRawName()}, m.getLockLeaseTime().Milliseconds(), m.getLockName(goroutineId)).Int64()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &result, err
+}
+
+// Lock locks m. Lock returns when locking is successful or when an exception is encountered.
+// it blocks until the lock is obtained
+func (m *RedissonSpinLock) Lock() error {
+	return m.lockContext(m.baseContext(), 0)
+}
+
+// LockWithLease locks m for leaseTime, after which the lock expires instead of being renewed by the watchdog.
+// it blocks until the lock is obtained
+func (m *RedissonSpinLock) LockWithLease(leaseTime time.Duration) error {
+	return m.lockContext(m.baseContext(), leaseTime)
+}
+
+// LockContext locks m. Lock Returns when locking is successful or when the context timeout or an exception is encountered.
+func (m *RedissonSpinLock) LockContext(ctx context.Context) error {
+	return m.lockContext(ctx, 0)
+}
+
+// TryLockWithTimeout waits up to waitTime for m and reports whether it was locked.
+// A positive leaseTime makes the lock expire after leaseTime instead of being renewed by the watchdog.
+func (m *RedissonSpinLock) TryLockWithTimeout(waitTime, leaseTime time.Duration) (bool, error) {
+	return m.tryLockWithTimeout(m.lockContext, waitTime, leaseTime)
+}
+
+// LockContextWithOwner locks m on behalf of owner instead of the calling goroutine.
+func (m *RedissonSpinLock) LockContextWithOwner(ctx context.Context, owner string) error {
+	return m.lockContextAs(ctx, getOwnerId(owner), 0)
+}
+
+// LockAsync locks m for the calling goroutine in the background and sends the result on the returned channel.
+func (m *RedissonSpinLock) LockAsync(ctx context.Context) <-chan error {
+	return runAsync(func(goroutineId uint64) error {
+		return m.lockAbandonable(ctx, goroutineId, m.lockContextAs)
+	})
+}
+
+// lockContext retries to lock m with exponential backoff until it is locked or ctx is done
+func (m *RedissonSpinLock) lockContext(ctx context.Context, leaseTime time.Duration) error {
+	goroutineId, err := getId()
+	if err != nil {
+		return err
+	}
+	return m.lockContextAs(ctx, goroutineId, leaseTime)
+}
+
+// lockContextAs retries to lock m for goroutineId with exponential backoff until it is locked or ctx is done
+func (m *RedissonSpinLock) lockContextAs(ctx context.Context, goroutineId uint64, leaseTime time.Duration) error {
+	// an attempt cancelled by ctx may still acquire the lock without the watchdog renewing it
+	attemptCtx := context.WithoutCancel(ctx)
+	m.addWaiter(m.getRawName(), 1)
+	defer m.addWaiter(m.getRawName(), -1)
+	start := m.clock.Now()
+	delay := spinLockInitialDelay
+	for retries := 0; ; retries++ {
+		ttl, err := m.tryAcquire(attemptCtx, goroutineId, leaseTime)
+		if err != nil {
+			return err
+		}
+		// lock acquired
+		if ttl == nil {
+			m.recordAcquired(goroutineId, m.clock.Now().Sub(start), retries)
+			return nil
+		}
+		// jitter keeps the waiters of a lock from retrying in lockstep
+		wait := delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+		// there is no point in waiting longer than the lock lives
+		if remain := time.Duration(*ttl) * time.Millisecond; remain >= 0 && remain < wait {
+			wait = remain
+		}
+		select {
+		case <-ctx.Done():
+			return ErrObtainLockTimeout
+		case <-m.clock.After(wait):
+		}
+		if delay *= 2; delay > spinLockMaxDelay {
+			delay = spinLockMaxDelay
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package redisson
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSpinLock(t *testing.T) {
+	g := GetRedisson()
+	counter := 0
+	wg := sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			l := g.GetSpinLock("TestSpinLock")
+			for j := 0; j < 10; j++ {
+				if err := l.Lock(); err != nil {
+					panic(err)
+				}
+				// reentrant
+				if err := l.Lock(); err != nil {
+					panic(err)
+				}
+				counter++
+				if err := l.Unlock(); err != nil {
+					panic(err)
+				}
+				if err := l.Unlock(); err != nil {
+					panic(err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if counter != 100 {
+		t.Fatalf("counter=%v", counter)
+	}
+}
+
+func TestSpinLockTimeout(t *testing.T) {
+	g := GetRedisson()
+	if err := g.GetSpinLock("TestSpinLockTimeout").LockWithLease(time.Second); err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan error)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		done <- g.GetSpinLock("TestSpinLockTimeout").LockContext(ctx)
+	}()
+	if err := <-done; err != ErrObtainLockTimeout {
+		t.Fatalf("err=%v", err)
+	}
+	// the waiter gets the lock once the lease expires
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		done <- g.GetSpinLock("TestSpinLockTimeout").LockContext(ctx)
+	}()
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,446 @@
+package redisson
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// streamValueField is the field of the stream entries holding the encoded value
+const streamValueField = "value"
+
+// StreamMessage is an entry of RStream
+type StreamMessage[T any] struct {
+	// ID is the id of the entry, e.g. 1700000000000-0
+	ID string
+	// Value is the value of the entry
+	Value T
+}
+
+// StreamPendingEntry is an entry read by a consumer of a group which was not acked yet
+type StreamPendingEntry struct {
+	// ID is the id of the entry
+	ID string
+	// Consumer is the consumer the entry was last delivered to
+	Consumer string
+	// Idle is the time elapsed since the entry was last delivered
+	Idle time.Duration
+	// RetryCount is the number of times the entry was delivered
+	RetryCount int64
+}
+
+// StreamGroup describes a consumer group of RStream
+type StreamGroup struct {
+	// Name is the name of the group
+	Name string
+	// Consumers is the number of consumers of the group
+	Consumers int64
+	// Pending is the number of entries delivered to the group which were not acked yet
+	Pending int64
+	// LastDeliveredID is the id of the last entry delivered to the group
+	LastDeliveredID string
+}
+
+// RStream is a redis stream of values of type T, each entry holding one value encoded in its value field,
+// with consumer groups for at-least-once messaging, like the RStream of Java Redisson.
+type RStream[T any] interface {
+	RExpirable
+
+	// Add appends value to the stream and returns the id of its entry.
+	Add(value T) (string, error)
+
+	// Range returns at most count entries with an id between start and end, inclusive, "-" and "+" being the
+	// smallest and the greatest ids. A count of 0 returns all of them.
+	Range(start, end string, count int64) ([]StreamMessage[T], error)
+
+	// Remove removes the entries with ids and returns the number of them which existed.
+	Remove(ids ...string) (int64, error)
+
+	// Size returns the number of entries in the stream.
+	Size() (int64, error)
+
+	// Trim removes the oldest entries to keep at most maxLen, and returns the number of them removed.
+	Trim(maxLen int64) (int64, error)
+
+	// TrimMinID removes the entries with an id smaller than minID, and returns the number of them removed.
+	TrimMinID(minID string) (int64, error)
+
+	// CreateGroup creates the consumer group, which is delivered the entries after startID, "$" for the entries
+	// added from now on and "0" for all of them. It creates the stream if needed, and reports false if the group
+	// already existed.
+	CreateGroup(group, startID string) (bool, error)
+
+	// RemoveGroup removes the consumer group and reports whether it existed.
+	RemoveGroup(group string) (bool, error)
+
+	// CreateConsumer creates a consumer in the group and reports whether it did not exist.
+	// A consumer is also created by reading the group.
+	CreateConsumer(group, consumer string) (bool, error)
+
+	// RemoveConsumer removes a consumer from the group and returns the number of entries it had pending,
+	// which are no longer pending.
+	RemoveConsumer(group, consumer string) (int64, error)
+
+	// ListGroups returns the consumer groups of the stream.
+	ListGroups() ([]StreamGroup, error)
+
+	// ReadGroupBatch reads at most count entries which were never delivered to the group, for consumer,
+	// waiting up to block for one if there are none. A block of 0 does not wait.
+	ReadGroupBatch(group, consumer string, count int64, block time.Duration) ([]StreamMessage[T], error)
+
+	// ReadGroup returns an iterator over the entries of the group for consumer, until ctx is done.
+	// It first goes over the entries pending for consumer, e.g. read before a restart and never acked, then
+	// waits for the entries never delivered to the group. The entries must be acked with Ack once processed.
+	ReadGroup(ctx context.Context, group, consumer string, count int64) *StreamGroupIterator[T]
+
+	// Ack acknowledges the entries with ids for the group and returns the number of them which were pending.
+	Ack(group string, ids ...string) (int64, error)
+
+	// Pending returns at most count entries pending in the group, oldest first.
+	Pending(group string, count int64) ([]StreamPendingEntry, error)
+
+	// Claim transfers the entries with ids pending in the group for at least minIdle to consumer, and returns them.
+	Claim(group, consumer string, minIdle time.Duration, ids ...string) ([]StreamMessage[T], error)
+
+	// Delete deletes the stream with its groups and reports whether it existed.
+	Delete() (bool, error)
+}
+
+var (
+	_ RStream[string] = (*RedissonStream[string])(nil)
+)
+
+// RedissonStream is the implementation of RStream
+type RedissonStream[T any] struct {
+	*RedissonExpirable
+	codec Codec
+}
+
+// newRedissonStream creates a new RedissonStream
+func newRedissonStream[T any](name string, redisson *Redisson, options *objectOptions) *RedissonStream[T] {
+	m := &RedissonStream[T]{
+		RedissonExpirable: newRedissonExpirable(name, redisson),
+		codec:             options.codec,
+	}
+	m.ttl = options.ttl
+	return m
+}
+
+// Add appends value to the stream and returns the id of its entry.
+func (m *RedissonStream[T]) Add(value T) (string, error) {
+	data, err := m.codec.Encode(value)
+	if err != nil {
+		return "", err
+	}
+	ctx, cancel := m.newContext()
+	defer cancel()
+	id, err := m.client.XAdd(ctx, &redis.XAddArgs{
+		Stream: m.getRawName(),
+		Values: []interface{}{streamValueField, data},
+	}).Result()
+	if err != nil {
+		return "", err
+	}
+	return id, m.applyTTL(ctx, m.getRawName())
+}
+
+// Range returns at most count entries with an id between start and end, inclusive.
+func (m *RedissonStream[T]) Range(start, end string, count int64) ([]StreamMessage[T], error) {
+	ctx, cancel := m.newContext()
+	defer cancel()
+	var cmd *redis.XMessageSliceCmd
+	if count > 0 {
+		cmd = m.client.XRangeN(ctx, m.getRawName(), start, end, count)
+	} else {
+		cmd = m.client.XRange(ctx, m.getRawName(), start, end)
+	}
+	return m.decodeMessages(cmd.Result())
+}
+
+// Remove removes the entries with ids and returns the number of them which existed.
+func (m *RedissonStream[T]) Remove(ids ...string) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	ctx, cancel := m.newContext()
+	defer cancel()
+	return m.client.XDel(ctx, m.getRawName(), ids...).Result()
+}
+
+// Size returns the number of entries in the stream.
+func (m *RedissonStream[T]) Size() (int64, error) {
+	ctx, cancel := m.newContext()
+	defer cancel()
+	return m.client.XLen(ctx, m.getRawName()).Result()
+}
+
+// Trim removes the oldest entries to keep at most maxLen, and returns the number of them removed.
+func (m *RedissonStream[T]) Trim(maxLen int64) (int64, error) {
+	ctx, cancel := m.newContext()
+	defer cancel()
+	return m.client.XTrimMaxLen(ctx, m.getRawName(), maxLen).Result()
+}
+
+// TrimMinID removes the entries with an id smaller than minID, and returns the number of them removed.
+func (m *RedissonStream[T]) TrimMinID(minID string) (int64, error) {
+	ctx, cancel := m.newContext()
+	defer cancel()
+	return m.client.XTrimMinID(ctx, m.getRawName(), minID).Result()
+}
+
+// CreateGroup creates the consumer group, which is delivered the entries after startID.
+func (m *RedissonStream[T]) CreateGroup(group, startID string) (bool, error) {
+	ctx, cancel := m.newContext()
+	defer cancel()
+	err := m.client.XGroupCreateMkStream(ctx, m.getRawName(), group, startID).Err()
+	if err != nil && strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, m.applyTTL(ctx, m.getRawName())
+}
+
+// RemoveGroup removes the consumer group and reports whether it existed.
+func (m *RedissonStream[T]) RemoveGroup(group string) (bool, error) {
+	ctx, cancel := m.newContext()
+	defer cancel()
+	n, err := m.client.XGroupDestroy(ctx, m.getRawName(), group).Result()
+	return n == 1, err
+}
+
+// CreateConsumer creates a consumer in the group and reports whether it did not exist.
+func (m *RedissonStream[T]) CreateConsumer(group, consumer string) (bool, error) {
+	ctx, cancel := m.newContext()
+	defer cancel()
+	n, err := m.client.XGroupCreateConsumer(ctx, m.getRawName(), group, consumer).Result()
+	return n == 1, err
+}
+
+// RemoveConsumer removes a consumer from the group and returns the number of entries it had pending.
+func (m *RedissonStream[T]) RemoveConsumer(group, consumer string) (int64, error) {
+	ctx, cancel := m.newContext()
+	defer cancel()
+	return m.client.XGroupDelConsumer(ctx, m.getRawName(), group, consumer).Result()
+}
+
+// ListGroups returns the consumer groups of the stream.
+func (m *RedissonStream[T]) ListGroups() ([]StreamGroup, error) {
+	ctx, cancel := m.newContext()
+	defer cancel()
+	infos, err := m.client.XInfoGroups(ctx, m.getRawName()).Result()
+	if err != nil {
+		return nil, err
+	}
+	groups := make([]StreamGroup, len(infos))
+	for i, info := range infos {
+		groups[i] = StreamGroup{
+			Name:            info.Name,
+			Consumers:       info.Consumers,
+			Pending:         info.Pending,
+			LastDeliveredID: info.LastDeliveredID,
+		}
+	}
+	return groups, nil
+}
+
+// ReadGroupBatch reads at most count entries which were never delivered to the group, for consumer,
+// waiting up to block for one if there are none.
+func (m *RedissonStream[T]) ReadGroupBatch(group, consumer string, count int64, block time.Duration) ([]StreamMessage[T], error) {
+	if block > 0 {
+		// the command waits for block itself, the command timeout would cut it short
+		messages, _, err := m.readGroup(m.baseContext(), group, consumer, ">", count, block)
+		return messages, err
+	}
+	ctx, cancel := m.newContext()
+	defer cancel()
+	messages, _, err := m.readGroup(ctx, group, consumer, ">", count, 0)
+	return messages, err
+}
+
+// readGroup reads at most count entries of the group after id for consumer, ">" for the entries never delivered
+// to the group and any other id for the entries pending for consumer. A block of 0 does not wait.
+// It also returns the id of the last entry read, which may have been deleted and not be returned.
+func (m *RedissonStream[T]) readGroup(ctx context.Context, group, consumer, id string, count int64, block time.Duration) ([]StreamMessage[T], string, error) {
+	if block <= 0 {
+		// XREADGROUP does not block without the BLOCK argument, which go-redis omits for a negative duration
+		block = -1
+	}
+	streams, err := m.client.XReadGroup(ctx, &redis.XReadGroupArgs{
+		Group:    group,
+		Consumer: consumer,
+		Streams:  []string{m.getRawName(), id},
+		Count:    count,
+		Block:    block,
+	}).Result()
+	if err == redis.Nil {
+		return nil, "", nil
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	var messages []StreamMessage[T]
+	var lastID string
+	for _, stream := range streams {
+		decoded, err := m.decodeMessages(stream.Messages, nil)
+		if err != nil {
+			return nil, "", err
+		}
+		messages = append(messages, decoded...)
+		if len(stream.Messages) > 0 {
+			lastID = stream.Messages[len(stream.Messages)-1].ID
+		}
+	}
+	return messages, lastID, nil
+}
+
+// ReadGroup returns an iterator over the entries of the group for consumer, until ctx is done.
+func (m *RedissonStream[T]) ReadGroup(ctx context.Context, group, consumer string, count int64) *StreamGroupIterator[T] {
+	return &StreamGroupIterator[T]{
+		stream:    m,
+		ctx:       ctx,
+		group:     group,
+		consumer:  consumer,
+		count:     max(count, 1),
+		pendingID: "0",
+	}
+}
+
+// Ack acknowledges the entries with ids for the group and returns the number of them which were pending.
+func (m *RedissonStream[T]) Ack(group string, ids ...string) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	ctx, cancel := m.newContext()
+	defer cancel()
+	return m.client.XAck(ctx, m.getRawName(), group, ids...).Result()
+}
+
+// Pending returns at most count entries pending in the group, oldest first.
+func (m *RedissonStream[T]) Pending(group string, count int64) ([]StreamPendingEntry, error) {
+	ctx, cancel := m.newContext()
+	defer cancel()
+	pending, err := m.client.XPendingExt(ctx, &redis.XPendingExtArgs{
+		Stream: m.getRawName(),
+		Group:  group,
+		Start:  "-",
+		End:    "+",
+		Count:  count,
+	}).Result()
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]StreamPendingEntry, len(pending))
+	for i, p := range pending {
+		entries[i] = StreamPendingEntry{ID: p.ID, Consumer: p.Consumer, Idle: p.Idle, RetryCount: p.RetryCount}
+	}
+	return entries, nil
+}
+
+// Claim transfers the entries with ids pending in the group for at least minIdle to consumer, and returns them.
+func (m *RedissonStream[T]) Claim(group, consumer string, minIdle time.Duration, ids ...string) ([]StreamMessage[T], error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	ctx, cancel := m.newContext()
+	defer cancel()
+	return m.decodeMessages(m.client.XClaim(ctx, &redis.XClaimArgs{
+		Stream:   m.getRawName(),
+		Group:    group,
+		Consumer: consumer,
+		MinIdle:  minIdle,
+		Messages: ids,
+	}).Result())
+}
+
+// Delete deletes the stream with its groups and reports whether it existed.
+func (m *RedissonStream[T]) Delete() (bool, error) {
+	ctx, cancel := m.newContext()
+	defer cancel()
+	n, err := m.client.Del(ctx, m.getRawName()).Result()
+	return n == 1, err
+}
+
+// decodeMessages decodes the values of the entries, skipping the entries which were deleted while pending
+func (m *RedissonStream[T]) decodeMessages(entries []redis.XMessage, err error) ([]StreamMessage[T], error) {
+	if err != nil {
+		return nil, err
+	}
+	messages := make([]StreamMessage[T], 0, len(entries))
+	for _, entry := range entries {
+		data, ok := entry.Values[streamValueField].(string)
+		if !ok {
+			continue
+		}
+		message := StreamMessage[T]{ID: entry.ID}
+		if err = m.codec.Decode([]byte(data), &message.Value); err != nil {
+			return nil, err
+		}
+		messages = append(messages, message)
+	}
+	return messages, nil
+}
+
+// StreamGroupIterator iterates over the entries of a consumer group of RStream for a consumer, see RStream.ReadGroup.
+//
+//	it := stream.ReadGroup(ctx, "group", "consumer", 10)
+//	for it.Next() {
+//		msg := it.Message()
+//		...
+//		stream.Ack("group", msg.ID)
+//	}
+//	if err := it.Err(); err != nil && !errors.Is(err, context.Canceled) {
+//		...
+//	}
+type StreamGroupIterator[T any] struct {
+	stream   *RedissonStream[T]
+	ctx      context.Context
+	group    string
+	consumer string
+	count    int64
+
+	//pendingID is the id after which the entries pending for the consumer are read, empty once all were read
+	pendingID string
+	messages  []StreamMessage[T]
+	message   StreamMessage[T]
+	err       error
+}
+
+// Next advances to the next entry, waiting for one if needed, and reports false once ctx is done or a command failed.
+func (it *StreamGroupIterator[T]) Next() bool {
+	for len(it.messages) == 0 {
+		if it.err != nil {
+			return false
+		}
+		if it.err = it.ctx.Err(); it.err != nil {
+			return false
+		}
+		if it.pendingID != "" {
+			readCtx, cancel := it.stream.withCommandTimeout(it.ctx)
+			it.messages, it.pendingID, it.err = it.stream.readGroup(readCtx, it.group, it.consumer, it.pendingID, it.count, 0)
+			cancel()
+			continue
+		}
+		// the wait is bounded so that ctx being done is seen even if the redis client is not created
+		// with ContextTimeoutEnabled
+		it.messages, _, it.err = it.stream.readGroup(it.ctx, it.group, it.consumer, ">", it.count, blockingQueueWaitStep)
+		if it.err != nil && it.ctx.Err() != nil {
+			it.err = it.ctx.Err()
+		}
+	}
+	it.message, it.messages = it.messages[0], it.messages[1:]
+	return true
+}
+
+// Message returns the entry Next advanced to.
+func (it *StreamGroupIterator[T]) Message() StreamMessage[T] {
+	return it.message
+}
+
+// Err returns the error which ended the iteration, the error of ctx if it was done.
+func (it *StreamGroupIterator[T]) Err() error {
+	return it.err
+}
@@ -0,0 +1,112 @@
+package redisson
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestStream(t *testing.T) {
+	s := GetStream[User](GetRedisson(), "testStream")
+	if _, err := s.Delete(); err != nil {
+		t.Fatal(err)
+	}
+	ids := make([]string, 5)
+	for i := range ids {
+		id, err := s.Add(User{ID: i})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids[i] = id
+	}
+	if messages, err := s.Range("-", "+", 2); err != nil || len(messages) != 2 || messages[1].ID != ids[1] || messages[1].Value.ID != 1 {
+		t.Fatalf("messages=%v err=%v", messages, err)
+	}
+	if n, err := s.Remove(ids[0]); err != nil || n != 1 {
+		t.Fatalf("n=%v err=%v", n, err)
+	}
+	if n, err := s.Trim(3); err != nil || n != 1 {
+		t.Fatalf("n=%v err=%v", n, err)
+	}
+	if n, err := s.TrimMinID(ids[3]); err != nil || n != 1 {
+		t.Fatalf("n=%v err=%v", n, err)
+	}
+	if n, err := s.Size(); err != nil || n != 2 {
+		t.Fatalf("n=%v err=%v", n, err)
+	}
+}
+
+func TestStreamGroup(t *testing.T) {
+	s := GetStream[string](GetRedisson(), "testStreamGroup")
+	if _, err := s.Delete(); err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := s.CreateGroup("group", "0"); err != nil || !ok {
+		t.Fatalf("ok=%v err=%v", ok, err)
+	}
+	if ok, err := s.CreateGroup("group", "0"); err != nil || ok {
+		t.Fatalf("ok=%v err=%v", ok, err)
+	}
+	if ok, err := s.CreateConsumer("group", "c1"); err != nil || !ok {
+		t.Fatalf("ok=%v err=%v", ok, err)
+	}
+	for _, v := range []string{"a", "b", "c"} {
+		if _, err := s.Add(v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	messages, err := s.ReadGroupBatch("group", "c1", 2, 0)
+	if err != nil || len(messages) != 2 || messages[0].Value != "a" {
+		t.Fatalf("messages=%v err=%v", messages, err)
+	}
+	if n, err := s.Ack("group", messages[0].ID); err != nil || n != 1 {
+		t.Fatalf("n=%v err=%v", n, err)
+	}
+	pending, err := s.Pending("group", 10)
+	if err != nil || len(pending) != 1 || pending[0].ID != messages[1].ID || pending[0].Consumer != "c1" {
+		t.Fatalf("pending=%v err=%v", pending, err)
+	}
+	claimed, err := s.Claim("group", "c2", 0, messages[1].ID)
+	if err != nil || len(claimed) != 1 || claimed[0].Value != "b" {
+		t.Fatalf("claimed=%v err=%v", claimed, err)
+	}
+	groups, err := s.ListGroups()
+	if err != nil || len(groups) != 1 || groups[0].Name != "group" || groups[0].Pending != 1 {
+		t.Fatalf("groups=%v err=%v", groups, err)
+	}
+	if n, err := s.RemoveConsumer("group", "c1"); err != nil || n != 0 {
+		t.Fatalf("n=%v err=%v", n, err)
+	}
+
+	// c2 is first given "b" pending for it, then "c" and "d" never delivered
+	go func() {
+		time.Sleep(200 * time.Millisecond)
+		if _, err := s.Add("d"); err != nil {
+			t.Error(err)
+		}
+	}()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	it := s.ReadGroup(ctx, "group", "c2", 10)
+	var values []string
+	for it.Next() {
+		msg := it.Message()
+		values = append(values, msg.Value)
+		if _, err := s.Ack("group", msg.ID); err != nil {
+			t.Fatal(err)
+		}
+		if len(values) == 3 {
+			cancel()
+		}
+	}
+	if err := it.Err(); !errors.Is(err, context.Canceled) {
+		t.Fatalf("err=%v", err)
+	}
+	if len(values) != 3 || values[0] != "b" || values[1] != "c" || values[2] != "d" {
+		t.Fatalf("values=%v", values)
+	}
+	if ok, err := s.RemoveGroup("group"); err != nil || !ok {
+		t.Fatalf("ok=%v err=%v", ok, err)
+	}
+}
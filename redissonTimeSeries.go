@@ -0,0 +1,252 @@
+package redisson
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// timeSeriesIdLen is the length of the random id prefixing the members of RTimeSeries, which keeps the entries
+// of equal values distinct in the zset
+const timeSeriesIdLen = 32
+
+// timeSeriesCleanupScript removes the entries which expired at ARGV[1] from the KEYS[1] zset of the entries
+// and the KEYS[2] zset of their expire times, it starts the scripts of RTimeSeries
+const timeSeriesCleanupScript = `
+local expired = redis.call('zrangebyscore', KEYS[2], '-inf', ARGV[1]);
+for i = 1, #expired, 5000 do
+    local chunk = {unpack(expired, i, math.min(i + 4999, #expired))};
+    redis.call('zrem', KEYS[1], unpack(chunk));
+    redis.call('zrem', KEYS[2], unpack(chunk));
+end;
+`
+
+// TimeSeriesEntry is an entry of RTimeSeries
+type TimeSeriesEntry[T any] struct {
+	// Timestamp is the time of the entry, with a millisecond precision
+	Timestamp time.Time
+	// Value is the value of the entry
+	Value T
+}
+
+// RTimeSeries is a series of values of type T ordered by their timestamp, at most one per millisecond, stored in
+// a sorted set like the RTimeSeries of Java Redisson. Every entry may expire on its own, and the expired entries
+// are removed by the next call.
+type RTimeSeries[T any] interface {
+	RExpirable
+
+	// Add sets the value at timestamp, replacing the one there was, which expires after ttl, 0 for never.
+	Add(timestamp time.Time, value T, ttl time.Duration) error
+
+	// Get returns the value at timestamp and whether there is one.
+	Get(timestamp time.Time) (T, bool, error)
+
+	// Remove removes the value at timestamp and reports whether there was one.
+	Remove(timestamp time.Time) (bool, error)
+
+	// Range returns the entries from from to to, inclusive, oldest first.
+	Range(from, to time.Time) ([]TimeSeriesEntry[T], error)
+
+	// First returns the n oldest entries, oldest first.
+	First(n int64) ([]TimeSeriesEntry[T], error)
+
+	// Last returns the n newest entries, oldest first.
+	Last(n int64) ([]TimeSeriesEntry[T], error)
+
+	// Pour removes and returns the entries from from to to, inclusive, oldest first.
+	Pour(from, to time.Time) ([]TimeSeriesEntry[T], error)
+
+	// RemoveRange removes the entries from from to to, inclusive, and returns the number of them.
+	RemoveRange(from, to time.Time) (int64, error)
+
+	// Size returns the number of entries.
+	Size() (int64, error)
+
+	// Delete deletes the series and reports whether it existed.
+	Delete() (bool, error)
+}
+
+var (
+	_ RTimeSeries[string] = (*RedissonTimeSeries[string])(nil)
+)
+
+// RedissonTimeSeries is the implementation of RTimeSeries
+// the entries are stored in the name zset scored by their timestamp in milliseconds, each member being a random id
+// followed by the encoded value, and the expire times of the entries with a ttl in the {name}:timeout zset
+type RedissonTimeSeries[T any] struct {
+	*RedissonExpirable
+	codec Codec
+}
+
+// newRedissonTimeSeries creates a new RedissonTimeSeries
+func newRedissonTimeSeries[T any](name string, redisson *Redisson, options *objectOptions) *RedissonTimeSeries[T] {
+	m := &RedissonTimeSeries[T]{
+		RedissonExpirable: newRedissonExpirable(name, redisson),
+		codec:             options.codec,
+	}
+	m.ttl = options.ttl
+	m.componentKeys = func() []string {
+		return []string{m.getRawName(), m.getTimeoutName()}
+	}
+	return m
+}
+
+// getTimeoutName returns the name of the zset of the expire times
+func (m *RedissonTimeSeries[T]) getTimeoutName() string {
+	return m.suffixName(m.getRawName(), "timeout")
+}
+
+// Add sets the value at timestamp, replacing the one there was, which expires after ttl, 0 for never.
+func (m *RedissonTimeSeries[T]) Add(timestamp time.Time, value T, ttl time.Duration) error {
+	data, err := m.codec.Encode(value)
+	if err != nil {
+		return err
+	}
+	id := make([]byte, timeSeriesIdLen/2)
+	if _, err = rand.Read(id); err != nil {
+		return err
+	}
+	now := m.clock.Now()
+	var expireAt int64
+	if ttl > 0 {
+		expireAt = now.Add(ttl).UnixMilli()
+	}
+	ctx, cancel := m.newContext()
+	defer cancel()
+	err = m.eval(ctx, "timeSeries.add", timeSeriesCleanupScript+`
+local previous = redis.call('zrangebyscore', KEYS[1], ARGV[2], ARGV[2]);
+for i = 1, #previous do
+    redis.call('zrem', KEYS[1], previous[i]);
+    redis.call('zrem', KEYS[2], previous[i]);
+end;
+redis.call('zadd', KEYS[1], ARGV[2], ARGV[3]);
+if tonumber(ARGV[4]) > 0 then
+    redis.call('zadd', KEYS[2], ARGV[4], ARGV[3]);
+end;
+return 1;
+`, []string{m.getRawName(), m.getTimeoutName()}, now.UnixMilli(), timestamp.UnixMilli(), hex.EncodeToString(id)+string(data), expireAt).Err()
+	if err != nil {
+		return err
+	}
+	return m.applyTTL(ctx, m.getRawName(), m.getTimeoutName())
+}
+
+// Get returns the value at timestamp and whether there is one.
+func (m *RedissonTimeSeries[T]) Get(timestamp time.Time) (T, bool, error) {
+	var v T
+	entries, err := m.Range(timestamp, timestamp)
+	if err != nil || len(entries) == 0 {
+		return v, false, err
+	}
+	return entries[0].Value, true, nil
+}
+
+// Remove removes the value at timestamp and reports whether there was one.
+func (m *RedissonTimeSeries[T]) Remove(timestamp time.Time) (bool, error) {
+	n, err := m.RemoveRange(timestamp, timestamp)
+	return n > 0, err
+}
+
+// Range returns the entries from from to to, inclusive, oldest first.
+func (m *RedissonTimeSeries[T]) Range(from, to time.Time) ([]TimeSeriesEntry[T], error) {
+	return m.entries("timeSeries.range", timeSeriesCleanupScript+`
+return redis.call('zrangebyscore', KEYS[1], ARGV[2], ARGV[3], 'WITHSCORES');
+`, false, from.UnixMilli(), to.UnixMilli())
+}
+
+// First returns the n oldest entries, oldest first.
+func (m *RedissonTimeSeries[T]) First(n int64) ([]TimeSeriesEntry[T], error) {
+	if n <= 0 {
+		return nil, nil
+	}
+	return m.entries("timeSeries.first", timeSeriesCleanupScript+`
+return redis.call('zrange', KEYS[1], 0, tonumber(ARGV[2]) - 1, 'WITHSCORES');
+`, false, n)
+}
+
+// Last returns the n newest entries, oldest first.
+func (m *RedissonTimeSeries[T]) Last(n int64) ([]TimeSeriesEntry[T], error) {
+	if n <= 0 {
+		return nil, nil
+	}
+	return m.entries("timeSeries.last", timeSeriesCleanupScript+`
+return redis.call('zrevrange', KEYS[1], 0, tonumber(ARGV[2]) - 1, 'WITHSCORES');
+`, true, n)
+}
+
+// Pour removes and returns the entries from from to to, inclusive, oldest first.
+func (m *RedissonTimeSeries[T]) Pour(from, to time.Time) ([]TimeSeriesEntry[T], error) {
+	return m.entries("timeSeries.pour", timeSeriesCleanupScript+`
+local entries = redis.call('zrangebyscore', KEYS[1], ARGV[2], ARGV[3], 'WITHSCORES');
+for i = 1, #entries, 2 do
+    redis.call('zrem', KEYS[1], entries[i]);
+    redis.call('zrem', KEYS[2], entries[i]);
+end;
+return entries;
+`, false, from.UnixMilli(), to.UnixMilli())
+}
+
+// RemoveRange removes the entries from from to to, inclusive, and returns the number of them.
+func (m *RedissonTimeSeries[T]) RemoveRange(from, to time.Time) (int64, error) {
+	ctx, cancel := m.newContext()
+	defer cancel()
+	return m.eval(ctx, "timeSeries.removeRange", timeSeriesCleanupScript+`
+local members = redis.call('zrangebyscore', KEYS[1], ARGV[2], ARGV[3]);
+for i = 1, #members do
+    redis.call('zrem', KEYS[1], members[i]);
+    redis.call('zrem', KEYS[2], members[i]);
+end;
+return #members;
+`, []string{m.getRawName(), m.getTimeoutName()}, m.clock.Now().UnixMilli(), from.UnixMilli(), to.UnixMilli()).Int64()
+}
+
+// Size returns the number of entries.
+func (m *RedissonTimeSeries[T]) Size() (int64, error) {
+	ctx, cancel := m.newContext()
+	defer cancel()
+	return m.eval(ctx, "timeSeries.size", timeSeriesCleanupScript+`
+return redis.call('zcard', KEYS[1]);
+`, []string{m.getRawName(), m.getTimeoutName()}, m.clock.Now().UnixMilli()).Int64()
+}
+
+// Delete deletes the series and reports whether it existed.
+func (m *RedissonTimeSeries[T]) Delete() (bool, error) {
+	ctx, cancel := m.newContext()
+	defer cancel()
+	n, err := m.client.Del(ctx, m.getRawName(), m.getTimeoutName()).Result()
+	return n > 0, err
+}
+
+// entries runs a script replying members and scores, and decodes them oldest first, reversing them if the
+// script replies the newest first. The current time is given before args.
+func (m *RedissonTimeSeries[T]) entries(name, script string, reversed bool, args ...interface{}) ([]TimeSeriesEntry[T], error) {
+	ctx, cancel := m.newContext()
+	defer cancel()
+	reply, err := m.eval(ctx, name, script, []string{m.getRawName(), m.getTimeoutName()},
+		append([]interface{}{m.clock.Now().UnixMilli()}, args...)...).StringSlice()
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]TimeSeriesEntry[T], len(reply)/2)
+	for i := range entries {
+		member, score := reply[2*i], reply[2*i+1]
+		if len(member) < timeSeriesIdLen {
+			return nil, fmt.Errorf("time series %s: malformed entry %q", m.getRawName(), member)
+		}
+		ms, err := strconv.ParseFloat(score, 64)
+		if err != nil {
+			return nil, err
+		}
+		entry := &entries[i]
+		if reversed {
+			entry = &entries[len(entries)-1-i]
+		}
+		entry.Timestamp = time.UnixMilli(int64(ms))
+		if err = m.codec.Decode([]byte(member[timeSeriesIdLen:]), &entry.Value); err != nil {
+			return nil, err
+		}
+	}
+	return entries, nil
+}
@@ -0,0 +1,72 @@
+package redisson
+
+import (
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+func TestTimeSeries(t *testing.T) {
+	redisDB := redis.NewClient(&redis.Options{
+		Addr: redisAddr,
+	})
+	clock := NewManualClock(time.Now())
+	g := NewRedisson(redisDB, WithClock(clock))
+	ts := GetTimeSeries[User](g, "testTimeSeries")
+	if _, err := ts.Delete(); err != nil {
+		t.Fatal(err)
+	}
+	base := time.UnixMilli(1700000000000)
+	at := func(i int) time.Time {
+		return base.Add(time.Duration(i) * time.Second)
+	}
+	for i := 0; i < 5; i++ {
+		if err := ts.Add(at(i), User{ID: i}, 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// replaces the value at the same timestamp
+	if err := ts.Add(at(2), User{ID: 2, Name: "two"}, 0); err != nil {
+		t.Fatal(err)
+	}
+	if v, ok, err := ts.Get(at(2)); err != nil || !ok || v.Name != "two" {
+		t.Fatalf("v=%v ok=%v err=%v", v, ok, err)
+	}
+	if entries, err := ts.Range(at(1), at(3)); err != nil || len(entries) != 3 || !entries[0].Timestamp.Equal(at(1)) || entries[2].Value.ID != 3 {
+		t.Fatalf("entries=%v err=%v", entries, err)
+	}
+	if entries, err := ts.Last(2); err != nil || len(entries) != 2 || entries[0].Value.ID != 3 || entries[1].Value.ID != 4 {
+		t.Fatalf("entries=%v err=%v", entries, err)
+	}
+	if entries, err := ts.First(1); err != nil || len(entries) != 1 || entries[0].Value.ID != 0 {
+		t.Fatalf("entries=%v err=%v", entries, err)
+	}
+	if entries, err := ts.Pour(at(0), at(1)); err != nil || len(entries) != 2 || entries[1].Value.ID != 1 {
+		t.Fatalf("entries=%v err=%v", entries, err)
+	}
+	if n, err := ts.RemoveRange(at(4), at(10)); err != nil || n != 1 {
+		t.Fatalf("n=%v err=%v", n, err)
+	}
+	if ok, err := ts.Remove(at(3)); err != nil || !ok {
+		t.Fatalf("ok=%v err=%v", ok, err)
+	}
+	if n, err := ts.Size(); err != nil || n != 1 {
+		t.Fatalf("n=%v err=%v", n, err)
+	}
+
+	// the entries expire on their own
+	if err := ts.Add(at(5), User{ID: 5}, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := ts.Size(); err != nil || n != 2 {
+		t.Fatalf("n=%v err=%v", n, err)
+	}
+	clock.Advance(time.Minute)
+	if _, ok, err := ts.Get(at(5)); err != nil || ok {
+		t.Fatalf("ok=%v err=%v", ok, err)
+	}
+	if n, err := ts.Size(); err != nil || n != 1 {
+		t.Fatalf("n=%v err=%v", n, err)
+	}
+}
@@ -0,0 +1,76 @@
+package redisson
+
+import (
+	"context"
+	"log"
+)
+
+// RTopic publishes and receives messages of type T over redis Pub/Sub.
+type RTopic[T any] interface {
+	// Publish publishes the message and returns the number of clients that received it.
+	Publish(message T) (int64, error)
+
+	// Subscribe calls handler for every message received until ctx is done or unsubscribe is called.
+	Subscribe(ctx context.Context, handler func(message T)) (unsubscribe func() error, err error)
+}
+
+var (
+	_ RTopic[string] = (*RedissonTopic[string])(nil)
+)
+
+// RedissonTopic is the implementation of RTopic
+type RedissonTopic[T any] struct {
+	*RedissonObject
+	codec Codec
+}
+
+// newRedissonTopic creates a new RedissonTopic
+func newRedissonTopic[T any](name string, redisson *Redisson, options *objectOptions) *RedissonTopic[T] {
+	return &RedissonTopic[T]{
+		RedissonObject: newRedissonObject(name, redisson),
+		codec:          options.codec,
+	}
+}
+
+// Publish publishes the message and returns the number of clients that received it.
+func (m *RedissonTopic[T]) Publish(message T) (int64, error) {
+	ctx, cancel := m.newContext()
+	defer cancel()
+	data, err := m.codec.Encode(message)
+	if err != nil {
+		return 0, err
+	}
+	return m.client.Publish(ctx, m.getRawName(), data).Result()
+}
+
+// Subscribe calls handler for every message received until ctx is done or unsubscribe is called.
+// Messages which cannot be decoded are logged and dropped.
+func (m *RedissonTopic[T]) Subscribe(ctx context.Context, handler func(message T)) (func() error, error) {
+	sub := m.client.Subscribe(ctx, m.getRawName())
+	// wait for the subscription to be confirmed so no message published afterwards is missed
+	if _, err := sub.Receive(ctx); err != nil {
+		sub.Close()
+		return nil, err
+	}
+	go func() {
+		ch := sub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				sub.Close()
+				return
+			case msg, ok := <-ch:
+				if !ok {
+					return
+				}
+				var v T
+				if err := m.codec.Decode([]byte(msg.Payload), &v); err != nil {
+					log.Printf("topic %s: failed to decode message: %v", m.getRawName(), err)
+					continue
+				}
+				handler(v)
+			}
+		}
+	}()
+	return sub.Close, nil
+}
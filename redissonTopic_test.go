@@ -0,0 +1,34 @@
+package redisson
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestTopic(t *testing.T) {
+	g := GetRedisson()
+	topic := GetTopic[User](g, "testTopic")
+	received := make(chan User, 1)
+	unsubscribe, err := topic.Subscribe(context.Background(), func(u User) {
+		received <- u
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer unsubscribe()
+
+	if n, err := topic.Publish(User{ID: 1, Name: "Alice"}); err != nil {
+		t.Fatal(err)
+	} else if n != 1 {
+		t.Fatalf("n=%v", n)
+	}
+	select {
+	case u := <-received:
+		if u.Name != "Alice" {
+			t.Fatalf("u=%v", u)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("message not received")
+	}
+}
@@ -0,0 +1,271 @@
+package redisson
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// transferIdLen is the length of the random id prefixing the values of RTransferQueue
+	transferIdLen = 32
+
+	// transferAckTTL is how long the ack of a value is kept for its producer to see it
+	transferAckTTL = time.Minute
+
+	// transferCheckInterval is how often a waiting producer checks the ack of its value, in case the message
+	// of the consumer was missed
+	transferCheckInterval = time.Second
+)
+
+// RTransferQueue is a FIFO queue of values of type T whose producers may wait for a consumer to receive their
+// value, like the RTransferQueue of Java Redisson.
+// A consumer acks every value it receives with the {name}:ack:<id> key and a message on the channel of the same
+// name, which wakes the producer of the value up.
+type RTransferQueue[T any] interface {
+	RExpirable
+
+	// Transfer appends value to the tail of the queue and waits until a consumer received it.
+	// If ctx is done before, the value is removed from the queue and the error of ctx is returned,
+	// unless a consumer took it meanwhile. If the value was taken but its receipt is not acked, the error
+	// wraps both ErrTransferIndeterminate and the error of ctx.
+	Transfer(ctx context.Context, value T) error
+
+	// Offer appends value to the tail of the queue without waiting for a consumer.
+	Offer(value T) error
+
+	// Poll removes and returns the value at the head of the queue, waiting up to timeout for one to be available,
+	// or until ctx is done. It reports false if the queue is still empty after timeout, and 0 does not wait.
+	Poll(ctx context.Context, timeout time.Duration) (T, bool, error)
+
+	// Take removes and returns the value at the head of the queue, waiting until one is available or ctx is done.
+	Take(ctx context.Context) (T, error)
+
+	// Size returns the number of values in the queue.
+	Size() (int64, error)
+
+	// Delete deletes the queue and reports whether it existed.
+	Delete() (bool, error)
+}
+
+var (
+	_ RTransferQueue[string] = (*RedissonTransferQueue[string])(nil)
+
+	// ErrTransferIndeterminate is returned by Transfer when its ctx is done after a consumer took the value from
+	// the queue but before the consumer acked it, so whether the value was received is unknown
+	ErrTransferIndeterminate = errors.New("redisson: the value was taken from the queue but its receipt was not acked")
+)
+
+// RedissonTransferQueue is the implementation of RTransferQueue
+// values are stored in the name list prefixed with a random id, which names their ack key
+type RedissonTransferQueue[T any] struct {
+	*RedissonExpirable
+	codec Codec
+}
+
+// newRedissonTransferQueue creates a new RedissonTransferQueue
+func newRedissonTransferQueue[T any](name string, redisson *Redisson, options *objectOptions) *RedissonTransferQueue[T] {
+	m := &RedissonTransferQueue[T]{
+		RedissonExpirable: newRedissonExpirable(name, redisson),
+		codec:             options.codec,
+	}
+	m.ttl = options.ttl
+	return m
+}
+
+// getAckName returns the name of the ack key and of the ack channel of the value with id
+func (m *RedissonTransferQueue[T]) getAckName(id string) string {
+	return m.suffixName(m.getRawName(), "ack:"+id)
+}
+
+// Transfer appends value to the tail of the queue and waits until a consumer received it.
+func (m *RedissonTransferQueue[T]) Transfer(ctx context.Context, value T) error {
+	id, item, err := m.encodeItem(value)
+	if err != nil {
+		return err
+	}
+	// subscribe before pushing so the ack of a consumer is not missed
+	sub, err := m.subscriptions.subscribe(ctx, m.getAckName(id))
+	if err != nil {
+		return err
+	}
+	defer m.subscriptions.unsubscribe(context.WithoutCancel(ctx), sub)
+	if err = m.push(ctx, item); err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(transferCheckInterval)
+	defer ticker.Stop()
+	for received := false; !received; {
+		select {
+		case <-sub.c:
+			received = true
+		case <-ticker.C:
+			if received, err = m.acked(ctx, id); err != nil && ctx.Err() == nil {
+				return err
+			}
+		case <-ctx.Done():
+			// the value is no longer in the queue if a consumer took it meanwhile
+			removeCtx, cancel := m.withCommandTimeout(context.WithoutCancel(ctx))
+			n, err := m.client.LRem(removeCtx, m.getRawName(), 1, item).Result()
+			cancel()
+			if err != nil {
+				return err
+			}
+			if n == 1 {
+				return ctx.Err()
+			}
+			// the consumer may have failed before acking the value it took
+			if received, err = m.acked(context.WithoutCancel(ctx), id); err != nil {
+				return err
+			}
+			if !received {
+				return fmt.Errorf("%w: %w", ErrTransferIndeterminate, ctx.Err())
+			}
+		}
+	}
+	delCtx, cancel := m.withCommandTimeout(context.WithoutCancel(ctx))
+	defer cancel()
+	return m.client.Del(delCtx, m.getAckName(id)).Err()
+}
+
+// acked reports whether the value with id was acked by a consumer
+func (m *RedissonTransferQueue[T]) acked(ctx context.Context, id string) (bool, error) {
+	checkCtx, cancel := m.withCommandTimeout(ctx)
+	defer cancel()
+	n, err := m.client.Exists(checkCtx, m.getAckName(id)).Result()
+	return n == 1, err
+}
+
+// Offer appends value to the tail of the queue without waiting for a consumer.
+func (m *RedissonTransferQueue[T]) Offer(value T) error {
+	_, item, err := m.encodeItem(value)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := m.newContext()
+	defer cancel()
+	return m.push(ctx, item)
+}
+
+// Poll removes and returns the value at the head of the queue, waiting up to timeout for one to be available.
+func (m *RedissonTransferQueue[T]) Poll(ctx context.Context, timeout time.Duration) (T, bool, error) {
+	var zero T
+	if timeout <= 0 {
+		popCtx, cancel := m.withCommandTimeout(ctx)
+		item, err := m.client.LPop(popCtx, m.getRawName()).Result()
+		cancel()
+		if err == redis.Nil {
+			return zero, false, nil
+		}
+		if err != nil {
+			return zero, false, err
+		}
+		v, err := m.receive(ctx, item)
+		return v, err == nil, err
+	}
+	deadline := time.Now().Add(timeout)
+	for {
+		if err := ctx.Err(); err != nil {
+			return zero, false, err
+		}
+		if !time.Now().Before(deadline) {
+			return zero, false, nil
+		}
+		res, err := m.client.BLPop(ctx, blockingQueueWaitStep, m.getRawName()).Result()
+		if err == redis.Nil {
+			continue
+		}
+		if err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return zero, false, ctxErr
+			}
+			return zero, false, err
+		}
+		v, err := m.receive(ctx, res[1])
+		return v, err == nil, err
+	}
+}
+
+// Take removes and returns the value at the head of the queue, waiting until one is available or ctx is done.
+func (m *RedissonTransferQueue[T]) Take(ctx context.Context) (T, error) {
+	var zero T
+	for {
+		res, err := m.client.BLPop(ctx, blockingQueueWaitStep, m.getRawName()).Result()
+		if err == redis.Nil {
+			if err = ctx.Err(); err != nil {
+				return zero, err
+			}
+			continue
+		}
+		if err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return zero, ctxErr
+			}
+			return zero, err
+		}
+		return m.receive(ctx, res[1])
+	}
+}
+
+// Size returns the number of values in the queue.
+func (m *RedissonTransferQueue[T]) Size() (int64, error) {
+	ctx, cancel := m.newContext()
+	defer cancel()
+	return m.client.LLen(ctx, m.getRawName()).Result()
+}
+
+// Delete deletes the queue and reports whether it existed.
+func (m *RedissonTransferQueue[T]) Delete() (bool, error) {
+	ctx, cancel := m.newContext()
+	defer cancel()
+	n, err := m.client.Del(ctx, m.getRawName()).Result()
+	return n == 1, err
+}
+
+// encodeItem returns a new id and the item stored for value
+func (m *RedissonTransferQueue[T]) encodeItem(value T) (string, string, error) {
+	data, err := m.codec.Encode(value)
+	if err != nil {
+		return "", "", err
+	}
+	b := make([]byte, transferIdLen/2)
+	if _, err = rand.Read(b); err != nil {
+		return "", "", err
+	}
+	id := hex.EncodeToString(b)
+	return id, id + string(data), nil
+}
+
+// push appends item to the tail of the queue
+func (m *RedissonTransferQueue[T]) push(ctx context.Context, item string) error {
+	pushCtx, cancel := m.withCommandTimeout(ctx)
+	defer cancel()
+	if err := m.client.RPush(pushCtx, m.getRawName(), item).Err(); err != nil {
+		return err
+	}
+	return m.applyTTL(pushCtx, m.getRawName())
+}
+
+// receive acks item, which was popped from the queue, to its producer and decodes its value.
+// The ack is sent even if ctx is done, since the item is no longer in the queue.
+func (m *RedissonTransferQueue[T]) receive(ctx context.Context, item string) (T, error) {
+	var v T
+	id := item[:transferIdLen]
+	ackCtx, cancel := m.withCommandTimeout(context.WithoutCancel(ctx))
+	defer cancel()
+	err := m.eval(ackCtx, "transferQueue.ack", `
+redis.call('set', KEYS[1], 1, 'px', ARGV[1]);
+redis.call('publish', KEYS[1], 1);
+`, []string{m.getAckName(id)}, transferAckTTL.Milliseconds()).Err()
+	if err != nil && err != redis.Nil {
+		return v, err
+	}
+	err = m.codec.Decode([]byte(item[transferIdLen:]), &v)
+	return v, err
+}
@@ -0,0 +1,89 @@
+package redisson
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTransferQueue(t *testing.T) {
+	q := GetTransferQueue[User](GetRedisson(), "testTransferQueue")
+	if _, err := q.Delete(); err != nil {
+		t.Fatal(err)
+	}
+	taken := make(chan time.Time, 1)
+	go func() {
+		time.Sleep(300 * time.Millisecond)
+		v, err := q.Take(context.Background())
+		if err != nil || v.Name != "Alice" {
+			t.Errorf("v=%v err=%v", v, err)
+		}
+		taken <- time.Now()
+	}()
+	if err := q.Transfer(context.Background(), User{ID: 1, Name: "Alice"}); err != nil {
+		t.Fatal(err)
+	}
+	transferred := time.Now()
+	if takenAt := <-taken; transferred.Before(takenAt.Add(-100 * time.Millisecond)) {
+		t.Fatalf("transferred at %v before taken at %v", transferred, takenAt)
+	}
+	if n, err := q.Size(); err != nil || n != 0 {
+		t.Fatalf("n=%v err=%v", n, err)
+	}
+	// the ack channel of the value is unsubscribed
+	g := GetRedisson()
+	g.subscriptions.Lock()
+	defer g.subscriptions.Unlock()
+	for channel := range g.subscriptions.channels {
+		if strings.HasPrefix(channel, "{testTransferQueue}:ack:") {
+			t.Fatalf("%v is still subscribed", channel)
+		}
+	}
+}
+
+func TestTransferQueueCanceled(t *testing.T) {
+	q := GetTransferQueue[string](GetRedisson(), "testTransferQueueCanceled")
+	if _, err := q.Delete(); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+	if err := q.Transfer(ctx, "nobody"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err=%v", err)
+	}
+	// the value is taken back from the queue
+	if n, err := q.Size(); err != nil || n != 0 {
+		t.Fatalf("n=%v err=%v", n, err)
+	}
+	if err := q.Offer("offered"); err != nil {
+		t.Fatal(err)
+	}
+	if v, ok, err := q.Poll(context.Background(), time.Second); err != nil || !ok || v != "offered" {
+		t.Fatalf("v=%v ok=%v err=%v", v, ok, err)
+	}
+	if _, ok, err := q.Poll(context.Background(), 0); err != nil || ok {
+		t.Fatalf("ok=%v err=%v", ok, err)
+	}
+}
+
+func TestTransferQueueUnacked(t *testing.T) {
+	g := GetRedisson()
+	q := GetTransferQueue[string](g, "testTransferQueueUnacked")
+	if _, err := q.Delete(); err != nil {
+		t.Fatal(err)
+	}
+	// a consumer pops the value and fails before acking it
+	go func() {
+		if _, err := g.client.BLPop(context.Background(), time.Second, "testTransferQueueUnacked").Result(); err != nil {
+			t.Error(err)
+		}
+	}()
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+	err := q.Transfer(ctx, "lost")
+	if !errors.Is(err, ErrTransferIndeterminate) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err=%v", err)
+	}
+}
@@ -0,0 +1,130 @@
+package redisson
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// VerifyCheck is the result of one check made by Verify.
+type VerifyCheck struct {
+	// Name identifies the check, e.g. "command:bitfield" or "pubsub"
+	Name string
+	// Required reports whether a failure of the check breaks features of this package
+	Required bool
+	// OK reports whether the check passed
+	OK bool
+	// Detail explains a failure, or describes what was found for informational checks
+	Detail string
+}
+
+// VerifyReport is the result of Verify.
+type VerifyReport struct {
+	Checks []VerifyCheck
+}
+
+// OK reports whether every required check passed.
+func (r *VerifyReport) OK() bool {
+	return r.Err() == nil
+}
+
+// Err returns an error listing the required checks which failed, or nil if all of them passed.
+func (r *VerifyReport) Err() error {
+	var failed []string
+	for _, c := range r.Checks {
+		if c.Required && !c.OK {
+			failed = append(failed, c.Name+": "+c.Detail)
+		}
+	}
+	if len(failed) == 0 {
+		return nil
+	}
+	return errors.New("redisson: server verification failed: " + strings.Join(failed, "; "))
+}
+
+// add appends a check to the report
+func (r *VerifyReport) add(name string, required bool, err error, detail string) {
+	c := VerifyCheck{Name: name, Required: required, OK: err == nil, Detail: detail}
+	if err != nil {
+		c.Detail = err.Error()
+	}
+	r.Checks = append(r.Checks, c)
+}
+
+// verifyCommands lists the commands used by the objects of this package
+var verifyCommands = []string{
+	"eval", "evalsha", "publish", "subscribe", "bitfield", "bitcount", "getrange",
+	"hincrby", "pexpire", "pexpireat", "pttl", "mget", "incrbyfloat", "wait",
+}
+
+// Verify checks that the server supports everything the configured features need, so that misconfigured
+// environments can fail fast at deploy time. It returns an error only if the server cannot be reached,
+// use VerifyReport.Err to fail on unsupported features.
+func (g *Redisson) Verify(ctx context.Context) (*VerifyReport, error) {
+	if err := g.Ping(ctx); err != nil {
+		return nil, err
+	}
+	report := &VerifyReport{}
+
+	report.add("eval", true, g.client.Eval(ctx, "return 1", nil).Err(), "")
+	report.add("pubsub", true, g.verifyPubSub(ctx), "")
+
+	commands := append([]string(nil), verifyCommands...)
+	if g.expiryTracking {
+		commands = append(commands, "zadd", "zrem", "zrangebyscore", "zremrangebyscore")
+	}
+	if g.scriptProfiler != nil {
+		commands = append(commands, "slowlog")
+	}
+	args := []interface{}{"command", "info"}
+	for _, name := range commands {
+		args = append(args, name)
+	}
+	infos, err := g.client.Do(ctx, args...).Slice()
+	for i, name := range commands {
+		switch {
+		case err != nil:
+			report.add("command:"+name, true, fmt.Errorf("COMMAND INFO failed: %w", err), "")
+		case i >= len(infos) || infos[i] == nil:
+			report.add("command:"+name, true, errors.New("command is not supported or renamed"), "")
+		default:
+			report.add("command:"+name, true, nil, "")
+		}
+	}
+
+	// informational, the module list and notification config may be restricted on managed servers
+	if modules, err := g.client.Do(ctx, "module", "list").Slice(); err != nil {
+		report.add("modules", false, err, "")
+	} else {
+		report.add("modules", false, nil, fmt.Sprintf("%d module(s) loaded", len(modules)))
+	}
+	if config, err := g.client.ConfigGet(ctx, "notify-keyspace-events").Result(); err != nil {
+		report.add("notify-keyspace-events", false, err, "")
+	} else {
+		report.add("notify-keyspace-events", false, nil, fmt.Sprintf("%q", config["notify-keyspace-events"]))
+	}
+	return report, nil
+}
+
+// verifyPubSub checks that a message published on a channel reaches its subscribers
+func (g *Redisson) verifyPubSub(ctx context.Context) error {
+	channel := "redisson__verify:" + g.id
+	sub := g.client.Subscribe(ctx, channel)
+	defer sub.Close()
+	if _, err := sub.Receive(ctx); err != nil {
+		return err
+	}
+	if err := g.client.Publish(ctx, channel, "verify").Err(); err != nil {
+		return err
+	}
+	select {
+	case <-sub.Channel():
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(time.Second):
+		return errors.New("published message was not received")
+	}
+}
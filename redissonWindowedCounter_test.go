@@ -0,0 +1,36 @@
+package redisson
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWindowedCounter(t *testing.T) {
+	c := GetRedisson().GetWindowedCounter("windowedCounter"+time.Now().String(), time.Second)
+	for i := 0; i < 5; i++ {
+		if err := c.Increment(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := c.Add(5); err != nil {
+		t.Fatal(err)
+	}
+	if sum, err := c.Sum(); err != nil {
+		t.Fatal(err)
+	} else if sum != 10 {
+		t.Fatalf("sum=%v", sum)
+	}
+	if rate, err := c.Rate(); err != nil {
+		t.Fatal(err)
+	} else if rate != 10 {
+		t.Fatalf("rate=%v", rate)
+	}
+
+	// all events leave the window
+	time.Sleep(1100 * time.Millisecond)
+	if sum, err := c.Sum(); err != nil {
+		t.Fatal(err)
+	} else if sum != 0 {
+		t.Fatalf("sum=%v", sum)
+	}
+}
@@ -8,6 +8,9 @@ import (
 	"github.com/redis/go-redis/v9"
 )
 
+// lockUpgradeResult is returned by the try lock script when the goroutine holds the read lock
+const lockUpgradeResult = -3
+
 // redissonWriteLock implements Lock
 type redissonWriteLock struct {
 	RedissonBaseLock
@@ -24,16 +27,27 @@ func (m *redissonWriteLock) getLockName(goroutineId uint64) string {
 }
 
 // newRedisWriteLock creates a new redissonWriteLock
-func newRedisWriteLock(name string, redisson *Redisson) Lock {
+func newRedisWriteLock(name string, redisson *Redisson, opts ...ObjectOption) Lock {
 	redisWriteLock := &redissonWriteLock{}
-	redisWriteLock.RedissonBaseLock = *newBaseLock(redisson.id, name, redisson, redisWriteLock)
+	redisWriteLock.RedissonBaseLock = *newBaseLock(redisson.id, name, redisson, redisWriteLock, opts...)
 	return redisWriteLock
 }
 
 // tryLockInner tries to acquire the lock
 func (m *redissonWriteLock) tryLockInner(ctx context.Context, leaseTime time.Duration, goroutineId uint64) (*int64, error) {
-	result, err := m.client.Eval(ctx, `
+	timeoutPrefix := m.getReadWriteTimeoutNamePrefix(goroutineId)
+	keyPrefix := m.getKeyPrefix(goroutineId, timeoutPrefix)
+
+	result, err := m.eval(ctx, "writeLock.tryLock", purgeStaleReadersLua+`
 local mode = redis.call('hget', KEYS[1], 'mode');
+if (mode == 'read') then
+    -- the readers of crashed processes do not keep the writers waiting until the lock expires
+    purgeStaleReaders(KEYS[1], KEYS[2]);
+    if (redis.call('hlen', KEYS[1]) == 1) then
+        redis.call('del', KEYS[1]);
+        mode = false;
+    end ;
+end ;
 if (mode == false) then
     redis.call('hset', KEYS[1], 'mode', 'write');
     redis.call('hset', KEYS[1], ARGV[2], 1);
@@ -48,14 +62,20 @@ if (mode == 'write') then
         return nil;
     end ;
 end ;
+if (mode == 'read' and redis.call('hexists', KEYS[1], ARGV[3]) == 1) then
+    return -3;
+end ;
 return redis.call('pttl', KEYS[1]);
-`, []string{m.getRawName()}, leaseTime.Milliseconds(), m.getLockName(goroutineId)).Int64()
+`, []string{m.getRawName(), keyPrefix}, leaseTime.Milliseconds(), m.getLockName(goroutineId), m.RedissonBaseLock.getLockName(goroutineId)).Int64()
 	if err != nil {
 		if err == redis.Nil {
 			return nil, nil
 		}
 		return nil, err
 	}
+	if result == lockUpgradeResult {
+		return nil, ErrLockUpgrade
+	}
 
 	return &result, err
 }
@@ -64,10 +84,10 @@ return redis.call('pttl', KEYS[1]);
 func (m *redissonWriteLock) unlockInner(ctx context.Context, goroutineId uint64) (*int64, error) {
 	defer m.cancelExpirationRenewal(goroutineId)
 
-	result, err := m.client.Eval(ctx, `
+	result, err := m.eval(ctx, "writeLock.unlock", unlockPublishLua+`
 local mode = redis.call('hget', KEYS[1], 'mode');
 if (mode == false) then
-    redis.call('publish', KEYS[2], ARGV[1]);
+    publishUnlock(KEYS[2], ARGV[1], ARGV[4], KEYS[3], true);
     return 1;
 end ;
 if (mode == 'write') then
@@ -83,16 +103,18 @@ if (mode == 'write') then
             redis.call('hdel', KEYS[1], ARGV[3]);
             if (redis.call('hlen', KEYS[1]) == 1) then
                 redis.call('del', KEYS[1]);
-                redis.call('publish', KEYS[2], ARGV[1]);
+                publishUnlock(KEYS[2], ARGV[1], ARGV[4], KEYS[3], true);
             else
+                -- downgraded, the readers waiting for the writer can now share the lock
                 redis.call('hset', KEYS[1], 'mode', 'read');
+                publishUnlock(KEYS[2], ARGV[1], ARGV[4], KEYS[3], true);
             end ;
             return 1;
         end ;
     end ;
 end ;
 return nil;
-`, []string{m.getRawName(), m.getChannelName()}, readUnlockMessage, m.internalLockLeaseTime.Milliseconds(), m.getLockName(goroutineId)).Int64()
+`, []string{m.getRawName(), m.getChannelName(), m.getWaitersName()}, readUnlockMessage, m.getLockLeaseTime().Milliseconds(), m.getLockName(goroutineId), m.lockChannelShards).Int64()
 	if err != nil {
 		if err == redis.Nil {
 			return nil, nil
@@ -117,25 +139,50 @@ func (m *redissonWriteLock) renewExpirationInner(ctx context.Context, goroutineI
 	timeoutPrefix := m.getReadWriteTimeoutNamePrefix(goroutineId)
 	keyPrefix := m.getKeyPrefix(goroutineId, timeoutPrefix)
 
-	return m.client.Eval(ctx, `
+	// the reads held by the writer itself are renewed with the write, the other readers renew their own
+	return m.eval(ctx, "writeLock.renew", `
 local counter = redis.call('hget', KEYS[1], ARGV[2]);
 if (counter ~= false) then
     redis.call('pexpire', KEYS[1], ARGV[1]);
 
-    if (redis.call('hlen', KEYS[1]) > 1) then
-        local keys = redis.call('hkeys', KEYS[1]);
-        for n, key in ipairs(keys) do
-            counter = tonumber(redis.call('hget', KEYS[1], key));
-            if type(counter) == 'number' then
-                for i = counter, 1, -1 do
-                    redis.call('pexpire', KEYS[2] .. ':' .. key .. ':rwlock_timeout:' .. i, ARGV[1]);
-                end ;
-            end ;
+    local reads = tonumber(redis.call('hget', KEYS[1], ARGV[3]));
+    if (reads ~= nil) then
+        for i = reads, 1, -1 do
+            redis.call('pexpire', KEYS[2] .. ':' .. ARGV[3] .. ':rwlock_timeout:' .. i, ARGV[1]);
         end ;
     end ;
 
     return 1;
 end ;
 return 0;
-`, []string{m.getRawName(), keyPrefix}, m.internalLockLeaseTime.Milliseconds(), m.getLockName(goroutineId)).Int64()
+`, []string{m.getRawName(), keyPrefix}, m.getLockLeaseTime().Milliseconds(), m.getLockName(goroutineId), m.RedissonBaseLock.getLockName(goroutineId)).Int64()
+}
+
+// isLockedInner reports whether the lock is held for writing
+func (m *redissonWriteLock) isLockedInner(ctx context.Context) (bool, error) {
+	mode, err := m.client.HGet(ctx, m.getRawName(), "mode").Result()
+	if err == redis.Nil {
+		return false, nil
+	}
+	return mode == "write", err
+}
+
+// holdCountInner returns how many times the goroutine holds the lock for writing
+func (m *redissonWriteLock) holdCountInner(ctx context.Context, goroutineId uint64) (int64, error) {
+	count, err := m.client.HGet(ctx, m.getRawName(), m.getLockName(goroutineId)).Int64()
+	if err == redis.Nil {
+		return 0, nil
+	}
+	return count, err
+}
+
+// renewSpec returns how the renewalBatcher renews the lock held for writing by the goroutine
+func (m *redissonWriteLock) renewSpec(goroutineId uint64) (int, string) {
+	return renewHashField, m.getLockName(goroutineId)
+}
+
+// transferInner moves the holds of the lock for writing from a goroutine to another,
+// the reads of the writer stay with it
+func (m *redissonWriteLock) transferInner(ctx context.Context, from, to uint64) (int64, error) {
+	return m.transferHashHold(ctx, m.getLockName(from), m.getLockName(to))
 }
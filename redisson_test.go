@@ -0,0 +1,312 @@
+package redisson
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+func TestPing(t *testing.T) {
+	if err := GetRedisson().Ping(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWaitReady(t *testing.T) {
+	if err := GetRedisson().WaitReady(context.Background(), time.Second); err != nil {
+		t.Fatal(err)
+	}
+
+	// objects can be created without a live connection
+	g := NewRedisson(redis.NewClient(&redis.Options{
+		Addr: "localhost:1",
+	}))
+	_ = g.GetLock("TestWaitReady")
+	start := time.Now()
+	err := g.WaitReady(context.Background(), 300*time.Millisecond)
+	if !errors.Is(err, ErrNotReady) {
+		t.Fatalf("err=%v", err)
+	}
+	if time.Since(start) > time.Second {
+		t.Fatalf("WaitReady took %v", time.Since(start))
+	}
+}
+
+type traceKey struct{}
+
+// traceHook records the trace values of the contexts of the commands it sees
+type traceHook struct {
+	traces chan interface{}
+}
+
+func (h *traceHook) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+func (h *traceHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		select {
+		case h.traces <- ctx.Value(traceKey{}):
+		default:
+		}
+		return next(ctx, cmd)
+	}
+}
+
+func (h *traceHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return next
+}
+
+func TestContextProvider(t *testing.T) {
+	redisDB := redis.NewClient(&redis.Options{
+		Addr:                  redisAddr,
+		ContextTimeoutEnabled: true,
+	})
+	hook := &traceHook{traces: make(chan interface{}, 16)}
+	redisDB.AddHook(hook)
+	g := NewRedisson(redisDB, WithContextProvider(func() context.Context {
+		return context.WithValue(context.Background(), traceKey{}, "span-1")
+	}), WithCommandTimeout(time.Second))
+
+	if err := g.GetAtomicLong("TestContextProvider").Set(1); err != nil {
+		t.Fatal(err)
+	}
+	if trace := <-hook.traces; trace != "span-1" {
+		t.Fatalf("trace=%v", trace)
+	}
+}
+
+func TestContextWithCommandTimeout(t *testing.T) {
+	g := NewRedisson(GetRedisson().client, WithCommandTimeout(time.Hour))
+	ctx, cancel := g.withCommandTimeout(ContextWithCommandTimeout(context.Background(), time.Millisecond))
+	defer cancel()
+	deadline, ok := ctx.Deadline()
+	if !ok || time.Until(deadline) > time.Second {
+		t.Fatalf("deadline=%v ok=%v", deadline, ok)
+	}
+	ctx, cancel = g.newContext()
+	defer cancel()
+	if deadline, ok = ctx.Deadline(); !ok || time.Until(deadline) < time.Minute {
+		t.Fatalf("deadline=%v ok=%v", deadline, ok)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	report, err := GetRedisson().Verify(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = report.Err(); err != nil {
+		t.Fatal(err)
+	}
+	checked := map[string]bool{}
+	for _, c := range report.Checks {
+		checked[c.Name] = true
+	}
+	if !checked["eval"] || !checked["pubsub"] || !checked["command:bitfield"] {
+		t.Fatalf("checks=%+v", report.Checks)
+	}
+
+	report = &VerifyReport{}
+	report.add("command:bitfield", true, errors.New("command is not supported or renamed"), "")
+	report.add("modules", false, errors.New("unknown command"), "")
+	if report.OK() {
+		t.Fatal("report with a failed required check should not be OK")
+	}
+}
+
+func TestReleaseAll(t *testing.T) {
+	g := GetRedisson()
+	lock := g.GetLock("TestReleaseAll")
+	mutex := g.GetMutex("TestReleaseAllMutex")
+	rw := g.GetReadWriteLock("TestReleaseAllRW")
+	for _, l := range []Lock{lock, lock, mutex, rw.ReadLock()} {
+		if err := l.Lock(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// a lock acquired and released is no longer released on shutdown
+	released := g.GetLock("TestReleaseAllReleased")
+	if err := released.Lock(); err != nil {
+		t.Fatal(err)
+	}
+	if err := released.Unlock(); err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan error)
+	go func() {
+		done <- g.GetLock("TestReleaseAllOther").Lock()
+	}()
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+
+	if err := g.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	for _, l := range []Lock{lock, mutex, rw.ReadLock(), g.GetLock("TestReleaseAllOther")} {
+		locked, err := l.IsLocked()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if locked {
+			t.Fatal("locks should be released on shutdown")
+		}
+	}
+}
+
+// heldLockCount returns the number of holders of the lock named name in the registry of g
+func heldLockCount(g *Redisson, name string) int {
+	n := 0
+	g.heldLocks.Range(func(k, v any) bool {
+		if v.(*heldLock).lock.getRawName() == name {
+			n++
+		}
+		return true
+	})
+	return n
+}
+
+// TestReleaseAllLostHolds test the registry forgets the holds which are lost or released through another instance
+func TestReleaseAllLostHolds(t *testing.T) {
+	redisDB := redis.NewClient(&redis.Options{
+		Addr: redisAddr,
+	})
+	clock := NewManualClock(time.Now())
+	g := NewRedisson(redisDB, WithClock(clock))
+	redisDB.Del(context.Background(), "TestReleaseAllLostHoldsLeased", "TestReleaseAllLostHoldsDetached")
+
+	// the hold of a lock with a lease is forgotten when the lease expires
+	leased := g.GetLock("TestReleaseAllLostHoldsLeased", WithLease(time.Second))
+	if err := leased.Lock(); err != nil {
+		t.Fatal(err)
+	}
+	if heldLockCount(g, "TestReleaseAllLostHoldsLeased") != 1 {
+		t.Fatal("the hold should be registered")
+	}
+	clock.Advance(time.Second)
+	if heldLockCount(g, "TestReleaseAllLostHoldsLeased") != 0 {
+		t.Fatal("the hold should be forgotten with its lease")
+	}
+	redisDB.Del(context.Background(), "TestReleaseAllLostHoldsLeased")
+
+	// the hold of a lock deleted on the server is forgotten when unlocking fails
+	lost := g.GetLock("TestReleaseAllLostHoldsLost")
+	if err := lost.Lock(); err != nil {
+		t.Fatal(err)
+	}
+	redisDB.Del(context.Background(), "TestReleaseAllLostHoldsLost")
+	if err := lost.Unlock(); err == nil {
+		t.Fatal("unlocking a lost lock should fail")
+	}
+	if heldLockCount(g, "TestReleaseAllLostHoldsLost") != 0 {
+		t.Fatal("the hold of a lost lock should be forgotten")
+	}
+
+	// the hold of a lock lost on the server is forgotten once the watchdog fails to renew it
+	renewed := g.GetLock("TestReleaseAllLostHoldsRenewed")
+	if err := renewed.Lock(); err != nil {
+		t.Fatal(err)
+	}
+	redisDB.Del(context.Background(), "TestReleaseAllLostHoldsRenewed")
+	clock.Advance(g.watchDogTimeout / 3)
+	deadline := time.Now().Add(5 * time.Second)
+	for heldLockCount(g, "TestReleaseAllLostHoldsRenewed") != 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("the hold should be forgotten once renewing it fails")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// detached holds move to the goroutine attaching them
+	lock := g.GetLock("TestReleaseAllLostHoldsDetached")
+	if err := lock.Lock(); err != nil {
+		t.Fatal(err)
+	}
+	token, err := lock.Detach()
+	if err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan error)
+	go func() {
+		attached := g.GetLock("TestReleaseAllLostHoldsDetached")
+		if err := attached.Attach(token); err != nil {
+			done <- err
+			return
+		}
+		if heldLockCount(g, "TestReleaseAllLostHoldsDetached") != 1 {
+			done <- errors.New("the attached hold should replace the detached one")
+			return
+		}
+		done <- attached.Unlock()
+	}()
+	if err = <-done; err != nil {
+		t.Fatal(err)
+	}
+	if heldLockCount(g, "TestReleaseAllLostHoldsDetached") != 0 {
+		t.Fatal("the attached hold should be unregistered on unlock")
+	}
+}
+
+func TestDumpLocks(t *testing.T) {
+	g := GetRedisson()
+	lock := g.GetLock("TestDumpLocks")
+	if err := lock.Lock(); err != nil {
+		t.Fatal(err)
+	}
+	if err := lock.Lock(); err != nil {
+		t.Fatal(err)
+	}
+	mutex := g.GetMutex("TestDumpLocksMutex")
+	if err := mutex.Lock(); err != nil {
+		t.Fatal(err)
+	}
+	waited := make(chan error, 1)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := g.GetLock("TestDumpLocks").LockContext(ctx); err != nil {
+			waited <- err
+			return
+		}
+		waited <- g.GetLock("TestDumpLocks").Unlock()
+	}()
+	time.Sleep(100 * time.Millisecond)
+
+	infos, err := g.DumpLocks(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(infos) != 2 || infos[0].Name != "TestDumpLocks" || infos[1].Name != "TestDumpLocksMutex" {
+		t.Fatalf("unexpected locks %+v", infos)
+	}
+	if info := infos[0]; info.LocalHolds != 2 || info.LocalWaiters != 1 || len(info.Holders) != 1 || info.TTL <= 0 {
+		t.Fatalf("unexpected lock info %+v", info)
+	}
+	for _, count := range infos[0].Holders {
+		if count != 2 {
+			t.Fatalf("lock should be held twice, got %d", count)
+		}
+	}
+	if info := infos[1]; info.LocalHolds != 1 || info.LocalWaiters != 0 || len(info.Holders) != 1 {
+		t.Fatalf("unexpected mutex info %+v", info)
+	}
+
+	for _, l := range []Lock{lock, lock, mutex} {
+		if err = l.Unlock(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = <-waited; err != nil {
+		t.Fatal(err)
+	}
+	if infos, err = g.DumpLocks(context.Background()); err != nil || len(infos) != 0 {
+		t.Fatalf("no lock should be reported once released, got %+v %v", infos, err)
+	}
+	if _, ok := g.lockWaiters.Load("TestDumpLocks"); ok {
+		t.Fatal("the waiter count should be removed with the last waiter")
+	}
+}
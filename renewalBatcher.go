@@ -0,0 +1,180 @@
+package redisson
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+const (
+	// renewHashField renews a lock held as a field of a hash, like RedissonLock and the write lock
+	renewHashField = 1
+	// renewStringValue renews a lock held as the value of a string, like RedissonMutex
+	renewStringValue = 2
+)
+
+// batchRenewer is implemented by the lockers whose expiration the renewalBatcher can renew
+type batchRenewer interface {
+	// renewSpec returns how the renewalBatcher checks that the goroutine still holds the lock, and the name it holds it with
+	renewSpec(goroutineId uint64) (kind int, holder string)
+}
+
+// renewalTask is a lock renewed by the renewalBatcher
+type renewalTask struct {
+	key string
+	//spec returns the renewSpec of the lock, false once nobody holds it in this process
+	spec func() (int, string, bool)
+	//lease returns the lease the lock is renewed with
+	lease func() time.Duration
+	//renewed is called after every renewal of the lock, with the error if it failed
+	renewed func(err error)
+	//done is called once the lock is no longer renewed, because it was lost or renewing it failed
+	done func(err error)
+	//due time of the next renewal
+	due time.Time
+}
+
+// renewalBatcher renews the expiration of all the locks held by a Redisson instance which are due
+// with one script call, instead of one call per lock every lease/3
+type renewalBatcher struct {
+	sync.Mutex
+	g     *Redisson
+	tasks map[*renewalTask]struct{}
+	//wake wakes the renewal loop when a task is added
+	wake    chan struct{}
+	running bool
+}
+
+// newRenewalBatcher creates a new renewalBatcher
+func newRenewalBatcher(g *Redisson) *renewalBatcher {
+	return &renewalBatcher{
+		g:     g,
+		tasks: make(map[*renewalTask]struct{}),
+		wake:  make(chan struct{}, 1),
+	}
+}
+
+// add starts renewing the task, and returns the function which stops renewing it
+func (b *renewalBatcher) add(task *renewalTask) (remove func()) {
+	b.Lock()
+	task.due = b.g.clock.Now().Add(task.lease() / 3)
+	b.tasks[task] = struct{}{}
+	if !b.running {
+		b.running = true
+		go b.run()
+	}
+	b.Unlock()
+	select {
+	case b.wake <- struct{}{}:
+	default:
+	}
+	return func() {
+		b.Lock()
+		delete(b.tasks, task)
+		b.Unlock()
+	}
+}
+
+// run renews the due tasks until there are none left
+func (b *renewalBatcher) run() {
+	for {
+		b.Lock()
+		if len(b.tasks) == 0 {
+			b.running = false
+			b.Unlock()
+			return
+		}
+		var next time.Time
+		for task := range b.tasks {
+			if next.IsZero() || task.due.Before(next) {
+				next = task.due
+			}
+		}
+		b.Unlock()
+		select {
+		case <-b.wake:
+			continue
+		case <-b.g.clock.After(next.Sub(b.g.clock.Now())):
+		}
+		b.renewDue()
+	}
+}
+
+// renewDue renews the tasks due now, along with those due within half their interval
+// which would otherwise need another call soon after
+func (b *renewalBatcher) renewDue() {
+	now := b.g.clock.Now()
+	b.Lock()
+	var due []*renewalTask
+	var keys []string
+	var args []interface{}
+	for task := range b.tasks {
+		if task.due.After(now.Add(task.lease() / 6)) {
+			continue
+		}
+		kind, holder, ok := task.spec()
+		if !ok {
+			delete(b.tasks, task)
+			continue
+		}
+		due = append(due, task)
+		keys = append(keys, task.key)
+		args = append(args, kind, holder, task.lease().Milliseconds())
+	}
+	b.Unlock()
+	if len(due) == 0 {
+		return
+	}
+
+	ctx, cancel := b.g.withCommandTimeout(context.WithoutCancel(b.g.baseContext()))
+	defer cancel()
+	res, err := b.g.eval(ctx, "lock.renewBatch", `
+local result = {};
+for i = 1, #KEYS, 1 do
+    local kind = ARGV[i * 3 - 2];
+    local holder = ARGV[i * 3 - 1];
+    local held;
+    if kind == '1' then
+        held = redis.call('hexists', KEYS[i], holder) == 1;
+    else
+        held = redis.call('get', KEYS[i]) == holder;
+    end ;
+    if held then
+        redis.call('pexpire', KEYS[i], ARGV[i * 3]);
+        result[i] = 1;
+    else
+        result[i] = 0;
+    end ;
+end ;
+return result;
+`, keys, args...).Int64Slice()
+	if err != nil {
+		log.Printf("renewing %d locks failed: %v", len(due), err)
+	}
+
+	now = b.g.clock.Now()
+	var done, renewed []*renewalTask
+	b.Lock()
+	for i, task := range due {
+		if _, ok := b.tasks[task]; !ok {
+			continue
+		}
+		if err != nil || res[i] == 1 {
+			renewed = append(renewed, task)
+		}
+		if err != nil || res[i] == 0 {
+			delete(b.tasks, task)
+			done = append(done, task)
+			continue
+		}
+		task.due = now.Add(task.lease() / 3)
+	}
+	b.Unlock()
+	for _, task := range renewed {
+		task.renewed(err)
+	}
+	for _, task := range done {
+		task.done(err)
+	}
+}
@@ -0,0 +1,79 @@
+package redisson
+
+import (
+	"math/rand"
+	"time"
+)
+
+// RetryStrategy decides how long a lock waiter waits before retrying when no unlock notification arrives.
+type RetryStrategy interface {
+	// Delay returns the wait before retry number attempt, starting at 1, given the remaining ttl of the lock,
+	// which is negative when the lock does not expire.
+	Delay(attempt int, ttl time.Duration) time.Duration
+}
+
+// TTLRetry is the RetryStrategy of a lock when none is configured, it retries once the lock expires.
+var TTLRetry RetryStrategy = ttlRetry{}
+
+// ttlRetry is the RetryStrategy retrying when the lock expires
+type ttlRetry struct{}
+
+// Delay returns ttl.
+func (ttlRetry) Delay(_ int, ttl time.Duration) time.Duration {
+	return ttl
+}
+
+// FixedRetry returns a RetryStrategy retrying every interval.
+func FixedRetry(interval time.Duration) RetryStrategy {
+	return fixedRetry{interval: interval}
+}
+
+// fixedRetry is the RetryStrategy retrying every interval
+type fixedRetry struct {
+	interval time.Duration
+}
+
+// Delay returns the interval.
+func (r fixedRetry) Delay(int, time.Duration) time.Duration {
+	return r.interval
+}
+
+// ExponentialRetry returns a RetryStrategy doubling the wait from initial up to max.
+func ExponentialRetry(initial, max time.Duration) RetryStrategy {
+	return exponentialRetry{initial: initial, max: max}
+}
+
+// exponentialRetry is the RetryStrategy doubling the wait from initial up to max
+type exponentialRetry struct {
+	initial time.Duration
+	max     time.Duration
+}
+
+// Delay returns initial doubled attempt-1 times, at most max.
+func (r exponentialRetry) Delay(attempt int, _ time.Duration) time.Duration {
+	delay := r.initial
+	for i := 1; i < attempt && delay < r.max; i++ {
+		delay *= 2
+	}
+	if delay > r.max {
+		return r.max
+	}
+	return delay
+}
+
+// JitteredRetry returns a RetryStrategy waiting a random time up to the exponential wait from initial up to max,
+// so that the waiters of a lock do not retry in lockstep.
+func JitteredRetry(initial, max time.Duration) RetryStrategy {
+	return jitteredRetry{exponentialRetry{initial: initial, max: max}}
+}
+
+// jitteredRetry is the RetryStrategy waiting a random time up to the exponential wait
+type jitteredRetry struct {
+	exponentialRetry
+}
+
+// Delay returns a random time between half and all of the exponential wait.
+func (r jitteredRetry) Delay(attempt int, ttl time.Duration) time.Duration {
+	delay := r.exponentialRetry.Delay(attempt, ttl)
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
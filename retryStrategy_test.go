@@ -0,0 +1,62 @@
+package redisson
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRetryStrategyDelay(t *testing.T) {
+	if d := TTLRetry.Delay(3, time.Second); d != time.Second {
+		t.Fatalf("ttl retry should wait for the ttl, got %v", d)
+	}
+	if d := FixedRetry(100*time.Millisecond).Delay(5, time.Second); d != 100*time.Millisecond {
+		t.Fatalf("fixed retry should wait the interval, got %v", d)
+	}
+	exponential := ExponentialRetry(10*time.Millisecond, time.Second)
+	for attempt, want := range map[int]time.Duration{1: 10 * time.Millisecond, 2: 20 * time.Millisecond, 4: 80 * time.Millisecond, 20: time.Second} {
+		if d := exponential.Delay(attempt, time.Minute); d != want {
+			t.Fatalf("exponential retry attempt %d should wait %v, got %v", attempt, want, d)
+		}
+	}
+	jittered := JitteredRetry(10*time.Millisecond, time.Second)
+	for i := 0; i < 100; i++ {
+		if d := jittered.Delay(3, time.Minute); d < 20*time.Millisecond || d > 40*time.Millisecond {
+			t.Fatalf("jittered retry attempt 3 should wait between 20ms and 40ms, got %v", d)
+		}
+	}
+}
+
+// TestLockRetryStrategy test a waiter retrying with its strategy when the lock is released without notification
+func TestLockRetryStrategy(t *testing.T) {
+	g := GetRedisson()
+	holder := g.GetLock("TestLockRetryStrategy")
+	if err := holder.Lock(); err != nil {
+		t.Fatal(err)
+	}
+
+	waiter := g.GetLock("TestLockRetryStrategy", WithRetryStrategy(FixedRetry(50*time.Millisecond)))
+	done := make(chan error, 1)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := waiter.LockContext(ctx); err != nil {
+			done <- err
+			return
+		}
+		done <- waiter.Unlock()
+	}()
+	time.Sleep(100 * time.Millisecond)
+	// the lock disappears without an unlock notification, as when its holder died and it expired
+	if err := g.client.Del(context.Background(), "TestLockRetryStrategy").Err(); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("waiter should retry with its strategy instead of waiting for the ttl")
+	}
+}
@@ -0,0 +1,158 @@
+package redisson
+
+import (
+	"context"
+	"math/rand"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ScriptStats is the latency profile of one embedded Lua script.
+type ScriptStats struct {
+	// Name identifies the script, e.g. "lock.tryLock" or "rateLimiter.tryAcquire"
+	Name string
+	// SHA is the SHA1 digest the script is run with
+	SHA string
+	// Calls is the number of sampled calls
+	Calls int64
+	// ClientTotal and ClientMax are the round trip latencies of the sampled calls measured by the client
+	ClientTotal time.Duration
+	ClientMax   time.Duration
+	// SlowCalls is the number of executions found in the SLOWLOG of the server
+	SlowCalls int64
+	// ServerTotal and ServerMax are the execution times of the executions found in the SLOWLOG
+	ServerTotal time.Duration
+	ServerMax   time.Duration
+}
+
+// ClientMean returns the mean round trip latency of the sampled calls.
+func (s ScriptStats) ClientMean() time.Duration {
+	if s.Calls == 0 {
+		return 0
+	}
+	return s.ClientTotal / time.Duration(s.Calls)
+}
+
+// scriptProfiler samples the latency of the scripts run by a Redisson instance
+type scriptProfiler struct {
+	sync.Mutex
+	sampleRate float64
+	//scripts scripts by source, run with EVALSHA so the SLOWLOG entries carry their SHA
+	scripts sync.Map
+	stats   map[string]*ScriptStats
+	//names script names by SHA
+	names map[string]string
+	//lastSlowLogId id of the most recent SLOWLOG entry already accounted
+	lastSlowLogId int64
+}
+
+// newScriptProfiler creates a new scriptProfiler
+func newScriptProfiler(sampleRate float64) *scriptProfiler {
+	return &scriptProfiler{
+		sampleRate:    sampleRate,
+		stats:         make(map[string]*ScriptStats),
+		names:         make(map[string]string),
+		lastSlowLogId: -1,
+	}
+}
+
+// script returns the cached *redis.Script of src
+func (p *scriptProfiler) script(src string) *redis.Script {
+	s, ok := p.scripts.Load(src)
+	if !ok {
+		s, _ = p.scripts.LoadOrStore(src, redis.NewScript(src))
+	}
+	return s.(*redis.Script)
+}
+
+// record accounts a sampled call of the script
+func (p *scriptProfiler) record(name, sha string, elapsed time.Duration) {
+	p.Lock()
+	defer p.Unlock()
+	s := p.statsOf(name, sha)
+	s.Calls++
+	s.ClientTotal += elapsed
+	if elapsed > s.ClientMax {
+		s.ClientMax = elapsed
+	}
+}
+
+// statsOf returns the stats of the script, the caller must hold the lock
+func (p *scriptProfiler) statsOf(name, sha string) *ScriptStats {
+	s, ok := p.stats[name]
+	if !ok {
+		s = &ScriptStats{Name: name, SHA: sha}
+		p.stats[name] = s
+		p.names[sha] = name
+	}
+	return s
+}
+
+// eval runs the script, recording its latency when the profiler is enabled and the call is sampled
+func (g *Redisson) eval(ctx context.Context, name, script string, keys []string, args ...interface{}) *redis.Cmd {
+	p := g.scriptProfiler
+	if p == nil {
+		return g.client.Eval(ctx, script, keys, args...)
+	}
+	s := p.script(script)
+	if rand.Float64() >= p.sampleRate {
+		return s.Run(ctx, g.client, keys, args...)
+	}
+	start := time.Now()
+	cmd := s.Run(ctx, g.client, keys, args...)
+	p.record(name, s.Hash(), time.Since(start))
+	return cmd
+}
+
+// ScriptProfile returns the latency profile of every script sampled so far, slowest first.
+// Server execution times are correlated from the SLOWLOG by script SHA, so only executions slower than
+// the slowlog-log-slower-than setting of the server are counted. It returns nil if profiling is disabled.
+func (g *Redisson) ScriptProfile(ctx context.Context) ([]ScriptStats, error) {
+	p := g.scriptProfiler
+	if p == nil {
+		return nil, nil
+	}
+	entries, err := g.client.SlowLogGet(ctx, 128).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	p.Lock()
+	defer p.Unlock()
+	lastId := p.lastSlowLogId
+	for _, e := range entries {
+		if e.ID <= p.lastSlowLogId {
+			continue
+		}
+		if e.ID > lastId {
+			lastId = e.ID
+		}
+		if len(e.Args) < 2 || !strings.EqualFold(e.Args[0], "evalsha") {
+			continue
+		}
+		name, ok := p.names[e.Args[1]]
+		if !ok {
+			continue
+		}
+		s := p.stats[name]
+		s.SlowCalls++
+		s.ServerTotal += e.Duration
+		if e.Duration > s.ServerMax {
+			s.ServerMax = e.Duration
+		}
+	}
+	p.lastSlowLogId = lastId
+
+	report := make([]ScriptStats, 0, len(p.stats))
+	for _, s := range p.stats {
+		report = append(report, *s)
+	}
+	sort.Slice(report, func(i, j int) bool {
+		return report[i].ServerTotal+report[i].ClientTotal > report[j].ServerTotal+report[j].ClientTotal
+	})
+	return report, nil
+}
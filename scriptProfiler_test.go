@@ -0,0 +1,42 @@
+package redisson
+
+import (
+	"context"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+)
+
+func TestScriptProfile(t *testing.T) {
+	redisDB := redis.NewClient(&redis.Options{
+		Addr: redisAddr,
+	})
+	g := NewRedisson(redisDB, WithScriptProfiler(1))
+	l := g.GetLock("TestScriptProfile")
+	for i := 0; i < 3; i++ {
+		if err := l.Lock(); err != nil {
+			t.Fatal(err)
+		}
+		if err := l.Unlock(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	report, err := g.ScriptProfile(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	calls := map[string]int64{}
+	for _, s := range report {
+		calls[s.Name] = s.Calls
+		if s.SHA == "" || s.ClientMean() <= 0 {
+			t.Fatalf("stats=%+v", s)
+		}
+	}
+	if calls["lock.tryLock"] != 3 || calls["lock.unlock"] != 3 {
+		t.Fatalf("calls=%v", calls)
+	}
+
+	if report, err = GetRedisson().ScriptProfile(context.Background()); err != nil || report != nil {
+		t.Fatalf("report=%v err=%v", report, err)
+	}
+}
@@ -0,0 +1,98 @@
+package redisson
+
+import (
+	"context"
+	"sync"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// subscription is the registration of one waiter for the messages of a channel
+type subscription struct {
+	channel string
+	//c receives a value when a message is published on the channel, messages received while one is pending are merged
+	c chan struct{}
+	//notify if not nil is called instead of signaling c, it must not block
+	notify func()
+}
+
+// subscriptionManager multiplexes the subscriptions of all the waiters of a Redisson instance over one
+// Pub/Sub connection, subscribing each channel once and fanning its messages out to its waiters
+type subscriptionManager struct {
+	sync.Mutex
+	client *redis.Client
+	//pubsub the shared Pub/Sub connection, created on the first subscription
+	pubsub *redis.PubSub
+	//channels waiters by channel
+	channels map[string]map[*subscription]struct{}
+}
+
+// newSubscriptionManager creates a new subscriptionManager
+func newSubscriptionManager(client *redis.Client) *subscriptionManager {
+	return &subscriptionManager{
+		client:   client,
+		channels: make(map[string]map[*subscription]struct{}),
+	}
+}
+
+// subscribe registers a waiter for the messages of channel, subscribing the channel if it has no other waiter
+func (s *subscriptionManager) subscribe(ctx context.Context, channel string) (*subscription, error) {
+	return s.add(ctx, &subscription{channel: channel, c: make(chan struct{}, 1)})
+}
+
+// subscribeFunc registers a waiter calling notify on the messages of channel, for the waits which are not
+// blocked in a goroutine
+func (s *subscriptionManager) subscribeFunc(ctx context.Context, channel string, notify func()) (*subscription, error) {
+	return s.add(ctx, &subscription{channel: channel, notify: notify})
+}
+
+// add registers sub, subscribing its channel if it has no other waiter
+func (s *subscriptionManager) add(ctx context.Context, sub *subscription) (*subscription, error) {
+	s.Lock()
+	defer s.Unlock()
+	channel := sub.channel
+	if waiters, ok := s.channels[channel]; ok {
+		waiters[sub] = struct{}{}
+		return sub, nil
+	}
+	if s.pubsub == nil {
+		s.pubsub = s.client.Subscribe(context.WithoutCancel(ctx))
+		go s.dispatch(s.pubsub.Channel())
+	}
+	if err := s.pubsub.Subscribe(ctx, channel); err != nil {
+		return nil, err
+	}
+	s.channels[channel] = map[*subscription]struct{}{sub: {}}
+	return sub, nil
+}
+
+// unsubscribe removes the waiter, unsubscribing its channel if it was the last one
+func (s *subscriptionManager) unsubscribe(ctx context.Context, sub *subscription) error {
+	s.Lock()
+	defer s.Unlock()
+	waiters := s.channels[sub.channel]
+	delete(waiters, sub)
+	if len(waiters) > 0 {
+		return nil
+	}
+	delete(s.channels, sub.channel)
+	return s.pubsub.Unsubscribe(ctx, sub.channel)
+}
+
+// dispatch wakes the waiters of the channels of the messages received on ch
+func (s *subscriptionManager) dispatch(ch <-chan *redis.Message) {
+	for msg := range ch {
+		s.Lock()
+		for sub := range s.channels[msg.Channel] {
+			if sub.notify != nil {
+				sub.notify()
+				continue
+			}
+			select {
+			case sub.c <- struct{}{}:
+			default:
+			}
+		}
+		s.Unlock()
+	}
+}
@@ -0,0 +1,79 @@
+package redisson
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSubscriptionManager(t *testing.T) {
+	g := GetRedisson()
+	ctx := context.Background()
+	subs := make([]*subscription, 3)
+	for i := range subs {
+		sub, err := g.subscriptions.subscribe(ctx, "TestSubscriptionManager")
+		if err != nil {
+			t.Fatal(err)
+		}
+		subs[i] = sub
+	}
+	// the waiters share one subscription of the channel
+	time.Sleep(50 * time.Millisecond)
+	if n, err := g.client.PubSubNumSub(ctx, "TestSubscriptionManager").Result(); err != nil || n["TestSubscriptionManager"] != 1 {
+		t.Fatalf("n=%v err=%v", n, err)
+	}
+	if err := g.client.Publish(ctx, "TestSubscriptionManager", "m").Err(); err != nil {
+		t.Fatal(err)
+	}
+	for i, sub := range subs {
+		select {
+		case <-sub.c:
+		case <-time.After(time.Second):
+			t.Fatalf("waiter %d not woken", i)
+		}
+	}
+	for _, sub := range subs {
+		if err := g.subscriptions.unsubscribe(ctx, sub); err != nil {
+			t.Fatal(err)
+		}
+	}
+	time.Sleep(50 * time.Millisecond)
+	if n, err := g.client.PubSubNumSub(ctx, "TestSubscriptionManager").Result(); err != nil || n["TestSubscriptionManager"] != 0 {
+		t.Fatalf("n=%v err=%v", n, err)
+	}
+}
+
+func TestLockSharedSubscription(t *testing.T) {
+	g := GetRedisson()
+	l := g.GetLock("TestLockSharedSubscription")
+	if err := l.Lock(); err != nil {
+		t.Fatal(err)
+	}
+	wg := sync.WaitGroup{}
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if err := g.GetLock("TestLockSharedSubscription").LockContext(ctx); err != nil {
+				panic(err)
+			}
+			if err := g.GetLock("TestLockSharedSubscription").Unlock(); err != nil {
+				panic(err)
+			}
+		}()
+	}
+	time.Sleep(100 * time.Millisecond)
+	// the 50 waiters hold one subscription
+	channel := "redisson_lock__channel:{TestLockSharedSubscription}"
+	n, err := g.client.PubSubNumSub(context.Background(), channel).Result()
+	if err != nil || n[channel] != 1 {
+		t.Fatalf("n=%v err=%v", n, err)
+	}
+	if err = l.Unlock(); err != nil {
+		t.Fatal(err)
+	}
+	wg.Wait()
+}